@@ -7,3 +7,9 @@ package main
 
 //go:generate protoc --proto_path=. --go_out=. --go_opt=paths=source_relative --go-grpc_out=. --go-grpc_opt=paths=source_relative proto/candlestick/contracts/models.proto
 //go:generate protoc --proto_path=. --go_out=. --go_opt=paths=source_relative --go-grpc_out=. --go-grpc_opt=paths=source_relative proto/candlestick/contracts/service.proto
+//go:generate protoc --proto_path=. --grpc-gateway_out=. --grpc-gateway_opt=paths=source_relative,allow_delete_body=true proto/candlestick/contracts/service.proto
+//go:generate protoc --proto_path=. --go_out=. --go_opt=paths=source_relative --go-grpc_out=. --go-grpc_opt=paths=source_relative proto/alert/contracts/models.proto
+//go:generate protoc --proto_path=. --go_out=. --go_opt=paths=source_relative --go-grpc_out=. --go-grpc_opt=paths=source_relative proto/alert/contracts/service.proto
+//go:generate protoc --proto_path=. --grpc-gateway_out=. --grpc-gateway_opt=paths=source_relative,allow_delete_body=true proto/alert/contracts/service.proto
+//go:generate protoc --proto_path=. --go_out=. --go_opt=paths=source_relative --go-grpc_out=. --go-grpc_opt=paths=source_relative proto/export/contracts/models.proto
+//go:generate protoc --proto_path=. --go_out=. --go_opt=paths=source_relative --go-grpc_out=. --go-grpc_opt=paths=source_relative proto/export/contracts/service.proto
@@ -7,5 +7,4 @@ var (
 		"ethusdt",
 		"pepeusdt",
 	}
-	TRACE_CONTEXT_KEY = "TraceContextKey"
 )
@@ -0,0 +1,67 @@
+package internal
+
+import "time"
+
+const (
+	DEFAULT_GRPC_ADDRESS = ":50051"
+
+	DEFAULT_KEEPALIVE_TIME     = 30 * time.Second
+	DEFAULT_KEEPALIVE_TIMEOUT  = 10 * time.Second
+	DEFAULT_KEEPALIVE_MIN_TIME = 10 * time.Second
+
+	DEFAULT_MAX_CONCURRENT_STREAMS = 1000
+	DEFAULT_MAX_RECV_MSG_SIZE      = 4 << 20
+	DEFAULT_MAX_SEND_MSG_SIZE      = 4 << 20
+)
+
+type ServerConfig struct {
+	HttpPort string
+	// e.g. ":50051"
+	GrpcAddress string
+	TLS         TLSConfig
+	Keepalive   KeepaliveConfig
+	// per client connection
+	MaxConcurrentStreams uint32
+	MaxRecvMsgSize       int
+	MaxSendMsgSize       int
+	// lets grpcurl list the services, off by default outside dev mode
+	Reflection bool
+	// origins browsers may open the websocket stream from, besides the
+	// server's own, e.g. "https://trading.example.com", or "*" for any
+	AllowedOrigins []string
+}
+
+// TLSConfig serves the grpc server over TLS once the certificate is set,
+// the files being reloaded once they change
+type TLSConfig struct {
+	CertFile string
+	KeyFile  string
+	// verifies the client certificates, and the server certificate when
+	// dialing the server internally, e.g. from the http gateway
+	CAFile string
+	// requires client certificates verified by the CA, the server certificate
+	// being presented as one when dialing the server internally
+	ClientAuth bool
+	// expected in the server certificate when dialing the server internally,
+	// the dialed host if empty
+	ServerName string
+}
+
+func (c *TLSConfig) Enabled() bool {
+	return c.CertFile != ""
+}
+
+type KeepaliveConfig struct {
+	// an idle connection is pinged after Time, and closed unless the ping
+	// is acknowledged within Timeout
+	Time    time.Duration
+	Timeout time.Duration
+	// connections idle or older are closed gracefully, never if zero
+	MaxConnectionIdle     time.Duration
+	MaxConnectionAge      time.Duration
+	MaxConnectionAgeGrace time.Duration
+	// clients pinging more often are disconnected
+	MinTime time.Duration
+	// clients may ping without any stream open
+	PermitWithoutStream bool
+}
@@ -0,0 +1,35 @@
+package testutil
+
+import (
+	"sync"
+	"time"
+)
+
+// Clock only moves forward when told to
+type Clock struct {
+	mutex sync.Mutex
+	now   time.Time
+}
+
+func NewClock(
+	now time.Time,
+) *Clock {
+	return &Clock{now: now}
+}
+
+func (c *Clock) Now() time.Time {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	return c.now
+}
+
+// Advance moves the clock forward by d
+func (c *Clock) Advance(
+	d time.Duration,
+) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	c.now = c.now.Add(d)
+}
@@ -0,0 +1,16 @@
+package testutil
+
+import (
+	"context"
+
+	"github.com/ramasbeinaty/trading-chart-service/pkg/domain/base/logger"
+	"go.uber.org/zap"
+)
+
+// NopLogger discards every log of the services under test
+type NopLogger struct{}
+
+var _ logger.ILogger = NopLogger{}
+
+func (NopLogger) Get(context.Context) *zap.Logger { return zap.NewNop() }
+func (NopLogger) Close()                          {}
@@ -3,42 +3,207 @@ package main
 // https://developers.binance.com/docs/binance-spot-api-docs/web-socket-streams#aggregate-trade-streams
 
 import (
-	"context"
-	"log"
+	"errors"
+	"flag"
+	"fmt"
 	"os"
-	"os/signal"
-	"sync"
-	"syscall"
+	"strings"
+	"time"
 
 	"github.com/ramasbeinaty/trading-chart-service/pkg/app"
+	"github.com/ramasbeinaty/trading-chart-service/pkg/domain/export"
+	"github.com/ramasbeinaty/trading-chart-service/pkg/domain/indicator"
 )
 
+// command is a subcommand of the binary, parsing its own flags
+type command struct {
+	name        string
+	description string
+	run         func(args []string) error
+}
+
+var commands = []command{
+	{"serve", "Run the service, ingesting the trades streamed by binance (default)", runServe},
+	{"migrate", "Run the db migrations not applied yet", runMigrate},
+	{"backfill", "Store a symbol's bars of a range from binance's klines", runBackfill},
+	{"replay", "Run the service, ingesting the trades recorded in a csv file", runReplay},
+	{"export", "Export a symbol's bars of a timeframe to a csv, parquet or arrow file", runExport},
+}
+
 func main() {
-	ctx, cancel := context.WithCancel(context.Background())
-	defer cancel()
-
-	// ========= Setup graceful system shutdown =========
-	quit := make(chan os.Signal, 1)
-	signal.Notify(quit, syscall.SIGINT, syscall.SIGTERM)
-
-	// ========= Start the app ========
-	var wg sync.WaitGroup
-	_app := app.StartAppService(ctx, &wg)
-
-	// ========= Start GRPC server =========
-	_grpc := app.StartGRPCServer(
-		_app.Lgr,
-		&wg,
-		_app.CandlestickHandler,
-	)
-
-	// - Handle system shutdown
-	<-quit
-	log.Println("Shutting down system...")
-
-	_app.StopAppService()
-	_grpc.StopGrpcServer()
-	wg.Wait()
-	
-	log.Println("Gracefully terminated the system, exiting...")
+	if err := run(os.Args[1:]); err != nil {
+		if !errors.Is(err, flag.ErrHelp) {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		}
+		os.Exit(1)
+	}
+}
+
+// serves without a command
+func run(args []string) error {
+	if len(args) == 0 {
+		return runServe(args)
+	}
+
+	for _, c := range commands {
+		if c.name == args[0] {
+			return c.run(args[1:])
+		}
+	}
+
+	switch args[0] {
+	case "help", "-h", "-help", "--help":
+		usage()
+		return nil
+	}
+	usage()
+	return fmt.Errorf("unknown command %q", args[0])
+}
+
+func usage() {
+	fmt.Fprintf(os.Stderr, "Usage: %s <command> [flags]\n\nCommands:\n", os.Args[0])
+	for _, c := range commands {
+		fmt.Fprintf(os.Stderr, "  %-10s %s\n", c.name, c.description)
+	}
+	fmt.Fprintf(os.Stderr, "\nRun '%s <command> -h' for the flags of a command\n", os.Args[0])
+}
+
+func newFlagSet(name string) *flag.FlagSet {
+	return flag.NewFlagSet(name, flag.ContinueOnError)
+}
+
+func runServe(args []string) error {
+	if err := newFlagSet("serve").Parse(args); err != nil {
+		return err
+	}
+	return app.Serve()
+}
+
+func runMigrate(args []string) error {
+	if err := newFlagSet("migrate").Parse(args); err != nil {
+		return err
+	}
+	return app.Migrate()
+}
+
+func runBackfill(args []string) error {
+	flags := newFlagSet("backfill")
+	symbol := flags.String("symbol", "", "symbol to backfill, e.g. BTCUSDT (required)")
+	from := timeFlag(flags, "from", time.Time{}, "start of the range (required)")
+	to := timeFlag(flags, "to", time.Now(), "end of the range, now by default")
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+
+	if *symbol == "" || from.IsZero() {
+		flags.Usage()
+		return fmt.Errorf("backfill requires --symbol and --from")
+	}
+	return app.Backfill(*symbol, *from, *to)
+}
+
+func runReplay(args []string) error {
+	flags := newFlagSet("replay")
+	file := flags.String("file", "", "csv file of symbol,price,timestamp rows, in time order (required)")
+	speed := flags.Float64("speed", 1, "speed of the replay relative to the recorded time, 0 to replay as fast as possible")
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+
+	if *file == "" {
+		flags.Usage()
+		return fmt.Errorf("replay requires --file")
+	}
+	return app.Replay(*file, *speed)
+}
+
+func runExport(args []string) error {
+	flags := newFlagSet("export")
+	symbol := flags.String("symbol", "", "symbol to export, e.g. BTCUSDT (required)")
+	timeframe := flags.String("timeframe", string(indicator.TIMEFRAME_1M), "timeframe of the bars: 1m, 5m, 15m or 1h")
+	format := flags.String("format", string(export.FORMAT_CSV), "format of the file: csv, parquet or arrow")
+	columns := flags.String("columns", "", "comma separated columns written, in order, every column by default: symbol,timestamp,open,high,low,close")
+	from := timeFlag(flags, "from", time.Unix(0, 0), "start of the range, the first bar by default")
+	to := timeFlag(flags, "to", time.Now(), "end of the range, now by default")
+	out := flags.String("out", "-", "file written, - for stdout")
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+
+	if *symbol == "" {
+		flags.Usage()
+		return fmt.Errorf("export requires --symbol")
+	}
+
+	req := export.Request{
+		Symbol:    *symbol,
+		Timeframe: indicator.Timeframe(*timeframe),
+		Format:    export.Format(strings.ToLower(*format)),
+		From:      *from,
+		To:        *to,
+	}
+	if *columns != "" {
+		for _, column := range strings.Split(*columns, ",") {
+			req.Columns = append(req.Columns, export.Column(strings.ToLower(strings.TrimSpace(column))))
+		}
+	}
+
+	if *out == "-" {
+		return app.Export(req, os.Stdout)
+	}
+	return exportToFile(req, *out)
+}
+
+// writes the export to the file, removing it if the export fails midway
+func exportToFile(
+	req export.Request,
+	path string,
+) error {
+	file, err := os.Create(path)
+	if err != nil {
+		return fmt.Errorf("Failed to create the export file - %w", err)
+	}
+
+	err = app.Export(req, file)
+	if closeErr := file.Close(); err == nil && closeErr != nil {
+		err = fmt.Errorf("Failed to write the export file - %w", closeErr)
+	}
+	if err != nil {
+		os.Remove(path)
+		return err
+	}
+	return nil
+}
+
+// timeValue is a time flag, written in RFC 3339 or as a UTC date
+type timeValue struct {
+	t *time.Time
+}
+
+func timeFlag(
+	flags *flag.FlagSet,
+	name string,
+	value time.Time,
+	usage string,
+) *time.Time {
+	t := value
+	flags.Var(&timeValue{&t}, name, usage+", e.g. 2024-01-02 or 2024-01-02T15:04:05Z")
+	return &t
+}
+
+func (v *timeValue) String() string {
+	if v.t == nil || v.t.IsZero() {
+		return ""
+	}
+	return v.t.Format(time.RFC3339)
+}
+
+func (v *timeValue) Set(raw string) error {
+	for _, layout := range []string{time.RFC3339, time.DateOnly} {
+		if t, err := time.Parse(layout, raw); err == nil {
+			*v.t = t
+			return nil
+		}
+	}
+	return fmt.Errorf("expected RFC 3339 or a date, got %q", raw)
 }
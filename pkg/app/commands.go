@@ -0,0 +1,217 @@
+package app
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"log"
+	"os"
+	"os/signal"
+	"sync"
+	"syscall"
+	"time"
+
+	"github.com/ramasbeinaty/trading-chart-service/pkg/domain/backfill"
+	"github.com/ramasbeinaty/trading-chart-service/pkg/domain/export"
+	"github.com/ramasbeinaty/trading-chart-service/pkg/infra/clients/binance"
+	"github.com/ramasbeinaty/trading-chart-service/pkg/infra/exporters"
+	"github.com/ramasbeinaty/trading-chart-service/pkg/infra/repos/candlestickrepo"
+	"go.uber.org/zap"
+)
+
+// the file formats of the exports
+var exportFormats = map[export.Format]export.NewBarWriter{
+	export.FORMAT_CSV:     exporters.NewCSVWriter,
+	export.FORMAT_PARQUET: exporters.NewParquetWriter,
+	export.FORMAT_ARROW:   exporters.NewArrowWriter,
+}
+
+// Serve runs the service until SIGINT or SIGTERM, ingesting the trades
+// streamed by binance
+func Serve() error {
+	b, err := newBase()
+	if err != nil {
+		return err
+	}
+	return serve(b, nil)
+}
+
+// Replay runs the service as Serve does, ingesting the trades recorded in the
+// file instead, until they are all replayed or SIGINT or SIGTERM
+// the replica leads alone, the replayed bars only reaching its subscribers
+// and the db, without alerts, webhooks or bus messages
+func Replay(
+	file string,
+	speed float64,
+) error {
+	if speed < 0 {
+		return fmt.Errorf("Failed to replay - speed must not be negative, got %v", speed)
+	}
+	// fails before starting anything if the file can't be read
+	if _, err := os.Stat(file); err != nil {
+		return fmt.Errorf("Failed to replay - %w", err)
+	}
+
+	b, err := newBase()
+	if err != nil {
+		return err
+	}
+	// joining the election would take the leadership over from the replicas
+	// ingesting from binance
+	if b.config.Replication.Enabled {
+		b.lgrInstance.Close()
+		return fmt.Errorf("Failed to replay - replication must be disabled, unset REPLICATION_ADVERTISEADDRESS")
+	}
+	if b.config.DB.ReadOnly {
+		b.lgrInstance.Close()
+		return fmt.Errorf("Failed to replay - the replayed bars can't be committed to a read-only db")
+	}
+	return serve(b, &replaySource{file: file, speed: speed})
+}
+
+func serve(
+	b *base,
+	_replay *replaySource,
+) error {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	// ========= Setup graceful system shutdown =========
+	quit := make(chan os.Signal, 1)
+	signal.Notify(quit, syscall.SIGINT, syscall.SIGTERM)
+
+	// ========= Start the app ========
+	var wg sync.WaitGroup
+	_app := startApp(ctx, &wg, b, _replay)
+
+	// ========= Start GRPC server =========
+	_grpc := StartGRPCServer(
+		ctx,
+		_app.LgrInstance,
+		&wg,
+		_app.ServerConfig,
+		_app.CertReloader,
+		_app.CandlestickHandler,
+		_app.AlertHandler,
+		_app.ReplicationHandler,
+		_app.AdminHandler,
+		_app.ExportHandler,
+		_app.HealthService,
+		_app.AuthService,
+		_app.RateLimiter,
+		_app.HealthHandler,
+		_app.Metrics,
+	)
+
+	// - Handle system shutdown
+	var replayErr error
+	select {
+	case <-quit:
+	case replayErr = <-_app.done:
+		if replayErr != nil {
+			log.Printf("Failed to replay the trades - %v", replayErr)
+		}
+	}
+	log.Println("Shutting down system...")
+
+	if err := Shutdown(_app, _grpc, cancel, &wg); err != nil {
+		return fmt.Errorf("Terminated the system with errors - %w", err)
+	}
+
+	log.Println("Gracefully terminated the system, exiting...")
+	return replayErr
+}
+
+// Migrate runs the migrations not applied yet
+func Migrate() error {
+	b, err := newBase()
+	if err != nil {
+		return err
+	}
+	defer b.lgrInstance.Close()
+
+	if b.config.DB.ReadOnly {
+		return fmt.Errorf("Failed to migrate - the db is read-only, migrate with the config of an ingesting instance")
+	}
+
+	_db, err := newDB(context.Background(), b, true)
+	if err != nil {
+		return err
+	}
+	defer _db.Close()
+
+	b.lgr.Info("Applied the migrations")
+	return nil
+}
+
+// Backfill stores the symbol's bars of the minutes within the range, from
+// binance's klines
+func Backfill(
+	symbol string,
+	from time.Time,
+	to time.Time,
+) error {
+	ctx := context.Background()
+
+	b, err := newBase()
+	if err != nil {
+		return err
+	}
+	defer b.lgrInstance.Close()
+
+	_db, err := newDB(ctx, b, false)
+	if err != nil {
+		return err
+	}
+	defer _db.Close()
+
+	_backfillService := backfill.NewBackfillService(
+		candlestickrepo.NewCandlestickRepository(_db),
+		binance.NewKlinesClient(b.config.Binance),
+		b.lgrInstance,
+	)
+
+	stored, err := _backfillService.Backfill(ctx, symbol, from, to)
+	if err != nil {
+		return err
+	}
+
+	b.lgr.Info(
+		"Backfilled the bars",
+		zap.String("symbol", symbol),
+		zap.Int("bars", stored),
+	)
+	return nil
+}
+
+// Export writes the requested bars to out
+func Export(
+	req export.Request,
+	out io.Writer,
+) error {
+	ctx := context.Background()
+
+	b, err := newBase()
+	if err != nil {
+		return err
+	}
+	defer b.lgrInstance.Close()
+
+	_db, err := newDB(ctx, b, false)
+	if err != nil {
+		return err
+	}
+	defer _db.Close()
+
+	// the limits of the exports served to the clients don't apply, the
+	// command running on its own
+	_exportService := export.NewExportService(
+		&export.ExportConfig{},
+		candlestickrepo.NewCandlestickRepository(_db),
+		exportFormats,
+		b.lgrInstance,
+	)
+
+	_, err = _exportService.Export(ctx, out, req)
+	return err
+}
@@ -0,0 +1,164 @@
+package app
+
+import (
+	"context"
+	"reflect"
+	"slices"
+	"strings"
+	"sync"
+
+	"github.com/ramasbeinaty/trading-chart-service/pkg/domain/ratelimit"
+	"github.com/ramasbeinaty/trading-chart-service/pkg/domain/subscription"
+	"github.com/ramasbeinaty/trading-chart-service/pkg/infra/config"
+	"github.com/ramasbeinaty/trading-chart-service/pkg/infra/logger"
+	"go.uber.org/zap"
+)
+
+// configReloader applies the changes of a reloaded config that are safe to
+// make live, i.e. the log level, the streamed symbols, the rate limits and the
+// checkpoint interval, the other changes only being logged as requiring a
+// restart
+type configReloader struct {
+	ctx                 context.Context
+	lgr                 *zap.Logger
+	lgrInstance         *logger.Logger
+	subscriptionService *subscription.SubscriptionService
+	leadership          *leadership
+	rateLimiter         *ratelimit.RateLimiter
+
+	mutex   sync.Mutex
+	current *config.Config
+}
+
+func newConfigReloader(
+	ctx context.Context,
+	lgr *zap.Logger,
+	lgrInstance *logger.Logger,
+	current *config.Config,
+	subscriptionService *subscription.SubscriptionService,
+	_leadership *leadership,
+	rateLimiter *ratelimit.RateLimiter,
+) *configReloader {
+	return &configReloader{
+		ctx:                 ctx,
+		lgr:                 lgr,
+		lgrInstance:         lgrInstance,
+		subscriptionService: subscriptionService,
+		leadership:          _leadership,
+		rateLimiter:         rateLimiter,
+		current:             current,
+	}
+}
+
+// apply makes the live changes of the reloaded config, the config in effect
+// keeping the values of the other ones until a restart
+func (r *configReloader) apply(
+	c *config.Config,
+) {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	if c.Log.Level != r.current.Log.Level {
+		if err := r.lgrInstance.SetLevel(c.Log.Level); err != nil {
+			r.lgr.Error("Failed to apply the log level", zap.Error(err))
+		} else {
+			r.current.Log = c.Log
+			r.lgr.Info("Applied the log level", zap.String("level", c.Log.Level))
+		}
+	}
+
+	if !slices.Equal(c.Binance.Symbols, r.current.Binance.Symbols) {
+		r.applySymbols(c.Binance.Symbols)
+	}
+
+	if !reflect.DeepEqual(c.RateLimit, r.current.RateLimit) {
+		r.rateLimiter.SetConfig(r.ctx, c.RateLimit)
+		r.current.RateLimit = c.RateLimit
+		r.lgr.Info("Applied the rate limits")
+	}
+
+	if c.Candlestick.CheckpointInterval != r.current.Candlestick.CheckpointInterval {
+		r.leadership.setCheckpointInterval(c.Candlestick.CheckpointInterval)
+		r.current.Candlestick = c.Candlestick
+		r.lgr.Info(
+			"Applied the checkpoint interval",
+			zap.Duration("interval", c.Candlestick.CheckpointInterval),
+		)
+	}
+
+	if sections := r.restartRequired(c); len(sections) != 0 {
+		r.lgr.Warn(
+			"Config changes require a restart to be applied",
+			zap.Strings("sections", sections),
+		)
+	}
+}
+
+// streams the added symbols, the removed ones no
+// longer receiving any trade, though their subscribers stay subscribed and
+// are logged for the operators to tell them
+func (r *configReloader) applySymbols(
+	symbols []string,
+) {
+	previous := r.current.Binance.Symbols
+
+	if err := r.leadership.setSymbols(symbols); err != nil {
+		// the stream is resubscribed once reconnected, with the new symbols
+		r.lgr.Error("Failed to resubscribe the binance stream", zap.Error(err))
+	}
+
+	for _, symbol := range symbols {
+		if slices.Contains(previous, symbol) {
+			continue
+		}
+		r.subscriptionService.TrackSymbol(r.ctx, symbol)
+	}
+	for _, symbol := range previous {
+		if slices.Contains(symbols, symbol) {
+			continue
+		}
+		if subscribers := r.subscriptionService.Recipients(strings.ToUpper(symbol)); len(subscribers) != 0 {
+			ids := make([]int64, 0, len(subscribers))
+			for _, sub := range subscribers {
+				ids = append(ids, sub.ID)
+			}
+			r.lgr.Warn(
+				"Removed a symbol with subscribers, they no longer receive its updates",
+				zap.String("symbol", symbol),
+				zap.Int64s("subscribers", ids),
+			)
+		}
+	}
+
+	binanceConfig := *r.current.Binance
+	binanceConfig.Symbols = symbols
+	r.current.Binance = &binanceConfig
+	r.lgr.Info("Applied the binance symbols", zap.Strings("symbols", symbols))
+}
+
+// the sections of the config that changed but are only read on startup
+func (r *configReloader) restartRequired(
+	c *config.Config,
+) []string {
+	sections := []string{}
+	changed := func(section string, current, reloaded any) {
+		if !reflect.DeepEqual(current, reloaded) {
+			sections = append(sections, section)
+		}
+	}
+
+	changed("env", r.current.Env, c.Env)
+	changed("server", r.current.Server, c.Server)
+	changed("binance.baseendpoint", r.current.Binance.BaseEndpoint, c.Binance.BaseEndpoint)
+	changed("db", r.current.DB, c.DB)
+	changed("snowflake", r.current.Snowflake, c.Snowflake)
+	changed("webhook", r.current.Webhook, c.Webhook)
+	changed("bus", r.current.Bus, c.Bus)
+	changed("nats", r.current.Nats, c.Nats)
+	changed("tracing", r.current.Tracing, c.Tracing)
+	changed("health", r.current.Health, c.Health)
+	changed("replication", r.current.Replication, c.Replication)
+	changed("auth", r.current.Auth, c.Auth)
+
+	return sections
+}
@@ -0,0 +1,154 @@
+package app
+
+import (
+	"context"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/ramasbeinaty/trading-chart-service/internal"
+	"github.com/ramasbeinaty/trading-chart-service/internal/testutil"
+	"github.com/ramasbeinaty/trading-chart-service/pkg/domain/base/metrics"
+	"github.com/ramasbeinaty/trading-chart-service/pkg/domain/candlestick"
+	"github.com/ramasbeinaty/trading-chart-service/pkg/domain/health"
+	"github.com/ramasbeinaty/trading-chart-service/pkg/domain/ratelimit"
+	"github.com/ramasbeinaty/trading-chart-service/pkg/domain/subscription"
+	"github.com/ramasbeinaty/trading-chart-service/pkg/infra/clients/binance"
+	"github.com/ramasbeinaty/trading-chart-service/pkg/infra/config"
+	"github.com/ramasbeinaty/trading-chart-service/pkg/infra/logger"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+	"go.uber.org/zap/zaptest/observer"
+)
+
+type nopSink struct {
+	done chan struct{}
+}
+
+func (s *nopSink) Send(*subscription.CandlestickEvent) error { return nil }
+func (s *nopSink) Close()                                    {}
+func (s *nopSink) Done() <-chan struct{}                     { return s.done }
+
+func newTestConfig() *config.Config {
+	return &config.Config{
+		Log:         &logger.LoggerConfig{Level: "info"},
+		Server:      &internal.ServerConfig{HttpPort: "8080"},
+		Binance:     &binance.BinanceConfig{BaseEndpoint: "stream.binance.com:9443", Symbols: []string{"btcusdt", "ethusdt"}},
+		Candlestick: &candlestick.CandlestickConfig{CheckpointInterval: 5 * time.Second},
+		Health:      &health.HealthConfig{MaxTradeAge: time.Minute},
+		RateLimit:   &ratelimit.RateLimitConfig{Default: ratelimit.Limits{UnaryRate: 20, UnaryBurst: 40}},
+	}
+}
+
+type testReloader struct {
+	*configReloader
+	logs                *observer.ObservedLogs
+	subscriptionService *subscription.SubscriptionService
+	rateLimiter         *ratelimit.RateLimiter
+}
+
+func newTestReloader(t *testing.T) *testReloader {
+	t.Helper()
+
+	lgrInstance, err := logger.NewLogger(&logger.LoggerConfig{Level: "info"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	core, logs := observer.New(zapcore.InfoLevel)
+
+	current := newTestConfig()
+	subscriptionService := subscription.NewSubscriptionService(testutil.NopLogger{}, metrics.NopMetrics{})
+	rateLimiter := ratelimit.NewRateLimiter(current.RateLimit, testutil.NopLogger{})
+	_leadership := newLeadership(zap.NewNop(), current.Binance, current.Candlestick, nil, nil, nil)
+
+	return &testReloader{
+		configReloader: newConfigReloader(
+			context.Background(),
+			zap.New(core),
+			lgrInstance,
+			current,
+			subscriptionService,
+			_leadership,
+			rateLimiter,
+		),
+		logs:                logs,
+		subscriptionService: subscriptionService,
+		rateLimiter:         rateLimiter,
+	}
+}
+
+func TestConfigReloaderAppliesTheLiveSections(t *testing.T) {
+	r := newTestReloader(t)
+
+	reloaded := newTestConfig()
+	reloaded.Log.Level = "debug"
+	reloaded.Binance.Symbols = []string{"btcusdt", "solusdt"}
+	reloaded.Candlestick.CheckpointInterval = time.Second
+	reloaded.RateLimit = &ratelimit.RateLimitConfig{Default: ratelimit.Limits{UnaryRate: 0.001, UnaryBurst: 1}}
+	r.apply(reloaded)
+
+	if !r.lgrInstance.Get(nil).Core().Enabled(zapcore.DebugLevel) {
+		t.Error("expected the debug logs to be enabled")
+	}
+	if !slices.Equal(r.leadership.symbols, []string{"btcusdt", "solusdt"}) {
+		t.Errorf("expected the next leaderships to stream the symbols, got %v", r.leadership.symbols)
+	}
+	if r.leadership.getCheckpointInterval() != time.Second {
+		t.Errorf("expected the checkpoint interval to be applied, got %s", r.leadership.getCheckpointInterval())
+	}
+	if err := r.rateLimiter.AllowUnary("desk-1"); err != nil {
+		t.Fatal(err)
+	}
+	if err := r.rateLimiter.AllowUnary("desk-1"); err == nil {
+		t.Error("expected the rate limits to be applied")
+	}
+
+	if r.current.Log.Level != "debug" || !slices.Equal(r.current.Binance.Symbols, reloaded.Binance.Symbols) || r.current.Candlestick.CheckpointInterval != time.Second {
+		t.Errorf("expected the config in effect to be updated, got %+v", r.current)
+	}
+	if r.logs.FilterMessage("Config changes require a restart to be applied").Len() != 0 {
+		t.Error("expected no restart to be required")
+	}
+}
+
+func TestConfigReloaderKeepsTheSectionsRequiringARestart(t *testing.T) {
+	r := newTestReloader(t)
+
+	reloaded := newTestConfig()
+	reloaded.Server.HttpPort = "9090"
+	reloaded.Log.Level = "verbose"
+	r.apply(reloaded)
+
+	if r.current.Server.HttpPort != "8080" {
+		t.Errorf("expected the server to be kept until a restart, got %s", r.current.Server.HttpPort)
+	}
+	if r.current.Log.Level != "info" {
+		t.Errorf("expected the invalid log level to be rejected, got %s", r.current.Log.Level)
+	}
+
+	logged := r.logs.FilterMessage("Config changes require a restart to be applied").All()
+	if len(logged) != 1 || !slices.Equal(logged[0].ContextMap()["sections"].([]any), []any{"server"}) {
+		t.Errorf("expected the server to require a restart, got %+v", logged)
+	}
+}
+
+func TestConfigReloaderLogsTheSubscribersOfTheRemovedSymbols(t *testing.T) {
+	r := newTestReloader(t)
+	ctx := context.Background()
+	r.subscriptionService.TrackSymbol(ctx, "ethusdt")
+	if err := r.subscriptionService.AddUpdateSubscriber(ctx, 7, []string{"ETHUSDT"}, &nopSink{done: make(chan struct{})}); err != nil {
+		t.Fatal(err)
+	}
+
+	reloaded := newTestConfig()
+	reloaded.Binance.Symbols = []string{"btcusdt"}
+	r.apply(reloaded)
+
+	logged := r.logs.FilterMessage("Removed a symbol with subscribers, they no longer receive its updates").All()
+	if len(logged) != 1 {
+		t.Fatalf("expected the subscribers of ethusdt to be logged, got %+v", r.logs.All())
+	}
+	if fields := logged[0].ContextMap(); fields["symbol"] != "ethusdt" || !slices.Equal(fields["subscribers"].([]any), []any{int64(7)}) {
+		t.Errorf("expected subscriber 7 of ethusdt, got %+v", fields)
+	}
+}
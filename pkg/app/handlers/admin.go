@@ -0,0 +1,111 @@
+package handlers
+
+import (
+	"context"
+	"errors"
+
+	"github.com/ramasbeinaty/trading-chart-service/pkg/domain/auth"
+	"github.com/ramasbeinaty/trading-chart-service/pkg/domain/candlestick"
+	"github.com/ramasbeinaty/trading-chart-service/pkg/domain/replication"
+	"github.com/ramasbeinaty/trading-chart-service/pkg/domain/subscription"
+	adminpb "github.com/ramasbeinaty/trading-chart-service/proto/admin/contracts"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// AdminHandler serves the operators, only the admins may call it once
+// authentication is enabled
+type AdminHandler struct {
+	adminpb.UnimplementedAdminServiceServer
+	candlestickService  *candlestick.CandlestickService
+	subscriptionService *subscription.SubscriptionService
+	replicationService  *replication.ReplicationService
+}
+
+var _ adminpb.AdminServiceServer = &AdminHandler{}
+
+func NewAdminHandler(
+	candlestickService *candlestick.CandlestickService,
+	subscriptionService *subscription.SubscriptionService,
+	replicationService *replication.ReplicationService,
+) *AdminHandler {
+	return &AdminHandler{
+		candlestickService:  candlestickService,
+		subscriptionService: subscriptionService,
+		replicationService:  replicationService,
+	}
+}
+
+func (h *AdminHandler) ListSubscribers(
+	ctx context.Context,
+	req *adminpb.ListSubscribersRequest,
+) (*adminpb.ListSubscribersResponse, error) {
+	if err := auth.AuthorizeAdmin(ctx); err != nil {
+		return nil, err
+	}
+
+	infos := h.subscriptionService.ListSubscribers(ctx)
+
+	subscribers := make([]*adminpb.Subscriber, 0, len(infos))
+	for _, info := range infos {
+		subscribers = append(subscribers, toSubscriberContract(info))
+	}
+
+	return &adminpb.ListSubscribersResponse{
+		Subscribers: subscribers,
+	}, nil
+}
+
+func (h *AdminHandler) DisconnectSubscriber(
+	ctx context.Context,
+	req *adminpb.DisconnectSubscriberRequest,
+) (*adminpb.DisconnectSubscriberResponse, error) {
+	if err := auth.AuthorizeAdmin(ctx); err != nil {
+		return nil, err
+	}
+
+	err := h.subscriptionService.DisconnectSubscriber(ctx, req.Id, req.Reason)
+	if errors.Is(err, subscription.ERR_SUBSCRIBER_NOT_FOUND) {
+		return nil, status.Error(codes.NotFound, err.Error())
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return &adminpb.DisconnectSubscriberResponse{}, nil
+}
+
+func (h *AdminHandler) GetInProgressBars(
+	ctx context.Context,
+	req *adminpb.GetInProgressBarsRequest,
+) (*adminpb.GetInProgressBarsResponse, error) {
+	if err := auth.AuthorizeAdmin(ctx); err != nil {
+		return nil, err
+	}
+
+	return &adminpb.GetInProgressBarsResponse{
+		Bars: toBarContracts(h.candlestickService.InProgressBars()),
+	}, nil
+}
+
+// only the leader commits, the other replicas follow its bars
+func (h *AdminHandler) CommitBars(
+	ctx context.Context,
+	req *adminpb.CommitBarsRequest,
+) (*adminpb.CommitBarsResponse, error) {
+	if err := auth.AuthorizeAdmin(ctx); err != nil {
+		return nil, err
+	}
+
+	if !h.replicationService.IsLeader() {
+		return nil, status.Error(codes.FailedPrecondition, "Not the leader, commit on the leader")
+	}
+
+	if err := h.candlestickService.CommitCompleteBars(ctx); err != nil {
+		return nil, err
+	}
+
+	return &adminpb.CommitBarsResponse{
+		InProgressBars: int32(len(h.candlestickService.InProgressBars())),
+	}, nil
+}
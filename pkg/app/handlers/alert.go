@@ -0,0 +1,339 @@
+package handlers
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"sync"
+
+	"github.com/ramasbeinaty/trading-chart-service/pkg/domain/alert"
+	"github.com/ramasbeinaty/trading-chart-service/pkg/domain/auth"
+	"github.com/ramasbeinaty/trading-chart-service/pkg/domain/indicator"
+	"github.com/ramasbeinaty/trading-chart-service/pkg/domain/replication"
+	"github.com/ramasbeinaty/trading-chart-service/pkg/domain/uids"
+	alertpb "github.com/ramasbeinaty/trading-chart-service/proto/alert/contracts"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+type AlertHandler struct {
+	alertpb.UnimplementedAlertServiceServer
+	alertService       *alert.AlertService
+	replicationService *replication.ReplicationService
+	uidService         *uids.UIDService
+	// read-only instances don't evaluate the alerts, so don't take changes
+	readOnly bool
+}
+
+var _ alertpb.AlertServiceServer = &AlertHandler{}
+
+func NewAlertHandler(
+	alertService *alert.AlertService,
+	replicationService *replication.ReplicationService,
+	uidService *uids.UIDService,
+	readOnly bool,
+) *AlertHandler {
+	return &AlertHandler{
+		alertService:       alertService,
+		replicationService: replicationService,
+		uidService:         uidService,
+		readOnly:           readOnly,
+	}
+}
+
+func (h *AlertHandler) CreateAlert(
+	ctx context.Context,
+	req *alertpb.CreateAlertRequest,
+) (*alertpb.Alert, error) {
+	if err := h.checkWritable(ctx); err != nil {
+		return nil, err
+	}
+	if err := auth.AuthorizeSymbol(ctx, req.Symbol); err != nil {
+		return nil, err
+	}
+
+	id, err := h.uidService.GenerateUID()
+	if err != nil {
+		return nil, fmt.Errorf("Failed to generate an id for alert")
+	}
+
+	a := toAlert(
+		id,
+		req.Symbol,
+		req.Timeframe,
+		req.Metric,
+		req.Condition,
+		req.Threshold,
+		req.Mode,
+		req.OnClose,
+	)
+
+	if err := h.alertService.CreateAlert(ctx, a); err != nil {
+		return nil, err
+	}
+
+	return toAlertContract(a), nil
+}
+
+func (h *AlertHandler) GetAlert(
+	ctx context.Context,
+	req *alertpb.GetAlertRequest,
+) (*alertpb.Alert, error) {
+	a, err := h.alertService.GetAlert(ctx, req.Id)
+	if err != nil {
+		return nil, err
+	}
+	if err := auth.AuthorizeSymbol(ctx, a.Symbol); err != nil {
+		return nil, err
+	}
+
+	return toAlertContract(a), nil
+}
+
+func (h *AlertHandler) ListAlerts(
+	ctx context.Context,
+	req *alertpb.ListAlertsRequest,
+) (*alertpb.ListAlertsResponse, error) {
+	if req.Symbol != "" {
+		if err := auth.AuthorizeSymbol(ctx, req.Symbol); err != nil {
+			return nil, err
+		}
+	}
+
+	alerts, err := h.alertService.ListAlerts(ctx, strings.ToUpper(req.Symbol))
+	if err != nil {
+		return nil, err
+	}
+
+	contracts := make([]*alertpb.Alert, 0, len(alerts))
+	for _, a := range alerts {
+		if auth.EntitledTo(ctx, a.Symbol) {
+			contracts = append(contracts, toAlertContract(a))
+		}
+	}
+
+	return &alertpb.ListAlertsResponse{
+		Alerts: contracts,
+	}, nil
+}
+
+func (h *AlertHandler) UpdateAlert(
+	ctx context.Context,
+	req *alertpb.UpdateAlertRequest,
+) (*alertpb.Alert, error) {
+	if err := h.checkWritable(ctx); err != nil {
+		return nil, err
+	}
+	if req.Id == 0 {
+		return nil, fmt.Errorf("Failed to validate request - a valid alert id must be provided")
+	}
+	if err := auth.AuthorizeSymbol(ctx, req.Symbol); err != nil {
+		return nil, err
+	}
+	if err := h.authorizeAlert(ctx, req.Id); err != nil {
+		return nil, err
+	}
+
+	updated, err := h.alertService.UpdateAlert(
+		ctx,
+		toAlert(
+			req.Id,
+			req.Symbol,
+			req.Timeframe,
+			req.Metric,
+			req.Condition,
+			req.Threshold,
+			req.Mode,
+			req.OnClose,
+		),
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	return toAlertContract(updated), nil
+}
+
+func (h *AlertHandler) DeleteAlert(
+	ctx context.Context,
+	req *alertpb.DeleteAlertRequest,
+) (*alertpb.DeleteAlertResponse, error) {
+	if err := h.checkWritable(ctx); err != nil {
+		return nil, err
+	}
+	if err := h.authorizeAlert(ctx, req.Id); err != nil {
+		return nil, err
+	}
+	if err := h.alertService.DeleteAlert(ctx, req.Id); err != nil {
+		return nil, err
+	}
+
+	return &alertpb.DeleteAlertResponse{
+		Message: fmt.Sprintf("Successfully deleted alert %d", req.Id),
+	}, nil
+}
+
+// only the leader serves it, the alerts firing on the leader
+func (h *AlertHandler) StreamAlerts(
+	req *alertpb.StreamAlertsRequest,
+	srv alertpb.AlertService_StreamAlertsServer,
+) error {
+	if err := h.checkLeader(srv.Context(), "stream the alerts"); err != nil {
+		return err
+	}
+	for _, symbol := range req.Symbols {
+		if err := auth.AuthorizeSymbol(srv.Context(), symbol); err != nil {
+			return err
+		}
+	}
+
+	id, err := h.uidService.GenerateUID()
+	if err != nil {
+		return fmt.Errorf("Failed to generate an id for listener")
+	}
+
+	sink := newAlertGrpcSink(srv)
+	defer sink.Close()
+
+	listener := &alert.Listener{
+		ID:       id,
+		AlertIDs: map[int64]bool{},
+		Symbols:  map[string]bool{},
+		Owner:    auth.PrincipalFromContext(srv.Context()),
+		Sink:     sink,
+	}
+	for _, alertId := range req.AlertIds {
+		listener.AlertIDs[alertId] = true
+	}
+	for _, symbol := range req.Symbols {
+		listener.Symbols[strings.ToUpper(symbol)] = true
+	}
+
+	h.alertService.AddListener(srv.Context(), listener)
+	defer h.alertService.RemoveListener(srv.Context(), id)
+
+	// block until the client disconnects or the listener is removed
+	<-sink.Done()
+	return srv.Context().Err()
+}
+
+// fails on read-only instances and the followers, the alerts being changed
+// on the leader for it to evaluate them
+func (h *AlertHandler) checkWritable(
+	ctx context.Context,
+) error {
+	if h.readOnly {
+		return status.Error(codes.FailedPrecondition, "Read-only instance, change the alerts on an ingesting instance")
+	}
+	return h.checkLeader(ctx, "change the alerts")
+}
+
+// fails unless the replica leads, telling the client the leader's address
+// if advertised
+func (h *AlertHandler) checkLeader(
+	ctx context.Context,
+	action string,
+) error {
+	if h.replicationService.IsLeader() {
+		return nil
+	}
+
+	address, err := h.replicationService.LeaderAddress(ctx)
+	if err != nil || address == "" {
+		return status.Errorf(codes.FailedPrecondition, "Not the leader, %s on the leader", action)
+	}
+	return status.Errorf(codes.FailedPrecondition, "Not the leader, %s on the leader at %s", action, address)
+}
+
+// fails unless the principal of the request is entitled to the alert's symbol
+func (h *AlertHandler) authorizeAlert(
+	ctx context.Context,
+	id int64,
+) error {
+	a, err := h.alertService.GetAlert(ctx, id)
+	if err != nil {
+		return err
+	}
+	return auth.AuthorizeSymbol(ctx, a.Symbol)
+}
+
+// alertGrpcSink streams a listener's fired alerts over its grpc server stream
+// sends are not allowed once closed, as the stream must not be used after
+// its handler returns
+// the alerts of symbols the principal of the stream isn't entitled to are
+// skipped
+type alertGrpcSink struct {
+	srv    alertpb.AlertService_StreamAlertsServer
+	ctx    context.Context
+	cancel context.CancelFunc
+	mutex  sync.Mutex
+	closed bool
+}
+
+var _ alert.Sink = &alertGrpcSink{}
+
+func newAlertGrpcSink(
+	srv alertpb.AlertService_StreamAlertsServer,
+) *alertGrpcSink {
+	ctx, cancel := context.WithCancel(srv.Context())
+	return &alertGrpcSink{
+		srv:    srv,
+		ctx:    ctx,
+		cancel: cancel,
+	}
+}
+
+func (s *alertGrpcSink) Send(fired *alert.Fired) error {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	if s.closed {
+		return fmt.Errorf("Failed to send fired alert - stream is closed")
+	}
+	if !auth.EntitledTo(s.ctx, fired.Alert.Symbol) {
+		return nil
+	}
+	return s.srv.Send(toAlertFiredContract(fired))
+}
+
+// waits for any send in progress
+func (s *alertGrpcSink) Close() {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	s.closed = true
+	s.cancel()
+}
+
+func (s *alertGrpcSink) Done() <-chan struct{} {
+	return s.ctx.Done()
+}
+
+// builds an alert from the fields shared by the create and update requests
+// the symbol is upper-cased as the bars' are, and the timeframe defaults to
+// 1 minute
+func toAlert(
+	id int64,
+	symbol string,
+	timeframe string,
+	metric alertpb.AlertMetric,
+	condition alertpb.AlertCondition,
+	threshold float64,
+	mode alertpb.AlertMode,
+	onClose bool,
+) *alert.Alert {
+	if timeframe == "" {
+		timeframe = string(indicator.TIMEFRAME_1M)
+	}
+
+	return &alert.Alert{
+		ID:        id,
+		Symbol:    strings.ToUpper(symbol),
+		Timeframe: indicator.Timeframe(timeframe),
+		Metric:    alertMetrics[metric],
+		Condition: alertConditions[condition],
+		Threshold: threshold,
+		Mode:      alertModes[mode],
+		OnClose:   onClose,
+	}
+}
@@ -0,0 +1,92 @@
+package handlers
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/ramasbeinaty/trading-chart-service/pkg/domain/replication"
+	alertpb "github.com/ramasbeinaty/trading-chart-service/proto/alert/contracts"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func TestReadOnlyInstancesRejectAlertChanges(t *testing.T) {
+	ctx := context.Background()
+	h := NewAlertHandler(nil, nil, nil, true)
+
+	_, err := h.CreateAlert(ctx, &alertpb.CreateAlertRequest{Symbol: "BTCUSDT"})
+	if status.Code(err) != codes.FailedPrecondition {
+		t.Errorf("expected creating an alert to fail with FailedPrecondition, got %v", err)
+	}
+	_, err = h.UpdateAlert(ctx, &alertpb.UpdateAlertRequest{Id: 1, Symbol: "BTCUSDT"})
+	if status.Code(err) != codes.FailedPrecondition {
+		t.Errorf("expected updating an alert to fail with FailedPrecondition, got %v", err)
+	}
+	_, err = h.DeleteAlert(ctx, &alertpb.DeleteAlertRequest{Id: 1})
+	if status.Code(err) != codes.FailedPrecondition {
+		t.Errorf("expected deleting an alert to fail with FailedPrecondition, got %v", err)
+	}
+}
+
+// leaderAddressRepository advertises a leader at a fixed address
+type leaderAddressRepository struct {
+	address string
+}
+
+func (r *leaderAddressRepository) SetLeaderAddress(context.Context, string) error {
+	return nil
+}
+
+func (r *leaderAddressRepository) GetLeaderAddress(context.Context) (string, error) {
+	return r.address, nil
+}
+
+func TestFollowersSendAlertChangesToTheLeader(t *testing.T) {
+	ctx := context.Background()
+	replicationService := replication.NewReplicationService(
+		&replication.ReplicationConfig{},
+		nil,
+		&leaderAddressRepository{address: "replica-1:50051"},
+		nil,
+		nil,
+	)
+	h := NewAlertHandler(nil, replicationService, nil, false)
+
+	_, err := h.CreateAlert(ctx, &alertpb.CreateAlertRequest{Symbol: "BTCUSDT"})
+	if status.Code(err) != codes.FailedPrecondition {
+		t.Errorf("expected creating an alert to fail with FailedPrecondition, got %v", err)
+	}
+	if !strings.Contains(status.Convert(err).Message(), "replica-1:50051") {
+		t.Errorf("expected the error to tell the leader's address, got %v", err)
+	}
+	_, err = h.UpdateAlert(ctx, &alertpb.UpdateAlertRequest{Id: 1, Symbol: "BTCUSDT"})
+	if status.Code(err) != codes.FailedPrecondition {
+		t.Errorf("expected updating an alert to fail with FailedPrecondition, got %v", err)
+	}
+	_, err = h.DeleteAlert(ctx, &alertpb.DeleteAlertRequest{Id: 1})
+	if status.Code(err) != codes.FailedPrecondition {
+		t.Errorf("expected deleting an alert to fail with FailedPrecondition, got %v", err)
+	}
+	err = h.StreamAlerts(&alertpb.StreamAlertsRequest{}, &fakeAlertStream{ctx: ctx})
+	if status.Code(err) != codes.FailedPrecondition {
+		t.Errorf("expected streaming the alerts to fail with FailedPrecondition, got %v", err)
+	}
+}
+
+// fakeAlertStream is a server stream of fired alerts without a transport
+type fakeAlertStream struct {
+	alertpb.AlertService_StreamAlertsServer
+	ctx context.Context
+}
+
+func (s *fakeAlertStream) Context() context.Context {
+	return s.ctx
+}
+
+func TestToAlertUpperCasesTheSymbol(t *testing.T) {
+	a := toAlert(1, "btcusdt", "", alertpb.AlertMetric(0), alertpb.AlertCondition(0), 100, alertpb.AlertMode(0), false)
+	if a.Symbol != "BTCUSDT" {
+		t.Errorf("expected the symbol upper-cased, got %s", a.Symbol)
+	}
+}
@@ -3,11 +3,21 @@ package handlers
 import (
 	"context"
 	"fmt"
+	"slices"
+	"sync"
+	"time"
 
+	"github.com/gorilla/websocket"
+	"github.com/ramasbeinaty/trading-chart-service/pkg/domain/auth"
 	"github.com/ramasbeinaty/trading-chart-service/pkg/domain/candlestick"
+	"github.com/ramasbeinaty/trading-chart-service/pkg/domain/indicator"
+	"github.com/ramasbeinaty/trading-chart-service/pkg/domain/ratelimit"
 	"github.com/ramasbeinaty/trading-chart-service/pkg/domain/subscription"
 	"github.com/ramasbeinaty/trading-chart-service/pkg/domain/uids"
 	candlestickpb "github.com/ramasbeinaty/trading-chart-service/proto/candlestick/contracts"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/peer"
+	"google.golang.org/grpc/status"
 )
 
 type CandlestickHandler struct {
@@ -15,6 +25,8 @@ type CandlestickHandler struct {
 	candlestickService  *candlestick.CandlestickService
 	subscriptionService *subscription.SubscriptionService
 	uidService          *uids.UIDService
+	rateLimiter         *ratelimit.RateLimiter
+	wsUpgrader          *websocket.Upgrader
 }
 
 var _ candlestickpb.CandlestickServiceServer = &CandlestickHandler{}
@@ -23,11 +35,17 @@ func NewCandlestickHandler(
 	candlestickService *candlestick.CandlestickService,
 	subscriptionService *subscription.SubscriptionService,
 	uidService *uids.UIDService,
+	rateLimiter *ratelimit.RateLimiter,
+	allowedOrigins []string,
 ) *CandlestickHandler {
 	return &CandlestickHandler{
 		candlestickService:  candlestickService,
 		subscriptionService: subscriptionService,
 		uidService:          uidService,
+		rateLimiter:         rateLimiter,
+		wsUpgrader: &websocket.Upgrader{
+			CheckOrigin: checkOrigin(allowedOrigins),
+		},
 	}
 }
 
@@ -35,35 +53,142 @@ func (h *CandlestickHandler) SubscribeToCandlesticks(
 	req *candlestickpb.SubscribeToStreamRequest,
 	srv candlestickpb.CandlestickService_SubscribeToCandlesticksServer,
 ) error {
+	sink := newGrpcSink(srv)
+	defer sink.Close()
+
+	ctx := subscription.WithPeer(srv.Context(), grpcPeer(srv.Context()))
+	id, err := h.subscribe(ctx, 0, req, sink)
+	if err != nil {
+		return err
+	}
+
+	// cleanup when client disconnects
+	defer h.subscriptionService.RemoveSubscriber(srv.Context(), id, nil)
+
+	// block until context is done, client disconnects or the subscriber is removed
+	<-sink.Done()
+	if sink.isGoingAway() {
+		return status.Error(codes.Unavailable, "Server is shutting down, resubscribe to resume")
+	}
+	if cause := sink.cancelCause(); cause != nil {
+		return status.Error(codes.Aborted, cause.Error())
+	}
+	return srv.Context().Err()
+}
+
+// validates the request and subscribes the stream to its symbols,
+// shared by every transport candlesticks are streamed on
+// a new subscriber id is generated unless an existing one is provided
+func (h *CandlestickHandler) subscribe(
+	ctx context.Context,
+	id int64,
+	req *candlestickpb.SubscribeToStreamRequest,
+	sink subscription.Sink,
+) (int64, error) {
 	if len(req.Symbols) == 0 {
-		return fmt.Errorf("Failed to validate request - symbol must not be empty")
+		return 0, fmt.Errorf("Failed to validate request - symbol must not be empty")
+	}
+
+	if req.SnapshotBars < 0 || req.SnapshotBars > candlestick.MAX_RECENT_BARS {
+		return 0, fmt.Errorf(
+			"Failed to validate request - snapshot bars must be between 0 and %d",
+			candlestick.MAX_RECENT_BARS,
+		)
+	}
+
+	patterns := []*subscription.Pattern{}
+	for _, symbol := range req.Symbols {
+		if !subscription.IsPattern(symbol) {
+			continue
+		}
+		p, err := subscription.ParsePattern(symbol)
+		if err != nil {
+			return 0, fmt.Errorf("Failed to validate request - %w", err)
+		}
+		patterns = append(patterns, p)
+	}
+
+	for symbol := range req.ResumeFrom {
+		matched := slices.ContainsFunc(patterns, func(p *subscription.Pattern) bool {
+			return p.Match(symbol)
+		})
+		if !matched && !slices.Contains(req.Symbols, symbol) {
+			return 0, fmt.Errorf(
+				"Failed to validate request - resumed symbol %s must be one of the subscribed symbols",
+				symbol,
+			)
+		}
+	}
+
+	specs, err := parseIndicators(req.Indicators)
+	if err != nil {
+		return 0, err
 	}
 
-	id, err := h.uidService.GenerateUID()
+	// the subscriber's symbols are counted along with the ones it already receives
+	count, err := h.subscriptionService.CountSymbols(ctx, id, req.Symbols)
 	if err != nil {
-		return fmt.Errorf("Failed to generate an id for subscriber")
+		return 0, err
+	}
+	if err := h.rateLimiter.CheckSymbols(ctx, count); err != nil {
+		return 0, err
 	}
 
-	err = h.subscriptionService.AddUpdateSubscriber(
-		srv.Context(),
+	if id == 0 {
+		var err error
+		id, err = h.uidService.GenerateUID()
+		if err != nil {
+			return 0, fmt.Errorf("Failed to generate an id for subscriber")
+		}
+	}
+
+	err = h.candlestickService.Subscribe(
+		ctx,
 		id,
 		req.Symbols,
-		srv,
+		candlestick.SubscribeOptions{
+			Snapshot:     req.Snapshot,
+			SnapshotBars: int(req.SnapshotBars),
+			ResumeFrom:   req.ResumeFrom,
+			Indicators:   specs,
+		},
+		sink,
 	)
 	if err != nil {
-		return fmt.Errorf(
-			"Failed to add symbols %v to subscriber %d",
+		return 0, fmt.Errorf(
+			"Failed to add symbols %v to subscriber %d - %w",
 			req.Symbols,
 			id,
+			err,
 		)
 	}
 
-	// cleanup when client disconnects
-	defer h.subscriptionService.RemoveSubscriber(srv.Context(), id, nil)
+	return id, nil
+}
 
-	// block until context is done or client disconnects
-	<-srv.Context().Done()
-	return srv.Context().Err()
+// parses the indicators requested with a subscription, ignoring duplicates
+func parseIndicators(
+	indicators []string,
+) ([]indicator.Spec, error) {
+	if len(indicators) > indicator.MAX_SUBSCRIPTION_INDICATORS {
+		return nil, fmt.Errorf(
+			"Failed to validate request - at most %d indicators can be requested",
+			indicator.MAX_SUBSCRIPTION_INDICATORS,
+		)
+	}
+
+	specs := []indicator.Spec{}
+	for _, raw := range indicators {
+		spec, err := indicator.ParseSpec(raw)
+		if err != nil {
+			return nil, fmt.Errorf("Failed to validate request - %w", err)
+		}
+		if !slices.ContainsFunc(specs, func(s indicator.Spec) bool { return s.Name() == spec.Name() }) {
+			specs = append(specs, spec)
+		}
+	}
+
+	return specs, nil
 }
 
 // if not symbols are provided, will unsubscribe from all symbols
@@ -91,3 +216,147 @@ func (h *CandlestickHandler) UnsubscribeFromCandlesticks(
 		Message: message,
 	}, nil
 }
+
+// if no range is provided, returns the values of the latest 100 bars of the
+// indicator's timeframe
+func (h *CandlestickHandler) GetIndicatorHistory(
+	ctx context.Context,
+	req *candlestickpb.GetIndicatorHistoryRequest,
+) (*candlestickpb.GetIndicatorHistoryResponse, error) {
+	if req.Symbol == "" {
+		return nil, fmt.Errorf("Failed to validate request - symbol must not be empty")
+	}
+
+	spec, err := indicator.ParseSpec(req.Indicator)
+	if err != nil {
+		return nil, fmt.Errorf("Failed to validate request - %w", err)
+	}
+	if err := auth.AuthorizeSymbol(ctx, req.Symbol); err != nil {
+		return nil, err
+	}
+
+	to := time.Now()
+	if req.To != nil {
+		to = req.To.AsTime()
+	}
+	from := to.Add(-100 * spec.Timeframe.Duration())
+	if req.From != nil {
+		from = req.From.AsTime()
+	}
+
+	if from.After(to) {
+		return nil, fmt.Errorf("Failed to validate request - from must not be after to")
+	}
+	if to.Sub(from) > indicator.MAX_HISTORY_VALUES*spec.Timeframe.Duration() {
+		return nil, fmt.Errorf(
+			"Failed to validate request - the range must span at most %d bars of %s",
+			indicator.MAX_HISTORY_VALUES,
+			spec.Timeframe,
+		)
+	}
+
+	values, err := h.candlestickService.GetIndicatorHistory(ctx, req.Symbol, spec, from, to)
+	if err != nil {
+		return nil, fmt.Errorf("Failed to get history of %s - %w", spec.Name(), err)
+	}
+
+	return &candlestickpb.GetIndicatorHistoryResponse{
+		Values: toIndicatorValueContracts(values),
+	}, nil
+}
+
+// grpcSink streams a subscriber's events over its grpc server stream
+// sends are not allowed once closed, as the stream must not be used after
+// its handler returns
+type grpcSink struct {
+	srv       candlestickpb.CandlestickService_SubscribeToCandlesticksServer
+	ctx       context.Context
+	cancel    context.CancelFunc
+	mutex     sync.Mutex
+	closed    bool
+	goingAway bool
+	cause     error
+}
+
+var (
+	_ subscription.GoingAwaySink  = &grpcSink{}
+	_ subscription.CancelableSink = &grpcSink{}
+)
+
+func newGrpcSink(
+	srv candlestickpb.CandlestickService_SubscribeToCandlesticksServer,
+) *grpcSink {
+	ctx, cancel := context.WithCancel(srv.Context())
+	return &grpcSink{
+		srv:    srv,
+		ctx:    ctx,
+		cancel: cancel,
+	}
+}
+
+func (s *grpcSink) Send(event *subscription.CandlestickEvent) error {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	if s.closed {
+		return fmt.Errorf("Failed to send candlestick - stream is closed")
+	}
+	return s.srv.Send(toCandlestickContract(event))
+}
+
+// waits for any send in progress
+func (s *grpcSink) Close() {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	s.closed = true
+	s.cancel()
+}
+
+func (s *grpcSink) Done() <-chan struct{} {
+	return s.ctx.Done()
+}
+
+// the stream ends with an Unavailable status
+func (s *grpcSink) GoAway() {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	s.goingAway = true
+	s.closed = true
+	s.cancel()
+}
+
+func (s *grpcSink) isGoingAway() bool {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	return s.goingAway
+}
+
+// the stream ends with an Aborted status carrying the cause
+func (s *grpcSink) Cancel(cause error) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	s.cause = cause
+	s.closed = true
+	s.cancel()
+}
+
+// the cause the stream was cancelled with, nil unless cancelled
+func (s *grpcSink) cancelCause() error {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	return s.cause
+}
+
+// the remote address of the grpc call, empty if unknown
+func grpcPeer(
+	ctx context.Context,
+) string {
+	p, ok := peer.FromContext(ctx)
+	if !ok || p.Addr == nil {
+		return ""
+	}
+	return p.Addr.String()
+}
@@ -0,0 +1,199 @@
+package handlers
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/grpc-ecosystem/grpc-gateway/v2/runtime"
+	"github.com/grpc-ecosystem/grpc-gateway/v2/utilities"
+	"github.com/ramasbeinaty/trading-chart-service/pkg/domain/auth"
+	"github.com/ramasbeinaty/trading-chart-service/pkg/domain/ratelimit"
+	"github.com/ramasbeinaty/trading-chart-service/pkg/domain/subscription"
+	candlestickpb "github.com/ramasbeinaty/trading-chart-service/proto/candlestick/contracts"
+	"google.golang.org/protobuf/encoding/protojson"
+)
+
+const sseKeepAliveInterval = 15 * time.Second
+
+// StreamCandlesticksSSE serves candlestick subscriptions as Server-Sent Events
+// the request fields are bound from the query string the same way as the gateway,
+// e.g. /api/v1/candlestick/stream?symbols=BTCUSDT&snapshot=true
+func (h *CandlestickHandler) StreamCandlesticksSSE(
+	w http.ResponseWriter,
+	r *http.Request,
+) {
+	flusher, ok := w.(http.Flusher)
+	if !ok {
+		http.Error(w, "Streaming is not supported", http.StatusInternalServerError)
+		return
+	}
+
+	req := &candlestickpb.SubscribeToStreamRequest{}
+	err := runtime.PopulateQueryParameters(
+		req,
+		r.URL.Query(),
+		utilities.NewDoubleArray(nil),
+	)
+	if err != nil {
+		http.Error(
+			w,
+			fmt.Sprintf("Failed to parse request - %s", err.Error()),
+			http.StatusBadRequest,
+		)
+		return
+	}
+
+	sink := newSSESink(r.Context(), w, flusher)
+	defer sink.Close()
+
+	ctx := subscription.WithPeer(r.Context(), r.RemoteAddr)
+	id, err := h.subscribe(ctx, 0, req, sink)
+	if err != nil {
+		// the stream only starts once there is something to send
+		if !sink.hasStarted() {
+			code := http.StatusBadRequest
+			switch {
+			case errors.Is(err, auth.ERR_PERMISSION_DENIED):
+				code = http.StatusForbidden
+			case errors.Is(err, ratelimit.ERR_LIMIT_EXCEEDED):
+				code = http.StatusTooManyRequests
+			}
+			http.Error(w, err.Error(), code)
+		}
+		return
+	}
+
+	// cleanup when client disconnects
+	defer h.subscriptionService.RemoveSubscriber(r.Context(), id, nil)
+
+	// block until client disconnects or the subscriber is removed
+	<-sink.Done()
+}
+
+// sseSink streams a subscriber's events over an http response,
+// writing every candlestick as an event identified by its symbol and sequence
+type sseSink struct {
+	ctx     context.Context
+	cancel  context.CancelFunc
+	w       http.ResponseWriter
+	flusher http.Flusher
+	mutex   sync.Mutex
+	started bool
+	closed  bool
+}
+
+var (
+	_ subscription.GoingAwaySink  = &sseSink{}
+	_ subscription.CancelableSink = &sseSink{}
+)
+
+func newSSESink(
+	ctx context.Context,
+	w http.ResponseWriter,
+	flusher http.Flusher,
+) *sseSink {
+	ctx, cancel := context.WithCancel(ctx)
+	s := &sseSink{
+		ctx:     ctx,
+		cancel:  cancel,
+		w:       w,
+		flusher: flusher,
+	}
+
+	// keep idle connections from being closed by proxies
+	go func() {
+		ticker := time.NewTicker(sseKeepAliveInterval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ticker.C:
+				s.write(": keepalive\n\n")
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+
+	return s
+}
+
+func (s *sseSink) Send(event *subscription.CandlestickEvent) error {
+	data, err := protojson.MarshalOptions{EmitUnpopulated: true}.Marshal(
+		toCandlestickContract(event),
+	)
+	if err != nil {
+		return fmt.Errorf("Failed to marshal candlestick - %w", err)
+	}
+
+	return s.write(
+		fmt.Sprintf(
+			"id: %s:%d\nevent: candlestick\ndata: %s\n\n",
+			event.Symbol,
+			event.Sequence,
+			data,
+		),
+	)
+}
+
+// stops any further writes to the response
+func (s *sseSink) Close() {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	s.closed = true
+	s.cancel()
+}
+
+func (s *sseSink) Done() <-chan struct{} {
+	return s.ctx.Done()
+}
+
+// sends a goaway event before closing the stream
+func (s *sseSink) GoAway() {
+	s.write("event: goaway\ndata: server is shutting down, reconnect to resume\n\n")
+	s.Close()
+}
+
+// sends a disconnected event with the cause before closing the stream
+func (s *sseSink) Cancel(cause error) {
+	s.write(fmt.Sprintf("event: disconnected\ndata: %s\n\n", cause.Error()))
+	s.Close()
+}
+
+// writes the event, sending the response headers first if needed
+func (s *sseSink) write(event string) error {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	if s.closed {
+		return fmt.Errorf("Failed to write event - stream is closed")
+	}
+	if err := s.ctx.Err(); err != nil {
+		return err
+	}
+
+	if !s.started {
+		s.w.Header().Set("Content-Type", "text/event-stream")
+		s.w.Header().Set("Cache-Control", "no-cache")
+		s.w.Header().Set("Connection", "keep-alive")
+		s.w.WriteHeader(http.StatusOK)
+		s.started = true
+	}
+
+	if _, err := fmt.Fprint(s.w, event); err != nil {
+		return fmt.Errorf("Failed to write event - %w", err)
+	}
+	s.flusher.Flush()
+
+	return nil
+}
+
+func (s *sseSink) hasStarted() bool {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	return s.started
+}
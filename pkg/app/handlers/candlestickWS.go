@@ -0,0 +1,365 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/gorilla/websocket"
+	"github.com/ramasbeinaty/trading-chart-service/pkg/domain/ratelimit"
+	"github.com/ramasbeinaty/trading-chart-service/pkg/domain/subscription"
+	candlestickpb "github.com/ramasbeinaty/trading-chart-service/proto/candlestick/contracts"
+	"google.golang.org/protobuf/encoding/protojson"
+)
+
+const (
+	wsWriteWait  = 10 * time.Second
+	wsPongWait   = 60 * time.Second
+	wsPingPeriod = wsPongWait * 9 / 10
+	// a close frame's payload is limited to 125 bytes, 2 of them for the code
+	wsMaxCloseReason = 123
+)
+
+const (
+	WS_ACTION_SUBSCRIBE   = "subscribe"
+	WS_ACTION_UNSUBSCRIBE = "unsubscribe"
+
+	WS_MESSAGE_SUBSCRIBED   = "subscribed"
+	WS_MESSAGE_UNSUBSCRIBED = "unsubscribed"
+	WS_MESSAGE_CANDLESTICK  = "candlestick"
+	WS_MESSAGE_ERROR        = "error"
+)
+
+// lets browsers open the stream from the server's own origin or an allowed
+// one, "*" allowing any
+// clients sending no origin, i.e. not browsers, aren't checked
+func checkOrigin(
+	allowedOrigins []string,
+) func(r *http.Request) bool {
+	return func(r *http.Request) bool {
+		origin := r.Header.Get("Origin")
+		if origin == "" {
+			return true
+		}
+		if u, err := url.Parse(origin); err == nil && strings.EqualFold(u.Host, r.Host) {
+			return true
+		}
+		for _, allowed := range allowedOrigins {
+			if allowed == "*" || strings.EqualFold(strings.TrimSuffix(allowed, "/"), origin) {
+				return true
+			}
+		}
+		return false
+	}
+}
+
+// message sent by the client
+// e.g. {"action": "subscribe", "symbols": ["BTCUSDT"], "snapshot": true}
+type wsRequest struct {
+	Action       string            `json:"action"`
+	Symbols      []string          `json:"symbols"`
+	Snapshot     bool              `json:"snapshot"`
+	SnapshotBars int32             `json:"snapshot_bars"`
+	ResumeFrom   map[string]uint64 `json:"resume_from"`
+	Indicators   []string          `json:"indicators"`
+}
+
+// message sent to the client
+type wsResponse struct {
+	Type         string          `json:"type"`
+	SubscriberId int64           `json:"subscriber_id,omitempty"`
+	Symbols      []string        `json:"symbols,omitempty"`
+	Data         json.RawMessage `json:"data,omitempty"`
+	Message      string          `json:"message,omitempty"`
+}
+
+// StreamCandlesticksWS serves candlestick subscriptions over a websocket
+// the client subscribes to and unsubscribes from symbols with json messages,
+// and receives every candlestick as a json frame
+func (h *CandlestickHandler) StreamCandlesticksWS(
+	w http.ResponseWriter,
+	r *http.Request,
+) {
+	c, err := h.wsUpgrader.Upgrade(w, r, nil)
+	if err != nil {
+		// the upgrader already replied to the client
+		return
+	}
+
+	ctx, cancel := context.WithCancel(
+		subscription.WithPeer(r.Context(), r.RemoteAddr),
+	)
+	defer cancel()
+
+	conn := newWSConn(ctx, c)
+	defer conn.close()
+
+	// the connection's subscriber, receiving events on the current sink
+	var (
+		id   int64
+		sink *wsSink
+	)
+
+	// cleanup when client disconnects
+	defer func() {
+		if sink != nil {
+			h.subscriptionService.RemoveSubscriber(ctx, id, nil)
+		}
+	}()
+
+	go conn.keepAlive(cancel)
+
+	c.SetReadDeadline(time.Now().Add(wsPongWait))
+	c.SetPongHandler(func(string) error {
+		return c.SetReadDeadline(time.Now().Add(wsPongWait))
+	})
+
+	for {
+		var req wsRequest
+		if err := c.ReadJSON(&req); err != nil {
+			var (
+				syntaxErr *json.SyntaxError
+				typeErr   *json.UnmarshalTypeError
+			)
+			if errors.As(err, &syntaxErr) || errors.As(err, &typeErr) {
+				conn.sendError(fmt.Errorf("Failed to parse request - %w", err))
+				continue
+			}
+			// the client disconnected or stopped answering pings
+			return
+		}
+
+		// every action counts as a unary call of the client
+		if client := ratelimit.ClientFromContext(ctx); client != "" {
+			if err := h.rateLimiter.AllowUnary(client); err != nil {
+				conn.sendError(err)
+				continue
+			}
+		}
+
+		switch req.Action {
+		case WS_ACTION_SUBSCRIBE:
+			// the previous sink is closed once unsubscribed from all symbols
+			if sink == nil || sink.isClosed() {
+				sink = newWSSink(conn)
+			}
+
+			subscriberId, err := h.subscribe(
+				ctx,
+				id,
+				&candlestickpb.SubscribeToStreamRequest{
+					Symbols:      req.Symbols,
+					Snapshot:     req.Snapshot,
+					SnapshotBars: req.SnapshotBars,
+					ResumeFrom:   req.ResumeFrom,
+					Indicators:   req.Indicators,
+				},
+				sink,
+			)
+			if err != nil {
+				conn.sendError(err)
+				continue
+			}
+			id = subscriberId
+
+			conn.write(wsResponse{
+				Type:         WS_MESSAGE_SUBSCRIBED,
+				SubscriberId: id,
+				Symbols:      req.Symbols,
+			})
+		case WS_ACTION_UNSUBSCRIBE:
+			if sink != nil {
+				// if no symbols are provided, unsubscribes from all symbols
+				err := h.subscriptionService.RemoveSubscriber(ctx, id, req.Symbols)
+				if err != nil {
+					conn.sendError(fmt.Errorf("Unsubscribing failed - %w", err))
+					continue
+				}
+			}
+
+			conn.write(wsResponse{
+				Type:         WS_MESSAGE_UNSUBSCRIBED,
+				SubscriberId: id,
+				Symbols:      req.Symbols,
+			})
+		default:
+			conn.sendError(fmt.Errorf("Failed to validate request - unknown action %q", req.Action))
+		}
+	}
+}
+
+// wsConn serializes the writes to a websocket connection,
+// as broadcasts, pings and replies come from different goroutines
+type wsConn struct {
+	ctx   context.Context
+	conn  *websocket.Conn
+	mutex sync.Mutex
+}
+
+func newWSConn(
+	ctx context.Context,
+	conn *websocket.Conn,
+) *wsConn {
+	return &wsConn{
+		ctx:  ctx,
+		conn: conn,
+	}
+}
+
+func (c *wsConn) sendError(err error) error {
+	return c.write(wsResponse{
+		Type:    WS_MESSAGE_ERROR,
+		Message: err.Error(),
+	})
+}
+
+func (c *wsConn) write(msg wsResponse) error {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	if err := c.ctx.Err(); err != nil {
+		return err
+	}
+
+	c.conn.SetWriteDeadline(time.Now().Add(wsWriteWait))
+	if err := c.conn.WriteJSON(msg); err != nil {
+		return fmt.Errorf("Failed to write message - %w", err)
+	}
+
+	return nil
+}
+
+// pings the client until the connection is done, cancelling it if a ping fails
+// closes the connection once done, so a pending read returns
+func (c *wsConn) keepAlive(cancel context.CancelFunc) {
+	ticker := time.NewTicker(wsPingPeriod)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ticker.C:
+			c.mutex.Lock()
+			err := c.conn.WriteControl(
+				websocket.PingMessage,
+				nil,
+				time.Now().Add(wsWriteWait),
+			)
+			c.mutex.Unlock()
+			if err != nil {
+				cancel()
+				return
+			}
+		case <-c.ctx.Done():
+			c.close()
+			return
+		}
+	}
+}
+
+func (c *wsConn) close() {
+	c.closeWith(websocket.CloseNormalClosure, "")
+}
+
+func (c *wsConn) closeWith(code int, reason string) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	c.conn.WriteControl(
+		websocket.CloseMessage,
+		websocket.FormatCloseMessage(code, reason),
+		time.Now().Add(wsWriteWait),
+	)
+	c.conn.Close()
+}
+
+// wsSink streams a subscriber's events over a websocket connection
+// closing it ends the subscription, the connection stays open for new ones
+type wsSink struct {
+	conn *wsConn
+	done chan struct{}
+	once sync.Once
+}
+
+var (
+	_ subscription.GoingAwaySink  = &wsSink{}
+	_ subscription.CancelableSink = &wsSink{}
+)
+
+func newWSSink(conn *wsConn) *wsSink {
+	s := &wsSink{
+		conn: conn,
+		done: make(chan struct{}),
+	}
+
+	// the subscription ends with the connection
+	go func() {
+		select {
+		case <-conn.ctx.Done():
+			s.Close()
+		case <-s.done:
+		}
+	}()
+
+	return s
+}
+
+func (s *wsSink) Send(event *subscription.CandlestickEvent) error {
+	if s.isClosed() {
+		return fmt.Errorf("Failed to send candlestick - subscription is closed")
+	}
+
+	data, err := protojson.MarshalOptions{EmitUnpopulated: true}.Marshal(
+		toCandlestickContract(event),
+	)
+	if err != nil {
+		return fmt.Errorf("Failed to marshal candlestick - %w", err)
+	}
+
+	return s.conn.write(wsResponse{
+		Type: WS_MESSAGE_CANDLESTICK,
+		Data: data,
+	})
+}
+
+func (s *wsSink) Close() {
+	s.once.Do(func() {
+		close(s.done)
+	})
+}
+
+func (s *wsSink) Done() <-chan struct{} {
+	return s.done
+}
+
+// closes the connection with a going away close frame,
+// ending the connection's other subscriptions too
+func (s *wsSink) GoAway() {
+	s.conn.closeWith(websocket.CloseGoingAway, "server is shutting down")
+	s.Close()
+}
+
+// closes the connection with a policy violation close frame carrying the
+// cause, as the connection's subscriber is gone
+func (s *wsSink) Cancel(cause error) {
+	reason := cause.Error()
+	if len(reason) > wsMaxCloseReason {
+		reason = strings.ToValidUTF8(reason[:wsMaxCloseReason], "")
+	}
+	s.conn.closeWith(websocket.ClosePolicyViolation, reason)
+	s.Close()
+}
+
+func (s *wsSink) isClosed() bool {
+	select {
+	case <-s.done:
+		return true
+	default:
+		return false
+	}
+}
@@ -0,0 +1,34 @@
+package handlers
+
+import (
+	"net/http/httptest"
+	"testing"
+)
+
+func TestCheckOrigin(t *testing.T) {
+	cases := []struct {
+		name    string
+		allowed []string
+		origin  string
+		ok      bool
+	}{
+		{name: "no origin", ok: true},
+		{name: "same origin", origin: "http://localhost:8080", ok: true},
+		{name: "cross origin", origin: "https://evil.example.com"},
+		{name: "allowed origin", allowed: []string{"https://trading.example.com/"}, origin: "https://Trading.example.com", ok: true},
+		{name: "other scheme", allowed: []string{"https://trading.example.com"}, origin: "http://trading.example.com"},
+		{name: "any origin", allowed: []string{"*"}, origin: "https://evil.example.com", ok: true},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			r := httptest.NewRequest("GET", "http://localhost:8080/api/v1/candlestick/ws", nil)
+			if c.origin != "" {
+				r.Header.Set("Origin", c.origin)
+			}
+			if ok := checkOrigin(c.allowed)(r); ok != c.ok {
+				t.Errorf("expected allowed %v, got %v", c.ok, ok)
+			}
+		})
+	}
+}
@@ -0,0 +1,160 @@
+package handlers
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"time"
+
+	"github.com/ramasbeinaty/trading-chart-service/pkg/domain/auth"
+	"github.com/ramasbeinaty/trading-chart-service/pkg/domain/export"
+	"github.com/ramasbeinaty/trading-chart-service/pkg/domain/indicator"
+	exportpb "github.com/ramasbeinaty/trading-chart-service/proto/export/contracts"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// the bytes of the file sent per message, well below the max message size
+const exportChunkSize = 64 * 1024
+
+type ExportHandler struct {
+	exportpb.UnimplementedExportServiceServer
+	exportService *export.ExportService
+}
+
+var _ exportpb.ExportServiceServer = &ExportHandler{}
+
+func NewExportHandler(
+	exportService *export.ExportService,
+) *ExportHandler {
+	return &ExportHandler{
+		exportService: exportService,
+	}
+}
+
+func (h *ExportHandler) ExportCandlesticks(
+	req *exportpb.ExportCandlesticksRequest,
+	srv exportpb.ExportService_ExportCandlesticksServer,
+) error {
+	if req.Symbol == "" {
+		return status.Error(codes.InvalidArgument, "Failed to validate request - symbol must not be empty")
+	}
+	if err := auth.AuthorizeSymbol(srv.Context(), req.Symbol); err != nil {
+		return err
+	}
+
+	w := newChunkWriter(func(chunk []byte) error {
+		return srv.Send(&exportpb.ExportCandlesticksResponse{Chunk: chunk})
+	})
+
+	_, err := h.exportService.Export(srv.Context(), w, toExportRequest(req))
+	if err != nil {
+		return toExportStatus(srv.Context(), err)
+	}
+
+	if err := w.Flush(); err != nil {
+		return toExportStatus(srv.Context(), err)
+	}
+	return nil
+}
+
+// the status of a failed export, the other failures than the client's being
+// logged by the export service rather than returned
+func toExportStatus(
+	ctx context.Context,
+	err error,
+) error {
+	switch {
+	case errors.Is(err, export.ERR_INVALID_REQUEST):
+		return status.Error(codes.InvalidArgument, err.Error())
+	case errors.Is(err, export.ERR_TOO_MANY_EXPORTS):
+		return status.Error(codes.ResourceExhausted, err.Error())
+	case ctx.Err() != nil:
+		return status.FromContextError(ctx.Err()).Err()
+	default:
+		return status.Error(codes.Internal, "Failed to export the bars")
+	}
+}
+
+// the request of the export, defaulting to the 1 minute bars of the longest
+// range allowed until now as csv
+func toExportRequest(
+	req *exportpb.ExportCandlesticksRequest,
+) export.Request {
+	timeframe := indicator.TIMEFRAME_1M
+	if req.Timeframe != "" {
+		timeframe = indicator.Timeframe(req.Timeframe)
+	}
+
+	format := export.FORMAT_CSV
+	if req.Format != "" {
+		format = export.Format(strings.ToLower(req.Format))
+	}
+
+	columns := make([]export.Column, 0, len(req.Columns))
+	for _, column := range req.Columns {
+		columns = append(columns, export.Column(strings.ToLower(column)))
+	}
+
+	var from time.Time
+	if req.From != nil {
+		from = req.From.AsTime()
+	}
+	to := time.Now()
+	if req.To != nil {
+		to = req.To.AsTime()
+	}
+
+	return export.Request{
+		Symbol:    req.Symbol,
+		Timeframe: timeframe,
+		Format:    format,
+		Columns:   columns,
+		From:      from,
+		To:        to,
+	}
+}
+
+// chunkWriter sends what is written in chunks of exportChunkSize bytes, the
+// last one once flushed
+type chunkWriter struct {
+	buf  []byte
+	send func(chunk []byte) error
+}
+
+func newChunkWriter(
+	send func(chunk []byte) error,
+) *chunkWriter {
+	return &chunkWriter{
+		buf:  make([]byte, 0, exportChunkSize),
+		send: send,
+	}
+}
+
+func (c *chunkWriter) Write(p []byte) (int, error) {
+	written := 0
+	for len(p) > 0 {
+		n := min(len(p), cap(c.buf)-len(c.buf))
+		c.buf = append(c.buf, p[:n]...)
+		p = p[n:]
+		written += n
+
+		if len(c.buf) == cap(c.buf) {
+			if err := c.Flush(); err != nil {
+				return written, err
+			}
+		}
+	}
+	return written, nil
+}
+
+// sends the bytes buffered, if any
+func (c *chunkWriter) Flush() error {
+	if len(c.buf) == 0 {
+		return nil
+	}
+
+	err := c.send(c.buf)
+	c.buf = c.buf[:0]
+	return err
+}
@@ -0,0 +1,109 @@
+package handlers
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/grpc-ecosystem/grpc-gateway/v2/runtime"
+	"github.com/grpc-ecosystem/grpc-gateway/v2/utilities"
+	"github.com/ramasbeinaty/trading-chart-service/pkg/domain/auth"
+	"github.com/ramasbeinaty/trading-chart-service/pkg/domain/export"
+	exportpb "github.com/ramasbeinaty/trading-chart-service/proto/export/contracts"
+)
+
+var exportContentTypes = map[export.Format]string{
+	export.FORMAT_CSV:     "text/csv",
+	export.FORMAT_PARQUET: "application/vnd.apache.parquet",
+	export.FORMAT_ARROW:   "application/vnd.apache.arrow.file",
+}
+
+// DownloadCandlesticks serves the export of the bars as a file download
+// the request fields are bound from the query string the same way as the
+// gateway, e.g.
+// /api/v1/candlestick/export?symbol=BTCUSDT&timeframe=1h&format=parquet&columns=timestamp&columns=close&from=2024-01-01T00:00:00Z
+func (h *ExportHandler) DownloadCandlesticks(
+	w http.ResponseWriter,
+	r *http.Request,
+) {
+	req := &exportpb.ExportCandlesticksRequest{}
+	err := runtime.PopulateQueryParameters(
+		req,
+		r.URL.Query(),
+		utilities.NewDoubleArray(nil),
+	)
+	if err != nil {
+		http.Error(
+			w,
+			fmt.Sprintf("Failed to parse request - %s", err.Error()),
+			http.StatusBadRequest,
+		)
+		return
+	}
+
+	if req.Symbol == "" {
+		http.Error(w, "Failed to validate request - symbol must not be empty", http.StatusBadRequest)
+		return
+	}
+	if err := auth.AuthorizeSymbol(r.Context(), req.Symbol); err != nil {
+		http.Error(w, err.Error(), http.StatusForbidden)
+		return
+	}
+
+	exportReq := toExportRequest(req)
+	download := &downloadWriter{
+		w: w,
+		filename: fmt.Sprintf(
+			"%s-%s.%s",
+			strings.ToUpper(exportReq.Symbol),
+			exportReq.Timeframe,
+			exportReq.Format,
+		),
+		contentType: exportContentTypes[exportReq.Format],
+	}
+
+	_, err = h.exportService.Export(r.Context(), download, exportReq)
+	if err == nil {
+		return
+	}
+
+	// the file only starts once there is something to write
+	if !download.started {
+		switch {
+		case errors.Is(err, export.ERR_INVALID_REQUEST):
+			http.Error(w, err.Error(), http.StatusBadRequest)
+		case errors.Is(err, export.ERR_TOO_MANY_EXPORTS):
+			http.Error(w, err.Error(), http.StatusTooManyRequests)
+		default:
+			http.Error(w, "Failed to export the bars", http.StatusInternalServerError)
+		}
+		return
+	}
+	// the response is cut short rather than ending as a truncated file that
+	// looks complete
+	panic(http.ErrAbortHandler)
+}
+
+// downloadWriter sends the headers of the file download before its first
+// bytes
+type downloadWriter struct {
+	w           http.ResponseWriter
+	filename    string
+	contentType string
+	started     bool
+}
+
+func (d *downloadWriter) Write(p []byte) (int, error) {
+	if !d.started {
+		d.w.Header().Set("Content-Type", d.contentType)
+		d.w.Header().Set(
+			"Content-Disposition",
+			fmt.Sprintf("attachment; filename=%q", d.filename),
+		)
+		d.w.WriteHeader(http.StatusOK)
+		d.started = true
+	}
+
+	return d.w.Write(p)
+}
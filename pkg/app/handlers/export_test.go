@@ -0,0 +1,301 @@
+package handlers
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/ramasbeinaty/trading-chart-service/internal/testutil"
+	"github.com/ramasbeinaty/trading-chart-service/pkg/domain/auth"
+	"github.com/ramasbeinaty/trading-chart-service/pkg/domain/candlestick"
+	"github.com/ramasbeinaty/trading-chart-service/pkg/domain/export"
+	"github.com/ramasbeinaty/trading-chart-service/pkg/infra/exporters"
+	exportpb "github.com/ramasbeinaty/trading-chart-service/proto/export/contracts"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+var start = time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+
+// memoryRepo serves its bars, or fails with err
+type memoryRepo struct {
+	bars []*candlestick.Candlestick
+	err  error
+}
+
+func (r *memoryRepo) GetCandlestickBarsPage(
+	_ context.Context,
+	symbol string,
+	from time.Time,
+	to time.Time,
+	limit int,
+) ([]*candlestick.Candlestick, error) {
+	if r.err != nil {
+		return nil, r.err
+	}
+	page := []*candlestick.Candlestick{}
+	for _, bar := range r.bars {
+		if bar.Symbol == symbol && !bar.TradeTimestamp.Before(from) && !bar.TradeTimestamp.After(to) && len(page) < limit {
+			page = append(page, bar)
+		}
+	}
+	return page, nil
+}
+
+func newExportHandler(repo *memoryRepo, cfg *export.ExportConfig) *ExportHandler {
+	return NewExportHandler(export.NewExportService(
+		cfg,
+		repo,
+		map[export.Format]export.NewBarWriter{export.FORMAT_CSV: exporters.NewCSVWriter},
+		testutil.NopLogger{},
+	))
+}
+
+func minuteBars(n int) []*candlestick.Candlestick {
+	bars := []*candlestick.Candlestick{}
+	for i := range n {
+		bars = append(bars, &candlestick.Candlestick{
+			Symbol:         "BTCUSDT",
+			Open:           1,
+			High:           2,
+			Low:            0.5,
+			Close:          1.5,
+			TradeTimestamp: start.Add(time.Duration(i) * time.Minute),
+		})
+	}
+	return bars
+}
+
+// exportStream records the chunks sent
+type exportStream struct {
+	grpc.ServerStream
+	ctx    context.Context
+	chunks [][]byte
+}
+
+func (s *exportStream) Context() context.Context { return s.ctx }
+
+func (s *exportStream) Send(res *exportpb.ExportCandlesticksResponse) error {
+	s.chunks = append(s.chunks, bytes.Clone(res.Chunk))
+	return nil
+}
+
+func exportRequest() *exportpb.ExportCandlesticksRequest {
+	return &exportpb.ExportCandlesticksRequest{
+		Symbol: "BTCUSDT",
+		From:   timestamppb.New(start),
+		To:     timestamppb.New(start.Add(24 * time.Hour)),
+	}
+}
+
+func TestExportCandlesticksStreamsTheFileInChunks(t *testing.T) {
+	// a csv of well over a chunk
+	h := newExportHandler(&memoryRepo{bars: minuteBars(2000)}, &export.ExportConfig{})
+	srv := &exportStream{ctx: context.Background()}
+	req := exportRequest()
+	req.To = timestamppb.New(start.Add(72 * time.Hour))
+
+	if err := h.ExportCandlesticks(req, srv); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if len(srv.chunks) < 2 {
+		t.Fatalf("expected several chunks, got %d", len(srv.chunks))
+	}
+	for _, chunk := range srv.chunks[:len(srv.chunks)-1] {
+		if len(chunk) != exportChunkSize {
+			t.Fatalf("expected full chunks but the last, got %d bytes", len(chunk))
+		}
+	}
+	file := string(bytes.Join(srv.chunks, nil))
+	if rows := strings.Count(file, "\n"); rows != 2001 {
+		t.Fatalf("expected a header and 2000 rows, got %d lines", rows)
+	}
+	if !strings.HasPrefix(file, "symbol,timestamp,open,high,low,close\nBTCUSDT,2024-01-01T00:00:00Z,1,2,0.5,1.5\n") {
+		t.Fatalf("unexpected file start %q", file[:80])
+	}
+}
+
+func TestExportCandlesticksStatuses(t *testing.T) {
+	cases := []struct {
+		name string
+		repo *memoryRepo
+		cfg  *export.ExportConfig
+		req  func(req *exportpb.ExportCandlesticksRequest)
+		ctx  context.Context
+		code codes.Code
+	}{
+		{
+			name: "no symbol",
+			req:  func(req *exportpb.ExportCandlesticksRequest) { req.Symbol = "" },
+			code: codes.InvalidArgument,
+		},
+		{
+			name: "invalid format",
+			req:  func(req *exportpb.ExportCandlesticksRequest) { req.Format = "xlsx" },
+			code: codes.InvalidArgument,
+		},
+		{
+			name: "range over the max",
+			cfg:  &export.ExportConfig{MaxRange: time.Hour},
+			code: codes.InvalidArgument,
+		},
+		{
+			name: "db failure",
+			repo: &memoryRepo{err: errors.New("pq: connection refused to 10.0.0.5")},
+			code: codes.Internal,
+		},
+		{
+			name: "canceled",
+			ctx:  canceledContext(),
+			repo: &memoryRepo{err: context.Canceled},
+			code: codes.Canceled,
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			repo, cfg, ctx := c.repo, c.cfg, c.ctx
+			if repo == nil {
+				repo = &memoryRepo{bars: minuteBars(10)}
+			}
+			if cfg == nil {
+				cfg = &export.ExportConfig{}
+			}
+			if ctx == nil {
+				ctx = context.Background()
+			}
+			req := exportRequest()
+			if c.req != nil {
+				c.req(req)
+			}
+
+			err := newExportHandler(repo, cfg).ExportCandlesticks(req, &exportStream{ctx: ctx})
+			if status.Code(err) != c.code {
+				t.Fatalf("expected %s, got %v", c.code, err)
+			}
+			if c.code == codes.Internal && strings.Contains(err.Error(), "10.0.0.5") {
+				t.Fatalf("expected the cause to be left out of the status, got %v", err)
+			}
+		})
+	}
+}
+
+func TestExportCandlesticksRequiresTheSymbolsEntitlement(t *testing.T) {
+	ctx := auth.WithPrincipal(context.Background(), &auth.Principal{ID: "desk-1", Method: auth.METHOD_API_KEY, Symbols: []string{"ETH*"}})
+	h := newExportHandler(&memoryRepo{bars: minuteBars(10)}, &export.ExportConfig{})
+
+	err := h.ExportCandlesticks(exportRequest(), &exportStream{ctx: ctx})
+	if !errors.Is(err, auth.ERR_PERMISSION_DENIED) {
+		t.Fatalf("expected the export to be denied, got %v", err)
+	}
+}
+
+func TestDownloadCandlesticks(t *testing.T) {
+	const query = "/api/v1/candlestick/export?symbol=BTCUSDT&columns=timestamp&columns=close&from=2024-01-01T00:00:00Z&to=2024-01-01T00:01:00Z"
+
+	cases := []struct {
+		name  string
+		repo  *memoryRepo
+		url   string
+		code  int
+		body  string
+		ctype string
+	}{
+		{
+			name:  "download",
+			repo:  &memoryRepo{bars: minuteBars(10)},
+			url:   query,
+			code:  http.StatusOK,
+			body:  "timestamp,close\n2024-01-01T00:00:00Z,1.5\n2024-01-01T00:01:00Z,1.5\n",
+			ctype: "text/csv",
+		},
+		{
+			name: "invalid column",
+			repo: &memoryRepo{},
+			url:  query + "&columns=volume",
+			code: http.StatusBadRequest,
+		},
+		{
+			name: "db failure",
+			repo: &memoryRepo{err: errors.New("pq: connection refused to 10.0.0.5")},
+			url:  query,
+			code: http.StatusInternalServerError,
+			body: "Failed to export the bars\n",
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			rec := httptest.NewRecorder()
+			newExportHandler(c.repo, &export.ExportConfig{}).DownloadCandlesticks(rec, httptest.NewRequest(http.MethodGet, c.url, nil))
+
+			if rec.Code != c.code {
+				t.Fatalf("expected %d, got %d - %s", c.code, rec.Code, rec.Body.String())
+			}
+			if c.body != "" && rec.Body.String() != c.body {
+				t.Fatalf("expected the body %q, got %q", c.body, rec.Body.String())
+			}
+			if c.ctype != "" {
+				if ctype := rec.Header().Get("Content-Type"); ctype != c.ctype {
+					t.Fatalf("expected the content type %s, got %s", c.ctype, ctype)
+				}
+				if disposition := rec.Header().Get("Content-Disposition"); disposition != `attachment; filename="BTCUSDT-1m.csv"` {
+					t.Fatalf("unexpected content disposition %s", disposition)
+				}
+			}
+		})
+	}
+}
+
+func TestDownloadCandlesticksOverTheConcurrencyLimit(t *testing.T) {
+	h := newExportHandler(&memoryRepo{bars: minuteBars(10)}, &export.ExportConfig{MaxConcurrent: 1})
+
+	// holds the only slot until the first bytes are written
+	blocked := &blockingResponse{ResponseRecorder: httptest.NewRecorder(), writing: make(chan struct{}), release: make(chan struct{})}
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		h.DownloadCandlesticks(blocked, httptest.NewRequest(http.MethodGet, "/api/v1/candlestick/export?symbol=BTCUSDT", nil))
+	}()
+	<-blocked.writing
+
+	rec := httptest.NewRecorder()
+	h.DownloadCandlesticks(rec, httptest.NewRequest(http.MethodGet, "/api/v1/candlestick/export?symbol=BTCUSDT", nil))
+	if rec.Code != http.StatusTooManyRequests {
+		t.Fatalf("expected 429, got %d", rec.Code)
+	}
+
+	close(blocked.release)
+	<-done
+}
+
+// blockingResponse blocks the first write until released
+type blockingResponse struct {
+	*httptest.ResponseRecorder
+	writing chan struct{}
+	release chan struct{}
+	blocked bool
+}
+
+func (b *blockingResponse) Write(p []byte) (int, error) {
+	if !b.blocked {
+		b.blocked = true
+		close(b.writing)
+		<-b.release
+	}
+	return b.ResponseRecorder.Write(p)
+}
+
+func canceledContext() context.Context {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	return ctx
+}
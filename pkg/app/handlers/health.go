@@ -0,0 +1,53 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/ramasbeinaty/trading-chart-service/pkg/domain/health"
+)
+
+type HealthHandler struct {
+	healthService *health.HealthService
+}
+
+func NewHealthHandler(
+	healthService *health.HealthService,
+) *HealthHandler {
+	return &HealthHandler{
+		healthService: healthService,
+	}
+}
+
+// Liveness reports the process is up and serving requests
+func (h *HealthHandler) Liveness(
+	w http.ResponseWriter,
+	r *http.Request,
+) {
+	writeReport(w, &health.Report{
+		Status: health.STATUS_OK,
+		Checks: []health.CheckResult{},
+	})
+}
+
+// Readiness reports every dependency check, responding with
+// 503 Service Unavailable if any of them is failing
+func (h *HealthHandler) Readiness(
+	w http.ResponseWriter,
+	r *http.Request,
+) {
+	writeReport(w, h.healthService.Readiness(r.Context()))
+}
+
+func writeReport(
+	w http.ResponseWriter,
+	report *health.Report,
+) {
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("Cache-Control", "no-store")
+
+	if report.Status != health.STATUS_OK {
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}
+	json.NewEncoder(w).Encode(report)
+}
@@ -0,0 +1,168 @@
+package handlers
+
+import (
+	"github.com/ramasbeinaty/trading-chart-service/pkg/domain/alert"
+	"github.com/ramasbeinaty/trading-chart-service/pkg/domain/candlestick"
+	"github.com/ramasbeinaty/trading-chart-service/pkg/domain/indicator"
+	"github.com/ramasbeinaty/trading-chart-service/pkg/domain/subscription"
+	adminpb "github.com/ramasbeinaty/trading-chart-service/proto/admin/contracts"
+	alertpb "github.com/ramasbeinaty/trading-chart-service/proto/alert/contracts"
+	candlestickpb "github.com/ramasbeinaty/trading-chart-service/proto/candlestick/contracts"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+var eventKindContracts = map[subscription.EventKind]candlestickpb.CandlestickEventKind{
+	subscription.EVENT_KIND_LIVE:     candlestickpb.CandlestickEventKind_CANDLESTICK_EVENT_KIND_LIVE,
+	subscription.EVENT_KIND_SNAPSHOT: candlestickpb.CandlestickEventKind_CANDLESTICK_EVENT_KIND_SNAPSHOT,
+	subscription.EVENT_KIND_REPLAY:   candlestickpb.CandlestickEventKind_CANDLESTICK_EVENT_KIND_REPLAY,
+	subscription.EVENT_KIND_GAP:      candlestickpb.CandlestickEventKind_CANDLESTICK_EVENT_KIND_GAP,
+}
+
+func toCandlestickContract(
+	event *subscription.CandlestickEvent,
+) *candlestickpb.Candlestick {
+	return &candlestickpb.Candlestick{
+		Symbol:         event.Symbol,
+		OpenPrice:      event.Open,
+		HighPrice:      event.High,
+		LowPrice:       event.Low,
+		ClosePrice:     event.Close,
+		TradeTimestamp: timestamppb.New(event.TradeTimestamp),
+		Sequence:       event.Sequence,
+		Kind:           eventKindContracts[event.Kind],
+		Indicators:     toIndicatorValueContracts(event.Indicators),
+	}
+}
+
+func toIndicatorValueContracts(
+	values []indicator.Value,
+) []*candlestickpb.IndicatorValue {
+	if len(values) == 0 {
+		return nil
+	}
+
+	contracts := make([]*candlestickpb.IndicatorValue, 0, len(values))
+	for _, value := range values {
+		contracts = append(contracts, &candlestickpb.IndicatorValue{
+			Name:      value.Name,
+			Values:    value.Values,
+			Ready:     value.Ready,
+			Timestamp: timestamppb.New(value.Timestamp),
+		})
+	}
+	return contracts
+}
+
+var alertMetrics = map[alertpb.AlertMetric]alert.Metric{
+	alertpb.AlertMetric_ALERT_METRIC_CLOSE:          alert.METRIC_CLOSE,
+	alertpb.AlertMetric_ALERT_METRIC_RANGE_PERCENT:  alert.METRIC_RANGE_PERCENT,
+	alertpb.AlertMetric_ALERT_METRIC_CHANGE_PERCENT: alert.METRIC_CHANGE_PERCENT,
+}
+
+var alertConditions = map[alertpb.AlertCondition]alert.Condition{
+	alertpb.AlertCondition_ALERT_CONDITION_CROSSES_ABOVE: alert.CONDITION_CROSSES_ABOVE,
+	alertpb.AlertCondition_ALERT_CONDITION_CROSSES_BELOW: alert.CONDITION_CROSSES_BELOW,
+	alertpb.AlertCondition_ALERT_CONDITION_ABOVE:         alert.CONDITION_ABOVE,
+	alertpb.AlertCondition_ALERT_CONDITION_BELOW:         alert.CONDITION_BELOW,
+}
+
+var alertModes = map[alertpb.AlertMode]alert.Mode{
+	alertpb.AlertMode_ALERT_MODE_ONCE:  alert.MODE_ONCE,
+	alertpb.AlertMode_ALERT_MODE_REARM: alert.MODE_REARM,
+}
+
+var alertMetricContracts = map[alert.Metric]alertpb.AlertMetric{
+	alert.METRIC_CLOSE:          alertpb.AlertMetric_ALERT_METRIC_CLOSE,
+	alert.METRIC_RANGE_PERCENT:  alertpb.AlertMetric_ALERT_METRIC_RANGE_PERCENT,
+	alert.METRIC_CHANGE_PERCENT: alertpb.AlertMetric_ALERT_METRIC_CHANGE_PERCENT,
+}
+
+var alertConditionContracts = map[alert.Condition]alertpb.AlertCondition{
+	alert.CONDITION_CROSSES_ABOVE: alertpb.AlertCondition_ALERT_CONDITION_CROSSES_ABOVE,
+	alert.CONDITION_CROSSES_BELOW: alertpb.AlertCondition_ALERT_CONDITION_CROSSES_BELOW,
+	alert.CONDITION_ABOVE:         alertpb.AlertCondition_ALERT_CONDITION_ABOVE,
+	alert.CONDITION_BELOW:         alertpb.AlertCondition_ALERT_CONDITION_BELOW,
+}
+
+var alertModeContracts = map[alert.Mode]alertpb.AlertMode{
+	alert.MODE_ONCE:  alertpb.AlertMode_ALERT_MODE_ONCE,
+	alert.MODE_REARM: alertpb.AlertMode_ALERT_MODE_REARM,
+}
+
+var alertStatusContracts = map[alert.Status]alertpb.AlertStatus{
+	alert.STATUS_ACTIVE:    alertpb.AlertStatus_ALERT_STATUS_ACTIVE,
+	alert.STATUS_TRIGGERED: alertpb.AlertStatus_ALERT_STATUS_TRIGGERED,
+}
+
+func toAlertContract(
+	a *alert.Alert,
+) *alertpb.Alert {
+	contract := &alertpb.Alert{
+		Id:        a.ID,
+		Symbol:    a.Symbol,
+		Timeframe: string(a.Timeframe),
+		Metric:    alertMetricContracts[a.Metric],
+		Condition: alertConditionContracts[a.Condition],
+		Threshold: a.Threshold,
+		Mode:      alertModeContracts[a.Mode],
+		OnClose:   a.OnClose,
+		Status:    alertStatusContracts[a.Status],
+		FireCount: a.FireCount,
+		CreatedAt: timestamppb.New(a.CreatedAt),
+		UpdatedAt: timestamppb.New(a.UpdatedAt),
+	}
+
+	if !a.LastFiredAt.IsZero() {
+		contract.LastFiredAt = timestamppb.New(a.LastFiredAt)
+	}
+
+	return contract
+}
+
+func toAlertFiredContract(
+	fired *alert.Fired,
+) *alertpb.AlertFired {
+	return &alertpb.AlertFired{
+		Alert:        toAlertContract(&fired.Alert),
+		Value:        fired.Value,
+		BarTimestamp: timestamppb.New(fired.BarTimestamp),
+		FiredAt:      timestamppb.New(fired.FiredAt),
+	}
+}
+
+func toBarContracts(
+	bars []*candlestick.Candlestick,
+) []*candlestickpb.Candlestick {
+	contracts := make([]*candlestickpb.Candlestick, 0, len(bars))
+	for _, bar := range bars {
+		contracts = append(contracts, &candlestickpb.Candlestick{
+			Symbol:         bar.Symbol,
+			OpenPrice:      bar.Open,
+			HighPrice:      bar.High,
+			LowPrice:       bar.Low,
+			ClosePrice:     bar.Close,
+			TradeTimestamp: timestamppb.New(bar.TradeTimestamp),
+			Sequence:       bar.Sequence,
+		})
+	}
+	return contracts
+}
+
+func toSubscriberContract(
+	info *subscription.SubscriberInfo,
+) *adminpb.Subscriber {
+	contract := &adminpb.Subscriber{
+		Id:           info.ID,
+		Peer:         info.Peer,
+		Symbols:      info.Symbols,
+		ConnectedAt:  timestamppb.New(info.ConnectedAt),
+		QueueDepth:   info.QueueDepth,
+		MessagesSent: info.Sent,
+	}
+
+	if info.Owner != nil {
+		contract.Principal = info.Owner.String()
+	}
+
+	return contract
+}
@@ -0,0 +1,92 @@
+package handlers
+
+import (
+	"fmt"
+
+	"github.com/ramasbeinaty/trading-chart-service/pkg/domain/base/logger"
+	"github.com/ramasbeinaty/trading-chart-service/pkg/domain/candlestick"
+	"github.com/ramasbeinaty/trading-chart-service/pkg/domain/replication"
+	"github.com/ramasbeinaty/trading-chart-service/pkg/domain/subscription"
+	"github.com/ramasbeinaty/trading-chart-service/pkg/domain/uids"
+	replicationpb "github.com/ramasbeinaty/trading-chart-service/proto/replication/contracts"
+	"go.uber.org/zap"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// every tracked symbol is replicated
+const REPLICATED_SYMBOLS = "*"
+
+type ReplicationHandler struct {
+	replicationpb.UnimplementedReplicationServiceServer
+	candlestickService  *candlestick.CandlestickService
+	subscriptionService *subscription.SubscriptionService
+	replicationService  *replication.ReplicationService
+	uidService          *uids.UIDService
+	lgr                 logger.ILogger
+}
+
+var _ replicationpb.ReplicationServiceServer = &ReplicationHandler{}
+
+func NewReplicationHandler(
+	candlestickService *candlestick.CandlestickService,
+	subscriptionService *subscription.SubscriptionService,
+	replicationService *replication.ReplicationService,
+	uidService *uids.UIDService,
+	lgr logger.ILogger,
+) *ReplicationHandler {
+	return &ReplicationHandler{
+		candlestickService:  candlestickService,
+		subscriptionService: subscriptionService,
+		replicationService:  replicationService,
+		uidService:          uidService,
+		lgr:                 lgr,
+	}
+}
+
+// StreamBarUpdates subscribes the follower to every symbol, starting with
+// a snapshot of the recent bars
+// only the leader serves it, for the followers not to follow each other
+func (h *ReplicationHandler) StreamBarUpdates(
+	req *replicationpb.StreamBarUpdatesRequest,
+	srv replicationpb.ReplicationService_StreamBarUpdatesServer,
+) error {
+	if !h.replicationService.IsLeader() {
+		return status.Error(codes.FailedPrecondition, "Not the leader, follow the advertised leader")
+	}
+
+	ctx := subscription.WithPeer(srv.Context(), grpcPeer(srv.Context()))
+	h.lgr.Get(ctx).Info("Follower connected", zap.String("follower", req.Follower))
+
+	sink := newGrpcSink(srv)
+	defer sink.Close()
+
+	id, err := h.uidService.GenerateUID()
+	if err != nil {
+		return fmt.Errorf("Failed to generate an id for the follower")
+	}
+
+	err = h.candlestickService.Subscribe(
+		ctx,
+		id,
+		[]string{REPLICATED_SYMBOLS},
+		candlestick.SubscribeOptions{
+			Snapshot:     true,
+			SnapshotBars: candlestick.MAX_RECENT_BARS,
+		},
+		sink,
+	)
+	if err != nil {
+		return fmt.Errorf("Failed to subscribe the follower - %w", err)
+	}
+	defer h.subscriptionService.RemoveSubscriber(ctx, id, nil)
+
+	<-sink.Done()
+	if sink.isGoingAway() {
+		return status.Error(codes.Unavailable, "Leader is shutting down")
+	}
+	if cause := sink.cancelCause(); cause != nil {
+		return status.Error(codes.Aborted, cause.Error())
+	}
+	return ctx.Err()
+}
@@ -0,0 +1,141 @@
+package app
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/ramasbeinaty/trading-chart-service/pkg/domain/candlestick"
+	"github.com/ramasbeinaty/trading-chart-service/pkg/domain/health"
+	"github.com/ramasbeinaty/trading-chart-service/pkg/domain/replication"
+	"github.com/ramasbeinaty/trading-chart-service/pkg/infra/db"
+)
+
+// adds the checks of the service's dependencies to the readiness
+func addHealthChecks(
+	healthService *health.HealthService,
+	healthConfig *health.HealthConfig,
+	_db *sql.DB,
+	_leadership *leadership,
+	replicationService *replication.ReplicationService,
+	changeFeed *db.ChangeFeed,
+	candlestickService *candlestick.CandlestickService,
+) {
+	// read-only instances neither lead nor follow
+	if changeFeed != nil {
+		healthService.AddCheck("changefeed", func(ctx context.Context) error {
+			if !changeFeed.IsConnected() {
+				return fmt.Errorf("change feed is not connected")
+			}
+			return nil
+		})
+	} else {
+		healthService.AddCheck("replication", func(ctx context.Context) error {
+			if replicationService.Role() == replication.ROLE_NONE {
+				return fmt.Errorf("neither leading nor following a leader")
+			}
+			return nil
+		})
+	}
+
+	// the stream is only checked when ingesting from binance, not when
+	// replaying a file, i.e. without a leadership
+	if _leadership != nil {
+		healthService.AddCheck("binance", leaderOnly(replicationService, func(ctx context.Context) error {
+			if !_leadership.isConnected() {
+				return fmt.Errorf("binance stream is not connected")
+			}
+			return nil
+		}))
+
+		addTradeCheck(
+			healthService,
+			healthConfig,
+			_leadership,
+			replicationService,
+			candlestickService,
+		)
+	}
+
+	healthService.AddCheck("db", func(ctx context.Context) error {
+		if err := _db.PingContext(ctx); err != nil {
+			return fmt.Errorf("Failed to ping db - %w", err)
+		}
+		return nil
+	})
+
+	healthService.AddCheck("migrations", func(ctx context.Context) error {
+		return db.CheckMigrations(ctx, _db, db.GetMigrationScripts())
+	})
+
+	healthService.AddCheck("commit_backlog", leaderOnly(replicationService, func(ctx context.Context) error {
+		backlog := candlestickService.CommitBacklog(time.Now())
+		if backlog > healthConfig.MaxCommitBacklog {
+			return fmt.Errorf(
+				"%d closed bars waiting to be committed, more than %d",
+				backlog,
+				healthConfig.MaxCommitBacklog,
+			)
+		}
+		return nil
+	}))
+}
+
+// trades are expected on the streamed symbols, the first one within the max
+// age from the start of the leadership
+// only fails once none of them trades, a quiet symbol being no sign of a
+// stalled stream
+func addTradeCheck(
+	healthService *health.HealthService,
+	healthConfig *health.HealthConfig,
+	_leadership *leadership,
+	replicationService *replication.ReplicationService,
+	candlestickService *candlestick.CandlestickService,
+) {
+	healthService.AddCheck("trades", leaderOnly(replicationService, func(ctx context.Context) error {
+		return checkTrades(
+			_leadership.getSymbols(),
+			_leadership.ledSince(),
+			candlestickService.LastTradeAt,
+			healthConfig.MaxTradeAge,
+			time.Now(),
+		)
+	}))
+}
+
+// fails once none of the symbols traded within the max age, the age being
+// counted from since at the earliest
+func checkTrades(
+	symbols []string,
+	since time.Time,
+	lastTradeAt func(symbol string) time.Time,
+	maxAge time.Duration,
+	now time.Time,
+) error {
+	latest := since
+	for _, symbol := range symbols {
+		if tradeAt := lastTradeAt(strings.ToUpper(symbol)); tradeAt.After(latest) {
+			latest = tradeAt
+		}
+	}
+
+	if age := now.Sub(latest); age > maxAge {
+		return fmt.Errorf("no trade received on any symbol for %s", age.Round(time.Second))
+	}
+	return nil
+}
+
+// the ingestion is only checked on the leader
+func leaderOnly(
+	replicationService *replication.ReplicationService,
+	check health.Check,
+) health.Check {
+	return func(ctx context.Context) error {
+		if !replicationService.IsLeader() {
+			return nil
+		}
+		return check(ctx)
+	}
+}
@@ -0,0 +1,39 @@
+package app
+
+import (
+	"testing"
+	"time"
+)
+
+func TestCheckTrades(t *testing.T) {
+	now := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+	lastTrades := map[string]time.Time{
+		"BTCUSDT": now.Add(-30 * time.Second),
+		// quiet
+		"PEPEUSDT": now.Add(-time.Hour),
+	}
+	lastTradeAt := func(symbol string) time.Time {
+		return lastTrades[symbol]
+	}
+
+	cases := []struct {
+		name    string
+		symbols []string
+		since   time.Time
+		failing bool
+	}{
+		{name: "a quiet symbol", symbols: []string{"btcusdt", "pepeusdt"}, since: now.Add(-2 * time.Hour)},
+		{name: "every symbol quiet", symbols: []string{"pepeusdt", "ethusdt"}, since: now.Add(-2 * time.Hour), failing: true},
+		{name: "leading since recently", symbols: []string{"pepeusdt"}, since: now.Add(-time.Minute)},
+		{name: "no symbol", since: now.Add(-2 * time.Hour), failing: true},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			err := checkTrades(c.symbols, c.since, lastTradeAt, 2*time.Minute, now)
+			if (err != nil) != c.failing {
+				t.Fatalf("expected failing %v, got %v", c.failing, err)
+			}
+		})
+	}
+}
@@ -0,0 +1,176 @@
+package app
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/ramasbeinaty/trading-chart-service/pkg/domain/alert"
+	"github.com/ramasbeinaty/trading-chart-service/pkg/domain/candlestick"
+	"github.com/ramasbeinaty/trading-chart-service/pkg/domain/replication"
+	"github.com/ramasbeinaty/trading-chart-service/pkg/infra/clients/binance"
+	"github.com/ramasbeinaty/trading-chart-service/pkg/infra/metrics"
+	"go.uber.org/zap"
+)
+
+// leadership ingests the trades while the replica leads, on a binance
+// stream of its own each time it is elected
+type leadership struct {
+	lgr                *zap.Logger
+	binanceConfig      *binance.BinanceConfig
+	metrics            *metrics.PrometheusMetrics
+	candlestickService *candlestick.CandlestickService
+	alertService       *alert.AlertService
+
+	mutex     sync.Mutex
+	ingestion *ingestion
+	since     time.Time
+	symbols   []string // streamed by the next leaderships too
+	// nanoseconds between the checkpoints of the bars in progress
+	checkpointInterval atomic.Int64
+}
+
+var _ replication.ILeader = (*leadership)(nil)
+
+func newLeadership(
+	lgr *zap.Logger,
+	binanceConfig *binance.BinanceConfig,
+	candlestickConfig *candlestick.CandlestickConfig,
+	metrics *metrics.PrometheusMetrics,
+	candlestickService *candlestick.CandlestickService,
+	alertService *alert.AlertService,
+) *leadership {
+	l := &leadership{
+		lgr:                lgr,
+		binanceConfig:      binanceConfig,
+		metrics:            metrics,
+		candlestickService: candlestickService,
+		alertService:       alertService,
+		symbols:            binanceConfig.Symbols,
+	}
+	l.checkpointInterval.Store(int64(candlestickConfig.CheckpointInterval))
+	return l
+}
+
+// Lead evaluates the alerts as stored, since the replicas may have changed
+// them while following, then ingests the trades
+func (l *leadership) Lead(
+	ctx context.Context,
+) error {
+	if err := l.alertService.LoadAlerts(ctx); err != nil {
+		return fmt.Errorf("Failed to lead - %w", err)
+	}
+
+	l.mutex.Lock()
+	symbols := l.symbols
+	l.mutex.Unlock()
+
+	tradeDataChan := make(chan binance.TradeMessageParsed)
+	binanceClient := binance.NewBinanceClient(
+		tradeDataChan,
+		binance.AGG_TRADE_STREAM_NAME,
+		symbols,
+		ctx,
+		l.binanceConfig,
+		l.metrics,
+	)
+
+	_ingestion, err := runAppService(
+		ctx,
+		l.lgr,
+		&tradeDataChan,
+		binanceClient,
+		l.candlestickService,
+		l.getCheckpointInterval,
+		l.metrics,
+	)
+	if err != nil {
+		return err
+	}
+
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+
+	l.ingestion = _ingestion
+	l.since = time.Now()
+
+	// the symbols changed while connecting
+	if !slices.Equal(symbols, l.symbols) {
+		if err := binanceClient.SetSymbols(l.symbols); err != nil {
+			l.lgr.Error("Failed to resubscribe the binance stream", zap.Error(err))
+		}
+	}
+	return nil
+}
+
+// StepDown stops ingesting, and disconnects the alert listeners since the
+// alerts only fire on the leader
+func (l *leadership) StepDown(
+	ctx context.Context,
+) error {
+	l.alertService.RemoveListeners(ctx)
+
+	l.mutex.Lock()
+	_ingestion := l.ingestion
+	l.ingestion = nil
+	l.mutex.Unlock()
+
+	if _ingestion == nil {
+		return nil
+	}
+	return _ingestion.stop(ctx)
+}
+
+// reports whether the binance stream of the current leadership is connected
+func (l *leadership) isConnected() bool {
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+
+	return l.ingestion != nil && l.ingestion.binanceClient.IsConnected()
+}
+
+// returns when the replica started leading
+func (l *leadership) ledSince() time.Time {
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+
+	return l.since
+}
+
+// returns the symbols streamed by the current leadership if any, or the
+// next ones
+func (l *leadership) getSymbols() []string {
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+
+	return l.symbols
+}
+
+// streams the symbols from now on, on the stream of the current leadership
+// if any and the ones of the next leaderships
+func (l *leadership) setSymbols(
+	symbols []string,
+) error {
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+
+	l.symbols = symbols
+	if l.ingestion == nil {
+		return nil
+	}
+	return l.ingestion.binanceClient.SetSymbols(symbols)
+}
+
+// applies from the next checkpoint on
+func (l *leadership) setCheckpointInterval(
+	interval time.Duration,
+) {
+	l.checkpointInterval.Store(int64(interval))
+}
+
+func (l *leadership) getCheckpointInterval() time.Duration {
+	return time.Duration(l.checkpointInterval.Load())
+}
@@ -0,0 +1,105 @@
+package lifecycle
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+const (
+	// how long a stage is still waited for once the timeout is reached,
+	// letting the stages honoring the expired context return
+	STAGE_GRACE_PERIOD = time.Second
+)
+
+type stage struct {
+	name string
+	stop func(ctx context.Context) error
+}
+
+// Lifecycle stops the app's components in the order they were added,
+// within a total timeout
+type Lifecycle struct {
+	lgr     *zap.Logger
+	timeout time.Duration
+	stages  []stage
+}
+
+func NewLifecycle(
+	lgr *zap.Logger,
+	timeout time.Duration,
+) *Lifecycle {
+	return &Lifecycle{
+		lgr:     lgr,
+		timeout: timeout,
+	}
+}
+
+// OnStop adds a stage, run after the ones added before it
+// the stage is given a context expiring with the total timeout
+func (l *Lifecycle) OnStop(
+	name string,
+	stop func(ctx context.Context) error,
+) {
+	l.stages = append(l.stages, stage{name: name, stop: stop})
+}
+
+// Stop runs every stage in order
+// a stage still running past the timeout and its grace period is abandoned,
+// and the following ones are run with the expired context, to release what
+// they can right away
+func (l *Lifecycle) Stop() error {
+	ctx, cancel := context.WithTimeout(context.Background(), l.timeout)
+	defer cancel()
+
+	var errs []error
+	for _, s := range l.stages {
+		started := time.Now()
+		l.lgr.Info("Stopping", zap.String("stage", s.name))
+
+		if err := runStage(ctx, s); err != nil {
+			l.lgr.Error(
+				"Failed to stop",
+				zap.String("stage", s.name),
+				zap.Error(err),
+			)
+			errs = append(errs, fmt.Errorf("Failed to stop %s - %w", s.name, err))
+			continue
+		}
+
+		l.lgr.Info(
+			"Stopped",
+			zap.String("stage", s.name),
+			zap.Duration("duration", time.Since(started)),
+		)
+	}
+
+	return errors.Join(errs...)
+}
+
+// waits for the stage until it returns, or the grace period
+// once the context is done
+func runStage(
+	ctx context.Context,
+	s stage,
+) error {
+	done := make(chan error, 1)
+	go func() {
+		done <- s.stop(ctx)
+	}()
+
+	select {
+	case err := <-done:
+		return err
+	case <-ctx.Done():
+		select {
+		case err := <-done:
+			return err
+		case <-time.After(STAGE_GRACE_PERIOD):
+			return fmt.Errorf("abandoned as the shutdown timed out - %w", ctx.Err())
+		}
+	}
+}
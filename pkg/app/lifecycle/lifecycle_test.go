@@ -0,0 +1,60 @@
+package lifecycle
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+func TestStopRunsStagesInOrder(t *testing.T) {
+	l := NewLifecycle(zap.NewNop(), time.Second)
+
+	var order []string
+	for _, name := range []string{"ingestion", "flush", "servers"} {
+		l.OnStop(name, func(context.Context) error {
+			order = append(order, name)
+			return nil
+		})
+	}
+
+	if err := l.Stop(); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(order) != 3 || order[0] != "ingestion" || order[1] != "flush" || order[2] != "servers" {
+		t.Fatalf("expected the stages to run in order, got %v", order)
+	}
+}
+
+func TestStopIsBoundedByTimeout(t *testing.T) {
+	l := NewLifecycle(zap.NewNop(), 50*time.Millisecond)
+
+	released := make(chan struct{})
+	defer close(released)
+
+	var lastCtxErr error
+	l.OnStop("failing", func(context.Context) error { return errors.New("boom") })
+	l.OnStop("stuck", func(context.Context) error {
+		<-released
+		return nil
+	})
+	l.OnStop("last", func(ctx context.Context) error {
+		lastCtxErr = ctx.Err()
+		return nil
+	})
+
+	started := time.Now()
+	err := l.Stop()
+
+	if elapsed := time.Since(started); elapsed > 3*time.Second {
+		t.Fatalf("expected the stop to be bounded by the timeout, took %s", elapsed)
+	}
+	if err == nil {
+		t.Fatalf("expected the failing and abandoned stages to be reported")
+	}
+	if lastCtxErr == nil {
+		t.Errorf("expected the stage after the timeout to run with an expired context")
+	}
+}
@@ -0,0 +1,143 @@
+package middlewares
+
+import (
+	"context"
+	"strings"
+
+	grpc_middleware "github.com/grpc-ecosystem/go-grpc-middleware"
+	"github.com/ramasbeinaty/trading-chart-service/pkg/domain/auth"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/peer"
+)
+
+const (
+	API_KEY_HEADER       = "x-api-key"
+	AUTHORIZATION_HEADER = "authorization"
+	BEARER_PREFIX        = "Bearer "
+	// carries the token the replicas authenticate with, without a client
+	// certificate
+	REPLICA_TOKEN_HEADER = "x-replica-token"
+
+	REPLICATION_SERVICE = "/replication.ReplicationService/"
+)
+
+// services left open to every caller: the probes and grpcurl's reflection
+var publicServices = []string{
+	"/grpc.health.v1.Health/",
+	"/grpc.reflection.",
+}
+
+// authenticates the request with its x-api-key or bearer authorization
+// metadata, passing its principal on in the context
+func AuthUnaryInterceptor(
+	authService *auth.AuthService,
+) grpc.UnaryServerInterceptor {
+	return func(
+		ctx context.Context,
+		req any,
+		info *grpc.UnaryServerInfo,
+		handler grpc.UnaryHandler,
+	) (any, error) {
+		ctx, err := authenticate(ctx, authService, info.FullMethod)
+		if err != nil {
+			return nil, err
+		}
+		return handler(ctx, req)
+	}
+}
+
+// see AuthUnaryInterceptor
+func AuthStreamInterceptor(
+	authService *auth.AuthService,
+) grpc.StreamServerInterceptor {
+	return func(
+		srv any,
+		ss grpc.ServerStream,
+		info *grpc.StreamServerInfo,
+		handler grpc.StreamHandler,
+	) error {
+		ctx, err := authenticate(ss.Context(), authService, info.FullMethod)
+		if err != nil {
+			return err
+		}
+
+		wrapped := grpc_middleware.WrapServerStream(ss)
+		wrapped.WrappedContext = ctx
+		return handler(srv, wrapped)
+	}
+}
+
+func authenticate(
+	ctx context.Context,
+	authService *auth.AuthService,
+	method string,
+) (context.Context, error) {
+	if !authService.Enabled() || isPublic(method) {
+		return ctx, nil
+	}
+
+	md, _ := metadata.FromIncomingContext(ctx)
+
+	// the replicas following the leader aren't clients, and receive every
+	// symbol
+	if isReplication(method) {
+		if hasClientCertificate(ctx) {
+			return ctx, nil
+		}
+		if err := authService.AuthenticateReplica(first(md.Get(REPLICA_TOKEN_HEADER))); err != nil {
+			return nil, toStatus(err)
+		}
+		return ctx, nil
+	}
+
+	apiKey := first(md.Get(API_KEY_HEADER))
+	token := bearerToken(first(md.Get(AUTHORIZATION_HEADER)))
+
+	principal, err := authService.Authenticate(ctx, apiKey, token)
+	if err != nil {
+		return nil, toStatus(err)
+	}
+	return auth.WithPrincipal(ctx, principal), nil
+}
+
+func isPublic(method string) bool {
+	for _, prefix := range publicServices {
+		if strings.HasPrefix(method, prefix) {
+			return true
+		}
+	}
+	return false
+}
+
+func isReplication(method string) bool {
+	return strings.HasPrefix(method, REPLICATION_SERVICE)
+}
+
+// reports whether the peer presented a client certificate the server
+// verified, which it only asks for once server.tlsclientauth is set
+func hasClientCertificate(ctx context.Context) bool {
+	p, ok := peer.FromContext(ctx)
+	if !ok {
+		return false
+	}
+	info, ok := p.AuthInfo.(credentials.TLSInfo)
+	return ok && len(info.State.VerifiedChains) != 0
+}
+
+// returns the token of a "Bearer <token>" authorization, empty otherwise
+func bearerToken(authorization string) string {
+	if len(authorization) < len(BEARER_PREFIX) ||
+		!strings.EqualFold(authorization[:len(BEARER_PREFIX)], BEARER_PREFIX) {
+		return ""
+	}
+	return strings.TrimSpace(authorization[len(BEARER_PREFIX):])
+}
+
+func first(values []string) string {
+	if len(values) == 0 {
+		return ""
+	}
+	return values[0]
+}
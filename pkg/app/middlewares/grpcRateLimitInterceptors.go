@@ -0,0 +1,154 @@
+package middlewares
+
+import (
+	"context"
+	"net"
+	"strings"
+
+	grpc_middleware "github.com/grpc-ecosystem/go-grpc-middleware"
+	"github.com/ramasbeinaty/trading-chart-service/pkg/domain/auth"
+	"github.com/ramasbeinaty/trading-chart-service/pkg/domain/ratelimit"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/peer"
+)
+
+// set by the http gateway to the address of the http client
+const FORWARDED_FOR_HEADER = "x-forwarded-for"
+
+// limits the rate of unary calls of the peer, before authentication, so the
+// failed attempts count too
+func PeerRateLimitUnaryInterceptor(
+	rateLimiter *ratelimit.RateLimiter,
+) grpc.UnaryServerInterceptor {
+	return func(
+		ctx context.Context,
+		req any,
+		info *grpc.UnaryServerInfo,
+		handler grpc.UnaryHandler,
+	) (any, error) {
+		if isPublic(info.FullMethod) || isReplication(info.FullMethod) {
+			return handler(ctx, req)
+		}
+
+		client := peerClient(ctx)
+		if err := rateLimiter.AllowUnary(client); err != nil {
+			return nil, toStatus(err)
+		}
+		return handler(ratelimit.WithClient(ctx, client), req)
+	}
+}
+
+// limits the streams the peer has open at once, before authentication
+func PeerRateLimitStreamInterceptor(
+	rateLimiter *ratelimit.RateLimiter,
+) grpc.StreamServerInterceptor {
+	return func(
+		srv any,
+		ss grpc.ServerStream,
+		info *grpc.StreamServerInfo,
+		handler grpc.StreamHandler,
+	) error {
+		if isPublic(info.FullMethod) || isReplication(info.FullMethod) {
+			return handler(srv, ss)
+		}
+
+		client := peerClient(ss.Context())
+		release, err := rateLimiter.AcquireStream(client)
+		if err != nil {
+			return toStatus(err)
+		}
+		defer release()
+
+		wrapped := grpc_middleware.WrapServerStream(ss)
+		wrapped.WrappedContext = ratelimit.WithClient(ss.Context(), client)
+		return handler(srv, wrapped)
+	}
+}
+
+// limits the rate of unary calls of the authenticated client, the others
+// being limited by peer only
+func RateLimitUnaryInterceptor(
+	rateLimiter *ratelimit.RateLimiter,
+) grpc.UnaryServerInterceptor {
+	return func(
+		ctx context.Context,
+		req any,
+		info *grpc.UnaryServerInfo,
+		handler grpc.UnaryHandler,
+	) (any, error) {
+		principal := auth.PrincipalFromContext(ctx)
+		if principal == nil || isPublic(info.FullMethod) || isReplication(info.FullMethod) {
+			return handler(ctx, req)
+		}
+
+		client := principal.String()
+		if err := rateLimiter.AllowUnary(client); err != nil {
+			return nil, toStatus(err)
+		}
+		return handler(ratelimit.WithClient(ctx, client), req)
+	}
+}
+
+// limits the streams the authenticated client has open at once, see
+// RateLimitUnaryInterceptor
+func RateLimitStreamInterceptor(
+	rateLimiter *ratelimit.RateLimiter,
+) grpc.StreamServerInterceptor {
+	return func(
+		srv any,
+		ss grpc.ServerStream,
+		info *grpc.StreamServerInfo,
+		handler grpc.StreamHandler,
+	) error {
+		principal := auth.PrincipalFromContext(ss.Context())
+		if principal == nil || isPublic(info.FullMethod) || isReplication(info.FullMethod) {
+			return handler(srv, ss)
+		}
+
+		client := principal.String()
+		release, err := rateLimiter.AcquireStream(client)
+		if err != nil {
+			return toStatus(err)
+		}
+		defer release()
+
+		wrapped := grpc_middleware.WrapServerStream(ss)
+		wrapped.WrappedContext = ratelimit.WithClient(ss.Context(), client)
+		return handler(srv, wrapped)
+	}
+}
+
+// the peer ip of the client
+// the calls of the http gateway, dialing over the loopback, are made on
+// behalf of the http client it appends the address of, the addresses before
+// it being set by the client
+func peerClient(ctx context.Context) string {
+	p, ok := peer.FromContext(ctx)
+	if !ok {
+		return ""
+	}
+	ip := hostOf(p.Addr.String())
+
+	if isLoopback(ip) {
+		md, _ := metadata.FromIncomingContext(ctx)
+		if forwarded := first(md.Get(FORWARDED_FOR_HEADER)); forwarded != "" {
+			addresses := strings.Split(forwarded, ",")
+			return strings.TrimSpace(addresses[len(addresses)-1])
+		}
+	}
+	return ip
+}
+
+func hostOf(address string) string {
+	host, _, err := net.SplitHostPort(address)
+	if err != nil {
+		return address
+	}
+	return host
+}
+
+func isLoopback(ip string) bool {
+	parsed := net.ParseIP(ip)
+	return parsed != nil && parsed.IsLoopback()
+}
@@ -0,0 +1,93 @@
+package middlewares
+
+import (
+	"context"
+	"net"
+	"testing"
+
+	"github.com/ramasbeinaty/trading-chart-service/internal/testutil"
+	"github.com/ramasbeinaty/trading-chart-service/pkg/domain/auth"
+	"github.com/ramasbeinaty/trading-chart-service/pkg/domain/ratelimit"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/peer"
+	"google.golang.org/grpc/status"
+)
+
+var unaryInfo = &grpc.UnaryServerInfo{FullMethod: "/alert.AlertService/ListAlerts"}
+
+func okHandler(context.Context, any) (any, error) {
+	return nil, nil
+}
+
+func failingHandler(context.Context, any) (any, error) {
+	return nil, status.Error(codes.Unauthenticated, "Invalid api key")
+}
+
+func peerContext(ip string) context.Context {
+	return peer.NewContext(context.Background(), &peer.Peer{
+		Addr: &net.TCPAddr{IP: net.ParseIP(ip), Port: 40000},
+	})
+}
+
+// one call per client, without refilling in the test
+func newTestLimiter() *ratelimit.RateLimiter {
+	return ratelimit.NewRateLimiter(
+		&ratelimit.RateLimitConfig{Default: ratelimit.Limits{UnaryRate: 0.001, UnaryBurst: 1}},
+		testutil.NopLogger{},
+	)
+}
+
+func TestPeerRateLimitCountsTheFailedAuthentications(t *testing.T) {
+	limiter := newTestLimiter()
+	interceptor := PeerRateLimitUnaryInterceptor(limiter)
+	ctx := peerContext("10.0.0.5")
+
+	if _, err := interceptor(ctx, nil, unaryInfo, failingHandler); status.Code(err) != codes.Unauthenticated {
+		t.Fatalf("expected the authentication to fail, got %v", err)
+	}
+	if _, err := interceptor(ctx, nil, unaryInfo, okHandler); status.Code(err) != codes.ResourceExhausted {
+		t.Fatalf("expected the peer to be limited, got %v", err)
+	}
+	if _, err := interceptor(peerContext("10.0.0.6"), nil, unaryInfo, okHandler); err != nil {
+		t.Fatalf("expected another peer to be allowed, got %v", err)
+	}
+}
+
+func TestRateLimitKeysTheClientByAuthenticationMethod(t *testing.T) {
+	limiter := newTestLimiter()
+	interceptor := RateLimitUnaryInterceptor(limiter)
+	apiKey := auth.WithPrincipal(context.Background(), &auth.Principal{ID: "alice", Method: auth.METHOD_API_KEY})
+	jwt := auth.WithPrincipal(context.Background(), &auth.Principal{ID: "alice", Method: auth.METHOD_JWT})
+
+	var client string
+	handler := func(ctx context.Context, _ any) (any, error) {
+		client = ratelimit.ClientFromContext(ctx)
+		return nil, nil
+	}
+
+	if _, err := interceptor(apiKey, nil, unaryInfo, handler); err != nil {
+		t.Fatal(err)
+	}
+	if client != "api_key:alice" {
+		t.Errorf("expected the client api_key:alice, got %s", client)
+	}
+	if _, err := interceptor(jwt, nil, unaryInfo, handler); err != nil {
+		t.Fatalf("expected the jwt subject not to share the api key's limits, got %v", err)
+	}
+	if _, err := interceptor(apiKey, nil, unaryInfo, handler); status.Code(err) != codes.ResourceExhausted {
+		t.Fatalf("expected the api key to be limited, got %v", err)
+	}
+}
+
+func TestRateLimitLeavesTheUnauthenticatedCallsToThePeerLimit(t *testing.T) {
+	limiter := newTestLimiter()
+	interceptor := RateLimitUnaryInterceptor(limiter)
+	ctx := ratelimit.WithClient(peerContext("10.0.0.5"), "10.0.0.5")
+
+	for i := 0; i < 2; i++ {
+		if _, err := interceptor(ctx, nil, unaryInfo, okHandler); err != nil {
+			t.Fatalf("expected the call not to be limited again, got %v", err)
+		}
+	}
+}
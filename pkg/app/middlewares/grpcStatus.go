@@ -0,0 +1,39 @@
+package middlewares
+
+import (
+	"errors"
+
+	"github.com/ramasbeinaty/trading-chart-service/pkg/domain/auth"
+	"github.com/ramasbeinaty/trading-chart-service/pkg/domain/ratelimit"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/durationpb"
+)
+
+// converts the authentication, authorization and limit errors to their
+// status, returning nil for other errors
+func toStatus(err error) error {
+	var limitErr *ratelimit.LimitError
+
+	switch {
+	case errors.Is(err, auth.ERR_UNAUTHENTICATED):
+		return status.Error(codes.Unauthenticated, err.Error())
+	case errors.Is(err, auth.ERR_PERMISSION_DENIED):
+		return status.Error(codes.PermissionDenied, err.Error())
+	case errors.As(err, &limitErr):
+		st := status.New(codes.ResourceExhausted, err.Error())
+		if limitErr.RetryAfter > 0 {
+			// tells the client when to retry, as the standard RetryInfo detail
+			detailed, detailsErr := st.WithDetails(&errdetails.RetryInfo{
+				RetryDelay: durationpb.New(limitErr.RetryAfter),
+			})
+			if detailsErr == nil {
+				st = detailed
+			}
+		}
+		return st.Err()
+	default:
+		return nil
+	}
+}
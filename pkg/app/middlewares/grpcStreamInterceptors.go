@@ -1,27 +1,40 @@
 package middlewares
 
 import (
-	"context"
+	"time"
 
-	"github.com/ramasbeinaty/trading-chart-service/internal"
-	"github.com/ramasbeinaty/trading-chart-service/pkg/app/middlewares/tracing"
+	"github.com/ramasbeinaty/trading-chart-service/pkg/domain/base/logger"
+	"github.com/ramasbeinaty/trading-chart-service/pkg/domain/base/metrics"
 	"go.uber.org/zap"
 	"google.golang.org/grpc"
 	"google.golang.org/grpc/codes"
-	"google.golang.org/grpc/metadata"
 	"google.golang.org/grpc/status"
 )
 
+// the stream's span is started by the otel stats handler beforehand,
+// from the W3C trace context in its metadata if any
 func DefaultStreamInterceptor(
-	lgr *zap.Logger,
+	lgrInstance logger.ILogger,
+	metrics metrics.IMetrics,
 ) grpc.StreamServerInterceptor {
 	return func(
 		srv any,
 		ss grpc.ServerStream,
 		info *grpc.StreamServerInfo,
 		handler grpc.StreamHandler,
-	) error {
+	) (err error) {
+		// record the stream once completed, with its final status
+		start := time.Now()
+		defer func() {
+			metrics.ObserveGrpcRequest(
+				info.FullMethod,
+				status.Code(err).String(),
+				time.Since(start),
+			)
+		}()
+
 		ctx := ss.Context()
+		lgr := lgrInstance.Get(ctx)
 
 		// log incoming stream requests
 		lgr.Info("Stream request received", zap.String("method", info.FullMethod))
@@ -35,21 +48,8 @@ func DefaultStreamInterceptor(
 			return status.Errorf(codes.Canceled, "stream context was cancelled: %v", err)
 		}
 
-		// get trace context
-		traceContext, err := tracing.GetTraceContext(ctx)
-		if err != nil {
-			lgr.Error("Failed to extract or generate trace context", zap.Error(err))
-		}
-		lgr.Info("Stream trace context received", zap.Any("traceContext", traceContext))
-
-		// add trace info to context
-		ctx = context.WithValue(ctx, internal.TRACE_CONTEXT_KEY, traceContext)
-
-		// wrap stream with traced context
-		streamWithContext := newStreamWithContext(ss, ctx)
-
 		// proceed to handle stream
-		err = handler(srv, streamWithContext)
+		err = handler(srv, ss)
 
 		// handle error if any
 		if err != nil {
@@ -59,6 +59,12 @@ func DefaultStreamInterceptor(
 				zap.Error(err),
 			)
 
+			// keep the code of authentication, authorization and limit errors
+			if statusErr := toStatus(err); statusErr != nil {
+				err = statusErr
+				return statusErr
+			}
+
 			// convert to gRPC status
 			if _, ok := status.FromError(err); !ok {
 				// default to an internal error if err can't be converted
@@ -77,7 +83,7 @@ func DefaultStreamInterceptor(
 }
 
 func RecoveryStreamInterceptor(
-	lgr *zap.Logger,
+	lgrInstance logger.ILogger,
 ) grpc.StreamServerInterceptor {
 	return func(
 		srv any,
@@ -87,7 +93,7 @@ func RecoveryStreamInterceptor(
 	) (err error) {
 		defer func() {
 			if r := recover(); r != nil {
-				lgr.Error(
+				lgrInstance.Get(ss.Context()).Error(
 					"Recovered from a panic in stream",
 					zap.Any("panic", r),
 					zap.String("method", info.FullMethod),
@@ -99,36 +105,3 @@ func RecoveryStreamInterceptor(
 		return handler(srv, ss)
 	}
 }
-
-type streamWithContext struct {
-	stream grpc.ServerStream
-	ctx    context.Context
-}
-
-func newStreamWithContext(ss grpc.ServerStream, ctx context.Context) grpc.ServerStream {
-	return &streamWithContext{stream: ss, ctx: ctx}
-}
-
-func (s *streamWithContext) Context() context.Context {
-	return s.ctx
-}
-
-func (s *streamWithContext) RecvMsg(m interface{}) error {
-	return s.stream.RecvMsg(m)
-}
-
-func (s *streamWithContext) SendMsg(m interface{}) error {
-	return s.stream.SendMsg(m)
-}
-
-func (s *streamWithContext) SetHeader(md metadata.MD) error {
-	return s.stream.SetHeader(md)
-}
-
-func (s *streamWithContext) SendHeader(md metadata.MD) error {
-	return s.stream.SendHeader(md)
-}
-
-func (s *streamWithContext) SetTrailer(md metadata.MD) {
-	s.stream.SetTrailer(md)
-}
@@ -2,17 +2,21 @@ package middlewares
 
 import (
 	"context"
+	"time"
 
-	"github.com/ramasbeinaty/trading-chart-service/internal"
-	"github.com/ramasbeinaty/trading-chart-service/pkg/app/middlewares/tracing"
+	"github.com/ramasbeinaty/trading-chart-service/pkg/domain/base/logger"
+	"github.com/ramasbeinaty/trading-chart-service/pkg/domain/base/metrics"
 	"go.uber.org/zap"
 	"google.golang.org/grpc"
 	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/status"
 )
 
+// the request's span is started by the otel stats handler beforehand,
+// from the W3C trace context in its metadata if any
 func DefaultUnaryInterceptor(
-	lgr *zap.Logger,
+	lgrInstance logger.ILogger,
+	metrics metrics.IMetrics,
 ) grpc.UnaryServerInterceptor {
 	return func(
 		ctx context.Context,
@@ -20,6 +24,18 @@ func DefaultUnaryInterceptor(
 		info *grpc.UnaryServerInfo,
 		handler grpc.UnaryHandler,
 	) (resp any, err error) {
+		// record the request once completed, with its final status
+		start := time.Now()
+		defer func() {
+			metrics.ObserveGrpcRequest(
+				info.FullMethod,
+				status.Code(err).String(),
+				time.Since(start),
+			)
+		}()
+
+		lgr := lgrInstance.Get(ctx)
+
 		// log incoming requests
 		lgr.Info("Request received", zap.String("method", info.FullMethod))
 
@@ -32,19 +48,6 @@ func DefaultUnaryInterceptor(
 			return nil, status.Errorf(codes.Canceled, "request context was cancelled: %v", err)
 		}
 
-		// get trace context
-		traceContext, err := tracing.GetTraceContext(ctx)
-		if err != nil {
-			lgr.Error("Failed to extract or generate trace context", zap.Error(err))
-		}
-		lgr.Info(
-			"Request trace context received",
-			zap.Any("traceContext", traceContext),
-		)
-
-		// add trace info to context
-		ctx = context.WithValue(ctx, internal.TRACE_CONTEXT_KEY, traceContext)
-
 		// proceed to handling request
 		resp, err = handler(ctx, req)
 
@@ -56,12 +59,19 @@ func DefaultUnaryInterceptor(
 				zap.Error(err),
 			)
 
+			// keep the code of authentication, authorization and limit errors
+			if statusErr := toStatus(err); statusErr != nil {
+				err = statusErr
+				return nil, statusErr
+			}
+
 			// convert to gRPC status
 			if _, ok := status.FromError(err); !ok {
 				// default to an internal error if err can't be converted
 				err = status.Errorf(codes.Internal, "An internal error occurred")
 				return nil, err
 			}
+			return nil, err
 		}
 
 		lgr.Info(
@@ -74,7 +84,7 @@ func DefaultUnaryInterceptor(
 }
 
 func RecoveryUnaryInterceptor(
-	lgr *zap.Logger,
+	lgrInstance logger.ILogger,
 ) grpc.UnaryServerInterceptor {
 	return func(
 		ctx context.Context,
@@ -84,7 +94,7 @@ func RecoveryUnaryInterceptor(
 	) (resp interface{}, err error) {
 		defer func() {
 			if r := recover(); r != nil {
-				lgr.Error(
+				lgrInstance.Get(ctx).Error(
 					"Recovered from a panic",
 					zap.Any("panic", r),
 					zap.String("method", info.FullMethod),
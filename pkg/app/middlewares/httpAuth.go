@@ -0,0 +1,47 @@
+package middlewares
+
+import (
+	"net/http"
+
+	"github.com/ramasbeinaty/trading-chart-service/pkg/domain/auth"
+)
+
+const (
+	// browsers can't set headers on EventSource and WebSocket requests
+	API_KEY_QUERY_PARAM      = "api_key"
+	ACCESS_TOKEN_QUERY_PARAM = "access_token"
+)
+
+// AuthHTTP authenticates the request with its X-Api-Key or bearer
+// Authorization header, or their api_key and access_token query parameters,
+// passing its principal on in the context
+func AuthHTTP(
+	authService *auth.AuthService,
+	next http.HandlerFunc,
+) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if !authService.Enabled() {
+			next(w, r)
+			return
+		}
+
+		query := r.URL.Query()
+		apiKey := r.Header.Get(API_KEY_HEADER)
+		if apiKey == "" {
+			apiKey = query.Get(API_KEY_QUERY_PARAM)
+		}
+		token := bearerToken(r.Header.Get(AUTHORIZATION_HEADER))
+		if token == "" {
+			token = query.Get(ACCESS_TOKEN_QUERY_PARAM)
+		}
+
+		principal, err := authService.Authenticate(r.Context(), apiKey, token)
+		if err != nil {
+			w.Header().Set("WWW-Authenticate", "Bearer")
+			http.Error(w, err.Error(), http.StatusUnauthorized)
+			return
+		}
+
+		next(w, r.WithContext(auth.WithPrincipal(r.Context(), principal)))
+	}
+}
@@ -0,0 +1,67 @@
+package middlewares
+
+import (
+	"errors"
+	"math"
+	"net/http"
+	"strconv"
+
+	"github.com/ramasbeinaty/trading-chart-service/pkg/domain/auth"
+	"github.com/ramasbeinaty/trading-chart-service/pkg/domain/ratelimit"
+)
+
+// RateLimitPeerHTTP counts the event stream served by next against the
+// streams the remote ip may have open at once, before authentication
+func RateLimitPeerHTTP(
+	rateLimiter *ratelimit.RateLimiter,
+	next http.HandlerFunc,
+) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		client := hostOf(r.RemoteAddr)
+		release, err := rateLimiter.AcquireStream(client)
+		if err != nil {
+			writeLimitError(w, err)
+			return
+		}
+		defer release()
+
+		next(w, r.WithContext(ratelimit.WithClient(r.Context(), client)))
+	}
+}
+
+// RateLimitHTTP counts the event stream served by next against the streams
+// the authenticated client may have open at once, the others being limited
+// by RateLimitPeerHTTP only
+func RateLimitHTTP(
+	rateLimiter *ratelimit.RateLimiter,
+	next http.HandlerFunc,
+) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		principal := auth.PrincipalFromContext(r.Context())
+		if principal == nil {
+			next(w, r)
+			return
+		}
+
+		client := principal.String()
+		release, err := rateLimiter.AcquireStream(client)
+		if err != nil {
+			writeLimitError(w, err)
+			return
+		}
+		defer release()
+
+		next(w, r.WithContext(ratelimit.WithClient(r.Context(), client)))
+	}
+}
+
+func writeLimitError(w http.ResponseWriter, err error) {
+	var limitErr *ratelimit.LimitError
+	if errors.As(err, &limitErr) && limitErr.RetryAfter > 0 {
+		w.Header().Set(
+			"Retry-After",
+			strconv.Itoa(int(math.Ceil(limitErr.RetryAfter.Seconds()))),
+		)
+	}
+	http.Error(w, err.Error(), http.StatusTooManyRequests)
+}
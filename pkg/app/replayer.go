@@ -0,0 +1,104 @@
+package app
+
+import (
+	"context"
+	"fmt"
+	"sync"
+
+	"github.com/ramasbeinaty/trading-chart-service/pkg/domain/replay"
+	"github.com/ramasbeinaty/trading-chart-service/pkg/domain/replication"
+	"github.com/ramasbeinaty/trading-chart-service/pkg/infra/tradefile"
+	"go.uber.org/zap"
+)
+
+// replaySource is the file of recorded trades ingested instead of the ones
+// streamed by binance
+type replaySource struct {
+	file  string
+	speed float64
+}
+
+// replayer replays the file once the replica leads, a single time even if
+// it leads again later
+type replayer struct {
+	lgr           *zap.Logger
+	source        *replaySource
+	replayService *replay.ReplayService
+
+	mutex    sync.Mutex
+	started  bool
+	cancel   context.CancelFunc
+	finished chan struct{} // closed once the replay returned
+	// receives the outcome of the replay
+	done chan error
+}
+
+var _ replication.ILeader = (*replayer)(nil)
+
+func newReplayer(
+	lgr *zap.Logger,
+	source *replaySource,
+	replayService *replay.ReplayService,
+) *replayer {
+	return &replayer{
+		lgr:           lgr,
+		source:        source,
+		replayService: replayService,
+		done:          make(chan error, 1),
+	}
+}
+
+func (r *replayer) Lead(
+	ctx context.Context,
+) error {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	if r.started {
+		return nil
+	}
+
+	reader, err := tradefile.NewTradeFileReader(r.source.file)
+	if err != nil {
+		return err
+	}
+	r.started = true
+
+	ctx, r.cancel = context.WithCancel(ctx)
+	r.finished = make(chan struct{})
+	go func() {
+		defer close(r.finished)
+		defer reader.Close()
+
+		r.lgr.Info(
+			"Replaying the trades",
+			zap.String("file", r.source.file),
+			zap.Float64("speed", r.source.speed),
+		)
+		_, err := r.replayService.Replay(ctx, reader, r.source.speed)
+		r.done <- err
+	}()
+
+	return nil
+}
+
+// stops the replay, which isn't resumed if leading again
+func (r *replayer) StepDown(
+	ctx context.Context,
+) error {
+	r.mutex.Lock()
+	cancel, finished := r.cancel, r.finished
+	r.mutex.Unlock()
+
+	if cancel == nil {
+		return nil
+	}
+	cancel()
+
+	select {
+	case <-finished:
+		return nil
+	case <-ctx.Done():
+		return fmt.Errorf("Failed to stop the replay - %w", ctx.Err())
+	}
+}
@@ -0,0 +1,88 @@
+package app
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/ramasbeinaty/trading-chart-service/pkg/app/lifecycle"
+)
+
+const (
+	SHUTDOWN_TIMEOUT = 30 * time.Second
+	// within the shutdown timeout
+	FLUSH_TIMEOUT        = 10 * time.Second
+	SERVERS_STOP_TIMEOUT = 5 * time.Second
+)
+
+// Shutdown stops the app in order, within SHUTDOWN_TIMEOUT:
+// the service reports it is not ready, ingestion stops once the received
+// trades are processed, the leader flushes its bars to the db and releases
+// the lock for another replica to take over, subscribers are told
+// the server is going away, the servers stop, then the background workers
+// started with the context are cancelled and waited for
+func Shutdown(
+	app *App,
+	grpc *Grpc,
+	cancel context.CancelFunc,
+	wg *sync.WaitGroup,
+) error {
+	l := lifecycle.NewLifecycle(app.Lgr, SHUTDOWN_TIMEOUT)
+
+	l.OnStop("readiness", func(ctx context.Context) error {
+		app.HealthService.ShutDown()
+		return nil
+	})
+	l.OnStop("ingestion", app.replicationService.Stop)
+	l.OnStop("bars", func(ctx context.Context) error {
+		// the followers' bars are the leader's to flush
+		if !app.replicationService.IsLeader() {
+			return nil
+		}
+		ctx, cancel := context.WithTimeout(ctx, FLUSH_TIMEOUT)
+		defer cancel()
+		return app.candlestickService.FlushBars(ctx)
+	})
+	l.OnStop("leadership", app.replicationService.Release)
+	l.OnStop("subscribers", func(ctx context.Context) error {
+		app.subscriptionService.Shutdown(ctx)
+		return nil
+	})
+	l.OnStop("servers", func(ctx context.Context) error {
+		ctx, cancel := context.WithTimeout(ctx, SERVERS_STOP_TIMEOUT)
+		defer cancel()
+		return grpc.Stop(ctx)
+	})
+	l.OnStop("workers", func(ctx context.Context) error {
+		cancel()
+		return waitGroup(ctx, wg)
+	})
+	l.OnStop("tracer", app.Tracer.Shutdown)
+	l.OnStop("db", func(ctx context.Context) error {
+		return app.DB.Close()
+	})
+
+	err := l.Stop()
+	app.LgrInstance.Close()
+	return err
+}
+
+// waits for the group until the context is done
+func waitGroup(
+	ctx context.Context,
+	wg *sync.WaitGroup,
+) error {
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		wg.Wait()
+	}()
+
+	select {
+	case <-done:
+		return nil
+	case <-ctx.Done():
+		return fmt.Errorf("Failed to wait for the workers - %w", ctx.Err())
+	}
+}
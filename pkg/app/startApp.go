@@ -9,72 +9,243 @@ import (
 
 	"github.com/ramasbeinaty/trading-chart-service/internal"
 	"github.com/ramasbeinaty/trading-chart-service/pkg/app/handlers"
+	"github.com/ramasbeinaty/trading-chart-service/pkg/domain/alert"
+	"github.com/ramasbeinaty/trading-chart-service/pkg/domain/auth"
 	"github.com/ramasbeinaty/trading-chart-service/pkg/domain/base/utils"
+	"github.com/ramasbeinaty/trading-chart-service/pkg/domain/bus"
 	"github.com/ramasbeinaty/trading-chart-service/pkg/domain/candlestick"
+	"github.com/ramasbeinaty/trading-chart-service/pkg/domain/export"
+	"github.com/ramasbeinaty/trading-chart-service/pkg/domain/health"
+	"github.com/ramasbeinaty/trading-chart-service/pkg/domain/indicator"
+	"github.com/ramasbeinaty/trading-chart-service/pkg/domain/ratelimit"
+	"github.com/ramasbeinaty/trading-chart-service/pkg/domain/replay"
+	"github.com/ramasbeinaty/trading-chart-service/pkg/domain/replication"
 	"github.com/ramasbeinaty/trading-chart-service/pkg/domain/subscription"
 	"github.com/ramasbeinaty/trading-chart-service/pkg/domain/uids"
+	"github.com/ramasbeinaty/trading-chart-service/pkg/domain/webhook"
+	"github.com/ramasbeinaty/trading-chart-service/pkg/infra/certs"
 	"github.com/ramasbeinaty/trading-chart-service/pkg/infra/clients/binance"
+	"github.com/ramasbeinaty/trading-chart-service/pkg/infra/clients/leaderclient"
+	"github.com/ramasbeinaty/trading-chart-service/pkg/infra/clients/natsbus"
 	"github.com/ramasbeinaty/trading-chart-service/pkg/infra/clients/snowflake"
+	"github.com/ramasbeinaty/trading-chart-service/pkg/infra/clients/webhookclient"
 	"github.com/ramasbeinaty/trading-chart-service/pkg/infra/config"
 	"github.com/ramasbeinaty/trading-chart-service/pkg/infra/db"
+	"github.com/ramasbeinaty/trading-chart-service/pkg/infra/jwks"
 	"github.com/ramasbeinaty/trading-chart-service/pkg/infra/logger"
+	"github.com/ramasbeinaty/trading-chart-service/pkg/infra/metrics"
+	"github.com/ramasbeinaty/trading-chart-service/pkg/infra/repos/alertrepo"
+	"github.com/ramasbeinaty/trading-chart-service/pkg/infra/repos/busrepo"
 	"github.com/ramasbeinaty/trading-chart-service/pkg/infra/repos/candlestickrepo"
+	"github.com/ramasbeinaty/trading-chart-service/pkg/infra/repos/replicationrepo"
+	"github.com/ramasbeinaty/trading-chart-service/pkg/infra/repos/webhookrepo"
+	"github.com/ramasbeinaty/trading-chart-service/pkg/infra/tracing"
+	"github.com/spf13/viper"
 	"go.uber.org/zap"
 )
 
 type App struct {
 	Lgr                *zap.Logger
+	LgrInstance        *logger.Logger
+	Tracer             *tracing.Tracer
+	ServerConfig       *internal.ServerConfig
+	CertReloader       *certs.CertReloader
 	DB                 *sql.DB
-	BinanceClient      *binance.BinanceClient
+	Metrics            *metrics.PrometheusMetrics
 	CandlestickHandler *handlers.CandlestickHandler
+	AlertHandler       *handlers.AlertHandler
+	HealthService      *health.HealthService
+	AuthService        *auth.AuthService
+	RateLimiter        *ratelimit.RateLimiter
+	HealthHandler      *handlers.HealthHandler
+	ReplicationHandler *handlers.ReplicationHandler
+	AdminHandler       *handlers.AdminHandler
+	ExportHandler      *handlers.ExportHandler
+
+	candlestickService  *candlestick.CandlestickService
+	subscriptionService *subscription.SubscriptionService
+	replicationService  *replication.ReplicationService
+	// receives the outcome of the replay once done, nil unless replaying
+	done <-chan error
+}
+
+// base holds the components every command starts with
+type base struct {
+	cfg         *viper.Viper
+	config      *config.Config
+	lgr         *zap.Logger
+	lgrInstance *logger.Logger
+}
+
+// reads and validates the config, from the config file and the env vars,
+// then starts the logger
+func newBase() (*base, error) {
+	cfg, err := config.NewConfig()
+	if err != nil {
+		return nil, fmt.Errorf("Failed to read the config - %w", err)
+	}
+	_config, err := config.LoadConfig(cfg)
+	if err != nil {
+		return nil, err
+	}
+
+	_lgrInstance, err := logger.NewLogger(_config.Log)
+	if err != nil {
+		return nil, fmt.Errorf("Failed to initialize logger - %w", err)
+	}
+
+	return &base{
+		cfg:         cfg,
+		config:      _config,
+		lgr:         _lgrInstance.Get(nil),
+		lgrInstance: _lgrInstance,
+	}, nil
 }
 
+// connects to the db, running the migrations not applied yet if migrate is
+// set, or failing unless they all are otherwise
+func newDB(
+	ctx context.Context,
+	b *base,
+	migrate bool,
+) (*sql.DB, error) {
+	_db, err := db.InitializeDB(b.config.DB)
+	if err != nil {
+		return nil, fmt.Errorf("Failed to connect to db - %w", err)
+	}
+
+	if migrate {
+		err = db.RunMigrations(ctx, b.lgr, _db, db.GetMigrationScripts())
+	} else {
+		err = db.CheckMigrations(ctx, _db, db.GetMigrationScripts())
+	}
+	if err != nil {
+		_db.Close()
+		return nil, err
+	}
+
+	return _db, nil
+}
+
+// StartAppService starts the service, the elected replica ingesting the
+// trades streamed by binance
 func StartAppService(
 	ctx context.Context,
 	wg *sync.WaitGroup,
+) *App {
+	b, err := newBase()
+	if err != nil {
+		panic(fmt.Errorf("Error: %w", err))
+	}
+	return startApp(ctx, wg, b, nil)
+}
+
+// starts the service, the elected replica ingesting the trades recorded in
+// the replayed file if any, or the ones streamed by binance otherwise
+func startApp(
+	ctx context.Context,
+	wg *sync.WaitGroup,
+	b *base,
+	_replay *replaySource,
 ) *App {
 	// ========= Setup infra layer =========
-	// env configs
-	cfg := config.NewConfig()
-	_envConfig := config.NewInternalEnvConfig(cfg)
-	_binanceConfig := config.NewBinanceConfig(cfg)
-	_dbConfig := config.NewDBConfig(cfg)
-	_snowflakeConfig := config.NewSnowflakeConfig(cfg)
+	cfg := b.cfg
+	_config := b.config
+	_envConfig := _config.Env
+	_serverConfig := _config.Server
+	_binanceConfig := _config.Binance
+	_dbConfig := _config.DB
+	_snowflakeConfig := _config.Snowflake
+	_candlestickConfig := _config.Candlestick
+	_webhookConfig := _config.Webhook
+	_busConfig := _config.Bus
+	_tracingConfig := _config.Tracing
+	_healthConfig := _config.Health
+	_replicationConfig := _config.Replication
+	_authConfig := _config.Auth
+	_rateLimitConfig := _config.RateLimit
+
+	// the replayed bars aren't delivered outside of the instance
+	if _replay != nil {
+		_webhookConfig = &webhook.WebhookConfig{}
+		_busConfig = &bus.BusConfig{}
+	}
 
 	// logger
-	_lgrInstance, err := logger.NewLogger()
+	_lgrInstance := b.lgrInstance
+	_lgr := b.lgr
+
+	// tracing
+	_tracer, err := tracing.NewTracer(ctx, _tracingConfig)
 	if err != nil {
-		panic("Error: Failed to initialize logger")
+		panic(fmt.Errorf("Error: Failed to initialize tracing - %w", err))
+	}
+
+	// tls, nil if disabled
+	_certReloader, err := certs.NewCertReloader(&_serverConfig.TLS, _lgrInstance)
+	if err != nil {
+		panic(fmt.Errorf("Error: Failed to load the tls certificate - %w", err))
+	}
+	if _certReloader != nil {
+		_certReloader.Start(ctx, wg)
+	}
+
+	// jwt verification, nil if disabled
+	var _tokenVerifier auth.ITokenVerifier
+	if _authConfig.JWT.Enabled {
+		_tokenVerifier, err = jwks.NewJWKSVerifier(&_authConfig.JWT)
+		if err != nil {
+			panic(fmt.Errorf("Error: Failed to load the jwks - %w", err))
+		}
 	}
-	_lgr := _lgrInstance.Get(nil)
+
+	// metrics
+	_metrics := metrics.NewPrometheusMetrics()
 
 	// db
-	_db, err := db.InitializeDB(_dbConfig)
+	// read-only instances can't migrate, they run on the ingesting ones' schema
+	_db, err := newDB(ctx, b, !_dbConfig.ReadOnly)
 	if err != nil {
-		panic("Error: Failed to connect to db")
+		panic(fmt.Errorf("Error: %w", err))
 	}
-	db.RunMigrations(
-		ctx,
-		_lgr,
-		_db,
-		db.GetMigrationScripts(),
-	)
 
 	// snowflake
 	_snowflakeClient := snowflake.NewSnowflakeClient(ctx, _snowflakeConfig)
 
-	// binance
-	tradeDataChan := make(chan binance.TradeMessageParsed)
-	_binanceClient := binance.NewBinanceClient(
-		tradeDataChan,
-		binance.AGG_TRADE_STREAM_NAME,
-		internal.TRADE_SYMBOLS,
-		ctx,
-		_binanceConfig,
+	// read-only instances stream the committed bars from the change feed
+	var _changeFeed *db.ChangeFeed
+	if _dbConfig.ReadOnly {
+		_changeFeed = db.NewChangeFeed(_dbConfig, _lgrInstance)
+	}
+
+	// replication
+	_leaderLock := db.NewAdvisoryLock(_db, _replicationConfig.LockKey)
+	_leaderClient := leaderclient.NewLeaderClient(
+		_certReloader.ClientCredentials(),
+		&_serverConfig.Keepalive,
+		_config.Auth.ReplicaToken,
 	)
 
+	// webhooks
+	_webhookClient := webhookclient.NewWebhookClient()
+
+	// message bus
+	var _busPublisher bus.IPublisher
+	if _busConfig.Enabled {
+		_busPublisher, err = natsbus.NewNatsPublisher(
+			_config.Nats,
+		)
+		if err != nil {
+			panic(fmt.Errorf("Error: Failed to connect to the message bus - %w", err))
+		}
+	}
+
 	// ========= Setup repositories =========
 	_candlestickrepo := candlestickrepo.NewCandlestickRepository(_db)
+	_alertrepo := alertrepo.NewAlertRepository(_db)
+	_webhookrepo := webhookrepo.NewWebhookRepository(_db)
+	_busrepo := busrepo.NewBusRepository(_db)
+	_replicationrepo := replicationrepo.NewReplicationRepository(_db)
 
 	// ========= Setup domain layer =========
 	_uidService := uids.NewUIDService(
@@ -82,13 +253,121 @@ func StartAppService(
 		_envConfig.IsDevMode,
 		_snowflakeClient,
 	)
+	_authService := auth.NewAuthService(
+		_authConfig,
+		_tokenVerifier,
+		_lgrInstance,
+	)
+	_rateLimiter := ratelimit.NewRateLimiter(_rateLimitConfig, _lgrInstance)
+	_rateLimiter.Start(ctx, wg)
+
+	_replicationService := replication.NewReplicationService(
+		_replicationConfig,
+		_leaderLock,
+		_replicationrepo,
+		_leaderClient,
+		_lgrInstance,
+	)
 
-	_subscriptionService := subscription.NewSubscriptionService(_lgrInstance)
+	_subscriptionService := subscription.NewSubscriptionService(
+		_lgrInstance,
+		_metrics,
+	)
+	// the replayed symbols are tracked once their first trade is
+	if _replay == nil {
+		for _, symbol := range _binanceConfig.Symbols {
+			_subscriptionService.TrackSymbol(ctx, symbol)
+		}
+	}
+
+	_indicatorService := indicator.NewIndicatorService()
+
+	// the stored alerts are loaded once leading, they don't fire on the
+	// replayed bars
+	_alertService := alert.NewAlertService(_alertrepo, _lgrInstance)
+
+	_webhookDispatcher := webhook.NewWebhookDispatcher(
+		_webhookConfig,
+		_webhookrepo,
+		_webhookClient,
+		_lgrInstance,
+	)
+	if _replay == nil {
+		_webhookDispatcher.Start(ctx, wg, _replicationService.IsLeader)
+	}
+
+	// fired alerts are delivered to the webhook targets too
+	_webhookListenerId, err := _uidService.GenerateUID()
+	if err != nil {
+		panic("Error: Failed to generate an id for the webhook alert listener")
+	}
+	_alertService.AddListener(ctx, &alert.Listener{
+		ID:   _webhookListenerId,
+		Sink: webhook.NewAlertSink(_webhookDispatcher),
+	})
+
+	_busService := bus.NewBusService(
+		_busConfig,
+		_busrepo,
+		_busPublisher,
+		_lgrInstance,
+	)
+	if _replay == nil {
+		_busService.Start(ctx, wg, _replicationService.IsLeader)
+	}
 
 	_candlestickService := candlestick.NewCandlestickService(
 		_candlestickrepo,
 		_lgrInstance,
+		_metrics,
 		_subscriptionService,
+		_indicatorService,
+		_alertService,
+		_webhookDispatcher,
+		_busService,
+	)
+
+	// the leader replays the file instead of streaming from binance
+	var (
+		_leader     replication.ILeader
+		_leadership *leadership
+		_replayer   *replayer
+	)
+	if _replay != nil {
+		_replayer = newReplayer(
+			_lgr,
+			_replay,
+			replay.NewReplayService(_candlestickService, _lgrInstance),
+		)
+		_leader = _replayer
+	} else {
+		_leadership = newLeadership(
+			_lgr,
+			_binanceConfig,
+			_candlestickConfig,
+			_metrics,
+			_candlestickService,
+			_alertService,
+		)
+		_leader = _leadership
+	}
+
+	_healthService := health.NewHealthService(_lgrInstance)
+	addHealthChecks(
+		_healthService,
+		_healthConfig,
+		_db,
+		_leadership,
+		_replicationService,
+		_changeFeed,
+		_candlestickService,
+	)
+
+	_exportService := export.NewExportService(
+		_config.Export,
+		_candlestickrepo,
+		exportFormats,
+		_lgrInstance,
 	)
 
 	// ========= Setup app layer =========
@@ -96,60 +375,160 @@ func StartAppService(
 		_candlestickService,
 		_subscriptionService,
 		_uidService,
+		_rateLimiter,
+		_serverConfig.AllowedOrigins,
 	)
-
-	// ========= Start the app =========
-	runAppService(
-		ctx,
-		_lgr,
-		wg,
-		&tradeDataChan,
-		_binanceClient,
+	_alertHandler := handlers.NewAlertHandler(
+		_alertService,
+		_replicationService,
+		_uidService,
+		_dbConfig.ReadOnly,
+	)
+	_healthHandler := handlers.NewHealthHandler(_healthService)
+	_replicationHandler := handlers.NewReplicationHandler(
 		_candlestickService,
+		_subscriptionService,
+		_replicationService,
+		_uidService,
+		_lgrInstance,
+	)
+	_adminHandler := handlers.NewAdminHandler(
+		_candlestickService,
+		_subscriptionService,
+		_replicationService,
 	)
+	_exportHandler := handlers.NewExportHandler(_exportService)
+
+	// ========= Start the app =========
+	// the changes of the config file that are safe to make live are applied
+	// as soon as it changes, the others on the next restart
+	if _leadership != nil {
+		_configReloader := newConfigReloader(
+			ctx,
+			_lgr,
+			_lgrInstance,
+			_config,
+			_subscriptionService,
+			_leadership,
+			_rateLimiter,
+		)
+		config.WatchConfig(cfg, _lgrInstance, _configReloader.apply)
+	}
+
+	if _changeFeed != nil {
+		// the bars committed by the ingesting instances are streamed from the db
+		err := _changeFeed.Start(ctx, wg, _candlestickService.ApplyBarChange)
+		if err != nil {
+			panic(fmt.Errorf("Error: Failed to start the change feed - %w", err))
+		}
+	} else {
+		// the elected replica ingests the trades, the others follow it
+		_replicationService.Start(ctx, _leader, _candlestickService)
+	}
+
+	// the replay ends the app once done, the ingestion from binance never does
+	var _done <-chan error
+	if _replayer != nil {
+		_done = _replayer.done
+	}
 
 	return &App{
 		_lgr,
+		_lgrInstance,
+		_tracer,
+		_serverConfig,
+		_certReloader,
 		_db,
-		_binanceClient,
+		_metrics,
 		_candlestickHandler,
+		_alertHandler,
+		_healthService,
+		_authService,
+		_rateLimiter,
+		_healthHandler,
+		_replicationHandler,
+		_adminHandler,
+		_exportHandler,
+		_candlestickService,
+		_subscriptionService,
+		_replicationService,
+		_done,
 	}
 }
 
-func (a *App) StopAppService() error {
-	a.Lgr.Info("Stopping app service...")
+// ingestion receives the trades and commits the bars they make up
+type ingestion struct {
+	binanceClient  *binance.BinanceClient
+	processed      chan struct{} // closed once every received trade is processed
+	stopTicker     context.CancelFunc
+	tickerDone     chan struct{}
+	checkpointDone chan struct{}
+}
+
+// stop closes the binance stream, waits for the trades received
+// to be processed, then stops committing and checkpointing bars
+func (i *ingestion) stop(
+	ctx context.Context,
+) error {
+	err := i.binanceClient.Close()
 
-	if err := a.BinanceClient.Close(); err != nil {
-		a.Lgr.Error("Failed to close binance client", zap.Error(err))
-		return err
+	select {
+	case <-i.processed:
+	case <-ctx.Done():
+		return fmt.Errorf("Failed to drain the received trades - %w", ctx.Err())
 	}
 
-	return nil
+	i.stopTicker()
+	select {
+	case <-i.tickerDone:
+	case <-ctx.Done():
+		return fmt.Errorf("Failed to stop the minute ticker - %w", ctx.Err())
+	}
+	select {
+	case <-i.checkpointDone:
+	case <-ctx.Done():
+		return fmt.Errorf("Failed to stop the checkpoint ticker - %w", ctx.Err())
+	}
+
+	return err
 }
 
 func runAppService(
 	ctx context.Context,
 	lgr *zap.Logger,
-	wg *sync.WaitGroup,
 	tradeDataChan *chan binance.TradeMessageParsed,
 	binanceClient *binance.BinanceClient,
 	candlestickService *candlestick.CandlestickService,
-) {
+	checkpointInterval func() time.Duration,
+	metrics *metrics.PrometheusMetrics,
+) (*ingestion, error) {
 	if tradeDataChan == nil {
-		panic(fmt.Errorf("Failed to start app service - tradeDataChan is nil"))
+		return nil, fmt.Errorf("Failed to start app service - tradeDataChan is nil")
+	}
+
+	// continue the bars in progress when the app or the previous leader stopped
+	if err := candlestickService.RestoreBars(ctx); err != nil {
+		lgr.Error(
+			"Error: failed to restore bars in progress",
+			zap.Error(err),
+		)
 	}
 
 	// connect to binance
 	if err := binanceClient.ConnectToBinance(); err != nil {
-		panic(fmt.Sprintf("Failed to connect to Binance - %s", err.Error()))
+		return nil, fmt.Errorf("Failed to connect to Binance - %w", err)
 	}
 
-	// process candlestick ticks
-	wg.Add(1)
+	// process candlestick ticks, until the channel is closed with the client
+	processed := make(chan struct{})
 	go func() {
-		defer wg.Done()
+		defer close(processed)
 		for trade := range *tradeDataChan {
 			fmt.Printf("Received trade: %v\n", trade)
+			metrics.ObserveTradeLag(
+				trade.Symbol,
+				time.Since(utils.ConvertUnixMillisToTime(trade.EventTime)),
+			)
 			err := candlestickService.ProcessTicks(
 				ctx,
 				trade.Symbol,
@@ -166,40 +545,114 @@ func runAppService(
 	}()
 
 	// start minute ticker to store complete candlestick bars every 1 minute
-	startMinuteTicker(
-		ctx,
+	tickerCtx, stopTicker := context.WithCancel(ctx)
+	tickerDone := startMinuteTicker(
+		tickerCtx,
+		lgr,
+		candlestickService,
+	)
+
+	// save the bars in progress periodically, for them to survive a crash
+	checkpointDone := startCheckpointTicker(
+		tickerCtx,
 		lgr,
-		wg,
 		candlestickService,
+		checkpointInterval,
 	)
+
+	return &ingestion{
+		binanceClient:  binanceClient,
+		processed:      processed,
+		stopTicker:     stopTicker,
+		tickerDone:     tickerDone,
+		checkpointDone: checkpointDone,
+	}, nil
 }
 
+// commits the complete bars at the start of every minute until the context
+// is done, returning a channel closed once stopped
 func startMinuteTicker(
 	ctx context.Context,
 	lgr *zap.Logger,
-	wg *sync.WaitGroup,
 	candlestickService *candlestick.CandlestickService,
-) {
-	now := time.Now().UTC()
+) chan struct{} {
+	done := make(chan struct{})
 
-	delay := time.Minute - time.Duration(now.Second())*time.Second -
-		time.Duration(now.Nanosecond())
+	go func() {
+		defer close(done)
+
+		now := time.Now().UTC()
+		delay := time.Minute - time.Duration(now.Second())*time.Second -
+			time.Duration(now.Nanosecond())
+
+		select {
+		case <-time.After(delay):
+		case <-ctx.Done():
+			return
+		}
 
-	time.AfterFunc(delay, func() {
 		ticker := time.NewTicker(time.Minute)
-		wg.Add(1)
-		go func() {
-			defer wg.Done()
-			for range ticker.C {
-				err := candlestickService.CommitCompleteBars(ctx)
-				if err != nil {
-					lgr.Error(
-						"Error: failed to commit complete bars",
-						zap.Error(err),
-					)
-				}
+		defer ticker.Stop()
+
+		for {
+			err := candlestickService.CommitCompleteBars(ctx)
+			if err != nil {
+				lgr.Error(
+					"Error: failed to commit complete bars",
+					zap.Error(err),
+				)
 			}
-		}()
-	},
-	)
+
+			select {
+			case <-ticker.C:
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+
+	return done
+}
+
+// saves the bars in progress every checkpoint interval until the context
+// is done, returning a channel closed once stopped
+// the interval is read after each checkpoint, so it may change live
+func startCheckpointTicker(
+	ctx context.Context,
+	lgr *zap.Logger,
+	candlestickService *candlestick.CandlestickService,
+	checkpointInterval func() time.Duration,
+) chan struct{} {
+	done := make(chan struct{})
+
+	go func() {
+		defer close(done)
+
+		interval := checkpointInterval()
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-ticker.C:
+			case <-ctx.Done():
+				return
+			}
+
+			if next := checkpointInterval(); next != interval {
+				interval = next
+				ticker.Reset(interval)
+			}
+
+			err := candlestickService.CheckpointBars(ctx)
+			if err != nil {
+				lgr.Error(
+					"Error: failed to checkpoint bars in progress",
+					zap.Error(err),
+				)
+			}
+		}
+	}()
+
+	return done
 }
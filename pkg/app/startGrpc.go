@@ -1,41 +1,100 @@
 package app
 
 import (
+	"context"
 	"fmt"
 	"net"
+	"net/http"
 	"sync"
 
 	grpc_middleware "github.com/grpc-ecosystem/go-grpc-middleware"
+	"github.com/ramasbeinaty/trading-chart-service/internal"
 	"github.com/ramasbeinaty/trading-chart-service/pkg/app/handlers"
 	"github.com/ramasbeinaty/trading-chart-service/pkg/app/middlewares"
+	"github.com/ramasbeinaty/trading-chart-service/pkg/domain/auth"
+	"github.com/ramasbeinaty/trading-chart-service/pkg/domain/base/logger"
+	"github.com/ramasbeinaty/trading-chart-service/pkg/domain/health"
+	"github.com/ramasbeinaty/trading-chart-service/pkg/domain/ratelimit"
+	"github.com/ramasbeinaty/trading-chart-service/pkg/infra/certs"
+	"github.com/ramasbeinaty/trading-chart-service/pkg/infra/metrics"
+	adminpb "github.com/ramasbeinaty/trading-chart-service/proto/admin/contracts"
+	alertpb "github.com/ramasbeinaty/trading-chart-service/proto/alert/contracts"
 	candlestickpb "github.com/ramasbeinaty/trading-chart-service/proto/candlestick/contracts"
+	exportpb "github.com/ramasbeinaty/trading-chart-service/proto/export/contracts"
+	replicationpb "github.com/ramasbeinaty/trading-chart-service/proto/replication/contracts"
+	"go.opentelemetry.io/contrib/instrumentation/google.golang.org/grpc/otelgrpc"
 	"go.uber.org/zap"
 	"google.golang.org/grpc"
+	grpchealth "google.golang.org/grpc/health"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+	"google.golang.org/grpc/keepalive"
 	"google.golang.org/grpc/reflection"
 )
 
 type Grpc struct {
-	server *grpc.Server
-	lgr    *zap.Logger
+	server       *grpc.Server
+	httpServer   *http.Server
+	healthServer *grpchealth.Server
+	lgr          *zap.Logger
 }
 
 func StartGRPCServer(
-	lgr *zap.Logger,
+	ctx context.Context,
+	lgrInstance logger.ILogger,
 	wg *sync.WaitGroup,
+	serverConfig *internal.ServerConfig,
+	certReloader *certs.CertReloader,
 	candlestickHandler *handlers.CandlestickHandler,
+	alertHandler *handlers.AlertHandler,
+	replicationHandler *handlers.ReplicationHandler,
+	adminHandler *handlers.AdminHandler,
+	exportHandler *handlers.ExportHandler,
+	healthService *health.HealthService,
+	authService *auth.AuthService,
+	rateLimiter *ratelimit.RateLimiter,
+	healthHandler *handlers.HealthHandler,
+	metrics *metrics.PrometheusMetrics,
 ) *Grpc {
+	lgr := lgrInstance.Get(ctx)
+
 	opts := []grpc.ServerOption{
+		// spans every call, continuing the W3C trace context of the caller
+		grpc.StatsHandler(otelgrpc.NewServerHandler()),
 		grpc.UnaryInterceptor(
 			grpc_middleware.ChainUnaryServer(
-				middlewares.RecoveryUnaryInterceptor(lgr),
-				middlewares.DefaultUnaryInterceptor(lgr),
+				middlewares.RecoveryUnaryInterceptor(lgrInstance),
+				middlewares.DefaultUnaryInterceptor(lgrInstance, metrics),
+				middlewares.PeerRateLimitUnaryInterceptor(rateLimiter),
+				middlewares.AuthUnaryInterceptor(authService),
+				middlewares.RateLimitUnaryInterceptor(rateLimiter),
 			),
 		),
 		grpc.StreamInterceptor(
 			grpc_middleware.ChainStreamServer(
-				middlewares.RecoveryStreamInterceptor(lgr),
-				middlewares.DefaultStreamInterceptor(lgr)),
+				middlewares.RecoveryStreamInterceptor(lgrInstance),
+				middlewares.DefaultStreamInterceptor(lgrInstance, metrics),
+				middlewares.PeerRateLimitStreamInterceptor(rateLimiter),
+				middlewares.AuthStreamInterceptor(authService),
+				middlewares.RateLimitStreamInterceptor(rateLimiter),
+			),
 		),
+		grpc.KeepaliveParams(keepalive.ServerParameters{
+			Time:                  serverConfig.Keepalive.Time,
+			Timeout:               serverConfig.Keepalive.Timeout,
+			MaxConnectionIdle:     serverConfig.Keepalive.MaxConnectionIdle,
+			MaxConnectionAge:      serverConfig.Keepalive.MaxConnectionAge,
+			MaxConnectionAgeGrace: serverConfig.Keepalive.MaxConnectionAgeGrace,
+		}),
+		grpc.KeepaliveEnforcementPolicy(keepalive.EnforcementPolicy{
+			MinTime:             serverConfig.Keepalive.MinTime,
+			PermitWithoutStream: serverConfig.Keepalive.PermitWithoutStream,
+		}),
+		grpc.MaxConcurrentStreams(serverConfig.MaxConcurrentStreams),
+		grpc.MaxRecvMsgSize(serverConfig.MaxRecvMsgSize),
+		grpc.MaxSendMsgSize(serverConfig.MaxSendMsgSize),
+	}
+	if certReloader != nil {
+		opts = append(opts, grpc.Creds(certReloader.ServerCredentials()))
 	}
 	s := grpc.NewServer(opts...)
 
@@ -44,14 +103,62 @@ func StartGRPCServer(
 		candlestickHandler,
 	)
 
+	alertpb.RegisterAlertServiceServer(
+		s,
+		alertHandler,
+	)
+
+	// internal, streams the leader's bar updates to the other replicas
+	replicationpb.RegisterReplicationServiceServer(
+		s,
+		replicationHandler,
+	)
+
+	// for the operators, restricted to the admins, so only served once some
+	// can authenticate
+	if authService.AdminEnabled() {
+		adminpb.RegisterAdminServiceServer(
+			s,
+			adminHandler,
+		)
+	} else {
+		lgr.Info("Admin service disabled, enable authentication and list AUTH_ADMINS to serve it")
+	}
+
+	exportpb.RegisterExportServiceServer(
+		s,
+		exportHandler,
+	)
+
+	// the standard health service, serving while the service is ready
+	healthServer := grpchealth.NewServer()
+	healthpb.RegisterHealthServer(s, healthServer)
+	services := []string{
+		"",
+		candlestickpb.CandlestickService_ServiceDesc.ServiceName,
+		alertpb.AlertService_ServiceDesc.ServiceName,
+		exportpb.ExportService_ServiceDesc.ServiceName,
+	}
+	healthService.Watch(ctx, wg, func(report *health.Report) {
+		status := healthpb.HealthCheckResponse_SERVING
+		if report.Status != health.STATUS_OK {
+			status = healthpb.HealthCheckResponse_NOT_SERVING
+		}
+		for _, service := range services {
+			healthServer.SetServingStatus(service, status)
+		}
+	})
+
 	// to query grpc server using grpcurl
-	reflection.Register(s)
+	if serverConfig.Reflection {
+		reflection.Register(s)
+	}
 
 	wg.Add(1)
 	go func() {
 		defer wg.Done()
 
-		lis, err := net.Listen("tcp", ":50051")
+		lis, err := net.Listen("tcp", serverConfig.GrpcAddress)
 		if err != nil {
 			panic(fmt.Errorf("failed to listen: %v", err))
 		}
@@ -59,6 +166,7 @@ func StartGRPCServer(
 		lgr.Info(
 			"gRPC server listening at",
 			zap.Any("Address", lis.Addr()),
+			zap.Bool("TLS", certReloader != nil),
 		)
 
 		if err := s.Serve(lis); err != nil {
@@ -66,12 +174,74 @@ func StartGRPCServer(
 		}
 	}()
 
+	// serve the http gateway and event streams alongside
+	httpServer := startHTTPServer(
+		ctx,
+		lgr,
+		wg,
+		serverConfig.HttpPort,
+		loopbackAddress(serverConfig.GrpcAddress),
+		certReloader.ClientCredentials(),
+		serverConfig.MaxSendMsgSize,
+		candlestickHandler,
+		exportHandler,
+		healthHandler,
+		authService,
+		rateLimiter,
+		metrics,
+	)
+
 	return &Grpc{
-		server: s,
+		server:       s,
+		httpServer:   httpServer,
+		healthServer: healthServer,
+		lgr:          lgr,
 	}
 }
 
-func (g *Grpc) StopGrpcServer() {
+// Stop stops accepting requests and waits for the ones in progress until
+// the context is done, closing the remaining ones then
+func (g *Grpc) Stop(
+	ctx context.Context,
+) error {
 	g.lgr.Info("Stopping grpc server...")
-	g.server.GracefulStop()
+
+	// reports NOT_SERVING from now on
+	g.healthServer.Shutdown()
+
+	httpErr := g.httpServer.Shutdown(ctx)
+	if httpErr != nil {
+		g.httpServer.Close()
+	}
+
+	stopped := make(chan struct{})
+	go func() {
+		defer close(stopped)
+		g.server.GracefulStop()
+	}()
+
+	select {
+	case <-stopped:
+	case <-ctx.Done():
+		// cancels the streams still open
+		g.server.Stop()
+		<-stopped
+	}
+
+	if httpErr != nil {
+		return fmt.Errorf("Failed to gracefully stop the http server - %w", httpErr)
+	}
+	return nil
+}
+
+// the address the http gateway dials the grpc server at, i.e. localhost
+// unless it listens on a given host
+func loopbackAddress(
+	address string,
+) string {
+	host, port, err := net.SplitHostPort(address)
+	if err != nil || host == "" || host == "0.0.0.0" || host == "::" {
+		return net.JoinHostPort("localhost", port)
+	}
+	return address
 }
@@ -0,0 +1,22 @@
+package app
+
+import "testing"
+
+func TestLoopbackAddress(t *testing.T) {
+	cases := []struct {
+		address  string
+		expected string
+	}{
+		{address: ":50051", expected: "localhost:50051"},
+		{address: "0.0.0.0:50051", expected: "localhost:50051"},
+		{address: "[::]:50051", expected: "localhost:50051"},
+		{address: "10.0.0.5:50051", expected: "10.0.0.5:50051"},
+		{address: "grpc.internal:50051", expected: "grpc.internal:50051"},
+	}
+
+	for _, c := range cases {
+		if got := loopbackAddress(c.address); got != c.expected {
+			t.Errorf("expected %s to be dialed at %s, got %s", c.address, c.expected, got)
+		}
+	}
+}
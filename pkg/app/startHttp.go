@@ -0,0 +1,150 @@
+package app
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"strings"
+	"sync"
+
+	"github.com/grpc-ecosystem/grpc-gateway/v2/runtime"
+	"github.com/ramasbeinaty/trading-chart-service/pkg/app/handlers"
+	"github.com/ramasbeinaty/trading-chart-service/pkg/app/middlewares"
+	"github.com/ramasbeinaty/trading-chart-service/pkg/domain/auth"
+	"github.com/ramasbeinaty/trading-chart-service/pkg/domain/ratelimit"
+	"github.com/ramasbeinaty/trading-chart-service/pkg/infra/metrics"
+	alertpb "github.com/ramasbeinaty/trading-chart-service/proto/alert/contracts"
+	candlestickpb "github.com/ramasbeinaty/trading-chart-service/proto/candlestick/contracts"
+	"go.opentelemetry.io/contrib/instrumentation/google.golang.org/grpc/otelgrpc"
+	"go.opentelemetry.io/contrib/instrumentation/net/http/otelhttp"
+	"go.uber.org/zap"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials"
+)
+
+// serves the REST routes annotated in the protos through a gateway to the
+// grpc server, alongside the Server-Sent Events and websocket streams, the
+// export downloads, the health probes and the prometheus metrics
+func startHTTPServer(
+	ctx context.Context,
+	lgr *zap.Logger,
+	wg *sync.WaitGroup,
+	port string,
+	grpcEndpoint string,
+	grpcCreds credentials.TransportCredentials,
+	grpcMaxMsgSize int,
+	candlestickHandler *handlers.CandlestickHandler,
+	exportHandler *handlers.ExportHandler,
+	healthHandler *handlers.HealthHandler,
+	authService *auth.AuthService,
+	rateLimiter *ratelimit.RateLimiter,
+	metrics *metrics.PrometheusMetrics,
+) *http.Server {
+	gwmux := runtime.NewServeMux(
+		// the authorization header is forwarded by default
+		runtime.WithIncomingHeaderMatcher(func(key string) (string, bool) {
+			if strings.EqualFold(key, middlewares.API_KEY_HEADER) {
+				return middlewares.API_KEY_HEADER, true
+			}
+			return runtime.DefaultHeaderMatcher(key)
+		}),
+	)
+	dialOpts := []grpc.DialOption{
+		grpc.WithTransportCredentials(grpcCreds),
+		// accepts any response the server may send
+		grpc.WithDefaultCallOptions(grpc.MaxCallRecvMsgSize(grpcMaxMsgSize)),
+		// carries the trace context of the http request over to the grpc server
+		grpc.WithStatsHandler(otelgrpc.NewClientHandler()),
+	}
+	err := candlestickpb.RegisterCandlestickServiceHandlerFromEndpoint(
+		ctx,
+		gwmux,
+		grpcEndpoint,
+		dialOpts,
+	)
+	if err != nil {
+		panic(fmt.Errorf("failed to register grpc gateway: %w", err))
+	}
+	err = alertpb.RegisterAlertServiceHandlerFromEndpoint(
+		ctx,
+		gwmux,
+		grpcEndpoint,
+		dialOpts,
+	)
+	if err != nil {
+		panic(fmt.Errorf("failed to register grpc gateway: %w", err))
+	}
+
+	mux := http.NewServeMux()
+	mux.HandleFunc(
+		"GET /api/v1/candlestick/stream",
+		middlewares.RateLimitPeerHTTP(
+			rateLimiter,
+			middlewares.AuthHTTP(
+				authService,
+				middlewares.RateLimitHTTP(rateLimiter, candlestickHandler.StreamCandlesticksSSE),
+			),
+		),
+	)
+	mux.HandleFunc(
+		"GET /api/v1/candlestick/ws",
+		middlewares.RateLimitPeerHTTP(
+			rateLimiter,
+			middlewares.AuthHTTP(
+				authService,
+				middlewares.RateLimitHTTP(rateLimiter, candlestickHandler.StreamCandlesticksWS),
+			),
+		),
+	)
+	mux.HandleFunc(
+		"GET /api/v1/candlestick/export",
+		middlewares.RateLimitPeerHTTP(
+			rateLimiter,
+			middlewares.AuthHTTP(
+				authService,
+				middlewares.RateLimitHTTP(rateLimiter, exportHandler.DownloadCandlesticks),
+			),
+		),
+	)
+	mux.HandleFunc("GET /healthz", healthHandler.Liveness)
+	mux.HandleFunc("GET /readyz", healthHandler.Readiness)
+	mux.Handle("GET /metrics", metrics.Handler())
+	mux.Handle("/", gwmux)
+
+	// cancelled on shutdown, so open event streams don't keep it waiting
+	baseCtx, cancel := context.WithCancel(ctx)
+
+	s := &http.Server{
+		Addr: ":" + port,
+		// spans every request, continuing the W3C trace context of the caller
+		Handler: otelhttp.NewHandler(
+			mux,
+			"http",
+			otelhttp.WithSpanNameFormatter(func(_ string, r *http.Request) string {
+				return r.Method + " " + r.URL.Path
+			}),
+		),
+		BaseContext: func(net.Listener) context.Context {
+			return baseCtx
+		},
+	}
+	s.RegisterOnShutdown(cancel)
+
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+
+		lgr.Info(
+			"HTTP server listening at",
+			zap.String("Address", s.Addr),
+		)
+
+		if err := s.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
+			panic(fmt.Errorf("failed to serve http server: %w", err))
+		}
+	}()
+
+	return s
+}
@@ -0,0 +1,182 @@
+package alert
+
+import (
+	"time"
+
+	"github.com/ramasbeinaty/trading-chart-service/pkg/domain/indicator"
+)
+
+// timeframeBar is a bar aggregated to a timeframe from the 1 minute bars
+type timeframeBar struct {
+	Timestamp time.Time // open time
+	Open      float64
+	High      float64
+	Low       float64
+	Close     float64
+}
+
+// series aggregates a symbol's 1 minute bars into the bar in progress
+// of a timeframe
+type series struct {
+	timeframe indicator.Timeframe
+	bucket    time.Time // open time of the bar in progress
+	started   bool
+	closed    bool                // the bar in progress was closed, waiting for the next one
+	minutes   map[int64]minuteBar // latest version of each minute of the bar, keyed by unix time
+}
+
+type minuteBar struct {
+	timestamp time.Time
+	bar       Bar
+}
+
+func newSeries(timeframe indicator.Timeframe) *series {
+	return &series{
+		timeframe: timeframe,
+		minutes:   make(map[int64]minuteBar),
+	}
+}
+
+// applies the 1 minute bar update, returning the bar it closed if it opened
+// a new timeframe bar, and whether it changed the bar in progress
+// updates of bars older than the one in progress, or of a closed one,
+// are ignored
+func (s *series) update(bar Bar) (closed *timeframeBar, updated bool) {
+	bucket := bar.Timestamp.Truncate(s.timeframe.Duration())
+
+	if s.started {
+		if bucket.Before(s.bucket) || (s.closed && bucket.Equal(s.bucket)) {
+			return nil, false
+		}
+		if bucket.After(s.bucket) {
+			if !s.closed {
+				closed = s.aggregate()
+			}
+			s.minutes = make(map[int64]minuteBar)
+			s.closed = false
+		}
+	}
+
+	s.bucket = bucket
+	s.started = true
+	s.minutes[bar.Timestamp.Unix()] = minuteBar{timestamp: bar.Timestamp, bar: bar}
+
+	return closed, true
+}
+
+// closes the bar in progress if its timeframe ended by the given time
+func (s *series) close(now time.Time) *timeframeBar {
+	if !s.started || s.closed || now.Before(s.bucket.Add(s.timeframe.Duration())) {
+		return nil
+	}
+
+	s.closed = true
+	return s.aggregate()
+}
+
+func (s *series) aggregate() *timeframeBar {
+	var (
+		agg         *timeframeBar
+		first, last time.Time
+	)
+
+	for _, m := range s.minutes {
+		if agg == nil {
+			agg = &timeframeBar{
+				Timestamp: s.bucket,
+				Open:      m.bar.Open,
+				High:      m.bar.High,
+				Low:       m.bar.Low,
+				Close:     m.bar.Close,
+			}
+			first, last = m.timestamp, m.timestamp
+			continue
+		}
+
+		agg.High = max(agg.High, m.bar.High)
+		agg.Low = min(agg.Low, m.bar.Low)
+		if m.timestamp.Before(first) {
+			first = m.timestamp
+			agg.Open = m.bar.Open
+		}
+		if m.timestamp.After(last) {
+			last = m.timestamp
+			agg.Close = m.bar.Close
+		}
+	}
+
+	return agg
+}
+
+func (s *series) current() *timeframeBar {
+	if !s.started || s.closed {
+		return nil
+	}
+	return s.aggregate()
+}
+
+// rule is an active alert with its evaluation state
+type rule struct {
+	alert *Alert
+	// whether the alert can fire once its condition holds
+	// crossing alerts are armed once the metric was seen on the other side
+	// of the threshold, the others right away
+	// rearm alerts are disarmed when they fire, until the condition stops holding
+	armed bool
+}
+
+func newRule(alert *Alert) *rule {
+	crossing := alert.Condition == CONDITION_CROSSES_ABOVE ||
+		alert.Condition == CONDITION_CROSSES_BELOW
+
+	return &rule{
+		alert: alert,
+		armed: !crossing,
+	}
+}
+
+// evaluates the rule on the bar, returning the metric's value
+// and whether the alert fires
+func (r *rule) evaluate(bar *timeframeBar) (float64, bool) {
+	value := metricValue(r.alert.Metric, bar)
+
+	var holds bool
+	switch r.alert.Condition {
+	case CONDITION_CROSSES_ABOVE:
+		holds = value >= r.alert.Threshold
+	case CONDITION_CROSSES_BELOW:
+		holds = value <= r.alert.Threshold
+	case CONDITION_ABOVE:
+		holds = value > r.alert.Threshold
+	case CONDITION_BELOW:
+		holds = value < r.alert.Threshold
+	}
+
+	if !holds {
+		r.armed = true
+		return value, false
+	}
+	if !r.armed {
+		return value, false
+	}
+
+	r.armed = false
+	return value, true
+}
+
+func metricValue(metric Metric, bar *timeframeBar) float64 {
+	switch metric {
+	case METRIC_RANGE_PERCENT:
+		if bar.Low == 0 {
+			return 0
+		}
+		return (bar.High - bar.Low) / bar.Low * 100
+	case METRIC_CHANGE_PERCENT:
+		if bar.Open == 0 {
+			return 0
+		}
+		return (bar.Close - bar.Open) / bar.Open * 100
+	default:
+		return bar.Close
+	}
+}
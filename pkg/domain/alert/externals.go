@@ -0,0 +1,39 @@
+package alert
+
+import "context"
+
+type IRepository interface {
+	CreateAlert(
+		ctx context.Context,
+		alert *Alert,
+	) error
+	// returns nil if the alert does not exist
+	GetAlert(
+		ctx context.Context,
+		id int64,
+	) (*Alert, error)
+	// returns the alerts of every symbol if symbol is empty
+	GetAlerts(
+		ctx context.Context,
+		symbol string,
+	) ([]*Alert, error)
+	GetActiveAlerts(
+		ctx context.Context,
+	) ([]*Alert, error)
+	// replaces the rule of the alert, re-activating it
+	// returns false if the alert does not exist
+	UpdateAlert(
+		ctx context.Context,
+		alert *Alert,
+	) (bool, error)
+	// stores the status, fire count and last fired time of the alert
+	UpdateAlertFired(
+		ctx context.Context,
+		alert *Alert,
+	) error
+	// returns false if the alert does not exist
+	DeleteAlert(
+		ctx context.Context,
+		id int64,
+	) (bool, error)
+}
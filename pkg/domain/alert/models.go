@@ -0,0 +1,170 @@
+package alert
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/ramasbeinaty/trading-chart-service/pkg/domain/auth"
+	"github.com/ramasbeinaty/trading-chart-service/pkg/domain/indicator"
+)
+
+type Metric string
+
+const (
+	METRIC_CLOSE          Metric = "close"          // the close price of the bar
+	METRIC_RANGE_PERCENT  Metric = "range_percent"  // (high - low) / low, in percent
+	METRIC_CHANGE_PERCENT Metric = "change_percent" // (close - open) / open, in percent
+)
+
+type Condition string
+
+const (
+	// the metric goes from below the threshold to at or above it
+	CONDITION_CROSSES_ABOVE Condition = "crosses_above"
+	// the metric goes from above the threshold to at or below it
+	CONDITION_CROSSES_BELOW Condition = "crosses_below"
+	CONDITION_ABOVE         Condition = "above"
+	CONDITION_BELOW         Condition = "below"
+)
+
+type Mode string
+
+const (
+	// fires once, then stays triggered
+	MODE_ONCE Mode = "once"
+	// fires again once its condition stopped holding and holds again
+	MODE_REARM Mode = "rearm"
+)
+
+type Status string
+
+const (
+	STATUS_ACTIVE    Status = "active"
+	STATUS_TRIGGERED Status = "triggered" // a once alert that fired
+)
+
+// Alert is a rule evaluated on the bars of a symbol, aggregated
+// to its timeframe from the 1 minute bars
+type Alert struct {
+	ID        int64
+	Symbol    string
+	Timeframe indicator.Timeframe
+	Metric    Metric
+	Condition Condition
+	Threshold float64
+	Mode      Mode
+	OnClose   bool // only evaluate closed bars, otherwise every bar update
+	// the principal that created the alert, see auth.Principal.String, empty
+	// if authentication was disabled
+	Owner       string
+	Status      Status
+	FireCount   uint64
+	LastFiredAt time.Time // zero until the alert fires
+	CreatedAt   time.Time
+	UpdatedAt   time.Time
+}
+
+// reports whether the alerts are evaluated the same, whatever their status
+// and fires
+func (a *Alert) sameRule(other *Alert) bool {
+	return a.Symbol == other.Symbol &&
+		a.Timeframe == other.Timeframe &&
+		a.Metric == other.Metric &&
+		a.Condition == other.Condition &&
+		a.Threshold == other.Threshold &&
+		a.Mode == other.Mode &&
+		a.OnClose == other.OnClose
+}
+
+func (a *Alert) Validate() error {
+	if a.Symbol == "" {
+		return fmt.Errorf("Invalid alert - symbol must not be empty")
+	}
+	if a.Timeframe.Duration() == 0 {
+		return fmt.Errorf("Invalid alert - unsupported timeframe %s", a.Timeframe)
+	}
+
+	switch a.Metric {
+	case METRIC_CLOSE, METRIC_RANGE_PERCENT, METRIC_CHANGE_PERCENT:
+	default:
+		return fmt.Errorf("Invalid alert - unknown metric %s", a.Metric)
+	}
+
+	switch a.Condition {
+	case CONDITION_CROSSES_ABOVE, CONDITION_CROSSES_BELOW, CONDITION_ABOVE, CONDITION_BELOW:
+	default:
+		return fmt.Errorf("Invalid alert - unknown condition %s", a.Condition)
+	}
+
+	switch a.Mode {
+	case MODE_ONCE, MODE_REARM:
+	default:
+		return fmt.Errorf("Invalid alert - unknown mode %s", a.Mode)
+	}
+
+	if a.Metric == METRIC_CLOSE && a.Threshold <= 0 {
+		return fmt.Errorf("Invalid alert - close threshold must be positive")
+	}
+
+	return nil
+}
+
+// OwnedBy reports whether the principal may access the alert, every alert
+// being accessible if authentication is disabled
+func (a *Alert) OwnedBy(principal *auth.Principal) bool {
+	return principal == nil || a.Owner == principal.String()
+}
+
+// Bar is a symbol's 1 minute bar as of its latest update
+type Bar struct {
+	Symbol    string
+	Timestamp time.Time
+	Open      float64
+	High      float64
+	Low       float64
+	Close     float64
+}
+
+// Fired is an alert firing, published to the listeners
+type Fired struct {
+	Alert        Alert // as of after it fired
+	Value        float64
+	BarTimestamp time.Time // open time of the timeframe bar it fired on
+	FiredAt      time.Time
+}
+
+// Sink is the transport a listener receives the fired alerts on
+type Sink interface {
+	// Send delivers the fired alert to the listener
+	Send(fired *Fired) error
+	// Close terminates the transport from the server side
+	Close()
+	// Done is closed once the transport is terminated, from either side
+	Done() <-chan struct{}
+}
+
+// Listener receives the fired alerts of its owner matching either its alert
+// ids or symbols, or every fired alert of its owner if both are empty
+type Listener struct {
+	ID       int64
+	AlertIDs map[int64]bool
+	Symbols  map[string]bool
+	Owner    *auth.Principal // nil if authentication is disabled
+	Sink     Sink
+}
+
+// delivery is a fired alert with the listeners matching it as it fired
+type delivery struct {
+	fired     *Fired
+	listeners []*Listener
+}
+
+func (l *Listener) matches(fired *Fired) bool {
+	if !fired.Alert.OwnedBy(l.Owner) {
+		return false
+	}
+	if len(l.AlertIDs) == 0 && len(l.Symbols) == 0 {
+		return true
+	}
+	return l.AlertIDs[fired.Alert.ID] || l.Symbols[fired.Alert.Symbol]
+}
@@ -0,0 +1,530 @@
+package alert
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/ramasbeinaty/trading-chart-service/pkg/domain/auth"
+	"github.com/ramasbeinaty/trading-chart-service/pkg/domain/base/logger"
+	"github.com/ramasbeinaty/trading-chart-service/pkg/domain/indicator"
+	"go.uber.org/zap"
+)
+
+// AlertService evaluates the active alerts on the bar updates and closes
+// of their symbols, publishing the fired ones to the listeners
+type AlertService struct {
+	repo      IRepository
+	lgr       logger.ILogger
+	rules     map[string]map[int64]*rule                 // active alerts keyed by symbol then alert id
+	series    map[string]map[indicator.Timeframe]*series // keyed by symbol then timeframe
+	listeners map[int64]*Listener                        // keyed by listener id
+	mutex     sync.Mutex
+	// held from evaluating the alerts until the fired ones are stored and
+	// published, for the firings to be stored and published in order
+	// without holding the mutex
+	firing sync.Mutex
+}
+
+func NewAlertService(
+	repo IRepository,
+	lgr logger.ILogger,
+) *AlertService {
+	return &AlertService{
+		repo:      repo,
+		lgr:       lgr,
+		rules:     make(map[string]map[int64]*rule),
+		series:    make(map[string]map[indicator.Timeframe]*series),
+		listeners: make(map[int64]*Listener),
+		mutex:     sync.Mutex{},
+		firing:    sync.Mutex{},
+	}
+}
+
+// LoadAlerts evaluates the stored active alerts in place of the ones
+// evaluated so far, which other replicas may have changed meanwhile
+// the alerts evaluated the same as before keep their state
+func (a *AlertService) LoadAlerts(
+	ctx context.Context,
+) error {
+	lgr := a.lgr.Get(ctx)
+
+	alerts, err := a.repo.GetActiveAlerts(ctx)
+	if err != nil {
+		return fmt.Errorf("Failed to load active alerts - %w", err)
+	}
+
+	a.mutex.Lock()
+	defer a.mutex.Unlock()
+
+	loaded := make(map[int64]*Alert, len(alerts))
+	for _, alert := range alerts {
+		loaded[alert.ID] = alert
+	}
+
+	// the alerts deleted, fired or changed since are dropped
+	stale := []int64{}
+	for _, rules := range a.rules {
+		for id, r := range rules {
+			if alert, exists := loaded[id]; !exists || !alert.sameRule(r.alert) {
+				stale = append(stale, id)
+			}
+		}
+	}
+	for _, id := range stale {
+		a.removeRule(id)
+	}
+
+	for _, alert := range alerts {
+		if _, exists := a.rules[alert.Symbol][alert.ID]; !exists {
+			a.setRule(alert)
+		}
+	}
+
+	lgr.Info("Loaded active alerts", zap.Int("alerts", len(alerts)))
+
+	return nil
+}
+
+// CreateAlert stores the alert, owned by the principal of the request
+func (a *AlertService) CreateAlert(
+	ctx context.Context,
+	alert *Alert,
+) error {
+	if err := alert.Validate(); err != nil {
+		return err
+	}
+
+	if principal := auth.PrincipalFromContext(ctx); principal != nil {
+		alert.Owner = principal.String()
+	}
+
+	now := time.Now().UTC()
+	alert.Status = STATUS_ACTIVE
+	alert.CreatedAt = now
+	alert.UpdatedAt = now
+
+	if err := a.repo.CreateAlert(ctx, alert); err != nil {
+		return fmt.Errorf("Failed to create alert - %w", err)
+	}
+
+	a.mutex.Lock()
+	defer a.mutex.Unlock()
+
+	a.setRule(alert)
+
+	return nil
+}
+
+// GetAlert fails unless the alert belongs to the principal of the request
+func (a *AlertService) GetAlert(
+	ctx context.Context,
+	id int64,
+) (*Alert, error) {
+	alert, err := a.repo.GetAlert(ctx, id)
+	if err != nil {
+		return nil, fmt.Errorf("Failed to get alert %d - %w", id, err)
+	}
+	if alert == nil {
+		return nil, fmt.Errorf("Alert %d not found", id)
+	}
+	if !alert.OwnedBy(auth.PrincipalFromContext(ctx)) {
+		return nil, fmt.Errorf("%w - alert %d belongs to another client", auth.ERR_PERMISSION_DENIED, id)
+	}
+
+	return alert, nil
+}
+
+// returns the alerts of the principal of the request, of every symbol if
+// symbol is empty
+func (a *AlertService) ListAlerts(
+	ctx context.Context,
+	symbol string,
+) ([]*Alert, error) {
+	alerts, err := a.repo.GetAlerts(ctx, symbol)
+	if err != nil {
+		return nil, fmt.Errorf("Failed to list alerts - %w", err)
+	}
+
+	principal := auth.PrincipalFromContext(ctx)
+	owned := make([]*Alert, 0, len(alerts))
+	for _, alert := range alerts {
+		if alert.OwnedBy(principal) {
+			owned = append(owned, alert)
+		}
+	}
+
+	return owned, nil
+}
+
+// UpdateAlert replaces the rule of the alert, re-activating it
+// with a fresh evaluation state
+// fails unless the alert belongs to the principal of the request
+func (a *AlertService) UpdateAlert(
+	ctx context.Context,
+	alert *Alert,
+) (*Alert, error) {
+	if err := alert.Validate(); err != nil {
+		return nil, err
+	}
+
+	existing, err := a.GetAlert(ctx, alert.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	alert.Owner = existing.Owner
+	alert.Status = STATUS_ACTIVE
+
+	exists, err := a.repo.UpdateAlert(ctx, alert)
+	if err != nil {
+		return nil, fmt.Errorf("Failed to update alert %d - %w", alert.ID, err)
+	}
+	if !exists {
+		return nil, fmt.Errorf("Alert %d not found", alert.ID)
+	}
+
+	updated, err := a.GetAlert(ctx, alert.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	a.mutex.Lock()
+	defer a.mutex.Unlock()
+
+	a.removeRule(alert.ID)
+	a.setRule(updated)
+
+	return updated, nil
+}
+
+// DeleteAlert fails unless the alert belongs to the principal of the request
+func (a *AlertService) DeleteAlert(
+	ctx context.Context,
+	id int64,
+) error {
+	if _, err := a.GetAlert(ctx, id); err != nil {
+		return err
+	}
+
+	exists, err := a.repo.DeleteAlert(ctx, id)
+	if err != nil {
+		return fmt.Errorf("Failed to delete alert %d - %w", id, err)
+	}
+	if !exists {
+		return fmt.Errorf("Alert %d not found", id)
+	}
+
+	a.mutex.Lock()
+	defer a.mutex.Unlock()
+
+	a.removeRule(id)
+
+	return nil
+}
+
+func (a *AlertService) AddListener(
+	ctx context.Context,
+	listener *Listener,
+) {
+	a.mutex.Lock()
+	defer a.mutex.Unlock()
+
+	lgr := a.lgr.Get(ctx)
+	lgr.Info("Adding an alert listener", zap.Int64("listenerId", listener.ID))
+
+	a.listeners[listener.ID] = listener
+}
+
+func (a *AlertService) RemoveListener(
+	ctx context.Context,
+	listenerId int64,
+) {
+	a.mutex.Lock()
+	listener, exists := a.listeners[listenerId]
+	delete(a.listeners, listenerId)
+	a.mutex.Unlock()
+
+	// the stream is terminated without holding the mutex, as it waits on the
+	// alert being sent, if any
+	if exists {
+		listener.Sink.Close()
+	}
+}
+
+// RemoveListeners removes every listener and terminates their streams, for
+// them to listen to the next leader
+func (a *AlertService) RemoveListeners(
+	ctx context.Context,
+) {
+	a.mutex.Lock()
+	removed := make([]*Listener, 0, len(a.listeners))
+	for id, listener := range a.listeners {
+		delete(a.listeners, id)
+		removed = append(removed, listener)
+	}
+	a.mutex.Unlock()
+
+	lgr := a.lgr.Get(ctx)
+	lgr.Info("Removing the alert listeners", zap.Int("listeners", len(removed)))
+
+	// the streams are terminated without holding the mutex, as they wait on
+	// the alert being sent, if any
+	for _, listener := range removed {
+		listener.Sink.Close()
+	}
+}
+
+// OnBarUpdate evaluates the alerts of the bar's symbol on the updated bar of
+// their timeframe, and the close alerts on the bar it closed, if any
+// the fired alerts are stored and published once evaluated, without holding
+// the mutex
+func (a *AlertService) OnBarUpdate(
+	ctx context.Context,
+	bar Bar,
+) {
+	a.firing.Lock()
+	defer a.firing.Unlock()
+
+	a.deliver(ctx, a.evaluateBar(ctx, bar))
+}
+
+// evaluates the alerts of the bar's symbol, returning the fired ones with
+// their listeners
+func (a *AlertService) evaluateBar(
+	ctx context.Context,
+	bar Bar,
+) []*delivery {
+	a.mutex.Lock()
+	defer a.mutex.Unlock()
+
+	rules := a.rules[bar.Symbol]
+	if len(rules) == 0 {
+		return nil
+	}
+
+	fired := []*Fired{}
+	for timeframe, s := range a.series[bar.Symbol] {
+		closed, updated := s.update(bar)
+		if closed != nil {
+			fired = append(fired, a.evaluate(ctx, bar.Symbol, timeframe, true, closed)...)
+		}
+		if updated {
+			fired = append(fired, a.evaluate(ctx, bar.Symbol, timeframe, false, s.current())...)
+		}
+	}
+
+	return a.deliveries(fired)
+}
+
+// CloseBars evaluates the close alerts on the timeframe bars that ended
+// by the given time, including the ones of symbols with no update since
+// the fired alerts are stored and published once evaluated, without holding
+// the mutex
+func (a *AlertService) CloseBars(
+	ctx context.Context,
+	now time.Time,
+) {
+	a.firing.Lock()
+	defer a.firing.Unlock()
+
+	a.deliver(ctx, a.closeBars(ctx, now))
+}
+
+// evaluates the close alerts on the timeframe bars that ended, returning the
+// fired ones with their listeners
+func (a *AlertService) closeBars(
+	ctx context.Context,
+	now time.Time,
+) []*delivery {
+	a.mutex.Lock()
+	defer a.mutex.Unlock()
+
+	fired := []*Fired{}
+	for symbol, bySymbol := range a.series {
+		for timeframe, s := range bySymbol {
+			if closed := s.close(now); closed != nil {
+				fired = append(fired, a.evaluate(ctx, symbol, timeframe, true, closed)...)
+			}
+		}
+	}
+
+	return a.deliveries(fired)
+}
+
+// evaluates the symbol's alerts of the timeframe that are evaluated on
+// either bar updates or closes
+// expects the caller to hold the mutex
+func (a *AlertService) evaluate(
+	ctx context.Context,
+	symbol string,
+	timeframe indicator.Timeframe,
+	onClose bool,
+	bar *timeframeBar,
+) []*Fired {
+	fired := []*Fired{}
+	for _, r := range a.rules[symbol] {
+		if r.alert.Timeframe != timeframe || r.alert.OnClose != onClose {
+			continue
+		}
+
+		if value, fires := r.evaluate(bar); fires {
+			fired = append(fired, a.fire(ctx, r, value, bar))
+		}
+	}
+	return fired
+}
+
+// records the firing of the alert, removing once alerts, the firing being
+// stored once delivered
+// expects the caller to hold the mutex
+func (a *AlertService) fire(
+	ctx context.Context,
+	r *rule,
+	value float64,
+	bar *timeframeBar,
+) *Fired {
+	lgr := a.lgr.Get(ctx)
+
+	now := time.Now().UTC()
+	r.alert.FireCount++
+	r.alert.LastFiredAt = now
+	r.alert.UpdatedAt = now
+	if r.alert.Mode == MODE_ONCE {
+		r.alert.Status = STATUS_TRIGGERED
+		a.removeRule(r.alert.ID)
+	}
+
+	lgr.Info(
+		"Alert fired",
+		zap.Int64("alertId", r.alert.ID),
+		zap.String("symbol", r.alert.Symbol),
+		zap.Float64("value", value),
+	)
+
+	return &Fired{
+		Alert:        *r.alert,
+		Value:        value,
+		BarTimestamp: bar.Timestamp,
+		FiredAt:      now,
+	}
+}
+
+// pairs the fired alerts with the listeners matching them
+// expects the caller to hold the mutex
+func (a *AlertService) deliveries(
+	fired []*Fired,
+) []*delivery {
+	deliveries := make([]*delivery, 0, len(fired))
+	for _, f := range fired {
+		d := &delivery{fired: f}
+		for _, listener := range a.listeners {
+			if listener.matches(f) {
+				d.listeners = append(d.listeners, listener)
+			}
+		}
+		deliveries = append(deliveries, d)
+	}
+	return deliveries
+}
+
+// stores the fired alerts and sends them to their listeners
+// listeners that fail to receive them are removed
+// expects the caller to hold the firing mutex, but not the mutex
+func (a *AlertService) deliver(
+	ctx context.Context,
+	deliveries []*delivery,
+) {
+	lgr := a.lgr.Get(ctx)
+
+	for _, d := range deliveries {
+		// the firing is still published if it could not be stored
+		if err := a.repo.UpdateAlertFired(ctx, &d.fired.Alert); err != nil {
+			lgr.Error(
+				"Failed to store fired alert",
+				zap.Int64("alertId", d.fired.Alert.ID),
+				zap.Error(err),
+			)
+		}
+
+		for _, listener := range d.listeners {
+			if err := listener.Sink.Send(d.fired); err != nil {
+				lgr.Error(
+					"Failed to send fired alert to listener. Connection might've broke",
+					zap.Int64("listenerId", listener.ID),
+					zap.Error(err),
+				)
+				a.dropListener(listener)
+			}
+		}
+	}
+}
+
+// removes the listener failing to receive its alerts and terminates its
+// stream, unless it was removed meanwhile
+func (a *AlertService) dropListener(listener *Listener) {
+	a.mutex.Lock()
+	current, exists := a.listeners[listener.ID]
+	removed := exists && current == listener
+	if removed {
+		delete(a.listeners, listener.ID)
+	}
+	a.mutex.Unlock()
+
+	if removed {
+		listener.Sink.Close()
+	}
+}
+
+// starts evaluating the alert if it is active, tracking the bars
+// of its timeframe
+// expects the caller to hold the mutex
+func (a *AlertService) setRule(alert *Alert) {
+	if alert.Status != STATUS_ACTIVE {
+		return
+	}
+
+	if _, exists := a.rules[alert.Symbol]; !exists {
+		a.rules[alert.Symbol] = make(map[int64]*rule)
+	}
+	a.rules[alert.Symbol][alert.ID] = newRule(alert)
+
+	if _, exists := a.series[alert.Symbol]; !exists {
+		a.series[alert.Symbol] = make(map[indicator.Timeframe]*series)
+	}
+	if _, exists := a.series[alert.Symbol][alert.Timeframe]; !exists {
+		a.series[alert.Symbol][alert.Timeframe] = newSeries(alert.Timeframe)
+	}
+}
+
+// stops evaluating the alert, dropping the bars of its timeframe
+// if no other alert of the symbol needs them
+// expects the caller to hold the mutex
+func (a *AlertService) removeRule(id int64) {
+	for symbol, rules := range a.rules {
+		r, exists := rules[id]
+		if !exists {
+			continue
+		}
+
+		delete(rules, id)
+
+		needed := false
+		for _, other := range rules {
+			if other.alert.Timeframe == r.alert.Timeframe {
+				needed = true
+				break
+			}
+		}
+		if !needed {
+			delete(a.series[symbol], r.alert.Timeframe)
+		}
+
+		if len(rules) == 0 {
+			delete(a.rules, symbol)
+			delete(a.series, symbol)
+		}
+		return
+	}
+}
@@ -0,0 +1,529 @@
+package alert
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/ramasbeinaty/trading-chart-service/internal/testutil"
+	"github.com/ramasbeinaty/trading-chart-service/pkg/domain/auth"
+	"github.com/ramasbeinaty/trading-chart-service/pkg/domain/indicator"
+)
+
+// memoryRepository keeps the alerts in memory
+type memoryRepository struct {
+	alerts map[int64]*Alert
+}
+
+func newMemoryRepository() *memoryRepository {
+	return &memoryRepository{alerts: map[int64]*Alert{}}
+}
+
+func (r *memoryRepository) CreateAlert(_ context.Context, a *Alert) error {
+	stored := *a
+	r.alerts[a.ID] = &stored
+	return nil
+}
+
+func (r *memoryRepository) GetAlert(_ context.Context, id int64) (*Alert, error) {
+	a, exists := r.alerts[id]
+	if !exists {
+		return nil, nil
+	}
+	stored := *a
+	return &stored, nil
+}
+
+func (r *memoryRepository) GetAlerts(_ context.Context, symbol string) ([]*Alert, error) {
+	alerts := []*Alert{}
+	for _, a := range r.alerts {
+		if symbol == "" || a.Symbol == symbol {
+			stored := *a
+			alerts = append(alerts, &stored)
+		}
+	}
+	return alerts, nil
+}
+
+func (r *memoryRepository) GetActiveAlerts(context.Context) ([]*Alert, error) {
+	alerts := []*Alert{}
+	for _, a := range r.alerts {
+		if a.Status == STATUS_ACTIVE {
+			stored := *a
+			alerts = append(alerts, &stored)
+		}
+	}
+	return alerts, nil
+}
+
+func (r *memoryRepository) UpdateAlert(_ context.Context, a *Alert) (bool, error) {
+	stored, exists := r.alerts[a.ID]
+	if !exists {
+		return false, nil
+	}
+	updated := *a
+	updated.FireCount = stored.FireCount
+	updated.LastFiredAt = stored.LastFiredAt
+	r.alerts[a.ID] = &updated
+	return true, nil
+}
+
+func (r *memoryRepository) UpdateAlertFired(_ context.Context, a *Alert) error {
+	stored := *a
+	r.alerts[a.ID] = &stored
+	return nil
+}
+
+func (r *memoryRepository) DeleteAlert(_ context.Context, id int64) (bool, error) {
+	_, exists := r.alerts[id]
+	delete(r.alerts, id)
+	return exists, nil
+}
+
+// fakeSink records the fired alerts sent to it
+type fakeSink struct {
+	fired []*Fired
+	done  chan struct{}
+}
+
+func newFakeSink() *fakeSink {
+	return &fakeSink{done: make(chan struct{})}
+}
+
+func (s *fakeSink) Send(fired *Fired) error {
+	s.fired = append(s.fired, fired)
+	return nil
+}
+
+func (s *fakeSink) Close()                { close(s.done) }
+func (s *fakeSink) Done() <-chan struct{} { return s.done }
+
+var start = time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+
+func newTestService(t *testing.T, alerts ...*Alert) (*AlertService, *memoryRepository, *fakeSink) {
+	t.Helper()
+
+	repo := newMemoryRepository()
+	service := NewAlertService(repo, testutil.NopLogger{})
+	for _, a := range alerts {
+		if err := service.CreateAlert(context.Background(), a); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	}
+
+	sink := newFakeSink()
+	service.AddListener(context.Background(), &Listener{ID: 1, Sink: sink})
+
+	return service, repo, sink
+}
+
+// sends a tick as the update of the 1 minute bar it falls in
+func tick(service *AlertService, minute int, open, high, low, close float64) {
+	service.OnBarUpdate(context.Background(), Bar{
+		Symbol:    "BTCUSDT",
+		Timestamp: start.Add(time.Duration(minute) * time.Minute),
+		Open:      open,
+		High:      high,
+		Low:       low,
+		Close:     close,
+	})
+}
+
+func TestValidate(t *testing.T) {
+	valid := Alert{
+		Symbol:    "BTCUSDT",
+		Timeframe: indicator.TIMEFRAME_1M,
+		Metric:    METRIC_CLOSE,
+		Condition: CONDITION_CROSSES_ABOVE,
+		Threshold: 70000,
+		Mode:      MODE_ONCE,
+	}
+	if err := valid.Validate(); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	invalid := []func(a *Alert){
+		func(a *Alert) { a.Symbol = "" },
+		func(a *Alert) { a.Timeframe = "2m" },
+		func(a *Alert) { a.Metric = "volume" },
+		func(a *Alert) { a.Condition = "equals" },
+		func(a *Alert) { a.Mode = "twice" },
+		func(a *Alert) { a.Threshold = 0 },
+	}
+	for i, change := range invalid {
+		a := valid
+		change(&a)
+		if err := a.Validate(); err == nil {
+			t.Fatalf("case %d: expected %+v to be invalid", i, a)
+		}
+	}
+}
+
+func TestCrossingAlertFiresOnce(t *testing.T) {
+	service, repo, sink := newTestService(t, &Alert{
+		ID:        1,
+		Symbol:    "BTCUSDT",
+		Timeframe: indicator.TIMEFRAME_1M,
+		Metric:    METRIC_CLOSE,
+		Condition: CONDITION_CROSSES_ABOVE,
+		Threshold: 70000,
+		Mode:      MODE_ONCE,
+	})
+
+	// already above when first seen, it must cross from below first
+	tick(service, 0, 70100, 70100, 70100, 70100)
+	tick(service, 0, 70100, 70100, 69900, 69900)
+	tick(service, 0, 70100, 70200, 69900, 70200)
+	tick(service, 1, 69800, 69800, 69800, 69800)
+	tick(service, 1, 69800, 70300, 69800, 70300)
+
+	if len(sink.fired) != 1 {
+		t.Fatalf("expected the alert to fire once, got %d", len(sink.fired))
+	}
+	fired := sink.fired[0]
+	if fired.Value != 70200 || !fired.BarTimestamp.Equal(start) {
+		t.Fatalf("expected the alert to fire on the first crossing, got %+v", fired)
+	}
+	if fired.Alert.Status != STATUS_TRIGGERED || repo.alerts[1].Status != STATUS_TRIGGERED {
+		t.Fatalf("expected the alert to be stored as triggered, got %+v", repo.alerts[1])
+	}
+}
+
+func TestRearmAlertFiresAgainOnceReset(t *testing.T) {
+	service, repo, sink := newTestService(t, &Alert{
+		ID:        1,
+		Symbol:    "BTCUSDT",
+		Timeframe: indicator.TIMEFRAME_1M,
+		Metric:    METRIC_CLOSE,
+		Condition: CONDITION_BELOW,
+		Threshold: 100,
+		Mode:      MODE_REARM,
+	})
+
+	tick(service, 0, 101, 101, 99, 99) // fires
+	tick(service, 0, 101, 101, 98, 98) // still below, disarmed
+	tick(service, 0, 101, 102, 98, 102)
+	tick(service, 0, 101, 102, 97, 97) // fires again
+
+	if len(sink.fired) != 2 {
+		t.Fatalf("expected the alert to fire twice, got %d", len(sink.fired))
+	}
+	if a := repo.alerts[1]; a.Status != STATUS_ACTIVE || a.FireCount != 2 || a.LastFiredAt.IsZero() {
+		t.Fatalf("expected the alert to stay active and count its firings, got %+v", a)
+	}
+}
+
+func TestCloseAlertEvaluatesClosedTimeframeBars(t *testing.T) {
+	service, _, sink := newTestService(t, &Alert{
+		ID:        1,
+		Symbol:    "BTCUSDT",
+		Timeframe: indicator.TIMEFRAME_5M,
+		Metric:    METRIC_RANGE_PERCENT,
+		Condition: CONDITION_ABOVE,
+		Threshold: 2,
+		Mode:      MODE_REARM,
+		OnClose:   true,
+	})
+
+	// a 5 minute bar from 100 down to 99 then up to 102, a 3.03% range
+	tick(service, 0, 100, 100, 99, 99)
+	tick(service, 3, 99, 102, 99, 102)
+	if len(sink.fired) != 0 {
+		t.Fatalf("expected no firing before the bar closes, got %+v", sink.fired)
+	}
+
+	// opening the next bar closes it
+	tick(service, 5, 102, 102, 102, 102)
+	if len(sink.fired) != 1 || !sink.fired[0].BarTimestamp.Equal(start) {
+		t.Fatalf("expected a firing on the closed bar, got %+v", sink.fired)
+	}
+
+	// a bar with no later update is closed once its timeframe ends,
+	// its flat range re-arms the alert
+	service.CloseBars(context.Background(), start.Add(9*time.Minute))
+	service.CloseBars(context.Background(), start.Add(10*time.Minute))
+	tick(service, 9, 102, 110, 102, 110) // late update of the closed bar
+	if len(sink.fired) != 1 {
+		t.Fatalf("expected no firing on the flat bar, got %d", len(sink.fired))
+	}
+
+	tick(service, 10, 110, 110, 106, 106)
+	service.CloseBars(context.Background(), start.Add(15*time.Minute))
+	service.CloseBars(context.Background(), start.Add(15*time.Minute))
+	if len(sink.fired) != 2 || !sink.fired[1].BarTimestamp.Equal(start.Add(10*time.Minute)) {
+		t.Fatalf("expected a single firing on the third bar, got %+v", sink.fired)
+	}
+}
+
+func TestLoadAlertsReplacesTheEvaluatedAlerts(t *testing.T) {
+	crossing := &Alert{
+		ID:        1,
+		Symbol:    "BTCUSDT",
+		Timeframe: indicator.TIMEFRAME_1M,
+		Metric:    METRIC_CLOSE,
+		Condition: CONDITION_CROSSES_ABOVE,
+		Threshold: 70000,
+		Mode:      MODE_ONCE,
+	}
+	deleted := &Alert{
+		ID:        2,
+		Symbol:    "BTCUSDT",
+		Timeframe: indicator.TIMEFRAME_1M,
+		Metric:    METRIC_CLOSE,
+		Condition: CONDITION_ABOVE,
+		Threshold: 70100,
+		Mode:      MODE_ONCE,
+	}
+	service, repo, sink := newTestService(t, crossing, deleted)
+
+	// arms the crossing alert
+	tick(service, 0, 69900, 69900, 69900, 69900)
+
+	// changed by another replica meanwhile
+	delete(repo.alerts, 2)
+	repo.alerts[3] = &Alert{
+		ID:        3,
+		Symbol:    "BTCUSDT",
+		Timeframe: indicator.TIMEFRAME_1M,
+		Metric:    METRIC_CLOSE,
+		Condition: CONDITION_ABOVE,
+		Threshold: 70150,
+		Mode:      MODE_ONCE,
+		Status:    STATUS_ACTIVE,
+	}
+
+	if err := service.LoadAlerts(context.Background()); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	tick(service, 0, 69900, 70200, 69900, 70200)
+
+	fired := map[int64]bool{}
+	for _, f := range sink.fired {
+		fired[f.Alert.ID] = true
+	}
+	if len(sink.fired) != 2 || !fired[1] || !fired[3] {
+		t.Fatalf("expected the kept and the new alerts to fire, got %v", fired)
+	}
+}
+
+func TestUpdateAndDeleteAlert(t *testing.T) {
+	service, _, sink := newTestService(t, &Alert{
+		ID:        1,
+		Symbol:    "BTCUSDT",
+		Timeframe: indicator.TIMEFRAME_1M,
+		Metric:    METRIC_CLOSE,
+		Condition: CONDITION_ABOVE,
+		Threshold: 100,
+		Mode:      MODE_ONCE,
+	})
+	ctx := context.Background()
+
+	tick(service, 0, 101, 101, 101, 101)
+	if len(sink.fired) != 1 {
+		t.Fatalf("expected the alert to fire, got %d", len(sink.fired))
+	}
+
+	// updating re-activates it
+	updated, err := service.UpdateAlert(ctx, &Alert{
+		ID:        1,
+		Symbol:    "BTCUSDT",
+		Timeframe: indicator.TIMEFRAME_1M,
+		Metric:    METRIC_CLOSE,
+		Condition: CONDITION_ABOVE,
+		Threshold: 105,
+		Mode:      MODE_ONCE,
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if updated.Status != STATUS_ACTIVE || updated.FireCount != 1 {
+		t.Fatalf("expected the updated alert to be active, got %+v", updated)
+	}
+
+	tick(service, 0, 101, 106, 101, 106)
+	if len(sink.fired) != 2 {
+		t.Fatalf("expected the updated alert to fire, got %d", len(sink.fired))
+	}
+
+	if err := service.DeleteAlert(ctx, 1); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if err := service.DeleteAlert(ctx, 1); err == nil {
+		t.Fatalf("expected deleting a missing alert to fail")
+	}
+	if _, err := service.UpdateAlert(ctx, updated); err == nil {
+		t.Fatalf("expected updating a missing alert to fail")
+	}
+}
+
+func TestListenersReceiveMatchingAlerts(t *testing.T) {
+	service, _, all := newTestService(t,
+		&Alert{ID: 1, Symbol: "BTCUSDT", Timeframe: "1m", Metric: METRIC_CLOSE, Condition: CONDITION_ABOVE, Threshold: 100, Mode: MODE_ONCE},
+		&Alert{ID: 2, Symbol: "BTCUSDT", Timeframe: "1m", Metric: METRIC_CLOSE, Condition: CONDITION_ABOVE, Threshold: 90, Mode: MODE_ONCE},
+	)
+	ctx := context.Background()
+
+	byId, bySymbol := newFakeSink(), newFakeSink()
+	service.AddListener(ctx, &Listener{ID: 2, AlertIDs: map[int64]bool{2: true}, Sink: byId})
+	service.AddListener(ctx, &Listener{ID: 3, Symbols: map[string]bool{"ETHUSDT": true}, Sink: bySymbol})
+
+	tick(service, 0, 101, 101, 101, 101)
+
+	if len(all.fired) != 2 || len(byId.fired) != 1 || len(bySymbol.fired) != 0 {
+		t.Fatalf(
+			"expected 2, 1 and 0 fired alerts, got %d, %d and %d",
+			len(all.fired),
+			len(byId.fired),
+			len(bySymbol.fired),
+		)
+	}
+	if byId.fired[0].Alert.ID != 2 {
+		t.Fatalf("expected alert 2, got %+v", byId.fired[0])
+	}
+
+	service.RemoveListener(ctx, 2)
+	select {
+	case <-byId.Done():
+	default:
+		t.Fatalf("expected the removed listener's sink to be closed")
+	}
+}
+
+func TestRemoveListenersClosesEverySink(t *testing.T) {
+	service, _, first := newTestService(t,
+		&Alert{ID: 1, Symbol: "BTCUSDT", Timeframe: "1m", Metric: METRIC_CLOSE, Condition: CONDITION_ABOVE, Threshold: 100, Mode: MODE_ONCE},
+	)
+	ctx := context.Background()
+
+	second := newFakeSink()
+	service.AddListener(ctx, &Listener{ID: 2, Sink: second})
+
+	service.RemoveListeners(ctx)
+	for _, sink := range []*fakeSink{first, second} {
+		select {
+		case <-sink.Done():
+		default:
+			t.Fatalf("expected the sinks of the removed listeners to be closed")
+		}
+	}
+
+	tick(service, 0, 101, 101, 101, 101)
+	if len(first.fired) != 0 || len(second.fired) != 0 {
+		t.Errorf("expected the removed listeners not to receive the alerts")
+	}
+}
+
+// blockingSink blocks every send until released
+type blockingSink struct {
+	*fakeSink
+	sending chan struct{}
+	release chan struct{}
+}
+
+func (s *blockingSink) Send(fired *Fired) error {
+	close(s.sending)
+	<-s.release
+	return s.fakeSink.Send(fired)
+}
+
+func TestFiredAlertsArePublishedWithoutHoldingTheMutex(t *testing.T) {
+	service, _, _ := newTestService(t,
+		&Alert{ID: 1, Symbol: "BTCUSDT", Timeframe: "1m", Metric: METRIC_CLOSE, Condition: CONDITION_ABOVE, Threshold: 100, Mode: MODE_ONCE},
+	)
+	ctx := context.Background()
+
+	slow := &blockingSink{fakeSink: newFakeSink(), sending: make(chan struct{}), release: make(chan struct{})}
+	service.AddListener(ctx, &Listener{ID: 2, Sink: slow})
+
+	ticked := make(chan struct{})
+	go func() {
+		tick(service, 0, 101, 101, 101, 101)
+		close(ticked)
+	}()
+	<-slow.sending
+
+	added := make(chan struct{})
+	go func() {
+		service.AddListener(ctx, &Listener{ID: 3, Sink: newFakeSink()})
+		close(added)
+	}()
+	select {
+	case <-added:
+	case <-time.After(time.Second):
+		t.Fatal("expected the listeners not to wait on the fired alert being sent")
+	}
+
+	close(slow.release)
+	<-ticked
+	if len(slow.fired) != 1 {
+		t.Errorf("expected the slow listener to receive the fired alert, got %d", len(slow.fired))
+	}
+}
+
+func TestOnlyTheOwnerCanAccessAnAlert(t *testing.T) {
+	service, _, _ := newTestService(t)
+
+	alice := auth.WithPrincipal(context.Background(), &auth.Principal{ID: "alice", Method: auth.METHOD_JWT, Symbols: []string{"*"}})
+	bob := auth.WithPrincipal(context.Background(), &auth.Principal{ID: "bob", Method: auth.METHOD_JWT, Symbols: []string{"*"}})
+
+	a := &Alert{ID: 1, Symbol: "BTCUSDT", Timeframe: indicator.TIMEFRAME_1M, Metric: METRIC_CLOSE, Condition: CONDITION_ABOVE, Threshold: 100, Mode: MODE_ONCE}
+	if err := service.CreateAlert(alice, a); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if a.Owner != "jwt:alice" {
+		t.Fatalf("expected the alert to belong to jwt:alice, got %q", a.Owner)
+	}
+
+	if _, err := service.GetAlert(bob, 1); !errors.Is(err, auth.ERR_PERMISSION_DENIED) {
+		t.Errorf("expected getting another client's alert to be denied, got %v", err)
+	}
+	update := *a
+	update.Threshold = 200
+	if _, err := service.UpdateAlert(bob, &update); !errors.Is(err, auth.ERR_PERMISSION_DENIED) {
+		t.Errorf("expected updating another client's alert to be denied, got %v", err)
+	}
+	if err := service.DeleteAlert(bob, 1); !errors.Is(err, auth.ERR_PERMISSION_DENIED) {
+		t.Errorf("expected deleting another client's alert to be denied, got %v", err)
+	}
+	if alerts, _ := service.ListAlerts(bob, ""); len(alerts) != 0 {
+		t.Errorf("expected another client's alerts to be left out, got %d", len(alerts))
+	}
+
+	updated, err := service.UpdateAlert(alice, &update)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if updated.Owner != "jwt:alice" || updated.Threshold != 200 {
+		t.Errorf("expected the owner to update the alert and keep it, got %+v", updated)
+	}
+	if alerts, _ := service.ListAlerts(alice, ""); len(alerts) != 1 {
+		t.Errorf("expected the owner to list the alert, got %d", len(alerts))
+	}
+	if err := service.DeleteAlert(alice, 1); err != nil {
+		t.Errorf("expected the owner to delete the alert, got %v", err)
+	}
+}
+
+func TestListenersOnlyReceiveTheirOwnersAlerts(t *testing.T) {
+	service, _, _ := newTestService(t)
+
+	alice := &auth.Principal{ID: "alice", Method: auth.METHOD_JWT, Symbols: []string{"*"}}
+	a := &Alert{ID: 1, Symbol: "BTCUSDT", Timeframe: indicator.TIMEFRAME_1M, Metric: METRIC_CLOSE, Condition: CONDITION_ABOVE, Threshold: 100, Mode: MODE_REARM}
+	if err := service.CreateAlert(auth.WithPrincipal(context.Background(), alice), a); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	aliceSink, bobSink := newFakeSink(), newFakeSink()
+	service.AddListener(context.Background(), &Listener{ID: 2, Owner: alice, Sink: aliceSink})
+	service.AddListener(context.Background(), &Listener{ID: 3, Owner: &auth.Principal{ID: "bob", Method: auth.METHOD_JWT}, Sink: bobSink})
+
+	tick(service, 0, 90, 110, 90, 110)
+
+	if len(aliceSink.fired) != 1 {
+		t.Errorf("expected the owner's listener to receive the alert, got %d", len(aliceSink.fired))
+	}
+	if len(bobSink.fired) != 0 {
+		t.Errorf("expected another client's listener to receive nothing, got %d", len(bobSink.fired))
+	}
+}
@@ -0,0 +1,26 @@
+package auth
+
+type AuthConfig struct {
+	// requests must authenticate once api keys or a jwks are configured
+	Enabled bool
+	APIKeys []*APIKey
+	JWT     JWTConfig
+	// principals allowed to use the admin service, written as METHOD:ID,
+	// e.g. "api_key:ops" or "jwt:alice"
+	Admins []string
+	// shared secret the replicas present to follow the leader, unless they
+	// present a client certificate
+	ReplicaToken string
+}
+
+type JWTConfig struct {
+	Enabled bool
+	// json web key set verifying the token signatures
+	JWKSFile string
+	// checked against the token's iss and aud claims if set
+	Issuer   string
+	Audience string
+	// claim listing the globs of the symbols the token is entitled to,
+	// every symbol if absent from the token
+	SymbolsClaim string
+}
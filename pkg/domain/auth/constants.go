@@ -0,0 +1,13 @@
+package auth
+
+import "time"
+
+const (
+	// entitles to every symbol
+	ALL_SYMBOLS = "*"
+
+	DEFAULT_SYMBOLS_CLAIM = "symbols"
+
+	// tolerated clock skew when validating the token times
+	JWT_LEEWAY = time.Minute
+)
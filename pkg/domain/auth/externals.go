@@ -0,0 +1,13 @@
+package auth
+
+import "context"
+
+// ITokenVerifier verifies bearer tokens, e.g. JWTs signed by a known key
+type ITokenVerifier interface {
+	// returns the principal the token was issued to, failing with
+	// ERR_UNAUTHENTICATED if it is invalid or expired
+	Verify(
+		ctx context.Context,
+		token string,
+	) (*Principal, error)
+}
@@ -0,0 +1,137 @@
+package auth
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"path"
+	"strings"
+)
+
+var (
+	// the request carries no valid credentials
+	ERR_UNAUTHENTICATED = errors.New("unauthenticated")
+	// the principal may not access the resource
+	ERR_PERMISSION_DENIED = errors.New("permission denied")
+)
+
+// APIKey authenticates the client presenting it as the named principal
+type APIKey struct {
+	Name string `json:"name"`
+	Key  string `json:"key"`
+	// globs of the symbols the key is entitled to, e.g. "BTC*" or "*"
+	Symbols []string `json:"symbols"`
+}
+
+func (k *APIKey) Validate() error {
+	if k.Name == "" {
+		return fmt.Errorf("Invalid api key - name must not be empty")
+	}
+	if k.Key == "" {
+		return fmt.Errorf("Invalid api key %s - key must not be empty", k.Name)
+	}
+	if len(k.Symbols) == 0 {
+		return fmt.Errorf("Invalid api key %s - symbols must not be empty, use %q for every symbol", k.Name, ALL_SYMBOLS)
+	}
+	for _, glob := range k.Symbols {
+		if _, err := path.Match(glob, ""); err != nil {
+			return fmt.Errorf("Invalid api key %s - symbol glob %q is malformed", k.Name, glob)
+		}
+	}
+	return nil
+}
+
+type Method string
+
+const (
+	METHOD_API_KEY Method = "api_key"
+	METHOD_JWT     Method = "jwt"
+)
+
+// Principal is the client a request is authenticated as
+type Principal struct {
+	ID     string
+	Method Method
+	// globs of the symbols the principal is entitled to
+	Symbols []string
+	// may use the admin service
+	Admin bool
+}
+
+// Is reports whether both are the same client, authenticated the same way
+func (p *Principal) Is(other *Principal) bool {
+	if p == nil || other == nil {
+		return p == other
+	}
+	return p.ID == other.ID && p.Method == other.Method
+}
+
+// Entitled reports whether the principal may receive the symbol
+func (p *Principal) Entitled(symbol string) bool {
+	symbol = strings.ToUpper(symbol)
+	for _, glob := range p.Symbols {
+		if matched, _ := path.Match(strings.ToUpper(glob), symbol); matched {
+			return true
+		}
+	}
+	return false
+}
+
+func (p *Principal) String() string {
+	return fmt.Sprintf("%s:%s", p.Method, p.ID)
+}
+
+type principalKey struct{}
+
+func WithPrincipal(
+	ctx context.Context,
+	principal *Principal,
+) context.Context {
+	return context.WithValue(ctx, principalKey{}, principal)
+}
+
+// PrincipalFromContext returns the principal the request is authenticated
+// as, nil if authentication is disabled
+func PrincipalFromContext(
+	ctx context.Context,
+) *Principal {
+	principal, _ := ctx.Value(principalKey{}).(*Principal)
+	return principal
+}
+
+// AuthorizeSymbol fails if the principal of the request isn't entitled to
+// the symbol, every symbol being allowed if authentication is disabled
+func AuthorizeSymbol(
+	ctx context.Context,
+	symbol string,
+) error {
+	principal := PrincipalFromContext(ctx)
+	if principal == nil || principal.Entitled(symbol) {
+		return nil
+	}
+	return fmt.Errorf("%w - not entitled to %s", ERR_PERMISSION_DENIED, symbol)
+}
+
+// EntitledTo reports whether the principal of the request may receive the
+// symbol, see AuthorizeSymbol
+func EntitledTo(
+	ctx context.Context,
+	symbol string,
+) bool {
+	return AuthorizeSymbol(ctx, symbol) == nil
+}
+
+// AuthorizeAdmin fails unless the principal of the request is an admin,
+// denying everyone if authentication is disabled
+func AuthorizeAdmin(
+	ctx context.Context,
+) error {
+	principal := PrincipalFromContext(ctx)
+	if principal == nil {
+		return fmt.Errorf("%w - the admin service requires authentication", ERR_PERMISSION_DENIED)
+	}
+	if !principal.Admin {
+		return fmt.Errorf("%w - %s is not an admin", ERR_PERMISSION_DENIED, principal)
+	}
+	return nil
+}
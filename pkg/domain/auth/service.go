@@ -0,0 +1,125 @@
+package auth
+
+import (
+	"context"
+	"crypto/sha256"
+	"crypto/subtle"
+	"fmt"
+
+	"github.com/ramasbeinaty/trading-chart-service/pkg/domain/base/logger"
+	"go.uber.org/zap"
+)
+
+// AuthService authenticates the requests with an api key or a bearer token
+type AuthService struct {
+	cfg      *AuthConfig
+	verifier ITokenVerifier
+	lgr      logger.ILogger
+	// keyed by the sha256 of the key, so lookups don't compare the keys
+	apiKeys map[[sha256.Size]byte]*APIKey
+	// keyed by the principal, as written by Principal.String
+	admins map[string]bool
+}
+
+// verifier may be nil if JWTs are disabled
+func NewAuthService(
+	cfg *AuthConfig,
+	verifier ITokenVerifier,
+	lgr logger.ILogger,
+) *AuthService {
+	apiKeys := make(map[[sha256.Size]byte]*APIKey, len(cfg.APIKeys))
+	for _, key := range cfg.APIKeys {
+		apiKeys[sha256.Sum256([]byte(key.Key))] = key
+	}
+
+	admins := make(map[string]bool, len(cfg.Admins))
+	for _, admin := range cfg.Admins {
+		admins[admin] = true
+	}
+
+	return &AuthService{
+		cfg:      cfg,
+		verifier: verifier,
+		lgr:      lgr,
+		apiKeys:  apiKeys,
+		admins:   admins,
+	}
+}
+
+func (a *AuthService) Enabled() bool {
+	return a.cfg.Enabled
+}
+
+// AdminEnabled reports whether anyone may use the admin service, i.e.
+// authentication is enabled and admins are listed
+func (a *AuthService) AdminEnabled() bool {
+	return a.cfg.Enabled && len(a.admins) != 0
+}
+
+// Authenticate returns the principal of the api key, or of the bearer token
+// if no key is presented
+// nil if authentication is disabled
+func (a *AuthService) Authenticate(
+	ctx context.Context,
+	apiKey string,
+	token string,
+) (*Principal, error) {
+	if !a.cfg.Enabled {
+		return nil, nil
+	}
+
+	principal, err := a.authenticate(ctx, apiKey, token)
+	if err != nil {
+		return nil, err
+	}
+	principal.Admin = a.admins[principal.String()]
+	return principal, nil
+}
+
+// AuthenticateReplica fails unless the token is the replicas' token, every
+// replica being allowed if authentication is disabled
+func (a *AuthService) AuthenticateReplica(
+	token string,
+) error {
+	if !a.cfg.Enabled {
+		return nil
+	}
+	if a.cfg.ReplicaToken == "" {
+		return fmt.Errorf("%w - replicas must present a client certificate", ERR_UNAUTHENTICATED)
+	}
+	if subtle.ConstantTimeCompare([]byte(token), []byte(a.cfg.ReplicaToken)) != 1 {
+		return fmt.Errorf("%w - invalid replica token", ERR_UNAUTHENTICATED)
+	}
+	return nil
+}
+
+func (a *AuthService) authenticate(
+	ctx context.Context,
+	apiKey string,
+	token string,
+) (*Principal, error) {
+	switch {
+	case apiKey != "":
+		key, ok := a.apiKeys[sha256.Sum256([]byte(apiKey))]
+		if !ok {
+			return nil, fmt.Errorf("%w - invalid api key", ERR_UNAUTHENTICATED)
+		}
+		return &Principal{
+			ID:      key.Name,
+			Method:  METHOD_API_KEY,
+			Symbols: key.Symbols,
+		}, nil
+	case token != "":
+		if a.verifier == nil {
+			return nil, fmt.Errorf("%w - bearer tokens are not accepted", ERR_UNAUTHENTICATED)
+		}
+		principal, err := a.verifier.Verify(ctx, token)
+		if err != nil {
+			a.lgr.Get(ctx).Info("Rejected a bearer token", zap.Error(err))
+			return nil, err
+		}
+		return principal, nil
+	default:
+		return nil, fmt.Errorf("%w - an api key or a bearer token must be provided", ERR_UNAUTHENTICATED)
+	}
+}
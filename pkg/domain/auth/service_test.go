@@ -0,0 +1,184 @@
+package auth
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/ramasbeinaty/trading-chart-service/internal/testutil"
+)
+
+// fakeVerifier accepts a single token
+type fakeVerifier struct {
+	token     string
+	principal *Principal
+}
+
+func (v *fakeVerifier) Verify(_ context.Context, token string) (*Principal, error) {
+	if token != v.token {
+		return nil, ERR_UNAUTHENTICATED
+	}
+	return v.principal, nil
+}
+
+func newTestService() *AuthService {
+	return NewAuthService(
+		&AuthConfig{
+			Enabled: true,
+			APIKeys: []*APIKey{{Name: "desk-1", Key: "secret", Symbols: []string{"BTC*"}}},
+		},
+		&fakeVerifier{
+			token:     "token",
+			principal: &Principal{ID: "user-1", Method: METHOD_JWT, Symbols: []string{ALL_SYMBOLS}},
+		},
+		testutil.NopLogger{},
+	)
+}
+
+func TestAuthenticateWithAnAPIKey(t *testing.T) {
+	service := newTestService()
+
+	principal, err := service.Authenticate(context.Background(), "secret", "")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if principal.ID != "desk-1" || principal.Method != METHOD_API_KEY {
+		t.Errorf("expected the key's principal, got %v", principal)
+	}
+
+	_, err = service.Authenticate(context.Background(), "wrong", "token")
+	if !errors.Is(err, ERR_UNAUTHENTICATED) {
+		t.Errorf("expected an invalid key to be rejected even with a valid token, got %v", err)
+	}
+}
+
+func TestAuthenticateWithABearerToken(t *testing.T) {
+	service := newTestService()
+
+	principal, err := service.Authenticate(context.Background(), "", "token")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if principal.ID != "user-1" {
+		t.Errorf("expected the token's principal, got %v", principal)
+	}
+
+	if _, err := service.Authenticate(context.Background(), "", ""); !errors.Is(err, ERR_UNAUTHENTICATED) {
+		t.Errorf("expected missing credentials to be rejected, got %v", err)
+	}
+}
+
+func TestAuthenticateIsSkippedWhenDisabled(t *testing.T) {
+	service := NewAuthService(&AuthConfig{}, nil, testutil.NopLogger{})
+
+	principal, err := service.Authenticate(context.Background(), "", "")
+	if err != nil || principal != nil {
+		t.Errorf("expected no principal and no error, got %v, %v", principal, err)
+	}
+	if err := AuthorizeSymbol(context.Background(), "ETHUSDT"); err != nil {
+		t.Errorf("expected every symbol to be allowed without a principal, got %v", err)
+	}
+}
+
+func TestAuthorizeSymbolMatchesTheEntitledGlobs(t *testing.T) {
+	ctx := WithPrincipal(
+		context.Background(),
+		&Principal{ID: "desk-1", Method: METHOD_API_KEY, Symbols: []string{"BTC*", "ethusdt"}},
+	)
+
+	for symbol, entitled := range map[string]bool{
+		"BTCUSDT": true,
+		"btceur":  true,
+		"ETHUSDT": true,
+		"ETHBTC":  false,
+	} {
+		err := AuthorizeSymbol(ctx, symbol)
+		if entitled != (err == nil) {
+			t.Errorf("expected %s entitled to be %v, got %v", symbol, entitled, err)
+		}
+		if err != nil && !errors.Is(err, ERR_PERMISSION_DENIED) {
+			t.Errorf("expected a permission denied error, got %v", err)
+		}
+	}
+}
+
+func TestOnlyTheListedPrincipalsAreAdmins(t *testing.T) {
+	service := NewAuthService(
+		&AuthConfig{
+			Enabled: true,
+			APIKeys: []*APIKey{
+				{Name: "ops", Key: "ops-secret", Symbols: []string{ALL_SYMBOLS}},
+				{Name: "desk-1", Key: "secret", Symbols: []string{ALL_SYMBOLS}},
+			},
+			Admins: []string{"api_key:ops"},
+		},
+		&fakeVerifier{
+			token:     "token",
+			principal: &Principal{ID: "ops", Method: METHOD_JWT, Symbols: []string{ALL_SYMBOLS}},
+		},
+		testutil.NopLogger{},
+	)
+
+	cases := []struct {
+		apiKey string
+		token  string
+		admin  bool
+	}{
+		{apiKey: "ops-secret", admin: true},
+		{apiKey: "secret", admin: false},
+		// a token with the same subject is another principal
+		{token: "token", admin: false},
+	}
+	for _, c := range cases {
+		principal, err := service.Authenticate(context.Background(), c.apiKey, c.token)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		err = AuthorizeAdmin(WithPrincipal(context.Background(), principal))
+		if c.admin && err != nil {
+			t.Errorf("expected %s to be an admin, got %v", principal, err)
+		}
+		if !c.admin && !errors.Is(err, ERR_PERMISSION_DENIED) {
+			t.Errorf("expected %s to be denied, got %v", principal, err)
+		}
+	}
+}
+
+func TestAdminIsDeniedWithoutAuthentication(t *testing.T) {
+	err := AuthorizeAdmin(context.Background())
+	if !errors.Is(err, ERR_PERMISSION_DENIED) {
+		t.Fatalf("expected an anonymous request to be denied, got %v", err)
+	}
+
+	disabled := NewAuthService(&AuthConfig{Admins: []string{"api_key:ops"}}, nil, testutil.NopLogger{})
+	if disabled.AdminEnabled() {
+		t.Fatal("expected the admin service to be disabled without authentication")
+	}
+	noAdmins := NewAuthService(&AuthConfig{Enabled: true}, nil, testutil.NopLogger{})
+	if noAdmins.AdminEnabled() {
+		t.Fatal("expected the admin service to be disabled without admins")
+	}
+	enabled := NewAuthService(&AuthConfig{Enabled: true, Admins: []string{"api_key:ops"}}, nil, testutil.NopLogger{})
+	if !enabled.AdminEnabled() {
+		t.Fatal("expected the admin service to be enabled")
+	}
+}
+
+func TestReplicasAuthenticateWithTheReplicaToken(t *testing.T) {
+	service := NewAuthService(&AuthConfig{Enabled: true, ReplicaToken: "replica-secret"}, nil, testutil.NopLogger{})
+	if err := service.AuthenticateReplica("replica-secret"); err != nil {
+		t.Fatalf("expected the replica token to be accepted, got %v", err)
+	}
+	for _, token := range []string{"", "other"} {
+		if err := service.AuthenticateReplica(token); !errors.Is(err, ERR_UNAUTHENTICATED) {
+			t.Errorf("expected %q to be rejected, got %v", token, err)
+		}
+	}
+
+	// without a token, only client certificates authenticate the replicas
+	noToken := NewAuthService(&AuthConfig{Enabled: true}, nil, testutil.NopLogger{})
+	if err := noToken.AuthenticateReplica(""); !errors.Is(err, ERR_UNAUTHENTICATED) {
+		t.Fatalf("expected an empty token to be rejected, got %v", err)
+	}
+}
@@ -0,0 +1,8 @@
+package backfill
+
+import "time"
+
+// SetTestClock replaces the clock the range is clamped with
+func SetTestClock(s *BackfillService, now func() time.Time) {
+	s.now = now
+}
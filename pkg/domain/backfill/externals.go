@@ -0,0 +1,29 @@
+package backfill
+
+import (
+	"context"
+	"time"
+
+	"github.com/ramasbeinaty/trading-chart-service/pkg/domain/candlestick"
+)
+
+type IRepository interface {
+	UpsertCandlestickBar(
+		ctx context.Context,
+		bar *candlestick.Candlestick,
+	) error
+}
+
+// IBarSource serves the historical 1 minute bars of the exchange
+type IBarSource interface {
+	// returns the symbol's bars of a page of minutes starting at from and
+	// ending by to at the latest, oldest first, along with the start of the
+	// next page
+	// minutes without trades have no bar
+	GetBars(
+		ctx context.Context,
+		symbol string,
+		from time.Time,
+		to time.Time,
+	) (bars []*candlestick.Candlestick, next time.Time, err error)
+}
@@ -0,0 +1,90 @@
+package backfill
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/ramasbeinaty/trading-chart-service/pkg/domain/base/logger"
+	"go.uber.org/zap"
+)
+
+// BackfillService stores the historical bars of the exchange, e.g. for the
+// minutes missed while no replica was ingesting
+type BackfillService struct {
+	repo   IRepository
+	source IBarSource
+	lgr    logger.ILogger
+	now    func() time.Time
+}
+
+func NewBackfillService(
+	repo IRepository,
+	source IBarSource,
+	lgr logger.ILogger,
+) *BackfillService {
+	return &BackfillService{
+		repo:   repo,
+		source: source,
+		lgr:    lgr,
+		now:    time.Now,
+	}
+}
+
+// Backfill stores the symbol's bars of the minutes within the range,
+// returning how many were stored
+// stored bars are overwritten but keep their open, as when committed live
+// the range ends with the last completed minute, the current one being
+// committed live once it ends
+func (s *BackfillService) Backfill(
+	ctx context.Context,
+	symbol string,
+	from time.Time,
+	to time.Time,
+) (int, error) {
+	lgr := s.lgr.Get(ctx)
+
+	symbol = strings.ToUpper(symbol)
+	from = from.UTC().Truncate(time.Minute)
+	to = to.UTC()
+	if !from.Before(to) {
+		return 0, fmt.Errorf("Failed to backfill %s - the range ends before it starts", symbol)
+	}
+	if current := s.now().UTC().Truncate(time.Minute); !to.Before(current) {
+		to = current.Add(-time.Millisecond)
+	}
+	if to.Before(from) {
+		return 0, fmt.Errorf("Failed to backfill %s - no minute of the range has ended yet", symbol)
+	}
+
+	stored := 0
+	for page := from; !page.After(to); {
+		bars, next, err := s.source.GetBars(ctx, symbol, page, to)
+		if err != nil {
+			return stored, fmt.Errorf("Failed to get the bars of %s from %s - %w", symbol, page, err)
+		}
+
+		for _, bar := range bars {
+			if err := s.repo.UpsertCandlestickBar(ctx, bar); err != nil {
+				return stored, fmt.Errorf("Failed to store the bar of %s at %s - %w", symbol, bar.TradeTimestamp, err)
+			}
+			stored++
+		}
+
+		lgr.Info(
+			"Backfilled bars",
+			zap.String("symbol", symbol),
+			zap.Time("from", page),
+			zap.Int("bars", len(bars)),
+		)
+
+		// a source not moving forward would loop forever
+		if !next.After(page) {
+			return stored, fmt.Errorf("Failed to backfill %s - the source didn't move past %s", symbol, page)
+		}
+		page = next
+	}
+
+	return stored, nil
+}
@@ -0,0 +1,152 @@
+package backfill_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/ramasbeinaty/trading-chart-service/internal/testutil"
+	"github.com/ramasbeinaty/trading-chart-service/pkg/domain/backfill"
+	"github.com/ramasbeinaty/trading-chart-service/pkg/domain/candlestick"
+)
+
+// memoryRepo keeps the stored bars in memory
+type memoryRepo struct {
+	bars []*candlestick.Candlestick
+}
+
+func (r *memoryRepo) UpsertCandlestickBar(_ context.Context, bar *candlestick.Candlestick) error {
+	r.bars = append(r.bars, bar)
+	return nil
+}
+
+// pagedSource serves a bar for each minute with a trade, in pages of
+// pageMinutes minutes
+type pagedSource struct {
+	pageMinutes int
+	traded      map[time.Time]bool
+	symbols     []string
+	pages       int
+}
+
+func (s *pagedSource) GetBars(
+	_ context.Context,
+	symbol string,
+	from time.Time,
+	to time.Time,
+) ([]*candlestick.Candlestick, time.Time, error) {
+	s.pages++
+	s.symbols = append(s.symbols, symbol)
+
+	bars := []*candlestick.Candlestick{}
+	next := from.Add(time.Duration(s.pageMinutes) * time.Minute)
+	for minute := from; minute.Before(next) && !minute.After(to); minute = minute.Add(time.Minute) {
+		if s.traded[minute] {
+			bars = append(bars, &candlestick.Candlestick{Symbol: symbol, TradeTimestamp: minute})
+		}
+	}
+	return bars, next, nil
+}
+
+func TestBackfillPagesThroughTheRange(t *testing.T) {
+	start := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	source := &pagedSource{
+		pageMinutes: 3,
+		traded: map[time.Time]bool{
+			start:                      true,
+			start.Add(time.Minute):     true,
+			start.Add(4 * time.Minute): true,
+			start.Add(9 * time.Minute): true,
+			// past the range
+			start.Add(11 * time.Minute): true,
+		},
+	}
+	repo := &memoryRepo{}
+	service := backfill.NewBackfillService(repo, source, testutil.NopLogger{})
+
+	stored, err := service.Backfill(
+		context.Background(),
+		"btcusdt",
+		start.Add(30*time.Second),
+		start.Add(10*time.Minute),
+	)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if stored != 4 || len(repo.bars) != 4 {
+		t.Fatalf("expected 4 bars stored, got %d (%d in the repo)", stored, len(repo.bars))
+	}
+	if !repo.bars[0].TradeTimestamp.Equal(start) {
+		t.Fatalf("expected the range to start with the minute of from, got %s", repo.bars[0].TradeTimestamp)
+	}
+	if !repo.bars[3].TradeTimestamp.Equal(start.Add(9 * time.Minute)) {
+		t.Fatalf("expected the last bar within the range, got %s", repo.bars[3].TradeTimestamp)
+	}
+	if source.pages != 4 {
+		t.Fatalf("expected 4 pages for 11 minutes of 3 minutes pages, got %d", source.pages)
+	}
+	if source.symbols[0] != "BTCUSDT" {
+		t.Fatalf("expected the symbol uppercased, got %s", source.symbols[0])
+	}
+}
+
+// stuckSource always serves the same page
+type stuckSource struct{}
+
+func (stuckSource) GetBars(
+	_ context.Context,
+	_ string,
+	from time.Time,
+	_ time.Time,
+) ([]*candlestick.Candlestick, time.Time, error) {
+	return nil, from, nil
+}
+
+func TestBackfillFailsIfTheSourceDoesNotMoveForward(t *testing.T) {
+	start := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	service := backfill.NewBackfillService(&memoryRepo{}, stuckSource{}, testutil.NopLogger{})
+
+	_, err := service.Backfill(context.Background(), "BTCUSDT", start, start.Add(time.Hour))
+	if err == nil {
+		t.Fatal("expected an error")
+	}
+}
+
+func TestBackfillRejectsAnEmptyRange(t *testing.T) {
+	start := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	service := backfill.NewBackfillService(&memoryRepo{}, stuckSource{}, testutil.NopLogger{})
+
+	_, err := service.Backfill(context.Background(), "BTCUSDT", start, start)
+	if err == nil {
+		t.Fatal("expected an error")
+	}
+}
+
+func TestBackfillEndsWithTheLastCompletedMinute(t *testing.T) {
+	start := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	source := &pagedSource{
+		pageMinutes: 10,
+		traded: map[time.Time]bool{
+			start.Add(3 * time.Minute): true,
+			// in progress
+			start.Add(4 * time.Minute): true,
+		},
+	}
+	repo := &memoryRepo{}
+	service := backfill.NewBackfillService(repo, source, testutil.NopLogger{})
+	backfill.SetTestClock(service, testutil.NewClock(start.Add(4*time.Minute+30*time.Second)).Now)
+
+	stored, err := service.Backfill(context.Background(), "BTCUSDT", start, start.Add(time.Hour))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if stored != 1 || !repo.bars[0].TradeTimestamp.Equal(start.Add(3*time.Minute)) {
+		t.Fatalf("expected only the bar of the completed minute, got %d bars", stored)
+	}
+
+	_, err = service.Backfill(context.Background(), "BTCUSDT", start.Add(4*time.Minute), start.Add(time.Hour))
+	if err == nil {
+		t.Fatal("expected a range within the current minute to be rejected")
+	}
+}
@@ -0,0 +1,14 @@
+package events
+
+import "time"
+
+// BarClosed is the json payload of a closed bar,
+// published to the bus and delivered to the webhooks alike
+type BarClosed struct {
+	Symbol         string    `json:"symbol"`
+	Open           float64   `json:"open"`
+	High           float64   `json:"high"`
+	Low            float64   `json:"low"`
+	Close          float64   `json:"close"`
+	TradeTimestamp time.Time `json:"trade_timestamp"`
+}
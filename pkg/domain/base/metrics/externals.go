@@ -0,0 +1,25 @@
+package metrics
+
+import "time"
+
+type IMetrics interface {
+	// ingest
+	TradeReceived(symbol string)
+	TradeParsed(symbol string)
+	TradeFailed(symbol string)
+	WebsocketReconnected()
+	// time from the exchange's event to the trade being processed
+	ObserveTradeLag(symbol string, lag time.Duration)
+
+	// aggregation
+	BarCommitted(symbol string, latency time.Duration)
+	BarCommitFailed(symbol string)
+
+	// fan-out
+	SetSubscribers(symbol string, count int)
+	MessageSent(symbol string)
+	MessageDropped(symbol string)
+
+	// grpc
+	ObserveGrpcRequest(method string, code string, duration time.Duration)
+}
@@ -0,0 +1,20 @@
+package metrics
+
+import "time"
+
+// NopMetrics discards every metric
+type NopMetrics struct{}
+
+var _ IMetrics = NopMetrics{}
+
+func (NopMetrics) TradeReceived(string)                             {}
+func (NopMetrics) TradeParsed(string)                               {}
+func (NopMetrics) TradeFailed(string)                               {}
+func (NopMetrics) WebsocketReconnected()                            {}
+func (NopMetrics) ObserveTradeLag(string, time.Duration)            {}
+func (NopMetrics) BarCommitted(string, time.Duration)               {}
+func (NopMetrics) BarCommitFailed(string)                           {}
+func (NopMetrics) SetSubscribers(string, int)                       {}
+func (NopMetrics) MessageSent(string)                               {}
+func (NopMetrics) MessageDropped(string)                            {}
+func (NopMetrics) ObserveGrpcRequest(string, string, time.Duration) {}
@@ -0,0 +1,7 @@
+package bus
+
+type BusConfig struct {
+	Enabled bool
+	// subjects are "<prefix>.bars.<symbol>"
+	SubjectPrefix string
+}
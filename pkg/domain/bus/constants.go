@@ -0,0 +1,8 @@
+package bus
+
+import "time"
+
+const (
+	OUTBOX_POLL_INTERVAL = time.Second
+	OUTBOX_BATCH_SIZE    = 100
+)
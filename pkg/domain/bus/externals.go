@@ -0,0 +1,26 @@
+package bus
+
+import "context"
+
+type IPublisher interface {
+	// returns once the bus acknowledged the message
+	Publish(
+		ctx context.Context,
+		message *Message,
+	) error
+	Close() error
+}
+
+// IRepository reads the outbox written to by the transactions
+// producing the messages
+type IRepository interface {
+	// returns the oldest messages first
+	GetPendingMessages(
+		ctx context.Context,
+		limit int,
+	) ([]*Message, error)
+	DeleteMessages(
+		ctx context.Context,
+		ids []int64,
+	) error
+}
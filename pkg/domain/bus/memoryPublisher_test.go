@@ -0,0 +1,54 @@
+package bus
+
+import (
+	"context"
+	"fmt"
+	"sync"
+)
+
+// MemoryPublisher keeps the published messages in memory
+type MemoryPublisher struct {
+	mutex    sync.Mutex
+	messages []*Message
+	failing  bool
+}
+
+var _ IPublisher = &MemoryPublisher{}
+
+func NewMemoryPublisher() *MemoryPublisher {
+	return &MemoryPublisher{}
+}
+
+func (p *MemoryPublisher) Publish(
+	ctx context.Context,
+	message *Message,
+) error {
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+
+	if p.failing {
+		return fmt.Errorf("Failed to publish message %d - publisher is failing", message.ID)
+	}
+	p.messages = append(p.messages, message)
+	return nil
+}
+
+func (p *MemoryPublisher) Close() error {
+	return nil
+}
+
+// Messages returns the published messages, in order
+func (p *MemoryPublisher) Messages() []*Message {
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+
+	return append([]*Message{}, p.messages...)
+}
+
+// SetFailing makes the following publishes fail, or succeed again
+func (p *MemoryPublisher) SetFailing(failing bool) {
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+
+	p.failing = failing
+}
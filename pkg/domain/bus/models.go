@@ -0,0 +1,11 @@
+package bus
+
+import "time"
+
+// Message is published to the bus from the outbox it is written to
+type Message struct {
+	ID        int64 // outbox id, unique and increasing, used to deduplicate redeliveries
+	Subject   string
+	Payload   []byte
+	CreatedAt time.Time
+}
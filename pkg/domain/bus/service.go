@@ -0,0 +1,145 @@
+package bus
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/ramasbeinaty/trading-chart-service/pkg/domain/base/events"
+	"github.com/ramasbeinaty/trading-chart-service/pkg/domain/base/logger"
+	"go.uber.org/zap"
+)
+
+// BusService builds the messages written to the outbox along with the data
+// they describe, and relays them from the outbox to the bus
+// messages are published at least once and in order, a message is only
+// removed from the outbox once the bus acknowledged it
+type BusService struct {
+	cfg       *BusConfig
+	repo      IRepository
+	publisher IPublisher
+	lgr       logger.ILogger
+
+	pollInterval time.Duration
+}
+
+func NewBusService(
+	cfg *BusConfig,
+	repo IRepository,
+	publisher IPublisher,
+	lgr logger.ILogger,
+) *BusService {
+	return &BusService{
+		cfg:          cfg,
+		repo:         repo,
+		publisher:    publisher,
+		lgr:          lgr,
+		pollInterval: OUTBOX_POLL_INTERVAL,
+	}
+}
+
+// BarClosedMessage returns the message publishing the closed bar,
+// or nil if the bus is disabled
+func (b *BusService) BarClosedMessage(
+	bar events.BarClosed,
+) (*Message, error) {
+	if !b.cfg.Enabled {
+		return nil, nil
+	}
+
+	payload, err := json.Marshal(bar)
+	if err != nil {
+		return nil, fmt.Errorf("Failed to marshal closed bar message - %w", err)
+	}
+
+	return &Message{
+		Subject:   fmt.Sprintf("%s.bars.%s", b.cfg.SubjectPrefix, strings.ToUpper(bar.Symbol)),
+		Payload:   payload,
+		CreatedAt: time.Now().UTC(),
+	}, nil
+}
+
+// Start relays the outbox to the bus until the context is done
+// the outbox is shared by the replicas, only the leading one relays it
+func (b *BusService) Start(
+	ctx context.Context,
+	wg *sync.WaitGroup,
+	leading func() bool,
+) {
+	if !b.cfg.Enabled {
+		return
+	}
+
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		defer func() {
+			if err := b.publisher.Close(); err != nil {
+				b.lgr.Get(nil).Error("Failed to close bus publisher", zap.Error(err))
+			}
+		}()
+
+		ticker := time.NewTicker(b.pollInterval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+				if leading() {
+					b.relayOutbox(ctx)
+				}
+			}
+		}
+	}()
+}
+
+// publishes the pending messages in batches until the outbox is empty
+// or a message fails to publish, in which case it is retried on the next poll
+// before any later message
+func (b *BusService) relayOutbox(
+	ctx context.Context,
+) {
+	lgr := b.lgr.Get(ctx)
+
+	for ctx.Err() == nil {
+		messages, err := b.repo.GetPendingMessages(ctx, OUTBOX_BATCH_SIZE)
+		if err != nil {
+			lgr.Error("Failed to get pending bus messages", zap.Error(err))
+			return
+		}
+		if len(messages) == 0 {
+			return
+		}
+
+		published := make([]int64, 0, len(messages))
+		var publishErr error
+		for _, message := range messages {
+			if publishErr = b.publisher.Publish(ctx, message); publishErr != nil {
+				break
+			}
+			published = append(published, message.ID)
+		}
+
+		// a failure to delete only leads to the messages being published again
+		if len(published) != 0 {
+			if err := b.repo.DeleteMessages(ctx, published); err != nil {
+				lgr.Error("Failed to delete published bus messages", zap.Error(err))
+				return
+			}
+		}
+
+		if publishErr != nil {
+			lgr.Error(
+				"Failed to publish bus message, retrying on the next poll",
+				zap.Int64("messageId", messages[len(published)].ID),
+				zap.Error(publishErr),
+			)
+			return
+		}
+	}
+}
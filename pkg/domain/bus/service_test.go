@@ -0,0 +1,115 @@
+package bus
+
+import (
+	"context"
+	"sync"
+	"testing"
+
+	"github.com/ramasbeinaty/trading-chart-service/internal/testutil"
+	"github.com/ramasbeinaty/trading-chart-service/pkg/domain/base/events"
+)
+
+// memoryRepository is an in-memory outbox
+type memoryRepository struct {
+	mutex    sync.Mutex
+	messages []*Message
+}
+
+func (r *memoryRepository) insert(subject string) {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	r.messages = append(r.messages, &Message{
+		ID:      int64(len(r.messages) + 1),
+		Subject: subject,
+	})
+}
+
+func (r *memoryRepository) GetPendingMessages(_ context.Context, limit int) ([]*Message, error) {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	if len(r.messages) < limit {
+		limit = len(r.messages)
+	}
+	return append([]*Message{}, r.messages[:limit]...), nil
+}
+
+func (r *memoryRepository) DeleteMessages(_ context.Context, ids []int64) error {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	deleted := map[int64]bool{}
+	for _, id := range ids {
+		deleted[id] = true
+	}
+	kept := []*Message{}
+	for _, m := range r.messages {
+		if !deleted[m.ID] {
+			kept = append(kept, m)
+		}
+	}
+	r.messages = kept
+	return nil
+}
+
+func (r *memoryRepository) pending() int {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	return len(r.messages)
+}
+
+func TestRelayOutboxPublishesInOrder(t *testing.T) {
+	repo := &memoryRepository{}
+	publisher := NewMemoryPublisher()
+	service := NewBusService(&BusConfig{Enabled: true, SubjectPrefix: "candlestick"}, repo, publisher, testutil.NopLogger{})
+
+	for i := 0; i < OUTBOX_BATCH_SIZE+5; i++ {
+		repo.insert("candlestick.bars.BTCUSDT")
+	}
+	service.relayOutbox(context.Background())
+
+	published := publisher.Messages()
+	if len(published) != OUTBOX_BATCH_SIZE+5 {
+		t.Fatalf("expected every message to be published, got %d", len(published))
+	}
+	for i, m := range published {
+		if m.ID != int64(i+1) {
+			t.Fatalf("expected message %d at position %d, got %d", i+1, i, m.ID)
+		}
+	}
+	if repo.pending() != 0 {
+		t.Fatalf("expected the outbox to be emptied, %d messages left", repo.pending())
+	}
+}
+
+func TestRelayOutboxKeepsMessagesUntilPublished(t *testing.T) {
+	repo := &memoryRepository{}
+	publisher := NewMemoryPublisher()
+	service := NewBusService(&BusConfig{Enabled: true, SubjectPrefix: "candlestick"}, repo, publisher, testutil.NopLogger{})
+
+	repo.insert("candlestick.bars.BTCUSDT")
+	repo.insert("candlestick.bars.ETHUSDT")
+
+	publisher.SetFailing(true)
+	service.relayOutbox(context.Background())
+	if len(publisher.Messages()) != 0 || repo.pending() != 2 {
+		t.Fatalf("expected the messages to stay in the outbox while the bus fails")
+	}
+
+	publisher.SetFailing(false)
+	service.relayOutbox(context.Background())
+	if len(publisher.Messages()) != 2 || repo.pending() != 0 {
+		t.Fatalf("expected the messages to be published once the bus recovered")
+	}
+}
+
+func TestBarClosedMessageIsNilWhenDisabled(t *testing.T) {
+	service := NewBusService(&BusConfig{}, nil, nil, testutil.NopLogger{})
+
+	message, err := service.BarClosedMessage(events.BarClosed{Symbol: "BTCUSDT"})
+	if err != nil || message != nil {
+		t.Fatalf("expected no message while the bus is disabled, got %+v, %v", message, err)
+	}
+}
@@ -0,0 +1,8 @@
+package candlestick
+
+import "time"
+
+type CandlestickConfig struct {
+	// how often the bars in progress are saved, to be restored after a crash
+	CheckpointInterval time.Duration
+}
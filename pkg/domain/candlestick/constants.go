@@ -0,0 +1,13 @@
+package candlestick
+
+import "time"
+
+const (
+	// number of closed bars kept in memory per symbol for subscription snapshots
+	MAX_RECENT_BARS = 100
+	// number of bar updates kept in memory per symbol for resuming subscriptions
+	MAX_JOURNAL_UPDATES = 5000
+	// how often the bars in progress are saved by default, to be restored
+	// after a crash
+	DEFAULT_CHECKPOINT_INTERVAL = 5 * time.Second
+)
@@ -1,10 +1,38 @@
 package candlestick
 
-import "context"
+import (
+	"context"
+	"time"
+
+	"github.com/ramasbeinaty/trading-chart-service/pkg/domain/bus"
+)
 
 type IRepository interface {
 	UpsertCandlestickBar(
 		ctx context.Context,
 		bar *Candlestick,
 	) error
+	// upserts the bar and writes the message to the outbox, if any,
+	// in a single transaction
+	CommitCandlestickBar(
+		ctx context.Context,
+		bar *Candlestick,
+		message *bus.Message,
+	) error
+	// returns the symbol's committed bars within the range, oldest first
+	GetCandlestickBars(
+		ctx context.Context,
+		symbol string,
+		from time.Time,
+		to time.Time,
+	) ([]*Candlestick, error)
+	// replaces the stored bars in progress with the given ones
+	SaveInProgressBars(
+		ctx context.Context,
+		bars []*Candlestick,
+	) error
+	// returns the bars in progress last saved, oldest first
+	GetInProgressBars(
+		ctx context.Context,
+	) ([]*Candlestick, error)
 }
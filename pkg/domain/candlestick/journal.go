@@ -0,0 +1,69 @@
+package candlestick
+
+import "sort"
+
+// journal keeps the latest bar updates of each symbol in memory, so
+// reconnecting subscribers can be replayed the updates they missed
+type journal struct {
+	capacity int
+	updates  map[string][]Candlestick // keyed by symbol, oldest first
+}
+
+func newJournal(capacity int) *journal {
+	return &journal{
+		capacity: capacity,
+		updates:  make(map[string][]Candlestick),
+	}
+}
+
+// records a copy of the bar as it is after an update
+func (j *journal) append(candle *Candlestick) {
+	updates := append(j.updates[candle.Symbol], *candle)
+	if len(updates) > j.capacity {
+		updates = updates[len(updates)-j.capacity:]
+	}
+	j.updates[candle.Symbol] = updates
+}
+
+// returns the updates of the symbol that came after the given sequence
+// ok is false if some of them were already dropped from the journal, or
+// never reached it, as the updates a follower missed while reconnecting
+func (j *journal) since(
+	symbol string,
+	sequence uint64,
+) (updates []Candlestick, ok bool) {
+	all := j.updates[symbol]
+	if len(all) == 0 {
+		// nothing was missed unless the client saw updates this journal never had
+		return nil, sequence == 0
+	}
+
+	last := all[len(all)-1].Sequence
+	if sequence > last {
+		// the client saw sequences this journal never had, e.g. before a restart
+		return nil, false
+	}
+
+	// the sequences increase but may skip some, so the first update after
+	// the given sequence is searched for
+	i := sort.Search(len(all), func(i int) bool {
+		return all[i].Sequence > sequence
+	})
+	updates = all[i:]
+	// every update after the given sequence is journaled only if none is
+	// skipped
+	if uint64(len(updates)) != last-sequence {
+		return nil, false
+	}
+
+	return updates, true
+}
+
+// returns the latest update of the symbol, if any
+func (j *journal) latest(symbol string) (Candlestick, bool) {
+	all := j.updates[symbol]
+	if len(all) == 0 {
+		return Candlestick{}, false
+	}
+	return all[len(all)-1], true
+}
@@ -2,6 +2,8 @@ package candlestick
 
 import (
 	"time"
+
+	"github.com/ramasbeinaty/trading-chart-service/pkg/domain/indicator"
 )
 
 type Candlestick struct {
@@ -11,4 +13,19 @@ type Candlestick struct {
 	Low            float64
 	Close          float64
 	TradeTimestamp time.Time
+	Sequence       uint64 // sequence number of the last update applied to the bar
+}
+
+// BarChange is a closed bar committed to the db, possibly by another instance
+type BarChange struct {
+	Bar *Candlestick
+	// the bar was committed before and was updated since
+	Correction bool
+}
+
+type SubscribeOptions struct {
+	Snapshot     bool              // send the latest bars before the live updates
+	SnapshotBars int               // number of closed bars included in the snapshot
+	ResumeFrom   map[string]uint64 // last sequence the subscriber received, keyed by symbol
+	Indicators   []indicator.Spec  // indicators sent along with the live updates
 }
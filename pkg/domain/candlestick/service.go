@@ -2,37 +2,83 @@ package candlestick
 
 import (
 	"context"
+	"fmt"
+	"sort"
 	"sync"
 	"time"
 
+	"github.com/ramasbeinaty/trading-chart-service/pkg/domain/alert"
+	"github.com/ramasbeinaty/trading-chart-service/pkg/domain/base/events"
 	"github.com/ramasbeinaty/trading-chart-service/pkg/domain/base/logger"
+	"github.com/ramasbeinaty/trading-chart-service/pkg/domain/base/metrics"
+	"github.com/ramasbeinaty/trading-chart-service/pkg/domain/bus"
+	"github.com/ramasbeinaty/trading-chart-service/pkg/domain/indicator"
 	"github.com/ramasbeinaty/trading-chart-service/pkg/domain/subscription"
-	"github.com/ramasbeinaty/trading-chart-service/proto/candlestick/contracts"
+	"github.com/ramasbeinaty/trading-chart-service/pkg/domain/webhook"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
 	"go.uber.org/zap"
-	"google.golang.org/protobuf/types/known/timestamppb"
 )
 
+var tracer = otel.Tracer("github.com/ramasbeinaty/trading-chart-service/pkg/domain/candlestick")
+
 type CandlestickService struct {
 	repo         IRepository
 	lgr          logger.ILogger
+	metrics      metrics.IMetrics
 	candlesticks map[string]*Candlestick
-	mutex        sync.Mutex
+	recentBars   map[string][]*Candlestick // committed bars keyed by symbol, oldest first
+	sequences    map[string]uint64         // last update sequence keyed by symbol
+	lastTrades   map[string]time.Time      // when the last trade was processed keyed by symbol
+	journal      *journal
+	// whether the bars in progress changed since the last checkpoint
+	checkpointDirty bool
+	mutex           sync.Mutex
+	// serializes the checkpoints, for an older one not to replace a newer one
+	checkpointMutex sync.Mutex
 
 	subscriptionService *subscription.SubscriptionService
+	indicatorService    *indicator.IndicatorService
+	alertService        *alert.AlertService
+	webhookDispatcher   *webhook.WebhookDispatcher
+	busService          *bus.BusService
 }
 
 func NewCandlestickService(
 	repo IRepository,
 	lgr logger.ILogger,
+	metrics metrics.IMetrics,
 	subscriptionService *subscription.SubscriptionService,
+	indicatorService *indicator.IndicatorService,
+	alertService *alert.AlertService,
+	webhookDispatcher *webhook.WebhookDispatcher,
+	busService *bus.BusService,
 ) *CandlestickService {
-	return &CandlestickService{
+	c := &CandlestickService{
 		repo:                repo,
 		lgr:                 lgr,
+		metrics:             metrics,
 		candlesticks:        make(map[string]*Candlestick),
+		recentBars:          make(map[string][]*Candlestick),
+		sequences:           make(map[string]uint64),
+		lastTrades:          make(map[string]time.Time),
+		journal:             newJournal(MAX_JOURNAL_UPDATES),
 		mutex:               sync.Mutex{},
+		checkpointMutex:     sync.Mutex{},
 		subscriptionService: subscriptionService,
+		indicatorService:    indicatorService,
+		alertService:        alertService,
+		webhookDispatcher:   webhookDispatcher,
+		busService:          busService,
+	}
+
+	// the indicators are computed as long as a subscriber receives them
+	if indicatorService != nil {
+		subscriptionService.OnUnsubscribe(indicatorService.Release)
 	}
+	return c
 }
 
 func (c *CandlestickService) ProcessTicks(
@@ -41,13 +87,21 @@ func (c *CandlestickService) ProcessTicks(
 	price float64,
 	tradeTimestamp time.Time,
 ) error {
+	c.mutex.Lock()
+
+	ctx, span := c.startBarSpan(
+		ctx,
+		"CandlestickService.ProcessTicks",
+		symbol,
+		tradeTimestamp,
+		attribute.Float64("price", price),
+	)
+	defer span.End()
+
 	lgr := c.lgr.Get(ctx)
 	lgr.Info("Processing ticks...")
 
-	c.mutex.Lock()
-	defer c.mutex.Unlock()
-
-	key := symbol + tradeTimestamp.Truncate(time.Minute).Format("200602011504")
+	key := barKey(symbol, tradeTimestamp)
 	var (
 		candle *Candlestick
 		exists bool
@@ -88,17 +142,44 @@ func (c *CandlestickService) ProcessTicks(
 		candle = c.candlesticks[key]
 	}
 
-	c.subscriptionService.BroadcastToSubscribers(
-		ctx,
-		&contracts.Candlestick{
-			Symbol:         candle.Symbol,
-			OpenPrice:      candle.Open,
-			HighPrice:      candle.High,
-			LowPrice:       candle.Low,
-			ClosePrice:     candle.Close,
-			TradeTimestamp: timestamppb.New(candle.TradeTimestamp),
-		},
+	// symbols first seen are matched against the subscribers' patterns
+	if _, tracked := c.sequences[symbol]; !tracked {
+		c.subscriptionService.TrackSymbol(ctx, symbol)
+	}
+
+	c.lastTrades[symbol] = time.Now()
+	c.checkpointDirty = true
+	c.sequences[symbol]++
+	candle.Sequence = c.sequences[symbol]
+	c.journal.append(candle)
+
+	event := toCandlestickEvent(
+		candle,
+		subscription.EVENT_KIND_LIVE,
 	)
+	event.Indicators = c.indicatorService.Update(
+		symbol,
+		candle.TradeTimestamp,
+		candle.Close,
+	)
+
+	recipients := c.subscriptionService.Recipients(symbol)
+
+	bar := alert.Bar{
+		Symbol:    candle.Symbol,
+		Timestamp: candle.TradeTimestamp,
+		Open:      candle.Open,
+		High:      candle.High,
+		Low:       candle.Low,
+		Close:     candle.Close,
+	}
+
+	c.mutex.Unlock()
+
+	// the update is fanned out without holding the mutex, the ticks being
+	// processed one after the other for the updates to stay in order
+	c.subscriptionService.BroadcastToRecipients(ctx, event, recipients)
+	c.alertService.OnBarUpdate(ctx, bar)
 
 	return nil
 }
@@ -106,31 +187,860 @@ func (c *CandlestickService) ProcessTicks(
 func (c *CandlestickService) CommitCompleteBars(
 	ctx context.Context,
 ) error {
+	ctx, span := tracer.Start(ctx, "CandlestickService.CommitCompleteBars")
+	defer span.End()
+
 	lgr := c.lgr.Get(ctx)
 	lgr.Info("Committing complete bars...")
 
+	// bars that ended are closed for the alerts, even without a later tick
+	c.alertService.CloseBars(ctx, time.Now())
+
+	committed, err := c.commitCompleteBars(ctx)
+
+	// the webhooks are handed the committed bars without holding off the ticks,
+	// including the ones committed before a failure
+	for _, candle := range committed {
+		err := c.webhookDispatcher.Publish(
+			ctx,
+			webhook.EVENT_TYPE_BAR_CLOSED,
+			candle.Symbol,
+			events.BarClosed{
+				Symbol:         candle.Symbol,
+				Open:           candle.Open,
+				High:           candle.High,
+				Low:            candle.Low,
+				Close:          candle.Close,
+				TradeTimestamp: candle.TradeTimestamp,
+			},
+		)
+		if err != nil {
+			lgr.Error(
+				"Failed to publish closed bar to webhooks",
+				zap.Any("candle", candle),
+				zap.Error(err),
+			)
+		}
+	}
+
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+		return err
+	}
+
+	lgr.Info("Successfully committed completed bars")
+
+	return nil
+}
+
+// commits the bars that ended, returning a copy of the ones committed
+func (c *CandlestickService) commitCompleteBars(
+	ctx context.Context,
+) ([]Candlestick, error) {
+	lgr := c.lgr.Get(ctx)
+
 	c.mutex.Lock()
 	defer c.mutex.Unlock()
 
+	now := time.Now()
+
+	committed := []Candlestick{}
 	for key, candle := range c.candlesticks {
-		err := c.repo.UpsertCandlestickBar(
+		// the bar in progress keeps receiving ticks until its minute ends
+		if now.Before(candle.TradeTimestamp.Add(time.Minute)) {
+			continue
+		}
+
+		// published to the bus once stored, through the outbox
+		message, err := c.busService.BarClosedMessage(events.BarClosed{
+			Symbol:         candle.Symbol,
+			Open:           candle.Open,
+			High:           candle.High,
+			Low:            candle.Low,
+			Close:          candle.Close,
+			TradeTimestamp: candle.TradeTimestamp,
+		})
+		if err != nil {
+			lgr.Error(
+				"Error: failed to build closed bar message",
+				zap.Any("candle", candle),
+				zap.Error(err),
+			)
+			return committed, err
+		}
+
+		start := time.Now()
+		err = c.repo.CommitCandlestickBar(
 			ctx,
 			candle,
+			message,
 		)
 		if err != nil {
+			c.metrics.BarCommitFailed(candle.Symbol)
 			lgr.Error(
 				"Error: failed to commit complete bar",
 				zap.Any("candle", candle),
 				zap.Error(err),
 			)
-			return err
+			return committed, err
 		}
 
+		c.metrics.BarCommitted(candle.Symbol, time.Since(start))
+
 		// remove bar from memory after storing it in db
 		delete(c.candlesticks, key)
+		c.addRecentBar(candle)
+		committed = append(committed, *candle)
 	}
 
-	lgr.Info("Successfully committed completed bars")
+	return committed, nil
+}
+
+// FlushBars commits the bars that ended, then saves the bars in progress,
+// for them to be restored once started again
+func (c *CandlestickService) FlushBars(
+	ctx context.Context,
+) error {
+	ctx, span := tracer.Start(ctx, "CandlestickService.FlushBars")
+	defer span.End()
+
+	if err := c.CommitCompleteBars(ctx); err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+		return err
+	}
+
+	if err := c.CheckpointBars(ctx); err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+		return err
+	}
 
 	return nil
 }
+
+// CheckpointBars saves the bars in progress if they changed since the last
+// checkpoint, for them to be restored after a crash
+// the bars are copied, then saved without holding off the ticks
+func (c *CandlestickService) CheckpointBars(
+	ctx context.Context,
+) error {
+	lgr := c.lgr.Get(ctx)
+
+	c.checkpointMutex.Lock()
+	defer c.checkpointMutex.Unlock()
+
+	c.mutex.Lock()
+	if !c.checkpointDirty {
+		c.mutex.Unlock()
+		return nil
+	}
+
+	bars := make([]*Candlestick, 0, len(c.candlesticks))
+	for _, candle := range c.candlesticks {
+		bar := *candle
+		bars = append(bars, &bar)
+	}
+
+	// the ticks processed while saving make the next checkpoint save them
+	c.checkpointDirty = false
+	c.mutex.Unlock()
+
+	if err := c.repo.SaveInProgressBars(ctx, bars); err != nil {
+		lgr.Error(
+			"Error: failed to checkpoint bars in progress",
+			zap.Int("bars", len(bars)),
+			zap.Error(err),
+		)
+
+		c.mutex.Lock()
+		c.checkpointDirty = true
+		c.mutex.Unlock()
+		return err
+	}
+
+	return nil
+}
+
+// RestoreBars loads the bars in progress saved by the last checkpoint,
+// for the bars to continue with their open, high and low, and the symbols'
+// updates with their sequence
+// the bars that ended are committed by the next CommitCompleteBars, unless
+// they were already, e.g. by the previous leader
+func (c *CandlestickService) RestoreBars(
+	ctx context.Context,
+) error {
+	lgr := c.lgr.Get(ctx)
+
+	bars, err := c.repo.GetInProgressBars(ctx)
+	if err != nil {
+		return fmt.Errorf("Failed to restore bars in progress - %w", err)
+	}
+
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	for _, bar := range bars {
+		key := barKey(bar.Symbol, bar.TradeTimestamp)
+		// the ticks processed or the updates followed since are more recent
+		if _, exists := c.candlesticks[key]; exists {
+			continue
+		}
+		bar.TradeTimestamp = bar.TradeTimestamp.UTC()
+		c.candlesticks[key] = bar
+
+		// resumed subscribers are told about a gap rather than sent sequences
+		// they already received
+		if _, tracked := c.sequences[bar.Symbol]; !tracked {
+			c.subscriptionService.TrackSymbol(ctx, bar.Symbol)
+		}
+		c.sequences[bar.Symbol] = max(c.sequences[bar.Symbol], bar.Sequence)
+	}
+
+	now := time.Now()
+	for key, candle := range c.candlesticks {
+		if now.Before(candle.TradeTimestamp.Add(time.Minute)) {
+			continue
+		}
+
+		committed, err := c.repo.GetCandlestickBars(
+			ctx,
+			candle.Symbol,
+			candle.TradeTimestamp,
+			candle.TradeTimestamp,
+		)
+		if err != nil {
+			return fmt.Errorf("Failed to restore bars in progress - %w", err)
+		}
+		if len(committed) > 0 {
+			delete(c.candlesticks, key)
+			c.addRecentBar(candle)
+		}
+	}
+
+	lgr.Info("Restored bars in progress", zap.Int("bars", len(bars)))
+	return nil
+}
+
+// ResetUpdates drops the journaled updates, before following a leader whose
+// sequences may not continue them
+func (c *CandlestickService) ResetUpdates(
+	ctx context.Context,
+) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	c.journal = newJournal(MAX_JOURNAL_UPDATES)
+}
+
+// ApplyUpdate applies a bar update of the leader, while following it
+// the leader's bars are kept with their sequence, and its live updates are
+// broadcast to the subscribers as if the ticks were processed here
+func (c *CandlestickService) ApplyUpdate(
+	ctx context.Context,
+	event *subscription.CandlestickEvent,
+) {
+	c.mutex.Lock()
+	ctx, span := c.startBarSpan(
+		ctx,
+		"CandlestickService.ApplyUpdate",
+		event.Symbol,
+		event.TradeTimestamp,
+	)
+	c.mutex.Unlock()
+	defer span.End()
+
+	update, recipients := c.applyUpdate(ctx, event)
+	if update == nil {
+		return
+	}
+
+	c.subscriptionService.BroadcastToRecipients(ctx, update, recipients)
+}
+
+// applies the leader's bar update, returning the live update to broadcast
+// along with its recipients, if any
+func (c *CandlestickService) applyUpdate(
+	ctx context.Context,
+	event *subscription.CandlestickEvent,
+) (*subscription.CandlestickEvent, []*subscription.Subscriber) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	candle := &Candlestick{
+		Symbol:         event.Symbol,
+		Open:           event.Open,
+		High:           event.High,
+		Low:            event.Low,
+		Close:          event.Close,
+		TradeTimestamp: event.TradeTimestamp.UTC(),
+		Sequence:       event.Sequence,
+	}
+
+	// the leader commits a bar once a later one starts
+	current := candle
+	for key, bar := range c.candlesticks {
+		if bar.Symbol != candle.Symbol {
+			continue
+		}
+		if bar.TradeTimestamp.Before(candle.TradeTimestamp) {
+			delete(c.candlesticks, key)
+			c.addRecentBar(bar)
+		} else if bar.TradeTimestamp.After(candle.TradeTimestamp) {
+			current = bar
+		}
+	}
+	if current == candle {
+		c.candlesticks[barKey(candle.Symbol, candle.TradeTimestamp)] = candle
+	} else {
+		c.addRecentBar(candle)
+	}
+
+	if _, tracked := c.sequences[candle.Symbol]; !tracked {
+		c.subscriptionService.TrackSymbol(ctx, candle.Symbol)
+	}
+	c.sequences[candle.Symbol] = candle.Sequence
+
+	// snapshots only restore the state, only the live updates are broadcast
+	if event.Kind != subscription.EVENT_KIND_LIVE {
+		return nil, nil
+	}
+
+	c.journal.append(candle)
+
+	update := toCandlestickEvent(
+		candle,
+		subscription.EVENT_KIND_LIVE,
+	)
+	update.Indicators = c.indicatorService.Update(
+		candle.Symbol,
+		candle.TradeTimestamp,
+		candle.Close,
+	)
+
+	return update, c.subscriptionService.Recipients(candle.Symbol)
+}
+
+// ApplyBarChange applies a bar committed by another instance, read from
+// the change feed, and broadcasts it to the subscribers
+// the bars are sequenced here, as the instance committing them doesn't
+// share its sequences
+func (c *CandlestickService) ApplyBarChange(
+	ctx context.Context,
+	change *BarChange,
+) {
+	bar := change.Bar
+
+	ctx, span := tracer.Start(
+		ctx,
+		"CandlestickService.ApplyBarChange",
+		trace.WithAttributes(
+			attribute.String("symbol", bar.Symbol),
+			attribute.Bool("correction", change.Correction),
+		),
+	)
+	defer span.End()
+
+	c.mutex.Lock()
+
+	if _, tracked := c.sequences[bar.Symbol]; !tracked {
+		c.subscriptionService.TrackSymbol(ctx, bar.Symbol)
+	}
+	c.sequences[bar.Symbol]++
+	bar.Sequence = c.sequences[bar.Symbol]
+
+	c.addRecentBar(bar)
+	c.journal.append(bar)
+
+	event := toCandlestickEvent(
+		bar,
+		subscription.EVENT_KIND_LIVE,
+	)
+	// corrections of earlier bars would move the indicators back
+	if !change.Correction {
+		event.Indicators = c.indicatorService.Update(
+			bar.Symbol,
+			bar.TradeTimestamp,
+			bar.Close,
+		)
+	}
+
+	recipients := c.subscriptionService.Recipients(bar.Symbol)
+	c.mutex.Unlock()
+
+	c.subscriptionService.BroadcastToRecipients(ctx, event, recipients)
+}
+
+// keys the bar of the symbol's minute containing the timestamp
+// starts the span of the update opening the symbol's bar, the later updates
+// of the bar being left out of the traces for every trade not to start one
+// c.mutex must be held
+func (c *CandlestickService) startBarSpan(
+	ctx context.Context,
+	name string,
+	symbol string,
+	timestamp time.Time,
+	attributes ...attribute.KeyValue,
+) (context.Context, trace.Span) {
+	if _, exists := c.candlesticks[barKey(symbol, timestamp)]; exists {
+		return ctx, trace.SpanFromContext(context.Background())
+	}
+
+	return tracer.Start(
+		ctx,
+		name,
+		trace.WithAttributes(
+			append(
+				[]attribute.KeyValue{attribute.String("symbol", symbol)},
+				attributes...,
+			)...,
+		),
+	)
+}
+
+func barKey(
+	symbol string,
+	timestamp time.Time,
+) string {
+	return symbol + timestamp.UTC().Truncate(time.Minute).Format("200602011504")
+}
+
+// LastTradeAt returns when the symbol's last trade was processed,
+// or the zero time if none was
+func (c *CandlestickService) LastTradeAt(
+	symbol string,
+) time.Time {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	return c.lastTrades[symbol]
+}
+
+// CommitBacklog returns the number of bars that ended by now
+// and are still waiting to be committed
+func (c *CandlestickService) CommitBacklog(
+	now time.Time,
+) int {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	backlog := 0
+	for _, candle := range c.candlesticks {
+		if !now.Before(candle.TradeTimestamp.Add(time.Minute)) {
+			backlog++
+		}
+	}
+	return backlog
+}
+
+// InProgressBars returns a copy of the bars held in memory, including the
+// ones that ended and wait to be committed, ordered by symbol then time
+func (c *CandlestickService) InProgressBars() []*Candlestick {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	bars := make([]*Candlestick, 0, len(c.candlesticks))
+	for _, candle := range c.candlesticks {
+		bar := *candle
+		bars = append(bars, &bar)
+	}
+
+	sort.Slice(bars, func(i, j int) bool {
+		if bars[i].Symbol != bars[j].Symbol {
+			return bars[i].Symbol < bars[j].Symbol
+		}
+		return bars[i].TradeTimestamp.Before(bars[j].TradeTimestamp)
+	})
+
+	return bars
+}
+
+// Subscribe registers the subscriber for live updates of the symbols,
+// which may contain patterns matching tracked symbols.
+// Before that, symbols being resumed are replayed the updates missed since
+// their last received sequence, or a gap event if those are no longer
+// available, and the other symbols are sent a snapshot of their latest bars
+// if requested.
+// The missed updates are copied along with registering the subscriber, then
+// sent without holding off the ticks, the live updates broadcast meanwhile
+// being held for the subscriber until they are sent. Live updates so continue
+// right after the sent sequence numbers without gaps or duplicates.
+// The requested indicators are tracked on the symbols resolved at this point,
+// warmed up with their stored bars, and sent along with the live updates.
+func (c *CandlestickService) Subscribe(
+	ctx context.Context,
+	subscriberId int64,
+	symbols []string,
+	opts SubscribeOptions,
+	sink subscription.Sink,
+) error {
+	// patterns are sent the bars of the tracked symbols they match
+	resolved, err := c.subscriptionService.ResolveSymbols(ctx, symbols)
+	if err != nil {
+		return err
+	}
+
+	// stored bars are loaded before holding off ticks
+	history, err := c.loadIndicatorHistory(ctx, resolved, opts.Indicators)
+	if err != nil {
+		return err
+	}
+
+	events, err := c.register(ctx, subscriberId, symbols, resolved, history, opts, sink)
+	if err != nil {
+		return err
+	}
+
+	for _, event := range events {
+		if err := sink.Send(event); err != nil {
+			// the transport is broken, the subscriber is removed with it
+			c.subscriptionService.RemoveSubscriber(ctx, subscriberId, nil)
+			return fmt.Errorf("Failed to send missed updates of %s - %w", event.Symbol, err)
+		}
+	}
+
+	if err := c.subscriptionService.ReleaseSubscriber(ctx, subscriberId); err != nil {
+		return err
+	}
+
+	if len(opts.Indicators) == 0 {
+		return nil
+	}
+
+	names := make([]string, 0, len(opts.Indicators))
+	for _, spec := range opts.Indicators {
+		names = append(names, spec.Name())
+	}
+	return c.subscriptionService.SetSubscriberIndicators(ctx, subscriberId, names)
+}
+
+// registers the subscriber and tracks its indicators, holding off its
+// broadcasts, returning the events it missed to be sent first
+// the mutex is held meanwhile, for no tick to be processed in between
+func (c *CandlestickService) register(
+	ctx context.Context,
+	subscriberId int64,
+	symbols []string,
+	resolved []string,
+	history map[string][]*Candlestick,
+	opts SubscribeOptions,
+	sink subscription.Sink,
+) ([]*subscription.CandlestickEvent, error) {
+	lgr := c.lgr.Get(ctx)
+
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	events := []*subscription.CandlestickEvent{}
+	for _, symbol := range resolved {
+		if sequence, ok := opts.ResumeFrom[symbol]; ok {
+			events = append(events, c.missedUpdates(ctx, subscriberId, symbol, sequence)...)
+			continue
+		}
+
+		if !opts.Snapshot {
+			continue
+		}
+
+		snapshot := c.getSnapshot(symbol, opts.SnapshotBars)
+
+		lgr.Info(
+			"Sending snapshot to subscriber",
+			zap.Int64("subscriberId", subscriberId),
+			zap.String("symbol", symbol),
+			zap.Int("bars", len(snapshot)),
+		)
+
+		for _, bar := range snapshot {
+			events = append(events, toCandlestickEvent(
+				bar,
+				subscription.EVENT_KIND_SNAPSHOT,
+			))
+		}
+	}
+
+	err := c.subscriptionService.AddUpdateSubscriber(
+		ctx,
+		subscriberId,
+		symbols,
+		sink,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	// tracked once subscribed, for them to be released along with the
+	// subscriber's symbols, the ones already tracked needing no warm up
+	tracked := []string{}
+	if len(opts.Indicators) != 0 {
+		for _, symbol := range resolved {
+			var bars []indicator.Bar
+			if committed, ok := history[symbol]; ok {
+				bars = c.mergeBars(symbol, committed, time.Time{})
+			}
+			for _, spec := range opts.Indicators {
+				c.indicatorService.Track(subscriberId, symbol, spec, bars)
+			}
+			tracked = append(tracked, symbol)
+		}
+	}
+
+	if err := c.subscriptionService.HoldSubscriber(subscriberId); err != nil {
+		// removed meanwhile, before the indicators were tracked
+		c.indicatorService.Release(subscriberId, tracked)
+		return nil, err
+	}
+
+	return events, nil
+}
+
+// GetIndicatorHistory returns the indicator's values for the symbol's
+// timeframe bars opened within the range, oldest first
+// bars before the range are used to warm the indicator up
+func (c *CandlestickService) GetIndicatorHistory(
+	ctx context.Context,
+	symbol string,
+	spec indicator.Spec,
+	from time.Time,
+	to time.Time,
+) ([]indicator.Value, error) {
+	from = from.Truncate(spec.Timeframe.Duration())
+
+	committed, err := c.repo.GetCandlestickBars(
+		ctx,
+		symbol,
+		from.Add(-spec.WarmupDuration()),
+		to,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("Failed to get bars of %s - %w", symbol, err)
+	}
+
+	c.mutex.Lock()
+	bars := c.mergeBars(symbol, committed, from.Add(-spec.WarmupDuration()))
+	c.mutex.Unlock()
+
+	values := []indicator.Value{}
+	for _, value := range c.indicatorService.ComputeHistory(spec, bars) {
+		if value.Timestamp.Before(from) || value.Timestamp.After(to) {
+			continue
+		}
+		values = append(values, value)
+	}
+
+	return values, nil
+}
+
+// loads the stored bars needed to warm up the indicators not tracked yet,
+// keyed by symbol
+func (c *CandlestickService) loadIndicatorHistory(
+	ctx context.Context,
+	symbols []string,
+	specs []indicator.Spec,
+) (map[string][]*Candlestick, error) {
+	history := map[string][]*Candlestick{}
+
+	for _, symbol := range symbols {
+		var warmup time.Duration
+		for _, spec := range specs {
+			if !c.indicatorService.IsTracked(symbol, spec) {
+				warmup = max(warmup, spec.WarmupDuration())
+			}
+		}
+		if warmup == 0 {
+			continue
+		}
+
+		now := time.Now()
+		bars, err := c.repo.GetCandlestickBars(ctx, symbol, now.Add(-warmup), now)
+		if err != nil {
+			return nil, fmt.Errorf("Failed to get bars of %s - %w", symbol, err)
+		}
+		history[symbol] = bars
+	}
+
+	return history, nil
+}
+
+// merges the symbol's stored bars with the ones in memory, which are more
+// recent versions of them, returning the ones opened from the given time
+// oldest first
+// expects the caller to hold the mutex
+func (c *CandlestickService) mergeBars(
+	symbol string,
+	committed []*Candlestick,
+	from time.Time,
+) []indicator.Bar {
+	// keyed by unix time, as stored bars may be in another location
+	merged := map[int64]*Candlestick{}
+	for _, bar := range committed {
+		merged[bar.TradeTimestamp.Unix()] = bar
+	}
+	for _, bar := range c.recentBars[symbol] {
+		merged[bar.TradeTimestamp.Unix()] = bar
+	}
+	for _, bar := range c.candlesticks {
+		if bar.Symbol == symbol {
+			merged[bar.TradeTimestamp.Unix()] = bar
+		}
+	}
+
+	bars := make([]indicator.Bar, 0, len(merged))
+	for _, bar := range merged {
+		if bar.TradeTimestamp.Before(from) {
+			continue
+		}
+		bars = append(bars, indicator.Bar{
+			Timestamp: bar.TradeTimestamp,
+			Close:     bar.Close,
+		})
+	}
+
+	sort.Slice(bars, func(i, j int) bool {
+		return bars[i].Timestamp.Before(bars[j].Timestamp)
+	})
+
+	return bars
+}
+
+// returns the updates of the symbol after the given sequence from the journal,
+// or a gap event carrying the latest state if they are no longer available
+// expects the caller to hold the mutex
+func (c *CandlestickService) missedUpdates(
+	ctx context.Context,
+	subscriberId int64,
+	symbol string,
+	sequence uint64,
+) []*subscription.CandlestickEvent {
+	lgr := c.lgr.Get(ctx)
+
+	updates, ok := c.journal.since(symbol, sequence)
+	if !ok {
+		lgr.Info(
+			"Missed updates are no longer available, sending gap to subscriber",
+			zap.Int64("subscriberId", subscriberId),
+			zap.String("symbol", symbol),
+			zap.Uint64("resumeFrom", sequence),
+		)
+
+		latest, exists := c.journal.latest(symbol)
+		if !exists {
+			latest = Candlestick{
+				Symbol:   symbol,
+				Sequence: c.sequences[symbol],
+			}
+		}
+
+		return []*subscription.CandlestickEvent{
+			toCandlestickEvent(
+				&latest,
+				subscription.EVENT_KIND_GAP,
+			),
+		}
+	}
+
+	lgr.Info(
+		"Replaying missed updates to subscriber",
+		zap.Int64("subscriberId", subscriberId),
+		zap.String("symbol", symbol),
+		zap.Uint64("resumeFrom", sequence),
+		zap.Int("updates", len(updates)),
+	)
+
+	events := make([]*subscription.CandlestickEvent, 0, len(updates))
+	for i := range updates {
+		events = append(events, toCandlestickEvent(
+			&updates[i],
+			subscription.EVENT_KIND_REPLAY,
+		))
+	}
+
+	return events
+}
+
+// returns up to closedBars closed bars of the symbol followed by its
+// in-progress bar, oldest first
+// expects the caller to hold the mutex
+func (c *CandlestickService) getSnapshot(
+	symbol string,
+	closedBars int,
+) []*Candlestick {
+	var current *Candlestick
+	closed := append([]*Candlestick{}, c.recentBars[symbol]...)
+
+	// the latest bar still in memory is the one in progress
+	for _, candle := range c.candlesticks {
+		if candle.Symbol != symbol {
+			continue
+		}
+		if current == nil || candle.TradeTimestamp.After(current.TradeTimestamp) {
+			if current != nil {
+				closed = append(closed, current)
+			}
+			current = candle
+		} else {
+			closed = append(closed, candle)
+		}
+	}
+
+	sort.Slice(closed, func(i, j int) bool {
+		return closed[i].TradeTimestamp.Before(closed[j].TradeTimestamp)
+	})
+
+	if len(closed) > closedBars {
+		closed = closed[len(closed)-closedBars:]
+	}
+	if current != nil {
+		closed = append(closed, current)
+	}
+
+	return closed
+}
+
+// keeps the committed bar around for snapshots, dropping the oldest ones
+// expects the caller to hold the mutex
+func (c *CandlestickService) addRecentBar(
+	candle *Candlestick,
+) {
+	bars := c.recentBars[candle.Symbol]
+
+	// a bar committed more than once replaces its previous version
+	replaced := false
+	for i, bar := range bars {
+		if bar.TradeTimestamp.Equal(candle.TradeTimestamp) {
+			bars[i] = candle
+			replaced = true
+			break
+		}
+	}
+	if !replaced {
+		bars = append(bars, candle)
+	}
+
+	sort.Slice(bars, func(i, j int) bool {
+		return bars[i].TradeTimestamp.Before(bars[j].TradeTimestamp)
+	})
+
+	if len(bars) > MAX_RECENT_BARS {
+		bars = bars[len(bars)-MAX_RECENT_BARS:]
+	}
+
+	c.recentBars[candle.Symbol] = bars
+}
+
+func toCandlestickEvent(
+	candle *Candlestick,
+	kind subscription.EventKind,
+) *subscription.CandlestickEvent {
+	return &subscription.CandlestickEvent{
+		Symbol:         candle.Symbol,
+		Open:           candle.Open,
+		High:           candle.High,
+		Low:            candle.Low,
+		Close:          candle.Close,
+		TradeTimestamp: candle.TradeTimestamp,
+		Sequence:       candle.Sequence,
+		Kind:           kind,
+	}
+}
@@ -0,0 +1,913 @@
+package candlestick
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/ramasbeinaty/trading-chart-service/internal/testutil"
+	"github.com/ramasbeinaty/trading-chart-service/pkg/domain/alert"
+	"github.com/ramasbeinaty/trading-chart-service/pkg/domain/base/metrics"
+	"github.com/ramasbeinaty/trading-chart-service/pkg/domain/bus"
+	"github.com/ramasbeinaty/trading-chart-service/pkg/domain/indicator"
+	"github.com/ramasbeinaty/trading-chart-service/pkg/domain/subscription"
+	"github.com/ramasbeinaty/trading-chart-service/pkg/domain/webhook"
+)
+
+type nopRepository struct{}
+
+func (nopRepository) UpsertCandlestickBar(context.Context, *Candlestick) error { return nil }
+
+func (nopRepository) CommitCandlestickBar(context.Context, *Candlestick, *bus.Message) error {
+	return nil
+}
+
+func (nopRepository) GetCandlestickBars(context.Context, string, time.Time, time.Time) ([]*Candlestick, error) {
+	return nil, nil
+}
+
+func (nopRepository) SaveInProgressBars(context.Context, []*Candlestick) error { return nil }
+
+func (nopRepository) GetInProgressBars(context.Context) ([]*Candlestick, error) {
+	return nil, nil
+}
+
+type nopAlertRepository struct{}
+
+func (nopAlertRepository) CreateAlert(context.Context, *alert.Alert) error { return nil }
+func (nopAlertRepository) GetAlert(context.Context, int64) (*alert.Alert, error) {
+	return nil, nil
+}
+func (nopAlertRepository) GetAlerts(context.Context, string) ([]*alert.Alert, error) {
+	return nil, nil
+}
+func (nopAlertRepository) GetActiveAlerts(context.Context) ([]*alert.Alert, error) {
+	return nil, nil
+}
+func (nopAlertRepository) UpdateAlert(context.Context, *alert.Alert) (bool, error) {
+	return false, nil
+}
+func (nopAlertRepository) UpdateAlertFired(context.Context, *alert.Alert) error { return nil }
+func (nopAlertRepository) DeleteAlert(context.Context, int64) (bool, error) {
+	return false, nil
+}
+
+// fakeSink records the events sent to it
+type fakeSink struct {
+	mutex  sync.Mutex
+	events []*subscription.CandlestickEvent
+	done   chan struct{}
+	once   sync.Once
+}
+
+func newFakeSink() *fakeSink {
+	return &fakeSink{done: make(chan struct{})}
+}
+
+func (s *fakeSink) Send(event *subscription.CandlestickEvent) error {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	s.events = append(s.events, event)
+	return nil
+}
+
+func (s *fakeSink) received() []*subscription.CandlestickEvent {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	return append([]*subscription.CandlestickEvent{}, s.events...)
+}
+
+// waits for at least n events, the live updates being written to the
+// subscribers asynchronously, returning them
+func (s *fakeSink) waitFor(t *testing.T, n int) []*subscription.CandlestickEvent {
+	t.Helper()
+	deadline := time.Now().Add(time.Second)
+	for len(s.received()) < n {
+		if time.Now().After(deadline) {
+			t.Fatalf("timed out waiting for %d events, got %d", n, len(s.received()))
+		}
+		time.Sleep(time.Millisecond)
+	}
+	return s.received()
+}
+
+// the sink may be closed by both the subscriber and the service
+func (s *fakeSink) Close() {
+	s.once.Do(func() { close(s.done) })
+}
+
+func (s *fakeSink) Done() <-chan struct{} { return s.done }
+
+func newTestService() *CandlestickService {
+	return NewCandlestickService(
+		nopRepository{},
+		testutil.NopLogger{},
+		metrics.NopMetrics{},
+		subscription.NewSubscriptionService(testutil.NopLogger{}, metrics.NopMetrics{}),
+		indicator.NewIndicatorService(),
+		alert.NewAlertService(nopAlertRepository{}, testutil.NopLogger{}),
+		webhook.NewWebhookDispatcher(&webhook.WebhookConfig{}, nil, nil, testutil.NopLogger{}),
+		bus.NewBusService(&bus.BusConfig{}, nil, nil, testutil.NopLogger{}),
+	)
+}
+
+func TestSubscribeSendsSnapshotThenLiveUpdates(t *testing.T) {
+	ctx := context.Background()
+	service := newTestService()
+	start := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	// two closed bars, one of them committed, and one in progress
+	service.ProcessTicks(ctx, "BTCUSDT", 100, start)
+	service.CommitCompleteBars(ctx)
+	service.ProcessTicks(ctx, "BTCUSDT", 101, start.Add(time.Minute))
+	service.ProcessTicks(ctx, "BTCUSDT", 102, start.Add(2*time.Minute))
+
+	sink := newFakeSink()
+	err := service.Subscribe(
+		ctx,
+		1,
+		[]string{"BTCUSDT"},
+		SubscribeOptions{Snapshot: true, SnapshotBars: 1},
+		sink,
+	)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	service.ProcessTicks(ctx, "BTCUSDT", 103, start.Add(2*time.Minute))
+
+	want := []struct {
+		close    float64
+		sequence uint64
+		kind     subscription.EventKind
+	}{
+		{101, 2, subscription.EVENT_KIND_SNAPSHOT},
+		{102, 3, subscription.EVENT_KIND_SNAPSHOT},
+		{103, 4, subscription.EVENT_KIND_LIVE},
+	}
+	events := sink.waitFor(t, len(want))
+	if len(events) != len(want) {
+		t.Fatalf("expected %d events, got %d", len(want), len(events))
+	}
+	for i, w := range want {
+		got := events[i]
+		if got.Close != w.close || got.Sequence != w.sequence || got.Kind != w.kind {
+			t.Errorf("event %d: expected %+v, got %+v", i, w, got)
+		}
+	}
+}
+
+// slowSink blocks its first send until released
+type slowSink struct {
+	*fakeSink
+	sending chan struct{}
+	release chan struct{}
+}
+
+func (s *slowSink) Send(event *subscription.CandlestickEvent) error {
+	if len(s.received()) == 0 {
+		close(s.sending)
+		<-s.release
+	}
+	return s.fakeSink.Send(event)
+}
+
+func TestSubscribeDoesNotHoldOffTicksWhileSendingTheSnapshot(t *testing.T) {
+	ctx := context.Background()
+	service := newTestService()
+	start := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	service.ProcessTicks(ctx, "BTCUSDT", 100, start)
+
+	sink := &slowSink{
+		fakeSink: newFakeSink(),
+		sending:  make(chan struct{}),
+		release:  make(chan struct{}),
+	}
+	subscribed := make(chan error)
+	go func() {
+		subscribed <- service.Subscribe(
+			ctx,
+			1,
+			[]string{"BTCUSDT"},
+			SubscribeOptions{Snapshot: true, SnapshotBars: 1},
+			sink,
+		)
+	}()
+	<-sink.sending
+
+	// processed while the subscriber is still receiving its snapshot
+	ticked := make(chan struct{})
+	go func() {
+		service.ProcessTicks(ctx, "BTCUSDT", 101, start)
+		close(ticked)
+	}()
+	select {
+	case <-ticked:
+	case <-time.After(time.Second):
+		t.Fatal("expected the tick not to wait on the snapshot being sent")
+	}
+
+	close(sink.release)
+	if err := <-subscribed; err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	want := []struct {
+		close    float64
+		sequence uint64
+		kind     subscription.EventKind
+	}{
+		{100, 1, subscription.EVENT_KIND_SNAPSHOT},
+		{101, 2, subscription.EVENT_KIND_LIVE},
+	}
+	events := sink.waitFor(t, len(want))
+	if len(events) != len(want) {
+		t.Fatalf("expected %d events, got %d", len(want), len(events))
+	}
+	for i, w := range want {
+		got := events[i]
+		if got.Close != w.close || got.Sequence != w.sequence || got.Kind != w.kind {
+			t.Errorf("event %d: expected %+v, got %+v", i, w, got)
+		}
+	}
+}
+
+func TestProcessTicksBroadcastsWithoutHoldingTheMutex(t *testing.T) {
+	ctx := context.Background()
+	service := newTestService()
+	start := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	sink := &slowSink{
+		fakeSink: newFakeSink(),
+		sending:  make(chan struct{}),
+		release:  make(chan struct{}),
+	}
+	if err := service.Subscribe(ctx, 1, []string{"BTCUSDT"}, SubscribeOptions{}, sink); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	ticked := make(chan struct{})
+	go func() {
+		service.ProcessTicks(ctx, "BTCUSDT", 100, start)
+		close(ticked)
+	}()
+	<-sink.sending
+
+	// the bars stay available while the subscriber is slow to receive
+	read := make(chan struct{})
+	go func() {
+		service.InProgressBars()
+		close(read)
+	}()
+	select {
+	case <-read:
+	case <-time.After(time.Second):
+		t.Fatal("expected the bars not to wait on the broadcast")
+	}
+
+	close(sink.release)
+	<-ticked
+	if events := sink.waitFor(t, 1); len(events) != 1 || events[0].Close != 100 {
+		t.Fatalf("expected the update to be broadcast, got %+v", events)
+	}
+}
+
+func TestSubscribeResumesFromJournal(t *testing.T) {
+	ctx := context.Background()
+	service := newTestService()
+	now := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	for i := 0; i < 5; i++ {
+		service.ProcessTicks(ctx, "BTCUSDT", float64(100+i), now)
+	}
+
+	sink := newFakeSink()
+	err := service.Subscribe(
+		ctx,
+		1,
+		[]string{"BTCUSDT"},
+		SubscribeOptions{ResumeFrom: map[string]uint64{"BTCUSDT": 3}},
+		sink,
+	)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	events := sink.received()
+	if len(events) != 2 {
+		t.Fatalf("expected 2 replayed events, got %d", len(events))
+	}
+	for i, event := range events {
+		if event.Kind != subscription.EVENT_KIND_REPLAY || event.Sequence != uint64(4+i) {
+			t.Errorf("event %d: expected replay of sequence %d, got %+v", i, 4+i, event)
+		}
+	}
+}
+
+func TestSubscribeSendsGapWhenJournalIsMissingUpdates(t *testing.T) {
+	ctx := context.Background()
+	service := newTestService()
+	service.journal = newJournal(2)
+	now := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	for i := 0; i < 5; i++ {
+		service.ProcessTicks(ctx, "BTCUSDT", float64(100+i), now)
+	}
+
+	sink := newFakeSink()
+	err := service.Subscribe(
+		ctx,
+		1,
+		[]string{"BTCUSDT"},
+		SubscribeOptions{ResumeFrom: map[string]uint64{"BTCUSDT": 1}},
+		sink,
+	)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	events := sink.received()
+	if len(events) != 1 {
+		t.Fatalf("expected a single gap event, got %d events", len(events))
+	}
+	if gap := events[0]; gap.Kind != subscription.EVENT_KIND_GAP || gap.Sequence != 5 || gap.Close != 104 {
+		t.Errorf("expected gap carrying the latest bar, got %+v", gap)
+	}
+}
+
+func TestJournalReportsTheUpdatesItSkipped(t *testing.T) {
+	// a follower journals no update while reconnecting to the leader
+	j := newJournal(10)
+	for _, sequence := range []uint64{1, 2, 5, 6} {
+		j.append(&Candlestick{Symbol: "BTCUSDT", Sequence: sequence})
+	}
+
+	for _, sequence := range []uint64{0, 2, 3} {
+		if updates, ok := j.since("BTCUSDT", sequence); ok {
+			t.Errorf("expected the updates after %d to be missing, got %+v", sequence, updates)
+		}
+	}
+	if updates, ok := j.since("BTCUSDT", 5); !ok || len(updates) != 1 || updates[0].Sequence != 6 {
+		t.Errorf("expected the update after 5 to be journaled, got %+v", updates)
+	}
+	if updates, ok := j.since("BTCUSDT", 6); !ok || len(updates) != 0 {
+		t.Errorf("expected no update after the latest, got %+v", updates)
+	}
+}
+
+// historyRepository serves stored bars, regardless of the requested range
+type historyRepository struct {
+	nopRepository
+	bars []*Candlestick
+}
+
+func (r historyRepository) GetCandlestickBars(context.Context, string, time.Time, time.Time) ([]*Candlestick, error) {
+	return r.bars, nil
+}
+
+func TestSubscribeStreamsRequestedIndicators(t *testing.T) {
+	ctx := context.Background()
+	start := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	service := NewCandlestickService(
+		historyRepository{bars: []*Candlestick{
+			{Symbol: "BTCUSDT", Close: 1, TradeTimestamp: start},
+			{Symbol: "BTCUSDT", Close: 2, TradeTimestamp: start.Add(time.Minute)},
+		}},
+		testutil.NopLogger{},
+		metrics.NopMetrics{},
+		subscription.NewSubscriptionService(testutil.NopLogger{}, metrics.NopMetrics{}),
+		indicator.NewIndicatorService(),
+		alert.NewAlertService(nopAlertRepository{}, testutil.NopLogger{}),
+		webhook.NewWebhookDispatcher(&webhook.WebhookConfig{}, nil, nil, testutil.NopLogger{}),
+		bus.NewBusService(&bus.BusConfig{}, nil, nil, testutil.NopLogger{}),
+	)
+
+	service.ProcessTicks(ctx, "BTCUSDT", 3, start.Add(2*time.Minute))
+
+	sma, _ := indicator.ParseSpec("SMA(3)")
+	withIndicators, withoutIndicators := newFakeSink(), newFakeSink()
+	service.Subscribe(ctx, 1, []string{"BTCUSDT"}, SubscribeOptions{Indicators: []indicator.Spec{sma}}, withIndicators)
+	service.Subscribe(ctx, 2, []string{"BTCUSDT"}, SubscribeOptions{}, withoutIndicators)
+
+	service.ProcessTicks(ctx, "BTCUSDT", 6, start.Add(2*time.Minute))
+
+	events := withIndicators.waitFor(t, 1)
+	if len(events) != 1 || len(events[0].Indicators) != 1 {
+		t.Fatalf("expected a live update with the requested indicator, got %+v", events)
+	}
+	value := events[0].Indicators[0]
+	if value.Name != "SMA(3)@1m" || !value.Ready || value.Values["value"] != 3 {
+		t.Fatalf("expected SMA(3) of the stored and live bars to be 3, got %+v", value)
+	}
+
+	if events := withoutIndicators.waitFor(t, 1); len(events) != 1 || len(events[0].Indicators) != 0 {
+		t.Fatalf("expected a live update without indicators, got %+v", events)
+	}
+
+	history, err := service.GetIndicatorHistory(ctx, "BTCUSDT", sma, start, start.Add(2*time.Minute))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(history) != 3 || history[1].Ready || history[2].Values["value"] != 3 {
+		t.Fatalf("expected the SMA(3) history to settle on the third bar, got %+v", history)
+	}
+}
+
+func TestIndicatorsAreUntrackedOnceNoSubscriberReceivesThem(t *testing.T) {
+	ctx := context.Background()
+	start := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	subscriptionService := subscription.NewSubscriptionService(testutil.NopLogger{}, metrics.NopMetrics{})
+	indicatorService := indicator.NewIndicatorService()
+	service := NewCandlestickService(
+		historyRepository{},
+		testutil.NopLogger{},
+		metrics.NopMetrics{},
+		subscriptionService,
+		indicatorService,
+		alert.NewAlertService(nopAlertRepository{}, testutil.NopLogger{}),
+		webhook.NewWebhookDispatcher(&webhook.WebhookConfig{}, nil, nil, testutil.NopLogger{}),
+		bus.NewBusService(&bus.BusConfig{}, nil, nil, testutil.NopLogger{}),
+	)
+	service.ProcessTicks(ctx, "BTCUSDT", 1, start)
+	service.ProcessTicks(ctx, "ETHUSDT", 1, start)
+
+	sma, _ := indicator.ParseSpec("SMA(3)")
+	opts := SubscribeOptions{Indicators: []indicator.Spec{sma}}
+	service.Subscribe(ctx, 1, []string{"BTCUSDT", "ETHUSDT"}, opts, newFakeSink())
+	service.Subscribe(ctx, 2, []string{"BTCUSDT"}, opts, newFakeSink())
+
+	// still received by subscriber 2
+	subscriptionService.RemoveSubscriber(ctx, 1, []string{"BTCUSDT"})
+	if !indicatorService.IsTracked("BTCUSDT", sma) || !indicatorService.IsTracked("ETHUSDT", sma) {
+		t.Fatal("expected the indicators still received to be tracked")
+	}
+
+	subscriptionService.RemoveSubscriber(ctx, 1, nil)
+	if indicatorService.IsTracked("ETHUSDT", sma) {
+		t.Fatal("expected the indicator of ETHUSDT to be untracked with its last subscriber")
+	}
+
+	// disconnected rather than removed, closing its sink along the way
+	if err := subscriptionService.DisconnectSubscriber(ctx, 2, "test"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if indicatorService.IsTracked("BTCUSDT", sma) {
+		t.Fatal("expected the indicator of BTCUSDT to be untracked with its last subscriber")
+	}
+}
+
+// commitRepository records the committed bars along with their outbox messages
+type commitRepository struct {
+	nopRepository
+	bars     []*Candlestick
+	messages []*bus.Message
+}
+
+func (r *commitRepository) CommitCandlestickBar(_ context.Context, bar *Candlestick, message *bus.Message) error {
+	r.bars = append(r.bars, bar)
+	r.messages = append(r.messages, message)
+	return nil
+}
+
+func TestCommitCompleteBarsWritesBusMessage(t *testing.T) {
+	ctx := context.Background()
+	start := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	repo := &commitRepository{}
+	service := NewCandlestickService(
+		repo,
+		testutil.NopLogger{},
+		metrics.NopMetrics{},
+		subscription.NewSubscriptionService(testutil.NopLogger{}, metrics.NopMetrics{}),
+		indicator.NewIndicatorService(),
+		alert.NewAlertService(nopAlertRepository{}, testutil.NopLogger{}),
+		webhook.NewWebhookDispatcher(&webhook.WebhookConfig{}, nil, nil, testutil.NopLogger{}),
+		bus.NewBusService(&bus.BusConfig{Enabled: true, SubjectPrefix: "candles"}, nil, nil, testutil.NopLogger{}),
+	)
+
+	service.ProcessTicks(ctx, "btcusdt", 100, start)
+	if err := service.CommitCompleteBars(ctx); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if len(repo.bars) != 1 || len(repo.messages) != 1 || repo.messages[0] == nil {
+		t.Fatalf("expected the closed bar to be committed with a message, got %+v", repo.messages)
+	}
+	if repo.messages[0].Subject != "candles.bars.BTCUSDT" {
+		t.Fatalf("expected the message on candles.bars.BTCUSDT, got %s", repo.messages[0].Subject)
+	}
+}
+
+func TestCommitCompleteBarsKeepsTheBarsInProgress(t *testing.T) {
+	ctx := context.Background()
+	now := time.Now().UTC()
+	minute := now.Truncate(time.Minute)
+	repo := &commitRepository{}
+	service := NewCandlestickService(
+		repo,
+		testutil.NopLogger{},
+		metrics.NopMetrics{},
+		subscription.NewSubscriptionService(testutil.NopLogger{}, metrics.NopMetrics{}),
+		indicator.NewIndicatorService(),
+		alert.NewAlertService(nopAlertRepository{}, testutil.NopLogger{}),
+		webhook.NewWebhookDispatcher(&webhook.WebhookConfig{}, nil, nil, testutil.NopLogger{}),
+		bus.NewBusService(&bus.BusConfig{}, nil, nil, testutil.NopLogger{}),
+	)
+
+	service.ProcessTicks(ctx, "BTCUSDT", 100, minute.Add(-time.Minute))
+	service.ProcessTicks(ctx, "BTCUSDT", 110, now)
+
+	if err := service.CommitCompleteBars(ctx); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(repo.bars) != 1 || !repo.bars[0].TradeTimestamp.Equal(minute.Add(-time.Minute)) {
+		t.Fatalf("expected only the bar that ended to be committed, got %+v", repo.bars)
+	}
+
+	// the bar in progress continues with its open rather than restarting
+	service.ProcessTicks(ctx, "BTCUSDT", 105, now)
+	bar := service.candlesticks[barKey("BTCUSDT", minute)]
+	if bar == nil || bar.Open != 110 || bar.High != 110 || bar.Close != 105 {
+		t.Fatalf("expected the bar in progress to be kept in memory, got %+v", bar)
+	}
+}
+
+// blockingOutbox blocks storing a delivery until released
+type blockingOutbox struct {
+	storing chan struct{}
+	release chan struct{}
+}
+
+func (o *blockingOutbox) CreateDelivery(context.Context, *webhook.Delivery) error {
+	close(o.storing)
+	<-o.release
+	return nil
+}
+
+func (o *blockingOutbox) GetDueDeliveries(context.Context, time.Time, int) ([]*webhook.Delivery, error) {
+	return nil, nil
+}
+
+func (o *blockingOutbox) UpdateDelivery(context.Context, *webhook.Delivery) error { return nil }
+func (o *blockingOutbox) DeleteDelivery(context.Context, int64) error             { return nil }
+
+func TestCommitCompleteBarsPublishesToWebhooksWithoutHoldingOffTicks(t *testing.T) {
+	ctx := context.Background()
+	now := time.Now().UTC()
+	outbox := &blockingOutbox{storing: make(chan struct{}), release: make(chan struct{})}
+	dispatcher := webhook.NewWebhookDispatcher(
+		&webhook.WebhookConfig{Targets: []webhook.Target{{Name: "desk", URL: "http://desk"}}},
+		outbox,
+		nil,
+		testutil.NopLogger{},
+	)
+	// the queue is full, for the closed bar to be stored in the outbox
+	for range webhook.QUEUE_SIZE {
+		dispatcher.Publish(ctx, webhook.EVENT_TYPE_BAR_CLOSED, "ETHUSDT", nil)
+	}
+	service := NewCandlestickService(
+		&commitRepository{},
+		testutil.NopLogger{},
+		metrics.NopMetrics{},
+		subscription.NewSubscriptionService(testutil.NopLogger{}, metrics.NopMetrics{}),
+		indicator.NewIndicatorService(),
+		alert.NewAlertService(nopAlertRepository{}, testutil.NopLogger{}),
+		dispatcher,
+		bus.NewBusService(&bus.BusConfig{}, nil, nil, testutil.NopLogger{}),
+	)
+
+	service.ProcessTicks(ctx, "BTCUSDT", 100, now.Add(-2*time.Minute))
+
+	committed := make(chan error)
+	go func() { committed <- service.CommitCompleteBars(ctx) }()
+	<-outbox.storing
+
+	ticked := make(chan struct{})
+	go func() {
+		service.ProcessTicks(ctx, "BTCUSDT", 101, now)
+		close(ticked)
+	}()
+	select {
+	case <-ticked:
+	case <-time.After(time.Second):
+		t.Fatal("expected the tick not to wait on the webhook outbox")
+	}
+
+	close(outbox.release)
+	if err := <-committed; err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+}
+
+// checkpointRepository keeps the bars in progress last saved
+type checkpointRepository struct {
+	commitRepository
+	inProgress []*Candlestick
+	saves      int
+}
+
+func (r *checkpointRepository) SaveInProgressBars(_ context.Context, bars []*Candlestick) error {
+	r.inProgress = bars
+	r.saves++
+	return nil
+}
+
+func (r *checkpointRepository) GetInProgressBars(context.Context) ([]*Candlestick, error) {
+	return r.inProgress, nil
+}
+
+func newCheckpointTestService(repo *checkpointRepository) *CandlestickService {
+	return NewCandlestickService(
+		repo,
+		testutil.NopLogger{},
+		metrics.NopMetrics{},
+		subscription.NewSubscriptionService(testutil.NopLogger{}, metrics.NopMetrics{}),
+		indicator.NewIndicatorService(),
+		alert.NewAlertService(nopAlertRepository{}, testutil.NopLogger{}),
+		webhook.NewWebhookDispatcher(&webhook.WebhookConfig{}, nil, nil, testutil.NopLogger{}),
+		bus.NewBusService(&bus.BusConfig{}, nil, nil, testutil.NopLogger{}),
+	)
+}
+
+func TestInProgressBarsReturnsACopyOfTheBarsInMemory(t *testing.T) {
+	ctx := context.Background()
+	service := newTestService()
+	start := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	service.ProcessTicks(ctx, "ETHUSDT", 10, start)
+	service.ProcessTicks(ctx, "BTCUSDT", 101, start.Add(time.Minute))
+	service.ProcessTicks(ctx, "BTCUSDT", 100, start)
+
+	bars := service.InProgressBars()
+	if len(bars) != 3 {
+		t.Fatalf("expected 3 bars, got %d", len(bars))
+	}
+	if bars[0].Symbol != "BTCUSDT" || !bars[0].TradeTimestamp.Equal(start) ||
+		bars[1].Symbol != "BTCUSDT" || bars[2].Symbol != "ETHUSDT" {
+		t.Errorf("expected the bars ordered by symbol then time, got %+v", bars)
+	}
+
+	bars[0].Close = 0
+	service.ProcessTicks(ctx, "BTCUSDT", 99, start)
+	if bars := service.InProgressBars(); bars[0].Close != 99 || bars[0].High != 100 {
+		t.Errorf("expected the bars in memory to be left untouched, got %+v", bars[0])
+	}
+}
+
+func TestFlushBarsCommitsCompleteAndSavesInProgressBars(t *testing.T) {
+	ctx := context.Background()
+	now := time.Now().UTC()
+	repo := &checkpointRepository{}
+	service := newCheckpointTestService(repo)
+
+	service.ProcessTicks(ctx, "BTCUSDT", 100, now.Add(-2*time.Minute))
+	service.ProcessTicks(ctx, "ETHUSDT", 10, now.Add(time.Minute))
+
+	if err := service.FlushBars(ctx); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if len(repo.bars) != 1 || repo.bars[0].Symbol != "BTCUSDT" {
+		t.Fatalf("expected the complete BTCUSDT bar to be committed, got %+v", repo.bars)
+	}
+	if len(repo.inProgress) != 1 || repo.inProgress[0].Symbol != "ETHUSDT" {
+		t.Fatalf("expected the ETHUSDT bar in progress to be saved, got %+v", repo.inProgress)
+	}
+}
+
+func TestCheckpointBarsSkipsUnchangedBars(t *testing.T) {
+	ctx := context.Background()
+	repo := &checkpointRepository{}
+	service := newCheckpointTestService(repo)
+
+	service.ProcessTicks(ctx, "BTCUSDT", 100, time.Now().UTC().Add(time.Minute))
+	service.CheckpointBars(ctx)
+	service.CheckpointBars(ctx)
+
+	if repo.saves != 1 {
+		t.Fatalf("expected the unchanged bars to be saved once, got %d saves", repo.saves)
+	}
+}
+
+func TestRestoreBarsContinuesBarsInProgress(t *testing.T) {
+	ctx := context.Background()
+	minute := time.Now().UTC().Add(time.Minute).Truncate(time.Minute)
+	repo := &checkpointRepository{
+		inProgress: []*Candlestick{{
+			Symbol:         "BTCUSDT",
+			Open:           100,
+			High:           120,
+			Low:            90,
+			Close:          110,
+			TradeTimestamp: minute,
+		}},
+	}
+	service := newCheckpointTestService(repo)
+
+	if err := service.RestoreBars(ctx); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	service.ProcessTicks(ctx, "BTCUSDT", 105, minute.Add(30*time.Second))
+
+	bar := service.candlesticks[barKey("BTCUSDT", minute)]
+	if bar == nil || bar.Open != 100 || bar.High != 120 || bar.Low != 90 || bar.Close != 105 {
+		t.Fatalf("expected the restored bar to continue, got %+v", bar)
+	}
+}
+
+func TestRestoreBarsContinuesTheSequences(t *testing.T) {
+	ctx := context.Background()
+	minute := time.Now().UTC().Add(time.Minute).Truncate(time.Minute)
+	repo := &checkpointRepository{
+		inProgress: []*Candlestick{{
+			Symbol:         "BTCUSDT",
+			Open:           100,
+			High:           100,
+			Low:            100,
+			Close:          100,
+			TradeTimestamp: minute,
+			Sequence:       7,
+		}},
+	}
+	service := newCheckpointTestService(repo)
+
+	if err := service.RestoreBars(ctx); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	service.ProcessTicks(ctx, "BTCUSDT", 105, minute)
+
+	if bar := service.candlesticks[barKey("BTCUSDT", minute)]; bar.Sequence != 8 {
+		t.Fatalf("expected the sequence to continue from 7, got %d", bar.Sequence)
+	}
+}
+
+// blockingCheckpointRepository blocks saving until released
+type blockingCheckpointRepository struct {
+	checkpointRepository
+	saving  chan struct{}
+	release chan struct{}
+}
+
+func (r *blockingCheckpointRepository) SaveInProgressBars(ctx context.Context, bars []*Candlestick) error {
+	close(r.saving)
+	<-r.release
+	return r.checkpointRepository.SaveInProgressBars(ctx, bars)
+}
+
+func TestCheckpointBarsSavesACopyWithoutHoldingOffTicks(t *testing.T) {
+	ctx := context.Background()
+	minute := time.Now().UTC().Add(time.Minute).Truncate(time.Minute)
+	repo := &blockingCheckpointRepository{
+		saving:  make(chan struct{}),
+		release: make(chan struct{}),
+	}
+	service := NewCandlestickService(
+		repo,
+		testutil.NopLogger{},
+		metrics.NopMetrics{},
+		subscription.NewSubscriptionService(testutil.NopLogger{}, metrics.NopMetrics{}),
+		indicator.NewIndicatorService(),
+		alert.NewAlertService(nopAlertRepository{}, testutil.NopLogger{}),
+		webhook.NewWebhookDispatcher(&webhook.WebhookConfig{}, nil, nil, testutil.NopLogger{}),
+		bus.NewBusService(&bus.BusConfig{}, nil, nil, testutil.NopLogger{}),
+	)
+
+	service.ProcessTicks(ctx, "BTCUSDT", 100, minute)
+
+	checkpointed := make(chan error)
+	go func() { checkpointed <- service.CheckpointBars(ctx) }()
+	<-repo.saving
+
+	ticked := make(chan struct{})
+	go func() {
+		service.ProcessTicks(ctx, "BTCUSDT", 101, minute)
+		close(ticked)
+	}()
+	select {
+	case <-ticked:
+	case <-time.After(time.Second):
+		t.Fatal("expected the tick not to wait on the checkpoint being saved")
+	}
+
+	close(repo.release)
+	if err := <-checkpointed; err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if len(repo.inProgress) != 1 || repo.inProgress[0].Close != 100 {
+		t.Fatalf("expected the bars as of the checkpoint to be saved, got %+v", repo.inProgress)
+	}
+	if !service.checkpointDirty {
+		t.Fatal("expected the tick processed while saving to be left for the next checkpoint")
+	}
+}
+
+func TestApplyUpdateFollowsTheLeaderBars(t *testing.T) {
+	ctx := context.Background()
+	start := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	service := newTestService()
+
+	sink := newFakeSink()
+	if err := service.Subscribe(ctx, 1, []string{"BTCUSDT"}, SubscribeOptions{}, sink); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	service.ResetUpdates(ctx)
+	service.ApplyUpdate(ctx, &subscription.CandlestickEvent{
+		Symbol: "BTCUSDT", Open: 100, High: 110, Low: 90, Close: 105,
+		TradeTimestamp: start, Sequence: 7, Kind: subscription.EVENT_KIND_SNAPSHOT,
+	})
+	service.ApplyUpdate(ctx, &subscription.CandlestickEvent{
+		Symbol: "BTCUSDT", Open: 105, High: 106, Low: 104, Close: 106,
+		TradeTimestamp: start.Add(time.Minute), Sequence: 8, Kind: subscription.EVENT_KIND_LIVE,
+	})
+
+	if events := sink.waitFor(t, 1); len(events) != 1 || events[0].Sequence != 8 {
+		t.Fatalf("expected only the live update to be broadcast, got %+v", events)
+	}
+
+	snapshot := service.getSnapshot("BTCUSDT", MAX_RECENT_BARS)
+	if len(snapshot) != 2 || snapshot[0].Close != 105 || snapshot[1].Close != 106 {
+		t.Fatalf("expected the snapshot bar closed and the live one in progress, got %+v", snapshot)
+	}
+	if _, exists := service.candlesticks[barKey("BTCUSDT", start)]; exists {
+		t.Fatal("expected the earlier bar to no longer be in progress")
+	}
+
+	updates, ok := service.journal.since("BTCUSDT", 7)
+	if !ok || len(updates) != 1 || updates[0].Sequence != 8 {
+		t.Fatalf("expected the live update to be journaled, got %+v", updates)
+	}
+}
+
+// storedRepository reports every bar as committed
+type storedRepository struct {
+	checkpointRepository
+}
+
+func (r *storedRepository) GetCandlestickBars(_ context.Context, symbol string, from time.Time, _ time.Time) ([]*Candlestick, error) {
+	return []*Candlestick{{Symbol: symbol, TradeTimestamp: from}}, nil
+}
+
+func TestRestoreBarsSkipsBarsCommittedByThePreviousLeader(t *testing.T) {
+	ctx := context.Background()
+	ended := time.Now().UTC().Add(-2 * time.Minute).Truncate(time.Minute)
+	repo := &storedRepository{}
+	service := NewCandlestickService(
+		repo,
+		testutil.NopLogger{},
+		metrics.NopMetrics{},
+		subscription.NewSubscriptionService(testutil.NopLogger{}, metrics.NopMetrics{}),
+		indicator.NewIndicatorService(),
+		alert.NewAlertService(nopAlertRepository{}, testutil.NopLogger{}),
+		webhook.NewWebhookDispatcher(&webhook.WebhookConfig{}, nil, nil, testutil.NopLogger{}),
+		bus.NewBusService(&bus.BusConfig{}, nil, nil, testutil.NopLogger{}),
+	)
+
+	// followed from the previous leader, which committed it
+	service.ApplyUpdate(ctx, &subscription.CandlestickEvent{
+		Symbol: "BTCUSDT", Open: 100, High: 100, Low: 100, Close: 100,
+		TradeTimestamp: ended, Sequence: 1, Kind: subscription.EVENT_KIND_SNAPSHOT,
+	})
+
+	if err := service.RestoreBars(ctx); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if err := service.CommitCompleteBars(ctx); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if len(repo.bars) != 0 {
+		t.Fatalf("expected the committed bar not to be committed again, got %+v", repo.bars)
+	}
+}
+
+func TestApplyBarChangeBroadcastsCommittedBars(t *testing.T) {
+	ctx := context.Background()
+	start := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	service := newTestService()
+
+	sink := newFakeSink()
+	if err := service.Subscribe(ctx, 1, []string{"BTCUSDT"}, SubscribeOptions{}, sink); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	service.ApplyBarChange(ctx, &BarChange{
+		Bar: &Candlestick{Symbol: "BTCUSDT", Open: 100, High: 110, Low: 90, Close: 105, TradeTimestamp: start},
+	})
+	service.ApplyBarChange(ctx, &BarChange{
+		Bar:        &Candlestick{Symbol: "BTCUSDT", Open: 100, High: 112, Low: 90, Close: 104, TradeTimestamp: start},
+		Correction: true,
+	})
+
+	if events := sink.waitFor(t, 2); len(events) != 2 || events[0].Sequence != 1 || events[1].Sequence != 2 {
+		t.Fatalf("expected both changes to be broadcast in sequence, got %+v", events)
+	}
+
+	snapshot := service.getSnapshot("BTCUSDT", MAX_RECENT_BARS)
+	if len(snapshot) != 1 || snapshot[0].High != 112 {
+		t.Fatalf("expected the correction to replace the closed bar, got %+v", snapshot)
+	}
+}
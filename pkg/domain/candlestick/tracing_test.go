@@ -0,0 +1,73 @@
+package candlestick
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"go.opentelemetry.io/otel"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+)
+
+// the package tracer only delegates to the first global provider set,
+// the tests share it
+var spanRecorder = tracetest.NewSpanRecorder()
+
+func init() {
+	otel.SetTracerProvider(sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(spanRecorder)))
+}
+
+// returns the spans ended while running fn
+func recordSpans(fn func()) []sdktrace.ReadOnlySpan {
+	before := len(spanRecorder.Ended())
+	fn()
+	return spanRecorder.Ended()[before:]
+}
+
+func TestProcessTicksSpansBroadcast(t *testing.T) {
+	service := newTestService()
+	ended := recordSpans(func() {
+		service.ProcessTicks(context.Background(), "BTCUSDT", 100, time.Now())
+	})
+
+	spans := map[string]sdktrace.ReadOnlySpan{}
+	for _, span := range ended {
+		spans[span.Name()] = span
+	}
+
+	process, ok := spans["CandlestickService.ProcessTicks"]
+	if !ok {
+		t.Fatalf("expected a trade processing span, got %v", spans)
+	}
+	broadcast, ok := spans["SubscriptionService.BroadcastToSubscribers"]
+	if !ok {
+		t.Fatalf("expected a broadcast span, got %v", spans)
+	}
+	if broadcast.Parent().SpanID() != process.SpanContext().SpanID() {
+		t.Errorf("expected the broadcast span to be a child of the trade processing span")
+	}
+}
+
+func TestProcessTicksSpansOnlyTheTickOpeningABar(t *testing.T) {
+	service := newTestService()
+	minute := time.Now().Truncate(time.Minute)
+	ended := recordSpans(func() {
+		service.ProcessTicks(context.Background(), "BTCUSDT", 100, minute)
+		service.ProcessTicks(context.Background(), "BTCUSDT", 101, minute.Add(time.Second))
+		service.ProcessTicks(context.Background(), "BTCUSDT", 102, minute.Add(2*time.Second))
+		service.ProcessTicks(context.Background(), "BTCUSDT", 103, minute.Add(time.Minute))
+	})
+
+	counts := map[string]int{}
+	for _, span := range ended {
+		counts[span.Name()]++
+	}
+
+	if counts["CandlestickService.ProcessTicks"] != 2 {
+		t.Errorf("expected a trade processing span per bar, got %v", counts)
+	}
+	if counts["SubscriptionService.BroadcastToSubscribers"] != 2 {
+		t.Errorf("expected a broadcast span per bar, got %v", counts)
+	}
+}
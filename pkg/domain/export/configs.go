@@ -0,0 +1,12 @@
+package export
+
+import "time"
+
+// ExportConfig bounds the exports served to the clients, a zero value
+// leaving them unbounded
+type ExportConfig struct {
+	// longest range of an export, the default range ending at its end
+	MaxRange time.Duration
+	// exports running at once, the others failing with ERR_TOO_MANY_EXPORTS
+	MaxConcurrent int
+}
@@ -0,0 +1,37 @@
+package export
+
+import "time"
+
+const (
+	DEFAULT_MAX_RANGE      = 366 * 24 * time.Hour
+	DEFAULT_MAX_CONCURRENT = 4
+
+	// 1 minute bars read from the db at once, the connection being released
+	// while they are written out, however slow the client
+	PAGE_SIZE = 10_000
+)
+
+const (
+	FORMAT_CSV     Format = "csv"
+	FORMAT_PARQUET Format = "parquet"
+	FORMAT_ARROW   Format = "arrow"
+)
+
+const (
+	COLUMN_SYMBOL    Column = "symbol"
+	COLUMN_TIMESTAMP Column = "timestamp"
+	COLUMN_OPEN      Column = "open"
+	COLUMN_HIGH      Column = "high"
+	COLUMN_LOW       Column = "low"
+	COLUMN_CLOSE     Column = "close"
+)
+
+// every column, in the order they are exported unless selected
+var COLUMNS = []Column{
+	COLUMN_SYMBOL,
+	COLUMN_TIMESTAMP,
+	COLUMN_OPEN,
+	COLUMN_HIGH,
+	COLUMN_LOW,
+	COLUMN_CLOSE,
+}
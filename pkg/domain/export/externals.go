@@ -0,0 +1,32 @@
+package export
+
+import (
+	"context"
+	"io"
+	"time"
+
+	"github.com/ramasbeinaty/trading-chart-service/pkg/domain/candlestick"
+)
+
+type IRepository interface {
+	// returns up to limit of the symbol's committed bars within the range,
+	// oldest first
+	GetCandlestickBarsPage(
+		ctx context.Context,
+		symbol string,
+		from time.Time,
+		to time.Time,
+		limit int,
+	) ([]*candlestick.Candlestick, error)
+}
+
+// IBarWriter encodes the bars in a format
+type IBarWriter interface {
+	WriteBar(bar *candlestick.Candlestick) error
+	// writes what is left, e.g. the footer of the file
+	Close() error
+}
+
+// NewBarWriter creates the writer of a format writing the columns of the
+// bars to w
+type NewBarWriter func(w io.Writer, columns []Column) IBarWriter
@@ -0,0 +1,74 @@
+package export
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"time"
+
+	"github.com/ramasbeinaty/trading-chart-service/pkg/domain/indicator"
+)
+
+var (
+	ERR_INVALID_REQUEST = errors.New("invalid export request")
+	// as many exports as allowed are running
+	ERR_TOO_MANY_EXPORTS = errors.New("too many exports running")
+)
+
+// Format is the file format the bars are exported in
+type Format string
+
+// Column is a field of the exported bars
+type Column string
+
+// Request exports the symbol's bars of the timeframe opened within the range
+type Request struct {
+	Symbol    string
+	Timeframe indicator.Timeframe
+	Format    Format
+	// the columns written, in order, every column if empty
+	Columns []Column
+	// the longest range allowed before To if zero
+	From time.Time
+	To   time.Time
+}
+
+func (r *Request) validate(
+	formats map[Format]NewBarWriter,
+	maxRange time.Duration,
+) error {
+	if err := r.check(formats, maxRange); err != nil {
+		return fmt.Errorf("%w - %w", ERR_INVALID_REQUEST, err)
+	}
+	return nil
+}
+
+func (r *Request) check(
+	formats map[Format]NewBarWriter,
+	maxRange time.Duration,
+) error {
+	if r.Symbol == "" {
+		return fmt.Errorf("symbol not provided")
+	}
+	if r.Timeframe.Duration() == 0 {
+		return fmt.Errorf("unknown timeframe %q", r.Timeframe)
+	}
+	if _, ok := formats[r.Format]; !ok {
+		return fmt.Errorf("unknown format %q", r.Format)
+	}
+	for i, column := range r.Columns {
+		if !slices.Contains(COLUMNS, column) {
+			return fmt.Errorf("unknown column %q", column)
+		}
+		if slices.Contains(r.Columns[:i], column) {
+			return fmt.Errorf("column %q selected twice", column)
+		}
+	}
+	if r.To.Before(r.From) {
+		return fmt.Errorf("the range ends before it starts")
+	}
+	if maxRange > 0 && r.To.Sub(r.From) > maxRange {
+		return fmt.Errorf("the range spans more than %s", maxRange)
+	}
+	return nil
+}
@@ -0,0 +1,170 @@
+package export
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+
+	"github.com/ramasbeinaty/trading-chart-service/pkg/domain/base/logger"
+	"github.com/ramasbeinaty/trading-chart-service/pkg/domain/candlestick"
+	"go.uber.org/zap"
+)
+
+// ExportService exports the committed bars to files, aggregated into the
+// bars of a timeframe
+type ExportService struct {
+	cfg     *ExportConfig
+	repo    IRepository
+	formats map[Format]NewBarWriter
+	lgr     logger.ILogger
+	// holds a slot per export running, unbounded if nil
+	slots chan struct{}
+}
+
+func NewExportService(
+	cfg *ExportConfig,
+	repo IRepository,
+	formats map[Format]NewBarWriter,
+	lgr logger.ILogger,
+) *ExportService {
+	s := &ExportService{
+		cfg:     cfg,
+		repo:    repo,
+		formats: formats,
+		lgr:     lgr,
+	}
+	if cfg.MaxConcurrent > 0 {
+		s.slots = make(chan struct{}, cfg.MaxConcurrent)
+	}
+	return s
+}
+
+// Export writes the requested bars to w in the requested format, returning
+// how many were written
+// the 1 minute bars are read from the db a page at a time, so any range is
+// exported with constant memory, without holding a connection while writing
+func (s *ExportService) Export(
+	ctx context.Context,
+	w io.Writer,
+	req Request,
+) (int, error) {
+	lgr := s.lgr.Get(ctx)
+
+	req.Symbol = strings.ToUpper(req.Symbol)
+	if req.From.IsZero() && s.cfg.MaxRange > 0 {
+		req.From = req.To.Add(-s.cfg.MaxRange)
+	}
+	if err := req.validate(s.formats, s.cfg.MaxRange); err != nil {
+		return 0, fmt.Errorf("Failed to export - %w", err)
+	}
+
+	if s.slots != nil {
+		select {
+		case s.slots <- struct{}{}:
+			defer func() { <-s.slots }()
+		default:
+			return 0, fmt.Errorf("Failed to export - %w, at most %d at once", ERR_TOO_MANY_EXPORTS, s.cfg.MaxConcurrent)
+		}
+	}
+
+	written, err := s.export(ctx, w, req)
+	if err != nil {
+		lgr.Error(
+			"Failed to export bars",
+			zap.String("symbol", req.Symbol),
+			zap.Int("bars", written),
+			zap.Error(err),
+		)
+		return written, err
+	}
+
+	lgr.Info(
+		"Exported bars",
+		zap.String("symbol", req.Symbol),
+		zap.String("timeframe", string(req.Timeframe)),
+		zap.String("format", string(req.Format)),
+		zap.Int("bars", written),
+	)
+
+	return written, nil
+}
+
+func (s *ExportService) export(
+	ctx context.Context,
+	w io.Writer,
+	req Request,
+) (int, error) {
+	columns := req.Columns
+	if len(columns) == 0 {
+		columns = COLUMNS
+	}
+	writer := s.formats[req.Format](w, columns)
+	duration := req.Timeframe.Duration()
+
+	var (
+		bar     *candlestick.Candlestick // bar of the timeframe in progress
+		written int
+	)
+	write := func() error {
+		if err := writer.WriteBar(bar); err != nil {
+			return fmt.Errorf("Failed to write the bar at %s - %w", bar.TradeTimestamp, err)
+		}
+		written++
+		return nil
+	}
+
+	// the bars of the timeframe opened within the range are made of the 1
+	// minute bars up to the end of the last one
+	from := req.From.Truncate(duration)
+	to := req.To.Truncate(duration).Add(duration - time.Nanosecond)
+	for {
+		page, err := s.repo.GetCandlestickBarsPage(ctx, req.Symbol, from, to, PAGE_SIZE)
+		if err != nil {
+			return written, fmt.Errorf("Failed to export the bars of %s - %w", req.Symbol, err)
+		}
+
+		for _, minute := range page {
+			bucket := minute.TradeTimestamp.UTC().Truncate(duration)
+
+			if bar != nil && bucket.Equal(bar.TradeTimestamp) {
+				bar.High = max(bar.High, minute.High)
+				bar.Low = min(bar.Low, minute.Low)
+				bar.Close = minute.Close
+				continue
+			}
+
+			if bar != nil {
+				if err := write(); err != nil {
+					return written, fmt.Errorf("Failed to export the bars of %s - %w", req.Symbol, err)
+				}
+			}
+			bar = &candlestick.Candlestick{
+				Symbol:         minute.Symbol,
+				Open:           minute.Open,
+				High:           minute.High,
+				Low:            minute.Low,
+				Close:          minute.Close,
+				TradeTimestamp: bucket,
+			}
+		}
+
+		if len(page) < PAGE_SIZE {
+			break
+		}
+		// the db keeps microseconds
+		from = page[len(page)-1].TradeTimestamp.Add(time.Microsecond)
+	}
+
+	if bar != nil {
+		if err := write(); err != nil {
+			return written, fmt.Errorf("Failed to export the bars of %s - %w", req.Symbol, err)
+		}
+	}
+	if err := writer.Close(); err != nil {
+		return written, fmt.Errorf("Failed to export the bars of %s - %w", req.Symbol, err)
+	}
+
+	return written, nil
+}
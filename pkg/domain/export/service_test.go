@@ -0,0 +1,301 @@
+package export_test
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"io"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/ramasbeinaty/trading-chart-service/internal/testutil"
+	"github.com/ramasbeinaty/trading-chart-service/pkg/domain/candlestick"
+	"github.com/ramasbeinaty/trading-chart-service/pkg/domain/export"
+	"github.com/ramasbeinaty/trading-chart-service/pkg/domain/indicator"
+)
+
+// memoryRepo serves the bars it holds, oldest first
+type memoryRepo struct {
+	bars []*candlestick.Candlestick
+	// range of the first page read
+	from  time.Time
+	to    time.Time
+	pages int
+}
+
+func (r *memoryRepo) GetCandlestickBarsPage(
+	_ context.Context,
+	symbol string,
+	from time.Time,
+	to time.Time,
+	limit int,
+) ([]*candlestick.Candlestick, error) {
+	if r.pages == 0 {
+		r.from, r.to = from, to
+	}
+	r.pages++
+
+	page := []*candlestick.Candlestick{}
+	for _, bar := range r.bars {
+		if len(page) == limit {
+			break
+		}
+		if bar.Symbol != symbol || bar.TradeTimestamp.Before(from) || bar.TradeTimestamp.After(to) {
+			continue
+		}
+		stored := *bar
+		page = append(page, &stored)
+	}
+	return page, nil
+}
+
+// recordingWriter keeps the written bars
+type recordingWriter struct {
+	columns []export.Column
+	bars    []*candlestick.Candlestick
+	closed  bool
+}
+
+func (w *recordingWriter) WriteBar(bar *candlestick.Candlestick) error {
+	stored := *bar
+	w.bars = append(w.bars, &stored)
+	return nil
+}
+
+func (w *recordingWriter) Close() error {
+	w.closed = true
+	return nil
+}
+
+func newService(repo *memoryRepo) (*export.ExportService, *recordingWriter) {
+	return newServiceWithConfig(&export.ExportConfig{}, repo)
+}
+
+func newServiceWithConfig(cfg *export.ExportConfig, repo *memoryRepo) (*export.ExportService, *recordingWriter) {
+	writer := &recordingWriter{}
+	return export.NewExportService(
+		cfg,
+		repo,
+		map[export.Format]export.NewBarWriter{
+			export.FORMAT_CSV: func(_ io.Writer, columns []export.Column) export.IBarWriter {
+				writer.columns = columns
+				return writer
+			},
+		},
+		testutil.NopLogger{},
+	), writer
+}
+
+func minuteBar(start time.Time, minute int, open, high, low, close float64) *candlestick.Candlestick {
+	return &candlestick.Candlestick{
+		Symbol:         "BTCUSDT",
+		Open:           open,
+		High:           high,
+		Low:            low,
+		Close:          close,
+		TradeTimestamp: start.Add(time.Duration(minute) * time.Minute),
+	}
+}
+
+func TestExportAggregatesTheMinutesIntoTheTimeframe(t *testing.T) {
+	start := time.Date(2024, 1, 1, 10, 0, 0, 0, time.UTC)
+	repo := &memoryRepo{bars: []*candlestick.Candlestick{
+		minuteBar(start, 0, 10, 12, 9, 11),
+		minuteBar(start, 2, 11, 15, 10, 14),
+		minuteBar(start, 4, 14, 14, 8, 9),
+		minuteBar(start, 5, 9, 10, 9, 10),
+		minuteBar(start, 7, 10, 11, 7, 8),
+		// past the last 5 minutes bar of the range
+		minuteBar(start, 10, 8, 8, 8, 8),
+	}}
+	service, writer := newService(repo)
+
+	written, err := service.Export(context.Background(), &bytes.Buffer{}, export.Request{
+		Symbol:    "btcusdt",
+		Timeframe: indicator.TIMEFRAME_5M,
+		Format:    export.FORMAT_CSV,
+		From:      start.Add(2 * time.Minute),
+		To:        start.Add(6 * time.Minute),
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if written != 2 || len(writer.bars) != 2 {
+		t.Fatalf("expected 2 bars written, got %d (%d in the writer)", written, len(writer.bars))
+	}
+	if !writer.closed {
+		t.Fatal("expected the writer to be closed")
+	}
+	if !repo.from.Equal(start) {
+		t.Fatalf("expected the range to start with the bar containing from, got %s", repo.from)
+	}
+
+	first := writer.bars[0]
+	if !first.TradeTimestamp.Equal(start) || first.Open != 10 || first.High != 15 || first.Low != 8 || first.Close != 9 {
+		t.Fatalf("unexpected first bar %+v", first)
+	}
+	second := writer.bars[1]
+	if !second.TradeTimestamp.Equal(start.Add(5*time.Minute)) || second.Open != 9 || second.High != 11 || second.Low != 7 || second.Close != 8 {
+		t.Fatalf("unexpected second bar %+v", second)
+	}
+}
+
+func TestExportRejectsAnInvalidRequest(t *testing.T) {
+	start := time.Date(2024, 1, 1, 10, 0, 0, 0, time.UTC)
+	service, _ := newService(&memoryRepo{})
+
+	requests := map[string]export.Request{
+		"no symbol":     {Timeframe: indicator.TIMEFRAME_1M, Format: export.FORMAT_CSV, From: start, To: start},
+		"timeframe":     {Symbol: "BTCUSDT", Timeframe: "2m", Format: export.FORMAT_CSV, From: start, To: start},
+		"format":        {Symbol: "BTCUSDT", Timeframe: indicator.TIMEFRAME_1M, Format: "xlsx", From: start, To: start},
+		"reverse range": {Symbol: "BTCUSDT", Timeframe: indicator.TIMEFRAME_1M, Format: export.FORMAT_CSV, From: start, To: start.Add(-time.Minute)},
+		"column":        {Symbol: "BTCUSDT", Timeframe: indicator.TIMEFRAME_1M, Format: export.FORMAT_CSV, Columns: []export.Column{"volume"}, From: start, To: start},
+		"column twice":  {Symbol: "BTCUSDT", Timeframe: indicator.TIMEFRAME_1M, Format: export.FORMAT_CSV, Columns: []export.Column{export.COLUMN_CLOSE, export.COLUMN_CLOSE}, From: start, To: start},
+	}
+	for name, req := range requests {
+		_, err := service.Export(context.Background(), &bytes.Buffer{}, req)
+		if !errors.Is(err, export.ERR_INVALID_REQUEST) {
+			t.Errorf("%s: expected an invalid request error, got %v", name, err)
+		}
+	}
+}
+
+func TestExportWritesTheSelectedColumns(t *testing.T) {
+	start := time.Date(2024, 1, 1, 10, 0, 0, 0, time.UTC)
+	service, writer := newService(&memoryRepo{})
+	req := export.Request{
+		Symbol:    "BTCUSDT",
+		Timeframe: indicator.TIMEFRAME_1M,
+		Format:    export.FORMAT_CSV,
+		From:      start,
+		To:        start,
+	}
+
+	if _, err := service.Export(context.Background(), &bytes.Buffer{}, req); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !slices.Equal(writer.columns, export.COLUMNS) {
+		t.Fatalf("expected every column by default, got %v", writer.columns)
+	}
+
+	req.Columns = []export.Column{export.COLUMN_CLOSE, export.COLUMN_TIMESTAMP}
+	if _, err := service.Export(context.Background(), &bytes.Buffer{}, req); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !slices.Equal(writer.columns, req.Columns) {
+		t.Fatalf("expected the selected columns in order, got %v", writer.columns)
+	}
+}
+
+func TestExportReadsTheBarsAPageAtATime(t *testing.T) {
+	start := time.Date(2024, 1, 1, 10, 0, 0, 0, time.UTC)
+	repo := &memoryRepo{}
+	for minute := range export.PAGE_SIZE + 5 {
+		repo.bars = append(repo.bars, minuteBar(start, minute, 10, 10, 10, 10))
+	}
+	service, writer := newService(repo)
+
+	written, err := service.Export(context.Background(), &bytes.Buffer{}, export.Request{
+		Symbol:    "BTCUSDT",
+		Timeframe: indicator.TIMEFRAME_1M,
+		Format:    export.FORMAT_CSV,
+		From:      start,
+		To:        start.Add(time.Duration(export.PAGE_SIZE+5) * time.Minute),
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if written != export.PAGE_SIZE+5 || repo.pages != 2 {
+		t.Fatalf("expected %d bars written from 2 pages, got %d from %d", export.PAGE_SIZE+5, written, repo.pages)
+	}
+	last := writer.bars[len(writer.bars)-1]
+	if !last.TradeTimestamp.Equal(start.Add(time.Duration(export.PAGE_SIZE+4) * time.Minute)) {
+		t.Fatalf("expected every bar once, the last one being at %s", last.TradeTimestamp)
+	}
+}
+
+func TestExportBoundsTheRange(t *testing.T) {
+	start := time.Date(2024, 1, 1, 10, 0, 0, 0, time.UTC)
+	repo := &memoryRepo{}
+	service, _ := newServiceWithConfig(&export.ExportConfig{MaxRange: time.Hour}, repo)
+
+	_, err := service.Export(context.Background(), &bytes.Buffer{}, export.Request{
+		Symbol:    "BTCUSDT",
+		Timeframe: indicator.TIMEFRAME_1M,
+		Format:    export.FORMAT_CSV,
+		From:      start,
+		To:        start.Add(2 * time.Hour),
+	})
+	if !errors.Is(err, export.ERR_INVALID_REQUEST) {
+		t.Fatalf("expected a range over the max to be invalid, got %v", err)
+	}
+
+	_, err = service.Export(context.Background(), &bytes.Buffer{}, export.Request{
+		Symbol:    "BTCUSDT",
+		Timeframe: indicator.TIMEFRAME_1M,
+		Format:    export.FORMAT_CSV,
+		To:        start.Add(2 * time.Hour),
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !repo.from.Equal(start.Add(time.Hour)) {
+		t.Fatalf("expected the range to default to the max before its end, got %s", repo.from)
+	}
+}
+
+// blockingWriter blocks writing a bar until released
+type blockingWriter struct {
+	writing chan struct{}
+	release chan struct{}
+}
+
+func (w *blockingWriter) WriteBar(*candlestick.Candlestick) error {
+	w.writing <- struct{}{}
+	<-w.release
+	return nil
+}
+
+func (w *blockingWriter) Close() error { return nil }
+
+func TestExportLimitsTheConcurrentExports(t *testing.T) {
+	start := time.Date(2024, 1, 1, 10, 0, 0, 0, time.UTC)
+	writer := &blockingWriter{writing: make(chan struct{}, 1), release: make(chan struct{})}
+	service := export.NewExportService(
+		&export.ExportConfig{MaxConcurrent: 1},
+		&memoryRepo{bars: []*candlestick.Candlestick{minuteBar(start, 0, 10, 10, 10, 10)}},
+		map[export.Format]export.NewBarWriter{
+			export.FORMAT_CSV: func(io.Writer, []export.Column) export.IBarWriter { return writer },
+		},
+		testutil.NopLogger{},
+	)
+	req := export.Request{
+		Symbol:    "BTCUSDT",
+		Timeframe: indicator.TIMEFRAME_1M,
+		Format:    export.FORMAT_CSV,
+		From:      start,
+		To:        start,
+	}
+
+	done := make(chan error)
+	go func() {
+		_, err := service.Export(context.Background(), &bytes.Buffer{}, req)
+		done <- err
+	}()
+	<-writer.writing
+
+	if _, err := service.Export(context.Background(), &bytes.Buffer{}, req); !errors.Is(err, export.ERR_TOO_MANY_EXPORTS) {
+		t.Fatalf("expected an export over the limit to fail, got %v", err)
+	}
+
+	close(writer.release)
+	if err := <-done; err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if _, err := service.Export(context.Background(), &bytes.Buffer{}, req); err != nil {
+		t.Fatalf("expected an export once the others are done to run, got %v", err)
+	}
+}
@@ -0,0 +1,10 @@
+package health
+
+import "time"
+
+type HealthConfig struct {
+	// the leader without trades on any symbol for longer is not ready
+	MaxTradeAge time.Duration
+	// closed bars waiting to be committed, beyond which the service is not ready
+	MaxCommitBacklog int
+}
@@ -0,0 +1,14 @@
+package health
+
+import "time"
+
+const (
+	// each check is failed if it takes longer
+	CHECK_TIMEOUT = 2 * time.Second
+
+	// how often the readiness is refreshed for the grpc health service
+	WATCH_INTERVAL = 5 * time.Second
+
+	DEFAULT_MAX_TRADE_AGE      = 2 * time.Minute
+	DEFAULT_MAX_COMMIT_BACKLOG = 10
+)
@@ -0,0 +1,8 @@
+package health
+
+import "time"
+
+// SetTestTimeout shortens the check timeout for tests
+func SetTestTimeout(h *HealthService, timeout time.Duration) {
+	h.checkTimeout = timeout
+}
@@ -0,0 +1,25 @@
+package health
+
+import "context"
+
+type Status string
+
+const (
+	STATUS_OK      Status = "ok"
+	STATUS_FAILING Status = "failing"
+)
+
+// Check returns an error describing why the dependency is not ready
+type Check func(ctx context.Context) error
+
+type CheckResult struct {
+	Name    string `json:"name"`
+	Status  Status `json:"status"`
+	Message string `json:"message,omitempty"`
+}
+
+// Report is ok only if every check in it is
+type Report struct {
+	Status Status        `json:"status"`
+	Checks []CheckResult `json:"checks"`
+}
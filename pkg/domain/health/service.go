@@ -0,0 +1,170 @@
+package health
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/ramasbeinaty/trading-chart-service/pkg/domain/base/logger"
+	"go.uber.org/zap"
+)
+
+type namedCheck struct {
+	name  string
+	check Check
+}
+
+// HealthService reports the readiness of the service from the checks
+// of its dependencies
+type HealthService struct {
+	lgr    logger.ILogger
+	mutex  sync.RWMutex
+	checks []namedCheck
+
+	shuttingDown atomic.Bool
+	checkTimeout time.Duration
+}
+
+func NewHealthService(
+	lgr logger.ILogger,
+) *HealthService {
+	return &HealthService{
+		lgr:          lgr,
+		checkTimeout: CHECK_TIMEOUT,
+	}
+}
+
+// AddCheck adds a check to the readiness, reported under the name
+func (h *HealthService) AddCheck(
+	name string,
+	check Check,
+) {
+	h.mutex.Lock()
+	defer h.mutex.Unlock()
+
+	h.checks = append(h.checks, namedCheck{name: name, check: check})
+}
+
+// RemoveCheck removes the checks reported under the name
+func (h *HealthService) RemoveCheck(
+	name string,
+) {
+	h.mutex.Lock()
+	defer h.mutex.Unlock()
+
+	h.checks = slices.DeleteFunc(h.checks, func(c namedCheck) bool {
+		return c.name == name
+	})
+}
+
+// ShutDown makes the service report it is not ready from now on
+func (h *HealthService) ShutDown() {
+	h.shuttingDown.Store(true)
+}
+
+// Readiness runs every check concurrently, each within CHECK_TIMEOUT,
+// and reports them in the order they were added
+func (h *HealthService) Readiness(
+	ctx context.Context,
+) *Report {
+	h.mutex.RLock()
+	checks := append([]namedCheck{}, h.checks...)
+	h.mutex.RUnlock()
+
+	report := &Report{
+		Status: STATUS_OK,
+		Checks: make([]CheckResult, len(checks)),
+	}
+
+	var wg sync.WaitGroup
+	for i, c := range checks {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			report.Checks[i] = runCheck(ctx, c, h.checkTimeout)
+		}()
+	}
+	wg.Wait()
+
+	if h.shuttingDown.Load() {
+		report.Checks = append(report.Checks, CheckResult{
+			Name:    "shutdown",
+			Status:  STATUS_FAILING,
+			Message: "server is shutting down",
+		})
+	}
+
+	for _, result := range report.Checks {
+		if result.Status != STATUS_OK {
+			report.Status = STATUS_FAILING
+			h.lgr.Get(ctx).Warn(
+				"Readiness check is failing",
+				zap.String("check", result.Name),
+				zap.String("message", result.Message),
+			)
+		}
+	}
+
+	return report
+}
+
+// Watch reports the readiness to the callback on every WATCH_INTERVAL,
+// until the context is done
+func (h *HealthService) Watch(
+	ctx context.Context,
+	wg *sync.WaitGroup,
+	onReport func(report *Report),
+) {
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+
+		ticker := time.NewTicker(WATCH_INTERVAL)
+		defer ticker.Stop()
+
+		for {
+			onReport(h.Readiness(ctx))
+
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+			}
+		}
+	}()
+}
+
+// runs the check, failing it if it panics or exceeds the timeout
+func runCheck(
+	ctx context.Context,
+	c namedCheck,
+	timeout time.Duration,
+) CheckResult {
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	done := make(chan error, 1)
+	go func() {
+		defer func() {
+			if r := recover(); r != nil {
+				done <- fmt.Errorf("check panicked - %v", r)
+			}
+		}()
+		done <- c.check(ctx)
+	}()
+
+	var err error
+	select {
+	case err = <-done:
+	case <-ctx.Done():
+		err = fmt.Errorf("check timed out - %w", ctx.Err())
+	}
+
+	if err != nil {
+		return CheckResult{Name: c.name, Status: STATUS_FAILING, Message: err.Error()}
+	}
+	return CheckResult{Name: c.name, Status: STATUS_OK}
+}
@@ -0,0 +1,93 @@
+package health_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/ramasbeinaty/trading-chart-service/internal/testutil"
+	"github.com/ramasbeinaty/trading-chart-service/pkg/domain/health"
+)
+
+func TestReadinessIsOkWhenEveryCheckPasses(t *testing.T) {
+	service := health.NewHealthService(testutil.NopLogger{})
+	service.AddCheck("db", func(context.Context) error { return nil })
+	service.AddCheck("binance", func(context.Context) error { return nil })
+
+	report := service.Readiness(context.Background())
+
+	if report.Status != health.STATUS_OK {
+		t.Fatalf("expected ok, got %+v", report)
+	}
+	if len(report.Checks) != 2 || report.Checks[0].Name != "db" || report.Checks[1].Name != "binance" {
+		t.Fatalf("expected the checks in the order they were added, got %+v", report.Checks)
+	}
+}
+
+func TestReadinessReportsEachFailingCheck(t *testing.T) {
+	service := health.NewHealthService(testutil.NopLogger{})
+	health.SetTestTimeout(service, 50*time.Millisecond)
+
+	service.AddCheck("db", func(context.Context) error { return nil })
+	service.AddCheck("binance", func(context.Context) error {
+		return errors.New("binance stream is not connected")
+	})
+	service.AddCheck("slow", func(ctx context.Context) error {
+		<-ctx.Done()
+		time.Sleep(10 * time.Millisecond)
+		return nil
+	})
+	service.AddCheck("broken", func(context.Context) error { panic("boom") })
+
+	report := service.Readiness(context.Background())
+
+	if report.Status != health.STATUS_FAILING {
+		t.Fatalf("expected failing, got %+v", report)
+	}
+
+	expected := []health.Status{
+		health.STATUS_OK,
+		health.STATUS_FAILING,
+		health.STATUS_FAILING,
+		health.STATUS_FAILING,
+	}
+	for i, status := range expected {
+		if report.Checks[i].Status != status {
+			t.Errorf("expected check %s to be %s, got %+v", report.Checks[i].Name, status, report.Checks[i])
+		}
+	}
+	if report.Checks[1].Message != "binance stream is not connected" {
+		t.Errorf("expected the failure message to be reported, got %q", report.Checks[1].Message)
+	}
+}
+
+func TestReadinessFailsOnceShutDown(t *testing.T) {
+	service := health.NewHealthService(testutil.NopLogger{})
+	service.AddCheck("db", func(context.Context) error { return nil })
+
+	service.ShutDown()
+	report := service.Readiness(context.Background())
+
+	if report.Status != health.STATUS_FAILING {
+		t.Fatalf("expected failing once shut down, got %+v", report)
+	}
+}
+
+func TestRemovedChecksAreNoLongerReported(t *testing.T) {
+	service := health.NewHealthService(testutil.NopLogger{})
+	service.AddCheck("db", func(context.Context) error { return nil })
+	service.AddCheck("trades.PEPEUSDT", func(context.Context) error {
+		return errors.New("no trade received")
+	})
+
+	service.RemoveCheck("trades.PEPEUSDT")
+	report := service.Readiness(context.Background())
+
+	if report.Status != health.STATUS_OK {
+		t.Fatalf("expected ok, got %+v", report)
+	}
+	if len(report.Checks) != 1 || report.Checks[0].Name != "db" {
+		t.Fatalf("expected only the remaining check, got %+v", report.Checks)
+	}
+}
@@ -0,0 +1,259 @@
+package indicator
+
+import "math"
+
+// calculator maintains an indicator's state incrementally, bar by bar
+type calculator interface {
+	// advances the state with the close of a closed bar
+	push(close float64)
+	// returns the values as if the bar in progress closed at the given price,
+	// leaving the state untouched
+	peek(close float64) (values map[string]float64, ready bool)
+}
+
+func newCalculator(spec Spec) calculator {
+	switch spec.Type {
+	case TYPE_SMA:
+		return &smaCalculator{window: newWindow(spec.Periods[0])}
+	case TYPE_EMA:
+		return &emaCalculator{ema: newEMA(spec.Periods[0])}
+	case TYPE_RSI:
+		return newRSICalculator(spec.Periods[0])
+	case TYPE_MACD:
+		return &macdCalculator{
+			fast:   newEMA(spec.Periods[0]),
+			slow:   newEMA(spec.Periods[1]),
+			signal: newEMA(spec.Periods[2]),
+		}
+	case TYPE_BOLLINGER:
+		return &bollingerCalculator{
+			window: newWindow(spec.Periods[0]),
+			stdDev: spec.StdDev,
+		}
+	}
+	return nil
+}
+
+// window keeps the closes of the latest closed bars
+type window struct {
+	size   int
+	closes []float64
+}
+
+func newWindow(size int) *window {
+	return &window{size: size}
+}
+
+func (w *window) push(close float64) {
+	w.closes = append(w.closes, close)
+	if len(w.closes) > w.size {
+		w.closes = w.closes[len(w.closes)-w.size:]
+	}
+}
+
+// returns the latest size closes, ending with the given close
+// ready is false if not enough bars were seen yet
+func (w *window) with(close float64) (closes []float64, ready bool) {
+	if len(w.closes) < w.size-1 {
+		return nil, false
+	}
+	closes = make([]float64, 0, w.size)
+	closes = append(closes, w.closes[len(w.closes)-(w.size-1):]...)
+	return append(closes, close), true
+}
+
+// ema is an exponential moving average, seeded with the simple average
+// of its first period closes
+type ema struct {
+	period int
+	alpha  float64
+	count  int
+	sum    float64
+	value  float64
+}
+
+func newEMA(period int) *ema {
+	return &ema{
+		period: period,
+		alpha:  2 / float64(period+1),
+	}
+}
+
+func (e *ema) push(close float64) {
+	e.value, _ = e.peek(close)
+	e.count++
+	if e.count <= e.period {
+		e.sum += close
+	}
+}
+
+func (e *ema) peek(close float64) (float64, bool) {
+	switch {
+	case e.count+1 < e.period:
+		return 0, false
+	case e.count+1 == e.period:
+		return (e.sum + close) / float64(e.period), true
+	default:
+		return e.alpha*close + (1-e.alpha)*e.value, true
+	}
+}
+
+func (e *ema) ready() bool {
+	return e.count >= e.period
+}
+
+type smaCalculator struct {
+	window *window
+}
+
+func (c *smaCalculator) push(close float64) {
+	c.window.push(close)
+}
+
+func (c *smaCalculator) peek(close float64) (map[string]float64, bool) {
+	closes, ready := c.window.with(close)
+	if !ready {
+		return nil, false
+	}
+	return map[string]float64{"value": mean(closes)}, true
+}
+
+type emaCalculator struct {
+	ema *ema
+}
+
+func (c *emaCalculator) push(close float64) {
+	c.ema.push(close)
+}
+
+func (c *emaCalculator) peek(close float64) (map[string]float64, bool) {
+	value, ready := c.ema.peek(close)
+	if !ready {
+		return nil, false
+	}
+	return map[string]float64{"value": value}, true
+}
+
+// rsiCalculator smooths the average gains and losses with wilder's method,
+// seeded with the simple average of the first period changes
+type rsiCalculator struct {
+	period    int
+	prevClose float64
+	hasPrev   bool
+	count     int // number of changes seen
+	avgGain   float64
+	avgLoss   float64
+}
+
+func newRSICalculator(period int) *rsiCalculator {
+	return &rsiCalculator{period: period}
+}
+
+func (c *rsiCalculator) push(close float64) {
+	if c.hasPrev {
+		c.avgGain, c.avgLoss = c.averages(close)
+		c.count++
+	}
+	c.prevClose = close
+	c.hasPrev = true
+}
+
+func (c *rsiCalculator) peek(close float64) (map[string]float64, bool) {
+	if !c.hasPrev || c.count+1 < c.period {
+		return nil, false
+	}
+
+	avgGain, avgLoss := c.averages(close)
+	if avgLoss == 0 {
+		return map[string]float64{"value": 100}, true
+	}
+	return map[string]float64{"value": 100 - 100/(1+avgGain/avgLoss)}, true
+}
+
+// returns the averages including the change to the given close
+// before the first period changes, they are running sums of the changes
+func (c *rsiCalculator) averages(close float64) (avgGain float64, avgLoss float64) {
+	gain := math.Max(close-c.prevClose, 0)
+	loss := math.Max(c.prevClose-close, 0)
+	p := float64(c.period)
+
+	switch {
+	case c.count+1 < c.period:
+		return c.avgGain + gain, c.avgLoss + loss
+	case c.count+1 == c.period:
+		return (c.avgGain + gain) / p, (c.avgLoss + loss) / p
+	default:
+		return (c.avgGain*(p-1) + gain) / p, (c.avgLoss*(p-1) + loss) / p
+	}
+}
+
+type macdCalculator struct {
+	fast   *ema
+	slow   *ema
+	signal *ema // of the macd line, once the slow average is ready
+}
+
+func (c *macdCalculator) push(close float64) {
+	c.fast.push(close)
+	c.slow.push(close)
+	if c.slow.ready() {
+		c.signal.push(c.fast.value - c.slow.value)
+	}
+}
+
+func (c *macdCalculator) peek(close float64) (map[string]float64, bool) {
+	fast, _ := c.fast.peek(close)
+	slow, ready := c.slow.peek(close)
+	if !ready {
+		return nil, false
+	}
+
+	macd := fast - slow
+	signal, ready := c.signal.peek(macd)
+	if !ready {
+		return nil, false
+	}
+
+	return map[string]float64{
+		"macd":      macd,
+		"signal":    signal,
+		"histogram": macd - signal,
+	}, true
+}
+
+type bollingerCalculator struct {
+	window *window
+	stdDev float64
+}
+
+func (c *bollingerCalculator) push(close float64) {
+	c.window.push(close)
+}
+
+func (c *bollingerCalculator) peek(close float64) (map[string]float64, bool) {
+	closes, ready := c.window.with(close)
+	if !ready {
+		return nil, false
+	}
+
+	middle := mean(closes)
+	variance := 0.0
+	for _, v := range closes {
+		variance += (v - middle) * (v - middle)
+	}
+	deviation := c.stdDev * math.Sqrt(variance/float64(len(closes)))
+
+	return map[string]float64{
+		"upper":  middle + deviation,
+		"middle": middle,
+		"lower":  middle - deviation,
+	}, true
+}
+
+func mean(values []float64) float64 {
+	sum := 0.0
+	for _, v := range values {
+		sum += v
+	}
+	return sum / float64(len(values))
+}
@@ -0,0 +1,13 @@
+package indicator
+
+const (
+	// number of closed bars kept per symbol and timeframe to warm up indicators
+	// requested later on
+	MAX_SERIES_CLOSES = 1000
+	// largest period an indicator can be requested with
+	MAX_PERIOD = 200
+	// number of indicators a subscription can request
+	MAX_SUBSCRIPTION_INDICATORS = 10
+	// number of values a history request can return
+	MAX_HISTORY_VALUES = 1000
+)
@@ -0,0 +1,99 @@
+package indicator
+
+import (
+	"fmt"
+	"time"
+)
+
+type Type string
+
+const (
+	TYPE_SMA       Type = "SMA"  // simple moving average
+	TYPE_EMA       Type = "EMA"  // exponential moving average
+	TYPE_RSI       Type = "RSI"  // relative strength index, with wilder's smoothing
+	TYPE_MACD      Type = "MACD" // moving average convergence divergence
+	TYPE_BOLLINGER Type = "BB"   // bollinger bands
+)
+
+type Timeframe string
+
+const (
+	TIMEFRAME_1M  Timeframe = "1m"
+	TIMEFRAME_5M  Timeframe = "5m"
+	TIMEFRAME_15M Timeframe = "15m"
+	TIMEFRAME_1H  Timeframe = "1h"
+)
+
+var timeframeDurations = map[Timeframe]time.Duration{
+	TIMEFRAME_1M:  time.Minute,
+	TIMEFRAME_5M:  5 * time.Minute,
+	TIMEFRAME_15M: 15 * time.Minute,
+	TIMEFRAME_1H:  time.Hour,
+}
+
+func (t Timeframe) Duration() time.Duration {
+	return timeframeDurations[t]
+}
+
+// Spec is an indicator with its parameters, computed on the closes of the
+// bars of a timeframe, aggregated from the 1 minute bars
+type Spec struct {
+	Type      Type
+	Timeframe Timeframe
+	// SMA, EMA, RSI and BB: the period
+	// MACD: the fast, slow and signal periods
+	Periods []int
+	// BB: the number of standard deviations of the bands
+	StdDev float64
+}
+
+// Name identifies the spec with all its parameters, e.g. "MACD(12,26,9)@5m"
+func (s Spec) Name() string {
+	params := ""
+	for i, p := range s.Periods {
+		if i > 0 {
+			params += ","
+		}
+		params += fmt.Sprint(p)
+	}
+	if s.Type == TYPE_BOLLINGER {
+		params += fmt.Sprintf(",%g", s.StdDev)
+	}
+
+	return fmt.Sprintf("%s(%s)@%s", s.Type, params, s.Timeframe)
+}
+
+// returns the number of closed bars needed for the indicator to settle
+func (s Spec) warmupBars() int {
+	switch s.Type {
+	case TYPE_EMA, TYPE_RSI:
+		// exponential smoothing never fully forgets, 4 periods is close enough
+		return 4 * s.Periods[0]
+	case TYPE_MACD:
+		return 4 * (s.Periods[1] + s.Periods[2])
+	default:
+		return s.Periods[0]
+	}
+}
+
+// WarmupDuration returns how far back bars are needed for the indicator to settle
+func (s Spec) WarmupDuration() time.Duration {
+	return time.Duration(s.warmupBars()+1) * s.Timeframe.Duration()
+}
+
+// Bar is the close of a bar, opened at the timestamp
+type Bar struct {
+	Timestamp time.Time
+	Close     float64
+}
+
+// Value is the indicator's value for the timeframe bar opened at the timestamp
+type Value struct {
+	Name      string
+	Timestamp time.Time
+	// "value" for single valued indicators, otherwise
+	// MACD: "macd", "signal" and "histogram"
+	// BB: "upper", "middle" and "lower"
+	Values map[string]float64
+	Ready  bool // false until enough bars were seen
+}
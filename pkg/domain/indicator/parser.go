@@ -0,0 +1,92 @@
+package indicator
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// e.g. EMA, ema(50), MACD(12,26,9)@5m, BB(20,2.5)@1h
+var specPattern = regexp.MustCompile(`^([A-Za-z]+)(?:\(([0-9.,\s]*)\))?(?:@([0-9]+[mh]))?$`)
+
+var defaultParams = map[Type][]float64{
+	TYPE_SMA:       {20},
+	TYPE_EMA:       {20},
+	TYPE_RSI:       {14},
+	TYPE_MACD:      {12, 26, 9},
+	TYPE_BOLLINGER: {20, 2},
+}
+
+// ParseSpec parses an indicator written as TYPE(PARAMS)@TIMEFRAME
+// the parameters and timeframe are optional, defaulting to
+// SMA(20), EMA(20), RSI(14), MACD(12,26,9), BB(20,2) and 1m
+func ParseSpec(raw string) (Spec, error) {
+	match := specPattern.FindStringSubmatch(strings.TrimSpace(raw))
+	if match == nil {
+		return Spec{}, fmt.Errorf("Invalid indicator %s - expected TYPE(PARAMS)@TIMEFRAME", raw)
+	}
+
+	spec := Spec{
+		Type:      Type(strings.ToUpper(match[1])),
+		Timeframe: TIMEFRAME_1M,
+	}
+
+	defaults, ok := defaultParams[spec.Type]
+	if !ok {
+		return Spec{}, fmt.Errorf("Invalid indicator %s - unknown type %s", raw, match[1])
+	}
+
+	params := defaults
+	if strings.TrimSpace(match[2]) != "" {
+		params = []float64{}
+		for _, p := range strings.Split(match[2], ",") {
+			v, err := strconv.ParseFloat(strings.TrimSpace(p), 64)
+			if err != nil {
+				return Spec{}, fmt.Errorf("Invalid indicator %s - %w", raw, err)
+			}
+			params = append(params, v)
+		}
+	}
+	if len(params) != len(defaults) {
+		return Spec{}, fmt.Errorf(
+			"Invalid indicator %s - %s takes %d parameters",
+			raw,
+			spec.Type,
+			len(defaults),
+		)
+	}
+
+	if match[3] != "" {
+		spec.Timeframe = Timeframe(match[3])
+		if spec.Timeframe.Duration() == 0 {
+			return Spec{}, fmt.Errorf("Invalid indicator %s - unsupported timeframe %s", raw, match[3])
+		}
+	}
+
+	periods := params
+	if spec.Type == TYPE_BOLLINGER {
+		periods = params[:1]
+		spec.StdDev = params[1]
+		if spec.StdDev <= 0 {
+			return Spec{}, fmt.Errorf("Invalid indicator %s - standard deviations must be positive", raw)
+		}
+	}
+
+	for _, p := range periods {
+		if p != float64(int(p)) || p < 1 || p > MAX_PERIOD {
+			return Spec{}, fmt.Errorf(
+				"Invalid indicator %s - periods must be whole numbers between 1 and %d",
+				raw,
+				MAX_PERIOD,
+			)
+		}
+		spec.Periods = append(spec.Periods, int(p))
+	}
+
+	if spec.Type == TYPE_MACD && spec.Periods[0] >= spec.Periods[1] {
+		return Spec{}, fmt.Errorf("Invalid indicator %s - fast period must be shorter than slow period", raw)
+	}
+
+	return spec, nil
+}
@@ -0,0 +1,245 @@
+package indicator
+
+import (
+	"sort"
+	"sync"
+	"time"
+)
+
+// series aggregates a symbol's 1 minute bars into the bars of a timeframe,
+// feeding the closes of the closed ones to the indicators tracked on it
+type series struct {
+	timeframe   Timeframe
+	bucket      time.Time // open time of the bar in progress
+	close       float64   // latest close of the bar in progress
+	started     bool
+	closes      []float64 // closes of the closed bars, oldest first
+	specs       map[string]Spec
+	calculators map[string]calculator // keyed by spec name
+	// the owners each spec is computed for, keyed by spec name
+	owners map[string]map[int64]bool
+}
+
+func newSeries(timeframe Timeframe) *series {
+	return &series{
+		timeframe:   timeframe,
+		specs:       make(map[string]Spec),
+		calculators: make(map[string]calculator),
+		owners:      make(map[string]map[int64]bool),
+	}
+}
+
+// moves the series to the bar of the timestamp, closing the bar in progress
+// if it is older
+// updates of bars older than the one in progress are ignored
+func (s *series) update(timestamp time.Time, close float64) {
+	bucket := timestamp.Truncate(s.timeframe.Duration())
+
+	if s.started && bucket.Before(s.bucket) {
+		return
+	}
+
+	if s.started && bucket.After(s.bucket) {
+		s.closes = append(s.closes, s.close)
+		if len(s.closes) > MAX_SERIES_CLOSES {
+			s.closes = s.closes[len(s.closes)-MAX_SERIES_CLOSES:]
+		}
+		for _, calc := range s.calculators {
+			calc.push(s.close)
+		}
+	}
+
+	s.bucket = bucket
+	s.close = close
+	s.started = true
+}
+
+func (s *series) track(spec Spec) {
+	name := spec.Name()
+	if _, exists := s.calculators[name]; exists {
+		return
+	}
+
+	calc := newCalculator(spec)
+	for _, close := range s.closes {
+		calc.push(close)
+	}
+	s.specs[name] = spec
+	s.calculators[name] = calc
+}
+
+// stops computing the specs of the owner no other owner needs
+func (s *series) release(owner int64) {
+	for name, owners := range s.owners {
+		delete(owners, owner)
+		if len(owners) == 0 {
+			delete(s.owners, name)
+			delete(s.specs, name)
+			delete(s.calculators, name)
+		}
+	}
+}
+
+// returns the values of every indicator tracked on the series for the bar
+// in progress, sorted by name
+func (s *series) values() []Value {
+	values := make([]Value, 0, len(s.calculators))
+	for name, calc := range s.calculators {
+		v, ready := calc.peek(s.close)
+		values = append(values, Value{
+			Name:      name,
+			Timestamp: s.bucket,
+			Values:    v,
+			Ready:     ready,
+		})
+	}
+
+	sort.Slice(values, func(i, j int) bool {
+		return values[i].Name < values[j].Name
+	})
+
+	return values
+}
+
+// IndicatorService computes indicators incrementally as the bars of the
+// symbols they are tracked on are updated
+type IndicatorService struct {
+	series map[string]map[Timeframe]*series // keyed by symbol then timeframe
+	mutex  sync.Mutex
+}
+
+func NewIndicatorService() *IndicatorService {
+	return &IndicatorService{
+		series: make(map[string]map[Timeframe]*series),
+		mutex:  sync.Mutex{},
+	}
+}
+
+// IsTracked returns whether the indicator is already computed for the symbol
+func (i *IndicatorService) IsTracked(
+	symbol string,
+	spec Spec,
+) bool {
+	i.mutex.Lock()
+	defer i.mutex.Unlock()
+
+	s, exists := i.series[symbol][spec.Timeframe]
+	if !exists {
+		return false
+	}
+	_, exists = s.calculators[spec.Name()]
+	return exists
+}
+
+// Track starts computing the indicator for the symbol on behalf of the owner,
+// e.g. a subscriber, warming it up with the symbol's 1 minute bars, oldest
+// first, the last one being in progress
+// the series of the timeframe is rebuilt from the bars if they go further back
+// than the closes it already holds
+// the indicator is computed until every owner tracking it is released
+func (i *IndicatorService) Track(
+	owner int64,
+	symbol string,
+	spec Spec,
+	bars []Bar,
+) {
+	i.mutex.Lock()
+	defer i.mutex.Unlock()
+
+	if _, exists := i.series[symbol]; !exists {
+		i.series[symbol] = make(map[Timeframe]*series)
+	}
+
+	rebuilt := newSeries(spec.Timeframe)
+	for _, bar := range bars {
+		rebuilt.update(bar.Timestamp, bar.Close)
+	}
+
+	current, exists := i.series[symbol][spec.Timeframe]
+	if !exists || len(rebuilt.closes) > len(current.closes) {
+		if exists {
+			for _, s := range current.specs {
+				rebuilt.track(s)
+			}
+			rebuilt.owners = current.owners
+		}
+		current = rebuilt
+		i.series[symbol][spec.Timeframe] = current
+	}
+
+	current.track(spec)
+	name := spec.Name()
+	if current.owners[name] == nil {
+		current.owners[name] = make(map[int64]bool)
+	}
+	current.owners[name][owner] = true
+}
+
+// Release stops computing the indicators the owner tracks on the symbols
+// that no other owner tracks, dropping the series left without any
+func (i *IndicatorService) Release(
+	owner int64,
+	symbols []string,
+) {
+	i.mutex.Lock()
+	defer i.mutex.Unlock()
+
+	for _, symbol := range symbols {
+		for timeframe, s := range i.series[symbol] {
+			s.release(owner)
+			if len(s.calculators) == 0 {
+				delete(i.series[symbol], timeframe)
+			}
+		}
+		if len(i.series[symbol]) == 0 {
+			delete(i.series, symbol)
+		}
+	}
+}
+
+// Update feeds the symbol's 1 minute bar opened at the timestamp to its
+// tracked indicators, returning their values for the bar in progress
+func (i *IndicatorService) Update(
+	symbol string,
+	timestamp time.Time,
+	close float64,
+) []Value {
+	i.mutex.Lock()
+	defer i.mutex.Unlock()
+
+	values := []Value{}
+	for _, s := range i.series[symbol] {
+		s.update(timestamp, close)
+		values = append(values, s.values()...)
+	}
+
+	sort.Slice(values, func(i, j int) bool {
+		return values[i].Name < values[j].Name
+	})
+
+	return values
+}
+
+// ComputeHistory returns the indicator's value at the close of every
+// timeframe bar aggregated from the 1 minute bars, oldest first
+func (i *IndicatorService) ComputeHistory(
+	spec Spec,
+	bars []Bar,
+) []Value {
+	s := newSeries(spec.Timeframe)
+	s.track(spec)
+
+	values := []Value{}
+	for _, bar := range bars {
+		bucket := bar.Timestamp.Truncate(spec.Timeframe.Duration())
+		if s.started && bucket.After(s.bucket) {
+			values = append(values, s.values()...)
+		}
+		s.update(bar.Timestamp, bar.Close)
+	}
+	if s.started {
+		values = append(values, s.values()...)
+	}
+
+	return values
+}
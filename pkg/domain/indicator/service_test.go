@@ -0,0 +1,190 @@
+package indicator
+
+import (
+	"math"
+	"testing"
+	"time"
+)
+
+var start = time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+
+func minuteBars(closes ...float64) []Bar {
+	bars := make([]Bar, 0, len(closes))
+	for i, close := range closes {
+		bars = append(bars, Bar{
+			Timestamp: start.Add(time.Duration(i) * time.Minute),
+			Close:     close,
+		})
+	}
+	return bars
+}
+
+func mustParse(t *testing.T, raw string) Spec {
+	t.Helper()
+	spec, err := ParseSpec(raw)
+	if err != nil {
+		t.Fatalf("ParseSpec(%q) failed: %v", raw, err)
+	}
+	return spec
+}
+
+func assertValue(t *testing.T, value Value, key string, expected float64) {
+	t.Helper()
+	if !value.Ready {
+		t.Fatalf("expected %s at %s to be ready", value.Name, value.Timestamp)
+	}
+	if math.Abs(value.Values[key]-expected) > 1e-9 {
+		t.Fatalf("expected %s %s at %s to be %v, got %v",
+			value.Name, key, value.Timestamp, expected, value.Values[key])
+	}
+}
+
+func TestParseSpec(t *testing.T) {
+	cases := map[string]string{
+		"sma":              "SMA(20)@1m",
+		"EMA(50)@1h":       "EMA(50)@1h",
+		"RSI@5m":           "RSI(14)@5m",
+		"MACD":             "MACD(12,26,9)@1m",
+		"BB(20, 2.5)@15m":  "BB(20,2.5)@15m",
+		" macd(3,6,2)@5m ": "MACD(3,6,2)@5m",
+	}
+	for raw, expected := range cases {
+		if name := mustParse(t, raw).Name(); name != expected {
+			t.Fatalf("expected %q to parse as %s, got %s", raw, expected, name)
+		}
+	}
+
+	for _, raw := range []string{"", "VWAP", "SMA(0)", "SMA(2.5)", "SMA(1000)", "MACD(1,2)", "BB(20,-1)", "SMA@2m"} {
+		if _, err := ParseSpec(raw); err == nil {
+			t.Fatalf("expected %q to be rejected", raw)
+		}
+	}
+}
+
+func TestComputeHistory(t *testing.T) {
+	service := NewIndicatorService()
+	bars := minuteBars(1, 2, 3, 4, 5)
+
+	sma := service.ComputeHistory(mustParse(t, "SMA(3)"), bars)
+	if len(sma) != 5 || sma[1].Ready {
+		t.Fatalf("expected 5 values, the first 2 not ready, got %+v", sma)
+	}
+	assertValue(t, sma[2], "value", 2)
+	assertValue(t, sma[4], "value", 4)
+
+	ema := service.ComputeHistory(mustParse(t, "EMA(3)"), bars)
+	assertValue(t, ema[2], "value", 2)
+	assertValue(t, ema[3], "value", 3)
+	assertValue(t, ema[4], "value", 4)
+
+	rsi := service.ComputeHistory(mustParse(t, "RSI(2)"), minuteBars(1, 2, 3, 2))
+	if rsi[1].Ready {
+		t.Fatalf("expected RSI to need 2 changes, got %+v", rsi[1])
+	}
+	assertValue(t, rsi[2], "value", 100)
+	assertValue(t, rsi[3], "value", 50)
+
+	bb := service.ComputeHistory(mustParse(t, "BB(3,2)"), minuteBars(1, 2, 3))
+	assertValue(t, bb[2], "middle", 2)
+	assertValue(t, bb[2], "upper", 2+2*math.Sqrt(2.0/3))
+	assertValue(t, bb[2], "lower", 2-2*math.Sqrt(2.0/3))
+
+	macd := service.ComputeHistory(mustParse(t, "MACD(2,3,2)"), bars)
+	if macd[2].Ready || !macd[3].Ready {
+		t.Fatalf("expected MACD to be ready once the signal has 2 values, got %+v", macd)
+	}
+	histogram := macd[3].Values["macd"] - macd[3].Values["signal"]
+	assertValue(t, macd[3], "histogram", histogram)
+}
+
+func TestComputeHistoryAggregatesTimeframe(t *testing.T) {
+	service := NewIndicatorService()
+
+	// 5 minute bars close at 5, 10 then 12 in progress
+	bars := minuteBars(1, 2, 3, 4, 5, 6, 7, 8, 9, 10, 11, 12)
+	values := service.ComputeHistory(mustParse(t, "SMA(2)@5m"), bars)
+
+	if len(values) != 3 {
+		t.Fatalf("expected a value per 5 minute bar, got %+v", values)
+	}
+	if !values[1].Timestamp.Equal(start.Add(5 * time.Minute)) {
+		t.Fatalf("expected values to be stamped with the bar open time, got %s", values[1].Timestamp)
+	}
+	assertValue(t, values[1], "value", 7.5)
+	assertValue(t, values[2], "value", 11)
+}
+
+func TestUpdateMatchesHistory(t *testing.T) {
+	service := NewIndicatorService()
+	spec := mustParse(t, "MACD(3,5,2)")
+	closes := []float64{10, 11, 12, 11, 13, 14, 13, 15, 16, 15, 17, 18}
+	bars := minuteBars(closes...)
+	expected := service.ComputeHistory(spec, bars)
+
+	// warmed up with the first bars, the last one being in progress
+	service.Track(1, "BTCUSDT", spec, bars[:6])
+	if !service.IsTracked("BTCUSDT", spec) {
+		t.Fatalf("expected %s to be tracked", spec.Name())
+	}
+
+	for i := 5; i < len(bars); i++ {
+		// ticks of the bar in progress only change its current value
+		service.Update("BTCUSDT", bars[i].Timestamp, bars[i].Close-1)
+		values := service.Update("BTCUSDT", bars[i].Timestamp, bars[i].Close)
+
+		if len(values) != 1 {
+			t.Fatalf("expected a single value, got %+v", values)
+		}
+		for _, key := range []string{"macd", "signal", "histogram"} {
+			assertValue(t, values[0], key, expected[i].Values[key])
+		}
+	}
+
+	if values := service.Update("ETHUSDT", start, 1); len(values) != 0 {
+		t.Fatalf("expected no values for an untracked symbol, got %+v", values)
+	}
+}
+
+func TestTrackRebuildsShorterSeries(t *testing.T) {
+	service := NewIndicatorService()
+	sma := mustParse(t, "SMA(2)")
+	ema := mustParse(t, "EMA(5)")
+	bars := minuteBars(1, 2, 3, 4, 5, 6)
+
+	service.Track(1, "BTCUSDT", sma, bars[4:])
+	service.Track(1, "BTCUSDT", ema, bars)
+
+	values := service.Update("BTCUSDT", bars[5].Timestamp, bars[5].Close)
+	if len(values) != 2 {
+		t.Fatalf("expected both indicators, got %+v", values)
+	}
+	assertValue(t, values[0], "value", 4)   // EMA(5)
+	assertValue(t, values[1], "value", 5.5) // SMA(2)
+}
+
+func TestReleaseUntracksTheIndicatorsOfNoOwner(t *testing.T) {
+	service := NewIndicatorService()
+	sma := mustParse(t, "SMA(2)")
+	ema := mustParse(t, "EMA(2)@5m")
+	bars := minuteBars(1, 2, 3)
+
+	service.Track(1, "BTCUSDT", sma, bars)
+	service.Track(2, "BTCUSDT", sma, bars)
+	service.Track(2, "BTCUSDT", ema, bars)
+
+	service.Release(2, []string{"BTCUSDT"})
+	if !service.IsTracked("BTCUSDT", sma) {
+		t.Fatal("expected the indicator of owner 1 to stay tracked")
+	}
+	if service.IsTracked("BTCUSDT", ema) {
+		t.Fatal("expected the indicator of owner 2 only to be untracked")
+	}
+
+	service.Release(1, []string{"BTCUSDT"})
+	if values := service.Update("BTCUSDT", bars[2].Timestamp, 4); len(values) != 0 {
+		t.Fatalf("expected no indicator left to compute, got %+v", values)
+	}
+	if len(service.series) != 0 {
+		t.Fatalf("expected the series to be dropped, got %v", service.series)
+	}
+}
@@ -0,0 +1,54 @@
+package ratelimit
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Limits are enforced per client, zero leaving a limit off
+type Limits struct {
+	// unary calls per second, in bursts of up to UnaryBurst
+	UnaryRate  float64
+	UnaryBurst int
+	// streams open at once
+	MaxStreams int
+	// symbols a subscriber receives, patterns counting the tracked symbols
+	// they match when subscribing
+	MaxSymbols int
+}
+
+func (l *Limits) Validate() error {
+	if l.UnaryRate < 0 || l.UnaryBurst < 0 || l.MaxStreams < 0 || l.MaxSymbols < 0 {
+		return fmt.Errorf("Invalid limits - must not be negative - %+v", *l)
+	}
+	if l.UnaryRate > 0 && l.UnaryBurst == 0 {
+		return fmt.Errorf("Invalid limits - a unary rate needs a burst - %+v", *l)
+	}
+	return nil
+}
+
+type RateLimitConfig struct {
+	Default Limits
+	// keyed by the lowercased client, i.e. api_key:<name> or jwt:<subject>
+	// once authenticated, or the peer ip
+	Overrides map[string]Limits
+}
+
+func (c *RateLimitConfig) Validate() error {
+	if err := c.Default.Validate(); err != nil {
+		return err
+	}
+	for client, limits := range c.Overrides {
+		if err := limits.Validate(); err != nil {
+			return fmt.Errorf("%w, for %s", err, client)
+		}
+	}
+	return nil
+}
+
+func (c *RateLimitConfig) limitsFor(client string) Limits {
+	if limits, ok := c.Overrides[strings.ToLower(client)]; ok {
+		return limits
+	}
+	return c.Default
+}
@@ -0,0 +1,25 @@
+package ratelimit
+
+import "time"
+
+const (
+	// clients idle for longer are forgotten, their buckets starting full
+	// on their next call
+	CLIENT_IDLE_TIMEOUT = 10 * time.Minute
+	EVICT_INTERVAL      = time.Minute
+
+	// suggested to clients over their stream quota, as there is no telling
+	// when one of their streams ends
+	STREAM_RETRY_AFTER = 5 * time.Second
+
+	LIMIT_UNARY_RATE = "unary_rate"
+	LIMIT_STREAMS    = "streams"
+	LIMIT_SYMBOLS    = "symbols"
+)
+
+const (
+	DEFAULT_UNARY_RATE  = 20
+	DEFAULT_UNARY_BURST = 40
+	DEFAULT_MAX_STREAMS = 16
+	DEFAULT_MAX_SYMBOLS = 500
+)
@@ -0,0 +1,46 @@
+package ratelimit
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+)
+
+// matched by every LimitError
+var ERR_LIMIT_EXCEEDED = errors.New("limit exceeded")
+
+// LimitError is returned once a client exceeds one of its limits
+type LimitError struct {
+	Limit string
+	// when the client may retry, zero if retrying won't help
+	RetryAfter time.Duration
+	Message    string
+}
+
+func (e *LimitError) Error() string {
+	return fmt.Sprintf("%s - %s", ERR_LIMIT_EXCEEDED, e.Message)
+}
+
+func (e *LimitError) Is(target error) bool {
+	return target == ERR_LIMIT_EXCEEDED
+}
+
+type clientKey struct{}
+
+// WithClient sets the client the request is limited as
+func WithClient(
+	ctx context.Context,
+	client string,
+) context.Context {
+	return context.WithValue(ctx, clientKey{}, client)
+}
+
+// ClientFromContext returns the client the request is limited as,
+// empty if it isn't limited
+func ClientFromContext(
+	ctx context.Context,
+) string {
+	client, _ := ctx.Value(clientKey{}).(string)
+	return client
+}
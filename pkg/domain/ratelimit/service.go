@@ -0,0 +1,214 @@
+package ratelimit
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/ramasbeinaty/trading-chart-service/pkg/domain/base/logger"
+	"go.uber.org/zap"
+	"golang.org/x/time/rate"
+)
+
+// RateLimiter enforces the limits of every client, which can be changed
+// while running
+type RateLimiter struct {
+	lgr     logger.ILogger
+	mutex   sync.Mutex
+	cfg     *RateLimitConfig
+	clients map[string]*client
+	now     func() time.Time
+}
+
+type client struct {
+	limits Limits
+	// nil if the unary rate is unlimited
+	limiter  *rate.Limiter
+	streams  int
+	lastSeen time.Time
+}
+
+func NewRateLimiter(
+	cfg *RateLimitConfig,
+	lgr logger.ILogger,
+) *RateLimiter {
+	return &RateLimiter{
+		lgr:     lgr,
+		cfg:     cfg,
+		clients: map[string]*client{},
+		now:     time.Now,
+	}
+}
+
+// SetConfig applies the limits to every client from now on, the streams
+// already open over a lowered quota being kept
+func (r *RateLimiter) SetConfig(
+	ctx context.Context,
+	cfg *RateLimitConfig,
+) {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	r.cfg = cfg
+	for key, c := range r.clients {
+		r.applyLimits(c, cfg.limitsFor(key))
+	}
+
+	r.lgr.Get(ctx).Info(
+		"Applied the rate limits",
+		zap.Any("default", cfg.Default),
+		zap.Int("overrides", len(cfg.Overrides)),
+	)
+}
+
+// Start forgets the idle clients until the context is done
+func (r *RateLimiter) Start(
+	ctx context.Context,
+	wg *sync.WaitGroup,
+) {
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+
+		ticker := time.NewTicker(EVICT_INTERVAL)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+				r.evictIdle()
+			}
+		}
+	}()
+}
+
+// AllowUnary takes a token of the client's unary bucket, failing with the
+// time until the next one if it is empty
+func (r *RateLimiter) AllowUnary(
+	key string,
+) error {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	c := r.getClient(key)
+	if c.limiter == nil {
+		return nil
+	}
+
+	now := r.now()
+	reservation := c.limiter.ReserveN(now, 1)
+	if delay := reservation.DelayFrom(now); delay > 0 {
+		reservation.CancelAt(now)
+		return &LimitError{
+			Limit:      LIMIT_UNARY_RATE,
+			RetryAfter: delay,
+			Message:    fmt.Sprintf("at most %g calls per second are allowed", c.limits.UnaryRate),
+		}
+	}
+	return nil
+}
+
+// AcquireStream counts a stream opened by the client, failing if it has
+// too many open already
+// release must be called once the stream ends
+func (r *RateLimiter) AcquireStream(
+	key string,
+) (release func(), err error) {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	c := r.getClient(key)
+	if c.limits.MaxStreams != 0 && c.streams >= c.limits.MaxStreams {
+		return nil, &LimitError{
+			Limit:      LIMIT_STREAMS,
+			RetryAfter: STREAM_RETRY_AFTER,
+			Message:    fmt.Sprintf("at most %d streams can be open at once", c.limits.MaxStreams),
+		}
+	}
+	c.streams++
+
+	var once sync.Once
+	return func() {
+		once.Do(func() {
+			r.mutex.Lock()
+			defer r.mutex.Unlock()
+			c.streams--
+			c.lastSeen = r.now()
+		})
+	}, nil
+}
+
+// CheckSymbols fails if the client of the request may not receive as many
+// symbols on a subscriber, every request not limited passing
+func (r *RateLimiter) CheckSymbols(
+	ctx context.Context,
+	count int,
+) error {
+	key := ClientFromContext(ctx)
+	if key == "" {
+		return nil
+	}
+
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	c := r.getClient(key)
+	if c.limits.MaxSymbols != 0 && count > c.limits.MaxSymbols {
+		return &LimitError{
+			Limit: LIMIT_SYMBOLS,
+			Message: fmt.Sprintf(
+				"at most %d symbols can be subscribed to, the subscription would receive %d",
+				c.limits.MaxSymbols,
+				count,
+			),
+		}
+	}
+	return nil
+}
+
+// expects the caller to hold the mutex
+func (r *RateLimiter) getClient(
+	key string,
+) *client {
+	c, ok := r.clients[key]
+	if !ok {
+		c = &client{}
+		r.applyLimits(c, r.cfg.limitsFor(key))
+		r.clients[key] = c
+	}
+	c.lastSeen = r.now()
+	return c
+}
+
+// expects the caller to hold the mutex
+func (r *RateLimiter) applyLimits(
+	c *client,
+	limits Limits,
+) {
+	c.limits = limits
+
+	switch {
+	case limits.UnaryRate == 0:
+		c.limiter = nil
+	case c.limiter == nil:
+		c.limiter = rate.NewLimiter(rate.Limit(limits.UnaryRate), limits.UnaryBurst)
+	default:
+		now := r.now()
+		c.limiter.SetLimitAt(now, rate.Limit(limits.UnaryRate))
+		c.limiter.SetBurstAt(now, limits.UnaryBurst)
+	}
+}
+
+func (r *RateLimiter) evictIdle() {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	for key, c := range r.clients {
+		if c.streams == 0 && r.now().Sub(c.lastSeen) > CLIENT_IDLE_TIMEOUT {
+			delete(r.clients, key)
+		}
+	}
+}
@@ -0,0 +1,102 @@
+package ratelimit
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/ramasbeinaty/trading-chart-service/internal/testutil"
+)
+
+func newTestLimiter(limits Limits) (*RateLimiter, *testutil.Clock) {
+	clock := testutil.NewClock(time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC))
+	limiter := NewRateLimiter(&RateLimitConfig{Default: limits}, testutil.NopLogger{})
+	limiter.now = clock.Now
+	return limiter, clock
+}
+
+func TestAllowUnaryFailsOnceTheBurstIsSpentWithTheRetryDelay(t *testing.T) {
+	limiter, clock := newTestLimiter(Limits{UnaryRate: 2, UnaryBurst: 2})
+
+	for i := 0; i < 2; i++ {
+		if err := limiter.AllowUnary("desk-1"); err != nil {
+			t.Fatalf("expected call %d to be allowed, got %v", i, err)
+		}
+	}
+
+	err := limiter.AllowUnary("desk-1")
+	var limitErr *LimitError
+	if !errors.As(err, &limitErr) || !errors.Is(err, ERR_LIMIT_EXCEEDED) {
+		t.Fatalf("expected a limit error, got %v", err)
+	}
+	if limitErr.Limit != LIMIT_UNARY_RATE || limitErr.RetryAfter != 500*time.Millisecond {
+		t.Errorf("expected to retry the unary rate in 500ms, got %s in %s", limitErr.Limit, limitErr.RetryAfter)
+	}
+
+	if err := limiter.AllowUnary("desk-2"); err != nil {
+		t.Errorf("expected other clients to be limited separately, got %v", err)
+	}
+
+	clock.Advance(500 * time.Millisecond)
+	if err := limiter.AllowUnary("desk-1"); err != nil {
+		t.Errorf("expected the call to be allowed once refilled, got %v", err)
+	}
+}
+
+func TestAcquireStreamLimitsTheOpenStreams(t *testing.T) {
+	limiter, _ := newTestLimiter(Limits{MaxStreams: 1})
+
+	release, err := limiter.AcquireStream("desk-1")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if _, err := limiter.AcquireStream("desk-1"); !errors.Is(err, ERR_LIMIT_EXCEEDED) {
+		t.Fatalf("expected a second stream to be refused, got %v", err)
+	}
+
+	release()
+	release()
+	if _, err := limiter.AcquireStream("desk-1"); err != nil {
+		t.Errorf("expected a stream once released, got %v", err)
+	}
+	if _, err := limiter.AcquireStream("desk-1"); !errors.Is(err, ERR_LIMIT_EXCEEDED) {
+		t.Errorf("expected releasing twice to count once, got %v", err)
+	}
+}
+
+func TestCheckSymbolsOnlyLimitsLimitedRequests(t *testing.T) {
+	limiter, _ := newTestLimiter(Limits{MaxSymbols: 2})
+
+	if err := limiter.CheckSymbols(context.Background(), 10); err != nil {
+		t.Errorf("expected requests without a client to pass, got %v", err)
+	}
+
+	ctx := WithClient(context.Background(), "desk-1")
+	if err := limiter.CheckSymbols(ctx, 2); err != nil {
+		t.Errorf("expected 2 symbols to be allowed, got %v", err)
+	}
+	if err := limiter.CheckSymbols(ctx, 3); !errors.Is(err, ERR_LIMIT_EXCEEDED) {
+		t.Errorf("expected 3 symbols to be refused, got %v", err)
+	}
+}
+
+func TestSetConfigAppliesToKnownClients(t *testing.T) {
+	limiter, _ := newTestLimiter(Limits{MaxStreams: 1})
+
+	if _, err := limiter.AcquireStream("Desk-1"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	limiter.SetConfig(context.Background(), &RateLimitConfig{
+		Default:   Limits{MaxStreams: 1},
+		Overrides: map[string]Limits{"desk-1": {MaxStreams: 2}},
+	})
+
+	if _, err := limiter.AcquireStream("Desk-1"); err != nil {
+		t.Errorf("expected the raised override to apply, got %v", err)
+	}
+	if _, err := limiter.AcquireStream("Desk-1"); !errors.Is(err, ERR_LIMIT_EXCEEDED) {
+		t.Errorf("expected the override to still limit, got %v", err)
+	}
+}
@@ -0,0 +1,11 @@
+package replay
+
+import (
+	"context"
+	"time"
+)
+
+// SetTestWait replaces the wait between the trades
+func SetTestWait(s *ReplayService, wait func(ctx context.Context, delay time.Duration) error) {
+	s.wait = wait
+}
@@ -0,0 +1,26 @@
+package replay
+
+import (
+	"context"
+	"time"
+)
+
+// ITradeReader reads recorded trades, in time order
+type ITradeReader interface {
+	// returns io.EOF once every trade was read
+	Next() (*Trade, error)
+}
+
+// ITickProcessor makes the bars of the trades
+type ITickProcessor interface {
+	ProcessTicks(
+		ctx context.Context,
+		symbol string,
+		price float64,
+		tradeTimestamp time.Time,
+	) error
+	// commits the bars whose minute ended
+	CommitCompleteBars(
+		ctx context.Context,
+	) error
+}
@@ -0,0 +1,18 @@
+package replay
+
+import "time"
+
+// Trade is a recorded trade
+type Trade struct {
+	Symbol    string
+	Price     float64
+	Timestamp time.Time
+}
+
+// Stats sums up a replay
+type Stats struct {
+	Trades int // processed
+	// older than the bars already committed, as the trades are expected in
+	// time order
+	Skipped int
+}
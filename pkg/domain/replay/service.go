@@ -0,0 +1,119 @@
+package replay
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"time"
+
+	"github.com/ramasbeinaty/trading-chart-service/pkg/domain/base/logger"
+	"go.uber.org/zap"
+)
+
+// ReplayService feeds recorded trades to the bars as if they were received
+// live, e.g. to rebuild the bars or to demo the streams
+type ReplayService struct {
+	ticks ITickProcessor
+	lgr   logger.ILogger
+	// waits for the delay until the next trade, unless the context is done
+	wait func(ctx context.Context, delay time.Duration) error
+}
+
+func NewReplayService(
+	ticks ITickProcessor,
+	lgr logger.ILogger,
+) *ReplayService {
+	return &ReplayService{
+		ticks: ticks,
+		lgr:   lgr,
+		wait:  wait,
+	}
+}
+
+// Replay processes the trades of the reader, spacing them out by their time
+// apart divided by the speed, or back to back if the speed is 0
+// the bars of a minute are committed once a trade of a later minute is
+// replayed, and the ones that ended by now once every trade is
+func (s *ReplayService) Replay(
+	ctx context.Context,
+	reader ITradeReader,
+	speed float64,
+) (Stats, error) {
+	lgr := s.lgr.Get(ctx)
+
+	stats := Stats{}
+	if speed < 0 {
+		return stats, fmt.Errorf("Failed to replay - speed must not be negative, got %v", speed)
+	}
+
+	var last time.Time // time of the last trade processed
+	for {
+		trade, err := reader.Next()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return stats, fmt.Errorf("Failed to read the trade after %d - %w", stats.Trades+stats.Skipped, err)
+		}
+
+		minute := trade.Timestamp.Truncate(time.Minute)
+		if !last.IsZero() && minute.Before(last.Truncate(time.Minute)) {
+			lgr.Warn(
+				"Skipping a trade older than the bars committed",
+				zap.Any("trade", trade),
+			)
+			stats.Skipped++
+			continue
+		}
+
+		if !last.IsZero() {
+			if speed > 0 && trade.Timestamp.After(last) {
+				delay := time.Duration(float64(trade.Timestamp.Sub(last)) / speed)
+				if err := s.wait(ctx, delay); err != nil {
+					return stats, fmt.Errorf("Failed to replay - %w", err)
+				}
+			}
+
+			if minute.After(last.Truncate(time.Minute)) {
+				if err := s.ticks.CommitCompleteBars(ctx); err != nil {
+					return stats, fmt.Errorf("Failed to commit the replayed bars - %w", err)
+				}
+			}
+		}
+
+		err = s.ticks.ProcessTicks(ctx, trade.Symbol, trade.Price, trade.Timestamp)
+		if err != nil {
+			return stats, fmt.Errorf("Failed to process the trade at %s - %w", trade.Timestamp, err)
+		}
+		stats.Trades++
+		last = trade.Timestamp
+	}
+
+	if err := s.ticks.CommitCompleteBars(ctx); err != nil {
+		return stats, fmt.Errorf("Failed to commit the replayed bars - %w", err)
+	}
+
+	lgr.Info(
+		"Replayed the trades",
+		zap.Int("trades", stats.Trades),
+		zap.Int("skipped", stats.Skipped),
+	)
+
+	return stats, nil
+}
+
+func wait(
+	ctx context.Context,
+	delay time.Duration,
+) error {
+	timer := time.NewTimer(delay)
+	defer timer.Stop()
+
+	select {
+	case <-timer.C:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
@@ -0,0 +1,133 @@
+package replay_test
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"testing"
+	"time"
+
+	"github.com/ramasbeinaty/trading-chart-service/internal/testutil"
+	"github.com/ramasbeinaty/trading-chart-service/pkg/domain/replay"
+)
+
+// sliceReader reads the trades it holds
+type sliceReader struct {
+	trades []*replay.Trade
+}
+
+func (r *sliceReader) Next() (*replay.Trade, error) {
+	if len(r.trades) == 0 {
+		return nil, io.EOF
+	}
+	trade := r.trades[0]
+	r.trades = r.trades[1:]
+	return trade, nil
+}
+
+// recordingProcessor records the ticks and commits, in order
+type recordingProcessor struct {
+	calls []string
+}
+
+func (p *recordingProcessor) ProcessTicks(_ context.Context, symbol string, price float64, at time.Time) error {
+	p.calls = append(p.calls, fmt.Sprintf("%s %v %s", symbol, price, at.Format("15:04:05")))
+	return nil
+}
+
+func (p *recordingProcessor) CommitCompleteBars(context.Context) error {
+	p.calls = append(p.calls, "commit")
+	return nil
+}
+
+func trade(symbol string, price float64, at string) *replay.Trade {
+	timestamp, _ := time.Parse("15:04:05", at)
+	return &replay.Trade{Symbol: symbol, Price: price, Timestamp: timestamp}
+}
+
+func TestReplayCommitsTheBarsOnceTheirMinuteIsOver(t *testing.T) {
+	processor := &recordingProcessor{}
+	service := replay.NewReplayService(processor, testutil.NopLogger{})
+
+	delays := []time.Duration{}
+	replay.SetTestWait(service, func(_ context.Context, delay time.Duration) error {
+		delays = append(delays, delay)
+		return nil
+	})
+
+	stats, err := service.Replay(context.Background(), &sliceReader{trades: []*replay.Trade{
+		trade("BTCUSDT", 1, "10:00:10"),
+		trade("ETHUSDT", 2, "10:00:50"),
+		trade("BTCUSDT", 3, "10:01:30"),
+		// older than the committed bars
+		trade("ETHUSDT", 4, "10:00:55"),
+		trade("BTCUSDT", 5, "10:01:30"),
+		trade("BTCUSDT", 6, "10:03:00"),
+	}}, 2)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if stats.Trades != 5 || stats.Skipped != 1 {
+		t.Fatalf("expected 5 trades replayed and 1 skipped, got %+v", stats)
+	}
+
+	expected := []string{
+		"BTCUSDT 1 10:00:10",
+		"ETHUSDT 2 10:00:50",
+		"commit",
+		"BTCUSDT 3 10:01:30",
+		"BTCUSDT 5 10:01:30",
+		"commit",
+		"BTCUSDT 6 10:03:00",
+		"commit",
+	}
+	if fmt.Sprint(processor.calls) != fmt.Sprint(expected) {
+		t.Fatalf("expected %v, got %v", expected, processor.calls)
+	}
+
+	// the gaps at twice the speed, the trades of the same time back to back
+	expectedDelays := []time.Duration{20 * time.Second, 20 * time.Second, 45 * time.Second}
+	if fmt.Sprint(delays) != fmt.Sprint(expectedDelays) {
+		t.Fatalf("expected delays %v, got %v", expectedDelays, delays)
+	}
+}
+
+func TestReplayWithoutSpeedDoesNotWait(t *testing.T) {
+	processor := &recordingProcessor{}
+	service := replay.NewReplayService(processor, testutil.NopLogger{})
+	replay.SetTestWait(service, func(context.Context, time.Duration) error {
+		t.Fatal("expected no wait")
+		return nil
+	})
+
+	stats, err := service.Replay(context.Background(), &sliceReader{trades: []*replay.Trade{
+		trade("BTCUSDT", 1, "10:00:10"),
+		trade("BTCUSDT", 2, "11:00:10"),
+	}}, 0)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if stats.Trades != 2 {
+		t.Fatalf("expected 2 trades replayed, got %d", stats.Trades)
+	}
+}
+
+func TestReplayStopsOnceTheContextIsDone(t *testing.T) {
+	processor := &recordingProcessor{}
+	service := replay.NewReplayService(processor, testutil.NopLogger{})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	_, err := service.Replay(ctx, &sliceReader{trades: []*replay.Trade{
+		trade("BTCUSDT", 1, "10:00:10"),
+		trade("BTCUSDT", 2, "11:00:10"),
+	}}, 1)
+	if err == nil {
+		t.Fatal("expected an error")
+	}
+	if len(processor.calls) != 1 {
+		t.Fatalf("expected only the first trade processed, got %v", processor.calls)
+	}
+}
@@ -0,0 +1,10 @@
+package replication
+
+type ReplicationConfig struct {
+	// the replicas elect a leader among them, otherwise the service leads alone
+	Enabled bool
+	// grpc address the other replicas reach this one on once elected
+	AdvertiseAddress string
+	// key of the postgres advisory lock held by the leader
+	LockKey int64
+}
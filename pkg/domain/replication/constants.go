@@ -0,0 +1,14 @@
+package replication
+
+import "time"
+
+const (
+	// how often the lock is tried by the replicas not leading, and checked
+	// by the leader
+	ELECTION_INTERVAL = 5 * time.Second
+	// the leader stops ingesting within it once the lock is lost
+	STEP_DOWN_TIMEOUT = 10 * time.Second
+
+	// "tcs" in ascii
+	DEFAULT_LOCK_KEY = 0x746373
+)
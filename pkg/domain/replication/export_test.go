@@ -0,0 +1,8 @@
+package replication
+
+import "time"
+
+// SetTestInterval shortens the election interval for tests
+func SetTestInterval(r *ReplicationService, interval time.Duration) {
+	r.interval = interval
+}
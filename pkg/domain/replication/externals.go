@@ -0,0 +1,57 @@
+package replication
+
+import (
+	"context"
+
+	"github.com/ramasbeinaty/trading-chart-service/pkg/domain/subscription"
+)
+
+// ILock is held by a single replica at a time, and released
+// if the replica holding it dies
+type ILock interface {
+	// takes the lock if free, reporting whether it is held
+	TryAcquire(ctx context.Context) (bool, error)
+	// fails if the lock is no longer held
+	Check(ctx context.Context) error
+	Release(ctx context.Context) error
+}
+
+type IRepository interface {
+	SetLeaderAddress(
+		ctx context.Context,
+		address string,
+	) error
+	// returns an empty address if no replica led yet
+	GetLeaderAddress(
+		ctx context.Context,
+	) (string, error)
+}
+
+// ILeaderClient receives the bar updates of the leader
+type ILeaderClient interface {
+	// streams the updates of the leader at the address to onUpdate, until
+	// the stream or the context ends
+	StreamBarUpdates(
+		ctx context.Context,
+		address string,
+		follower string,
+		onUpdate func(event *subscription.CandlestickEvent),
+	) error
+}
+
+// ILeader ingests the trades while the replica leads
+type ILeader interface {
+	// starts ingesting, until StepDown
+	Lead(ctx context.Context) error
+	StepDown(ctx context.Context) error
+}
+
+// IFollower applies the bar updates of the leader while the replica follows
+type IFollower interface {
+	// called before following a leader, which starts with a snapshot
+	ResetUpdates(ctx context.Context)
+	ApplyUpdate(
+		ctx context.Context,
+		event *subscription.CandlestickEvent,
+	)
+}
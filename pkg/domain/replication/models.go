@@ -0,0 +1,23 @@
+package replication
+
+type Role int32
+
+const (
+	// the replica neither leads nor follows a leader, e.g. between elections
+	ROLE_NONE Role = iota
+	// the replica ingests the trades and commits the bars
+	ROLE_LEADER
+	// the replica receives the bar updates of the leader
+	ROLE_FOLLOWER
+)
+
+func (r Role) String() string {
+	switch r {
+	case ROLE_LEADER:
+		return "leader"
+	case ROLE_FOLLOWER:
+		return "follower"
+	default:
+		return "none"
+	}
+}
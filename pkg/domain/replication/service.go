@@ -0,0 +1,253 @@
+package replication
+
+import (
+	"context"
+	"fmt"
+	"sync/atomic"
+	"time"
+
+	"github.com/ramasbeinaty/trading-chart-service/pkg/domain/base/logger"
+	"github.com/ramasbeinaty/trading-chart-service/pkg/domain/subscription"
+	"go.uber.org/zap"
+)
+
+// ReplicationService elects the replica ingesting the trades: the one
+// holding the lock leads, while the others follow its bar updates to serve
+// their own subscribers
+type ReplicationService struct {
+	cfg    *ReplicationConfig
+	lock   ILock
+	repo   IRepository
+	client ILeaderClient
+	lgr    logger.ILogger
+	role   atomic.Int32
+
+	leader   ILeader
+	cancel   context.CancelFunc
+	done     chan struct{}
+	interval time.Duration
+}
+
+func NewReplicationService(
+	cfg *ReplicationConfig,
+	lock ILock,
+	repo IRepository,
+	client ILeaderClient,
+	lgr logger.ILogger,
+) *ReplicationService {
+	return &ReplicationService{
+		cfg:      cfg,
+		lock:     lock,
+		repo:     repo,
+		client:   client,
+		lgr:      lgr,
+		interval: ELECTION_INTERVAL,
+	}
+}
+
+func (r *ReplicationService) Role() Role {
+	return Role(r.role.Load())
+}
+
+func (r *ReplicationService) IsLeader() bool {
+	return r.Role() == ROLE_LEADER
+}
+
+// LeaderAddress returns the address advertised by the leader, empty if no
+// replica led yet
+func (r *ReplicationService) LeaderAddress(
+	ctx context.Context,
+) (string, error) {
+	return r.repo.GetLeaderAddress(ctx)
+}
+
+// Start runs the election until Stop, the replica leading from the start
+// if replication is disabled
+func (r *ReplicationService) Start(
+	ctx context.Context,
+	leader ILeader,
+	follower IFollower,
+) {
+	ctx, cancel := context.WithCancel(ctx)
+	r.leader = leader
+	r.cancel = cancel
+	r.done = make(chan struct{})
+
+	go func() {
+		defer close(r.done)
+
+		for {
+			held := true
+			if r.cfg.Enabled {
+				var err error
+				held, err = r.lock.TryAcquire(ctx)
+				if err != nil && ctx.Err() == nil {
+					r.lgr.Get(ctx).Error("Failed to try the leader lock", zap.Error(err))
+				}
+			}
+
+			switch {
+			case ctx.Err() != nil:
+			case held:
+				r.lead(ctx, leader)
+			default:
+				r.follow(ctx, follower)
+			}
+
+			select {
+			case <-ctx.Done():
+				return
+			case <-time.After(r.interval):
+			}
+		}
+	}()
+}
+
+// Stop ends the election, stepping down if leading
+// the role and the lock are kept, for the leader to flush its bars before
+// releasing the lock
+func (r *ReplicationService) Stop(
+	ctx context.Context,
+) error {
+	if r.cancel == nil {
+		return nil
+	}
+	r.cancel()
+
+	select {
+	case <-r.done:
+	case <-ctx.Done():
+		return fmt.Errorf("Failed to stop the election - %w", ctx.Err())
+	}
+
+	if r.IsLeader() {
+		return r.leader.StepDown(ctx)
+	}
+	return nil
+}
+
+// Release releases the lock, for another replica to take over
+func (r *ReplicationService) Release(
+	ctx context.Context,
+) error {
+	if !r.cfg.Enabled {
+		return nil
+	}
+	return r.lock.Release(ctx)
+}
+
+// leads until the context is done or the lock is lost
+func (r *ReplicationService) lead(
+	ctx context.Context,
+	leader ILeader,
+) {
+	lgr := r.lgr.Get(ctx)
+
+	if r.cfg.Enabled {
+		err := r.repo.SetLeaderAddress(ctx, r.cfg.AdvertiseAddress)
+		if err != nil {
+			lgr.Error("Failed to advertise the leader address", zap.Error(err))
+			r.releaseLock(ctx)
+			return
+		}
+	}
+
+	// the leader ingests until it steps down, even once the election ends
+	r.role.Store(int32(ROLE_LEADER))
+	if err := leader.Lead(context.WithoutCancel(ctx)); err != nil {
+		lgr.Error("Failed to start leading", zap.Error(err))
+		r.role.Store(int32(ROLE_NONE))
+		r.releaseLock(ctx)
+		return
+	}
+
+	lgr.Info("Leading the replicas", zap.String("address", r.cfg.AdvertiseAddress))
+
+	if !r.cfg.Enabled {
+		<-ctx.Done()
+		return
+	}
+
+	ticker := time.NewTicker(r.interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+
+		err := r.lock.Check(ctx)
+		if err == nil || ctx.Err() != nil {
+			continue
+		}
+
+		// another replica may take over from now on
+		lgr.Error("Lost the leader lock, stepping down", zap.Error(err))
+
+		stepDownCtx, cancel := context.WithTimeout(ctx, STEP_DOWN_TIMEOUT)
+		if err := leader.StepDown(stepDownCtx); err != nil {
+			lgr.Error("Failed to step down", zap.Error(err))
+		}
+		r.role.Store(int32(ROLE_NONE))
+		r.releaseLock(stepDownCtx)
+		cancel()
+		return
+	}
+}
+
+// follows the leader until its stream or the context ends
+// the role is kept once the stream ends, unless it failed
+func (r *ReplicationService) follow(
+	ctx context.Context,
+	follower IFollower,
+) {
+	lgr := r.lgr.Get(ctx)
+
+	address, err := r.repo.GetLeaderAddress(ctx)
+	if err != nil {
+		lgr.Error("Failed to get the leader address", zap.Error(err))
+		r.role.Store(int32(ROLE_NONE))
+		return
+	}
+	// the address may be stale until the leader advertises it
+	if address == "" || address == r.cfg.AdvertiseAddress {
+		r.role.Store(int32(ROLE_NONE))
+		return
+	}
+
+	lgr.Info("Following the leader", zap.String("address", address))
+
+	// the replica keeps following between the streams, until one fails
+	r.role.Store(int32(ROLE_FOLLOWER))
+
+	follower.ResetUpdates(ctx)
+	err = r.client.StreamBarUpdates(
+		ctx,
+		address,
+		r.cfg.AdvertiseAddress,
+		func(event *subscription.CandlestickEvent) {
+			follower.ApplyUpdate(ctx, event)
+		},
+	)
+	if err != nil && ctx.Err() == nil {
+		lgr.Warn(
+			"Stopped following the leader",
+			zap.String("address", address),
+			zap.Error(err),
+		)
+		r.role.Store(int32(ROLE_NONE))
+	}
+}
+
+func (r *ReplicationService) releaseLock(
+	ctx context.Context,
+) {
+	if !r.cfg.Enabled {
+		return
+	}
+	if err := r.lock.Release(ctx); err != nil {
+		r.lgr.Get(ctx).Error("Failed to release the leader lock", zap.Error(err))
+	}
+}
@@ -0,0 +1,290 @@
+package replication_test
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/ramasbeinaty/trading-chart-service/internal/testutil"
+	"github.com/ramasbeinaty/trading-chart-service/pkg/domain/replication"
+	"github.com/ramasbeinaty/trading-chart-service/pkg/domain/subscription"
+)
+
+// fakeLock is free unless taken, and lost once checkErr is set
+type fakeLock struct {
+	mutex    sync.Mutex
+	taken    bool
+	held     bool
+	checkErr error
+}
+
+func (l *fakeLock) TryAcquire(context.Context) (bool, error) {
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+
+	if !l.taken {
+		l.held = true
+	}
+	return l.held, nil
+}
+
+func (l *fakeLock) Check(context.Context) error {
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+	return l.checkErr
+}
+
+func (l *fakeLock) Release(context.Context) error {
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+
+	l.held = false
+	l.taken = true
+	return nil
+}
+
+func (l *fakeLock) lose() {
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+	l.checkErr = errors.New("connection lost")
+}
+
+type memoryRepository struct {
+	mutex   sync.Mutex
+	address string
+}
+
+func (r *memoryRepository) SetLeaderAddress(_ context.Context, address string) error {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	r.address = address
+	return nil
+}
+
+func (r *memoryRepository) GetLeaderAddress(context.Context) (string, error) {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	return r.address, nil
+}
+
+// fakeClient sends the events then keeps streaming until the context is done
+type fakeClient struct {
+	events  []*subscription.CandlestickEvent
+	address chan string
+}
+
+func (c *fakeClient) StreamBarUpdates(
+	ctx context.Context,
+	address string,
+	follower string,
+	onUpdate func(event *subscription.CandlestickEvent),
+) error {
+	for _, event := range c.events {
+		onUpdate(event)
+	}
+	c.address <- address
+	<-ctx.Done()
+	return ctx.Err()
+}
+
+type fakeLeader struct {
+	led         chan struct{}
+	steppedDown chan struct{}
+}
+
+func newFakeLeader() *fakeLeader {
+	return &fakeLeader{
+		led:         make(chan struct{}, 1),
+		steppedDown: make(chan struct{}, 1),
+	}
+}
+
+func (l *fakeLeader) Lead(context.Context) error {
+	l.led <- struct{}{}
+	return nil
+}
+
+func (l *fakeLeader) StepDown(context.Context) error {
+	l.steppedDown <- struct{}{}
+	return nil
+}
+
+type fakeFollower struct {
+	mutex   sync.Mutex
+	resets  int
+	applied []*subscription.CandlestickEvent
+}
+
+func (f *fakeFollower) ResetUpdates(context.Context) {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+	f.resets++
+}
+
+func (f *fakeFollower) ApplyUpdate(_ context.Context, event *subscription.CandlestickEvent) {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+	f.applied = append(f.applied, event)
+}
+
+func waitFor(t *testing.T, ch <-chan struct{}, what string) {
+	t.Helper()
+	select {
+	case <-ch:
+	case <-time.After(time.Second):
+		t.Fatalf("timed out waiting for %s", what)
+	}
+}
+
+func newTestService(
+	cfg *replication.ReplicationConfig,
+	lock *fakeLock,
+	repo *memoryRepository,
+	client *fakeClient,
+) *replication.ReplicationService {
+	service := replication.NewReplicationService(cfg, lock, repo, client, testutil.NopLogger{})
+	replication.SetTestInterval(service, 5*time.Millisecond)
+	return service
+}
+
+func TestStartLeadsWithoutReplication(t *testing.T) {
+	service := newTestService(&replication.ReplicationConfig{}, nil, nil, nil)
+	leader := newFakeLeader()
+
+	service.Start(context.Background(), leader, &fakeFollower{})
+	waitFor(t, leader.led, "leading")
+
+	if !service.IsLeader() {
+		t.Fatalf("expected to lead, got %s", service.Role())
+	}
+
+	if err := service.Stop(context.Background()); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	waitFor(t, leader.steppedDown, "stepping down")
+}
+
+func TestStartLeadsWithTheLockAndStepsDownOnceLost(t *testing.T) {
+	cfg := &replication.ReplicationConfig{Enabled: true, AdvertiseAddress: "replica-1:50051"}
+	lock := &fakeLock{}
+	repo := &memoryRepository{}
+	service := newTestService(cfg, lock, repo, nil)
+	leader := newFakeLeader()
+
+	service.Start(context.Background(), leader, &fakeFollower{})
+	defer service.Stop(context.Background())
+	waitFor(t, leader.led, "leading")
+
+	if address, _ := repo.GetLeaderAddress(context.Background()); address != cfg.AdvertiseAddress {
+		t.Fatalf("expected the leader address to be advertised, got %q", address)
+	}
+
+	lock.lose()
+	waitFor(t, leader.steppedDown, "stepping down")
+}
+
+func TestStartFollowsTheLeaderWithoutTheLock(t *testing.T) {
+	cfg := &replication.ReplicationConfig{Enabled: true, AdvertiseAddress: "replica-2:50051"}
+	lock := &fakeLock{taken: true}
+	repo := &memoryRepository{address: "replica-1:50051"}
+	event := &subscription.CandlestickEvent{Symbol: "BTCUSDT", Sequence: 1}
+	client := &fakeClient{
+		events:  []*subscription.CandlestickEvent{event},
+		address: make(chan string, 1),
+	}
+	service := newTestService(cfg, lock, repo, client)
+	follower := &fakeFollower{}
+
+	service.Start(context.Background(), newFakeLeader(), follower)
+	defer service.Stop(context.Background())
+
+	select {
+	case address := <-client.address:
+		if address != "replica-1:50051" {
+			t.Fatalf("expected to follow replica-1, got %s", address)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("timed out waiting for following")
+	}
+
+	if service.Role() != replication.ROLE_FOLLOWER {
+		t.Fatalf("expected to follow, got %s", service.Role())
+	}
+
+	follower.mutex.Lock()
+	defer follower.mutex.Unlock()
+	if follower.resets != 1 || len(follower.applied) != 1 || follower.applied[0] != event {
+		t.Fatalf("expected the updates to be reset then applied, got %d resets and %v", follower.resets, follower.applied)
+	}
+}
+
+// endingClient ends every stream right away, with err
+type endingClient struct {
+	err error
+}
+
+func (c *endingClient) StreamBarUpdates(
+	context.Context,
+	string,
+	string,
+	func(event *subscription.CandlestickEvent),
+) error {
+	return c.err
+}
+
+// gatedRepository blocks the second lookup of the leader address until
+// released, once the first stream ended
+type gatedRepository struct {
+	memoryRepository
+	lookups int
+	looking chan struct{}
+	release chan struct{}
+}
+
+func (r *gatedRepository) GetLeaderAddress(ctx context.Context) (string, error) {
+	r.mutex.Lock()
+	r.lookups++
+	lookups := r.lookups
+	r.mutex.Unlock()
+
+	if lookups == 2 {
+		close(r.looking)
+		<-r.release
+	}
+	return r.memoryRepository.GetLeaderAddress(ctx)
+}
+
+func TestFollowKeepsTheRoleUntilTheStreamFails(t *testing.T) {
+	cases := []struct {
+		name string
+		err  error
+		role replication.Role
+	}{
+		{name: "stream ended", role: replication.ROLE_FOLLOWER},
+		{name: "stream failed", err: errors.New("leader unreachable"), role: replication.ROLE_NONE},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			cfg := &replication.ReplicationConfig{Enabled: true, AdvertiseAddress: "replica-2:50051"}
+			repo := &gatedRepository{
+				memoryRepository: memoryRepository{address: "replica-1:50051"},
+				looking:          make(chan struct{}),
+				release:          make(chan struct{}),
+			}
+			service := replication.NewReplicationService(cfg, &fakeLock{taken: true}, repo, &endingClient{err: c.err}, testutil.NopLogger{})
+			replication.SetTestInterval(service, 5*time.Millisecond)
+
+			service.Start(context.Background(), newFakeLeader(), &fakeFollower{})
+			defer service.Stop(context.Background())
+			waitFor(t, repo.looking, "following again")
+
+			if service.Role() != c.role {
+				t.Errorf("expected the role %s between the streams, got %s", c.role, service.Role())
+			}
+			close(repo.release)
+		})
+	}
+}
@@ -0,0 +1,7 @@
+package subscription
+
+const (
+	// live updates waiting to be written to a subscriber, beyond which the
+	// updates broadcast to it are dropped until it catches up
+	QUEUE_SIZE = 256
+)
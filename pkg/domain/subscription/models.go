@@ -2,13 +2,182 @@ package subscription
 
 import (
 	"context"
+	"errors"
+	"sync"
+	"sync/atomic"
+	"time"
 
-	"github.com/ramasbeinaty/trading-chart-service/proto/candlestick/contracts"
+	"github.com/ramasbeinaty/trading-chart-service/pkg/domain/auth"
+	"github.com/ramasbeinaty/trading-chart-service/pkg/domain/indicator"
 )
 
+var (
+	ERR_SUBSCRIBER_NOT_FOUND = errors.New("subscriber not found")
+	// the subscriber was disconnected by an operator
+	ERR_DISCONNECTED = errors.New("disconnected by an operator")
+	// the subscriber is too slow to receive the updates broadcast to it
+	ERR_QUEUE_FULL = errors.New("subscriber queue is full")
+)
+
+type EventKind int
+
+const (
+	// a live update of the symbol's current bar
+	EVENT_KIND_LIVE EventKind = iota
+	// a bar sent as part of the initial snapshot of a subscription
+	EVENT_KIND_SNAPSHOT
+	// a missed update replayed to a resumed subscription
+	EVENT_KIND_REPLAY
+	// the missed updates are no longer available, history should be refetched
+	EVENT_KIND_GAP
+)
+
+// CandlestickEvent is a bar update published to subscribers
+type CandlestickEvent struct {
+	Symbol         string
+	Open           float64
+	High           float64
+	Low            float64
+	Close          float64
+	TradeTimestamp time.Time
+	Sequence       uint64 // per-symbol sequence of the last update applied to the bar
+	Kind           EventKind
+	// values of the indicators tracked on the symbol, each subscriber
+	// only receives the ones it requested
+	Indicators []indicator.Value
+}
+
+// Sink is the transport a subscriber receives its events on,
+// e.g. a grpc server stream, a websocket or a server-sent events response
+type Sink interface {
+	// Send delivers the event to the subscriber
+	Send(event *CandlestickEvent) error
+	// Close terminates the transport from the server side
+	Close()
+	// Done is closed once the transport is terminated, from either side
+	Done() <-chan struct{}
+}
+
+// GoingAwaySink is implemented by the sinks able to tell the subscriber
+// the server is going away, for it to resubscribe elsewhere
+type GoingAwaySink interface {
+	Sink
+	// GoAway notifies the subscriber and terminates the transport
+	GoAway()
+}
+
+// CancelableSink is implemented by the sinks able to tell the subscriber
+// why the server terminated its transport
+type CancelableSink interface {
+	Sink
+	// Cancel notifies the subscriber of the cause and terminates the transport
+	Cancel(cause error)
+}
+
 type Subscriber struct {
-	ID      int64
-	Symbols map[string]bool
-	Stream  contracts.CandlestickService_SubscribeToCandlesticksServer
-	Cancel  context.CancelFunc // to help terminate the stream
+	ID       int64
+	Symbols  map[string]bool
+	Patterns map[string]*Pattern // keyed by the pattern as subscribed
+	Sink     Sink
+	// terminates the stream, telling the subscriber the cause if its sink
+	// supports it
+	Cancel context.CancelCauseFunc
+	// remote address of the subscriber, empty if unknown
+	Peer        string
+	ConnectedAt time.Time
+	// the principal that subscribed, the only one allowed to change the
+	// subscriber, nil if authentication is disabled
+	Owner *auth.Principal
+	// names of the requested indicators, replaced as a whole since it is read
+	// by broadcasts without holding the mutex
+	Indicators atomic.Pointer[map[string]bool]
+	// events delivered to the subscriber
+	sent atomic.Uint64
+	// live updates waiting to be written by the subscriber's writer, for a
+	// slow subscriber not to hold off the broadcasts
+	queue chan *CandlestickEvent
+	// closed once the subscriber is removed, stopping its writer
+	stopped  chan struct{}
+	stopOnce sync.Once
+	// guards holds and held, never held while sending
+	holdMutex sync.Mutex
+	// catch-ups in progress, the broadcasts are held off until they end
+	holds int
+	// broadcasts held off, sent in order once the catch-ups end
+	held []*CandlestickEvent
+}
+
+// queues the event if the subscriber is catching up, reporting whether it did
+func (s *Subscriber) hold(event *CandlestickEvent) bool {
+	s.holdMutex.Lock()
+	defer s.holdMutex.Unlock()
+
+	if s.holds == 0 {
+		return false
+	}
+	s.held = append(s.held, event)
+	return true
+}
+
+// queues the event for the subscriber's writer, reporting whether it did
+// rather than dropping it as the queue is full
+func (s *Subscriber) enqueue(event *CandlestickEvent) bool {
+	select {
+	case s.queue <- event:
+		return true
+	default:
+		return false
+	}
+}
+
+// stops the subscriber's writer, the events still queued being discarded
+func (s *Subscriber) stop() {
+	s.stopOnce.Do(func() { close(s.stopped) })
+}
+
+// reports whether the owner may receive the symbol
+func (s *Subscriber) entitled(symbol string) bool {
+	return s.Owner == nil || s.Owner.Entitled(symbol)
+}
+
+// SubscriberInfo describes a subscriber, for the operators
+type SubscriberInfo struct {
+	ID          int64
+	Peer        string
+	Owner       *auth.Principal // nil if authentication is disabled
+	Symbols     []string        // symbols and patterns, as subscribed
+	ConnectedAt time.Time
+	// live updates queued for the subscriber, growing with a slow subscriber
+	// up to QUEUE_SIZE, beyond which its updates are dropped
+	QueueDepth int64
+	Sent       uint64 // events delivered to the subscriber
+}
+
+type peerKey struct{}
+
+// WithPeer sets the remote address of the subscriber subscribing with the context
+func WithPeer(
+	ctx context.Context,
+	peer string,
+) context.Context {
+	return context.WithValue(ctx, peerKey{}, peer)
+}
+
+func peerFromContext(
+	ctx context.Context,
+) string {
+	peer, _ := ctx.Value(peerKey{}).(string)
+	return peer
+}
+
+// terminates the sink with the cause if it supports it, closing it otherwise
+func cancelSink(
+	sink Sink,
+) context.CancelCauseFunc {
+	if s, ok := sink.(CancelableSink); ok {
+		return s.Cancel
+	}
+	return func(error) {
+		sink.Close()
+	}
 }
@@ -0,0 +1,57 @@
+package subscription
+
+import (
+	"fmt"
+	"path"
+	"regexp"
+	"strings"
+)
+
+// Pattern matches the tracked symbols a subscriber receives, either
+// - a glob, e.g. "*" for every symbol or "*USDT" for every USDT pair
+// - a regular expression between slashes, e.g. "/^(BTC|ETH)USDT$/"
+type Pattern struct {
+	raw   string
+	match func(symbol string) bool
+}
+
+// IsPattern reports whether the subscribed symbol is a pattern
+// rather than a plain symbol
+func IsPattern(symbol string) bool {
+	return isRegexPattern(symbol) || strings.ContainsAny(symbol, "*?[")
+}
+
+func ParsePattern(raw string) (*Pattern, error) {
+	if isRegexPattern(raw) {
+		re, err := regexp.Compile(raw[1 : len(raw)-1])
+		if err != nil {
+			return nil, fmt.Errorf("Invalid symbol pattern %s - %w", raw, err)
+		}
+		return &Pattern{raw: raw, match: re.MatchString}, nil
+	}
+
+	// symbols are upper case, globs are matched regardless of case
+	glob := strings.ToUpper(raw)
+	if _, err := path.Match(glob, ""); err != nil {
+		return nil, fmt.Errorf("Invalid symbol pattern %s - %w", raw, err)
+	}
+	return &Pattern{
+		raw: raw,
+		match: func(symbol string) bool {
+			ok, _ := path.Match(glob, symbol)
+			return ok
+		},
+	}, nil
+}
+
+func (p *Pattern) String() string {
+	return p.raw
+}
+
+func (p *Pattern) Match(symbol string) bool {
+	return p.match(symbol)
+}
+
+func isRegexPattern(symbol string) bool {
+	return len(symbol) > 2 && strings.HasPrefix(symbol, "/") && strings.HasSuffix(symbol, "/")
+}
@@ -3,35 +3,179 @@ package subscription
 import (
 	"context"
 	"fmt"
+	"sort"
+	"strings"
 	"sync"
+	"sync/atomic"
+	"time"
 
+	"github.com/ramasbeinaty/trading-chart-service/pkg/domain/auth"
 	"github.com/ramasbeinaty/trading-chart-service/pkg/domain/base/logger"
-	"github.com/ramasbeinaty/trading-chart-service/proto/candlestick/contracts"
+	"github.com/ramasbeinaty/trading-chart-service/pkg/domain/base/metrics"
+	"github.com/ramasbeinaty/trading-chart-service/pkg/domain/indicator"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
 	"go.uber.org/zap"
 )
 
+var tracer = otel.Tracer("github.com/ramasbeinaty/trading-chart-service/pkg/domain/subscription")
+
+// symbolIndex maps each symbol to its subscribers
+// it is never modified once published, changes are made on a copy
+type symbolIndex map[string][]*Subscriber
+
 type SubscriptionService struct {
 	lgr         logger.ILogger
-	mutex       sync.Mutex
+	metrics     metrics.IMetrics
+	mutex       sync.Mutex            // serializes changes to the subscribers
 	subscribers map[int64]*Subscriber // Keyed by subscriber ID
+	tracked     map[string]bool       // symbols patterns are matched against
+	index       atomic.Pointer[symbolIndex]
+	// told of the symbols a subscriber no longer receives
+	onUnsubscribe func(subscriberId int64, symbols []string)
 }
 
 func NewSubscriptionService(
 	lgr logger.ILogger,
+	metrics metrics.IMetrics,
 ) *SubscriptionService {
-	return &SubscriptionService{
+	m := &SubscriptionService{
 		lgr:         lgr,
+		metrics:     metrics,
 		mutex:       sync.Mutex{},
 		subscribers: make(map[int64]*Subscriber),
+		tracked:     make(map[string]bool),
+	}
+	m.index.Store(&symbolIndex{})
+	return m
+}
+
+// OnUnsubscribe calls fn with the symbols a subscriber no longer receives,
+// once unsubscribed from them or removed
+// fn is called with the mutex held, and must not call the service back
+func (m *SubscriptionService) OnUnsubscribe(
+	fn func(subscriberId int64, symbols []string),
+) {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	m.onUnsubscribe = fn
+}
+
+// TrackSymbol adds the symbol to the ones patterns are matched against,
+// subscribing the subscribers with a matching pattern to it
+func (m *SubscriptionService) TrackSymbol(
+	ctx context.Context,
+	symbol string,
+) {
+	symbol = strings.ToUpper(symbol)
+
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	if m.tracked[symbol] {
+		return
+	}
+
+	lgr := m.lgr.Get(ctx)
+	lgr.Info("Tracking a new symbol", zap.String("symbol", symbol))
+
+	m.tracked[symbol] = true
+
+	for _, sub := range m.subscribers {
+		if !sub.Symbols[symbol] && sub.entitled(symbol) && matchesAny(sub.Patterns, symbol) {
+			m.updateIndex(sub, []string{symbol}, nil)
+		}
+	}
+}
+
+// ResolveSymbols expands the patterns among the subscribed symbols
+// into the tracked symbols they match, among the ones the principal of the
+// request is entitled to
+// fails if a symbol subscribed to directly isn't entitled
+func (m *SubscriptionService) ResolveSymbols(
+	ctx context.Context,
+	symbols []string,
+) ([]string, error) {
+	symbolSet, patterns, err := parseSymbols(symbols)
+	if err != nil {
+		return nil, err
+	}
+	for symbol := range symbolSet {
+		if err := auth.AuthorizeSymbol(ctx, symbol); err != nil {
+			return nil, err
+		}
+	}
+
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	for symbol := range m.tracked {
+		if auth.EntitledTo(ctx, symbol) && matchesAny(patterns, symbol) {
+			symbolSet[symbol] = true
+		}
 	}
+
+	resolved := make([]string, 0, len(symbolSet))
+	for symbol := range symbolSet {
+		resolved = append(resolved, symbol)
+	}
+	return resolved, nil
 }
 
+// CountSymbols returns the number of symbols the subscriber would receive
+// once subscribed to the symbols as well, patterns counting the tracked
+// symbols they match among the entitled ones
+func (m *SubscriptionService) CountSymbols(
+	ctx context.Context,
+	subscriberId int64,
+	symbols []string,
+) (int, error) {
+	resolved, err := m.ResolveSymbols(ctx, symbols)
+	if err != nil {
+		return 0, err
+	}
+
+	received := make(map[string]bool, len(resolved))
+	for _, s := range resolved {
+		received[s] = true
+	}
+
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	if sub, exists := m.GetSubscriber(subscriberId); exists {
+		for s := range m.resolveSubscriber(sub) {
+			received[s] = true
+		}
+	}
+
+	return len(received), nil
+}
+
+// symbols may contain patterns, see Pattern
+// the subscriber is bound to the principal of the request, which must be
+// entitled to the symbols subscribed to directly, patterns only matching
+// the entitled ones
 func (m *SubscriptionService) AddUpdateSubscriber(
 	ctx context.Context,
 	subscriberId int64,
 	symbols []string,
-	stream contracts.CandlestickService_SubscribeToCandlesticksServer,
+	sink Sink,
 ) error {
+	symbolSet, patterns, err := parseSymbols(symbols)
+	if err != nil {
+		return err
+	}
+	for symbol := range symbolSet {
+		if err := auth.AuthorizeSymbol(ctx, symbol); err != nil {
+			return err
+		}
+	}
+	principal := auth.PrincipalFromContext(ctx)
+
 	m.mutex.Lock()
 	defer m.mutex.Unlock()
 
@@ -40,35 +184,79 @@ func (m *SubscriptionService) AddUpdateSubscriber(
 		"Adding a subscriber",
 		zap.Int64("subscriberId", subscriberId),
 		zap.Strings("symbols", symbols),
-		zap.Any("stream", stream),
 	)
 
 	sub, exists := m.GetSubscriber(subscriberId)
-	if exists {
-		lgr.Info("Updating existing subscriber")
-		for _, s := range symbols {
-			sub.Symbols[s] = true
-		}
-	} else {
+	if !exists {
 		lgr.Info("Creating a new subscriber")
 
-		_symbols := map[string]bool{}
-		for _, s := range symbols {
-			_symbols[s] = true
-		}
-
 		sub = &Subscriber{
-			ID:      subscriberId,
-			Symbols: _symbols,
-			Stream:  stream,
+			ID:          subscriberId,
+			Symbols:     map[string]bool{},
+			Patterns:    map[string]*Pattern{},
+			Sink:        sink,
+			Cancel:      cancelSink(sink),
+			Peer:        peerFromContext(ctx),
+			ConnectedAt: time.Now(),
+			Owner:       principal,
+			queue:       make(chan *CandlestickEvent, QUEUE_SIZE),
+			stopped:     make(chan struct{}),
 		}
 
 		m.subscribers[sub.ID] = sub
+		go m.write(sub)
+	} else {
+		if !sub.Owner.Is(principal) {
+			return notOwnerError(subscriberId)
+		}
+		lgr.Info("Updating existing subscriber")
+	}
+
+	before := m.resolveSubscriber(sub)
+	for s := range symbolSet {
+		sub.Symbols[s] = true
+	}
+	for raw, p := range patterns {
+		sub.Patterns[raw] = p
 	}
+	m.reindexSubscriber(sub, before)
 
 	return nil
 }
 
+// SetSubscriberIndicators adds the indicators to the ones the subscriber
+// receives along with its candlesticks, keyed by their spec name
+func (m *SubscriptionService) SetSubscriberIndicators(
+	ctx context.Context,
+	subscriberId int64,
+	names []string,
+) error {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	sub, exists := m.GetSubscriber(subscriberId)
+	if !exists {
+		return fmt.Errorf("Failed to set indicators - subscriber %d not found", subscriberId)
+	}
+	if !sub.Owner.Is(auth.PrincipalFromContext(ctx)) {
+		return notOwnerError(subscriberId)
+	}
+
+	indicators := map[string]bool{}
+	if current := sub.Indicators.Load(); current != nil {
+		for name := range *current {
+			indicators[name] = true
+		}
+	}
+	for _, name := range names {
+		indicators[name] = true
+	}
+	sub.Indicators.Store(&indicators)
+
+	return nil
+}
+
+// expects the caller to hold the mutex
 func (m *SubscriptionService) GetSubscriber(id int64) (*Subscriber, bool) {
 	sub, exists := m.subscribers[id]
 	return sub, exists
@@ -76,11 +264,34 @@ func (m *SubscriptionService) GetSubscriber(id int64) (*Subscriber, bool) {
 
 // if no symbol is provided, remove the subscriber and disconnect them from stream
 // otherwise, just unsubscribe the subscriber from the symbol broadcast
+// patterns are unsubscribed from as subscribed, e.g. "*USDT"
+// only the principal that subscribed may remove the subscriber
 func (m *SubscriptionService) RemoveSubscriber(
 	ctx context.Context,
 	subscriberId int64,
 	symbols []string,
 ) error {
+	removed, err := m.removeSymbols(ctx, subscriberId, symbols)
+	if err != nil {
+		return err
+	}
+
+	// the stream is terminated without holding the mutex, as it waits on
+	// the event being sent, if any
+	if removed != nil {
+		removed.Sink.Close()
+	}
+
+	return nil
+}
+
+// unsubscribes the subscriber from the symbols, or from every symbol if none
+// is provided, returning it if it was removed for its stream to be terminated
+func (m *SubscriptionService) removeSymbols(
+	ctx context.Context,
+	subscriberId int64,
+	symbols []string,
+) (*Subscriber, error) {
 	m.mutex.Lock()
 	defer m.mutex.Unlock()
 
@@ -89,59 +300,490 @@ func (m *SubscriptionService) RemoveSubscriber(
 	sub, exists := m.GetSubscriber(subscriberId)
 	if !exists {
 		lgr.Warn("subscriber not found, skipping...")
-		return nil
+		return nil, nil
+	}
+	if !sub.Owner.Is(auth.PrincipalFromContext(ctx)) {
+		return nil, notOwnerError(subscriberId)
 	}
 
 	if len(symbols) != 0 {
+		before := m.resolveSubscriber(sub)
 		for _, s := range symbols {
 			delete(sub.Symbols, s)
+			delete(sub.Patterns, s)
 		}
+		m.reindexSubscriber(sub, before)
 
-		// if not subscribed to any symbols, remove the subscriber and terminate stream
-		if len(sub.Symbols) == 0 {
-			delete(m.subscribers, subscriberId)
-			sub.Stream.Context().Done()
+		// still subscribed to other symbols
+		if len(sub.Symbols) != 0 || len(sub.Patterns) != 0 {
+			return nil, nil
 		}
-	} else {
-		delete(m.subscribers, sub.ID)
-		sub.Stream.Context().Done()
 	}
 
-	return nil
+	m.unindexSubscriber(sub)
+	return sub, nil
 }
 
-func (m *SubscriptionService) BroadcastToSubscribers(
+// DisconnectSubscriber removes the subscriber regardless of its owner and
+// terminates its stream, telling it the reason
+func (m *SubscriptionService) DisconnectSubscriber(
 	ctx context.Context,
-	candlestick *contracts.Candlestick,
+	subscriberId int64,
+	reason string,
 ) error {
+	m.mutex.Lock()
+	sub, exists := m.GetSubscriber(subscriberId)
+	if exists {
+		m.unindexSubscriber(sub)
+	}
+	m.mutex.Unlock()
+
+	if !exists {
+		return fmt.Errorf("Failed to disconnect subscriber %d - %w", subscriberId, ERR_SUBSCRIBER_NOT_FOUND)
+	}
+
+	m.lgr.Get(ctx).Info(
+		"Disconnecting a subscriber",
+		zap.Int64("subscriberId", subscriberId),
+		zap.String("reason", reason),
+	)
+
+	cause := ERR_DISCONNECTED
+	if reason != "" {
+		cause = fmt.Errorf("%w - %s", ERR_DISCONNECTED, reason)
+	}
+	// the transport is terminated once removed, without holding the mutex as
+	// it waits on the event being sent, if any
+	// the sink is cancelled first, for closing it to be a no-op
+	sub.Cancel(cause)
+	sub.Sink.Close()
+
+	return nil
+}
+
+// ListSubscribers describes every subscriber, ordered by id
+func (m *SubscriptionService) ListSubscribers(
+	ctx context.Context,
+) []*SubscriberInfo {
 	m.mutex.Lock()
 	defer m.mutex.Unlock()
 
+	infos := make([]*SubscriberInfo, 0, len(m.subscribers))
+	for _, sub := range m.subscribers {
+		symbols := make([]string, 0, len(sub.Symbols)+len(sub.Patterns))
+		for s := range sub.Symbols {
+			symbols = append(symbols, s)
+		}
+		for raw := range sub.Patterns {
+			symbols = append(symbols, raw)
+		}
+		sort.Strings(symbols)
+
+		infos = append(infos, &SubscriberInfo{
+			ID:          sub.ID,
+			Peer:        sub.Peer,
+			Owner:       sub.Owner,
+			Symbols:     symbols,
+			ConnectedAt: sub.ConnectedAt,
+			QueueDepth:  int64(len(sub.queue)),
+			Sent:        sub.sent.Load(),
+		})
+	}
+	sort.Slice(infos, func(i, j int) bool {
+		return infos[i].ID < infos[j].ID
+	})
+
+	return infos
+}
+
+// Shutdown removes every subscriber, telling the ones whose sink supports it
+// that the server is going away
+func (m *SubscriptionService) Shutdown(
+	ctx context.Context,
+) {
+	m.mutex.Lock()
+	removed := make([]*Subscriber, 0, len(m.subscribers))
+	for _, sub := range m.subscribers {
+		m.unindexSubscriber(sub)
+		removed = append(removed, sub)
+	}
+	m.mutex.Unlock()
+
+	lgr := m.lgr.Get(ctx)
+	lgr.Info("Notifying subscribers of the shutdown", zap.Int("subscribers", len(removed)))
+
+	// the streams are terminated without holding the mutex
+	for _, sub := range removed {
+		if sink, ok := sub.Sink.(GoingAwaySink); ok {
+			sink.GoAway()
+		}
+		sub.Sink.Close()
+	}
+}
+
+// Recipients returns the current subscribers of the symbol, for an update
+// to be broadcast later to the ones subscribed by the time it was applied
+func (m *SubscriptionService) Recipients(
+	symbol string,
+) []*Subscriber {
+	return (*m.index.Load())[symbol]
+}
+
+// queues the event for every subscriber of its symbol
+// reads a snapshot of the subscribers, so changes to them don't hold off broadcasts
+// see BroadcastToRecipients
+func (m *SubscriptionService) BroadcastToSubscribers(
+	ctx context.Context,
+	event *CandlestickEvent,
+) error {
+	return m.BroadcastToRecipients(ctx, event, m.Recipients(event.Symbol))
+}
+
+// BroadcastToRecipients queues the event for the given subscribers of its
+// symbol, see Recipients
+// the events are written to each subscriber by its own writer, for a slow
+// subscriber not to hold off the broadcasts. The ones queued for a subscriber
+// falling behind by more than QUEUE_SIZE events are dropped, the later
+// updates of the bar superseding them
+func (m *SubscriptionService) BroadcastToRecipients(
+	ctx context.Context,
+	event *CandlestickEvent,
+	subscribers []*Subscriber,
+) error {
+	// traced as part of the update broadcast, the untraced updates of a bar
+	// not starting traces of their own
+	span := trace.SpanFromContext(ctx)
+	if span.SpanContext().IsValid() {
+		ctx, span = tracer.Start(
+			ctx,
+			"SubscriptionService.BroadcastToSubscribers",
+			trace.WithAttributes(
+				attribute.String("symbol", event.Symbol),
+				attribute.Int("subscribers", len(subscribers)),
+			),
+		)
+		defer span.End()
+	}
+
 	lgr := m.lgr.Get(ctx)
 	lgr.Info(
 		"Attempting to broadcast candlestick",
-		zap.Any("candlestick", candlestick),
+		zap.Any("candlestick", event),
 	)
 
-	for _, sub := range m.subscribers {
-		lgr.Debug("Checking subscriber", zap.Int64("ID", sub.ID), zap.Any("Symbols", sub.Symbols))
+	dropped := 0
+	for _, sub := range subscribers {
+		update := forSubscriber(event, sub)
+
+		// the subscriber catching up receives the event once it caught up
+		if sub.hold(update) {
+			continue
+		}
+
+		if !sub.enqueue(update) {
+			m.metrics.MessageDropped(event.Symbol)
+			lgr.Warn(
+				"Dropped candlestick for a subscriber falling behind",
+				zap.Any("candlestick", event),
+				zap.Int64("subscriberId", sub.ID),
+			)
+			dropped++
+		}
+	}
 
-		if _, ok := sub.Symbols[candlestick.Symbol]; ok {
-			lgr.Info("Found symbol in subscriber; sending", zap.String("Symbol", candlestick.Symbol), zap.Int64("SubscriberID", sub.ID))
+	if dropped != 0 {
+		span.SetStatus(codes.Error, "Dropped candlestick for some subscribers")
+		span.SetAttributes(attribute.Int("dropped", dropped))
+		return fmt.Errorf("Failed to queue candlestick for %d subscribers - %w", dropped, ERR_QUEUE_FULL)
+	}
+
+	return nil
+}
 
-			if err := sub.Stream.Send(candlestick); err != nil {
-				lgr.Error(
+// writes the events queued for the subscriber to its sink, until it is
+// removed or its transport terminated
+// the subscriber is removed once it fails to receive one
+func (m *SubscriptionService) write(sub *Subscriber) {
+	for {
+		select {
+		case <-sub.stopped:
+			return
+		case <-sub.Sink.Done():
+			return
+		case event := <-sub.queue:
+			if err := sub.Sink.Send(event); err != nil {
+				m.metrics.MessageDropped(event.Symbol)
+				m.lgr.Get(context.Background()).Error(
 					"Failed to send candlestick to subscriber. Connection might've broke",
-					zap.Any("candlestick", candlestick),
+					zap.Any("candlestick", event),
 					zap.Int64("subscriberId", sub.ID),
+					zap.Error(err),
 				)
-				delete(m.subscribers, sub.ID)
-				return fmt.Errorf("Failed to send candlestick to subscriber - %w", err)
+				m.dropSubscriber(sub)
+				return
 			}
-		} else {
-			lgr.Info("Symbol not found for subscriber", zap.String("Symbol", candlestick.Symbol), zap.Int64("SubscriberID", sub.ID))
+			sub.sent.Add(1)
+			m.metrics.MessageSent(event.Symbol)
 		}
 	}
+}
+
+// HoldSubscriber holds off the broadcasts to the subscriber while it is
+// sent the events it missed, for the live updates to follow them in order
+// the broadcasts held off are sent by ReleaseSubscriber
+func (m *SubscriptionService) HoldSubscriber(
+	subscriberId int64,
+) error {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	sub, exists := m.GetSubscriber(subscriberId)
+	if !exists {
+		return fmt.Errorf("Failed to hold subscriber %d - %w", subscriberId, ERR_SUBSCRIBER_NOT_FOUND)
+	}
 
+	sub.holdMutex.Lock()
+	defer sub.holdMutex.Unlock()
+
+	sub.holds++
 	return nil
 }
+
+// ReleaseSubscriber queues the broadcasts held off since HoldSubscriber,
+// then lets the next ones through
+// they are queued ahead of the next ones without being dropped, waiting for
+// the subscriber's writer if need be
+func (m *SubscriptionService) ReleaseSubscriber(
+	ctx context.Context,
+	subscriberId int64,
+) error {
+	m.mutex.Lock()
+	sub, exists := m.GetSubscriber(subscriberId)
+	m.mutex.Unlock()
+	if !exists {
+		return fmt.Errorf("Failed to release subscriber %d - %w", subscriberId, ERR_SUBSCRIBER_NOT_FOUND)
+	}
+
+	for {
+		// broadcasts keep being held off until there are none left to send
+		sub.holdMutex.Lock()
+		held := sub.held
+		sub.held = nil
+		if len(held) == 0 {
+			sub.holds--
+		}
+		sub.holdMutex.Unlock()
+
+		if len(held) == 0 {
+			return nil
+		}
+
+		for _, event := range held {
+			select {
+			case sub.queue <- event:
+			case <-sub.stopped:
+				return fmt.Errorf("Failed to release subscriber %d - %w", subscriberId, ERR_SUBSCRIBER_NOT_FOUND)
+			case <-sub.Sink.Done():
+				return fmt.Errorf("Failed to release subscriber %d - stream is closed", subscriberId)
+			case <-ctx.Done():
+				return fmt.Errorf("Failed to release subscriber %d - %w", subscriberId, ctx.Err())
+			}
+		}
+	}
+}
+
+// returns the event with only the indicators the subscriber requested
+// the event is shared as is when there is nothing to filter out
+func forSubscriber(
+	event *CandlestickEvent,
+	sub *Subscriber,
+) *CandlestickEvent {
+	if len(event.Indicators) == 0 {
+		return event
+	}
+
+	requested := sub.Indicators.Load()
+	if requested == nil {
+		filtered := *event
+		filtered.Indicators = nil
+		return &filtered
+	}
+
+	indicators := make([]indicator.Value, 0, len(*requested))
+	for _, value := range event.Indicators {
+		if (*requested)[value.Name] {
+			indicators = append(indicators, value)
+		}
+	}
+	if len(indicators) == len(event.Indicators) {
+		return event
+	}
+
+	filtered := *event
+	filtered.Indicators = indicators
+	return &filtered
+}
+
+// removes the subscriber failing to receive its events and terminates its
+// stream, unless it was replaced or removed meanwhile
+func (m *SubscriptionService) dropSubscriber(sub *Subscriber) {
+	m.mutex.Lock()
+	current, exists := m.subscribers[sub.ID]
+	removed := exists && current == sub
+	if removed {
+		m.unindexSubscriber(sub)
+	}
+	m.mutex.Unlock()
+
+	if removed {
+		sub.Sink.Close()
+	}
+}
+
+// removes the subscriber from every symbol, leaving its stream open
+// expects the caller to hold the mutex
+func (m *SubscriptionService) unindexSubscriber(sub *Subscriber) {
+	removed := make([]string, 0, len(sub.Symbols))
+	for s := range m.resolveSubscriber(sub) {
+		removed = append(removed, s)
+	}
+
+	m.updateIndex(sub, nil, removed)
+	delete(m.subscribers, sub.ID)
+	sub.stop()
+}
+
+// returns the symbols the subscriber receives, subscribed to directly
+// or through a pattern matching a tracked symbol
+// expects the caller to hold the mutex
+func (m *SubscriptionService) resolveSubscriber(sub *Subscriber) map[string]bool {
+	resolved := make(map[string]bool, len(sub.Symbols))
+	for s := range sub.Symbols {
+		resolved[s] = true
+	}
+
+	if len(sub.Patterns) != 0 {
+		for s := range m.tracked {
+			if sub.entitled(s) && matchesAny(sub.Patterns, s) {
+				resolved[s] = true
+			}
+		}
+	}
+
+	return resolved
+}
+
+// updates the index with the symbols the subscriber gained or lost
+// since it received the given ones
+// expects the caller to hold the mutex
+func (m *SubscriptionService) reindexSubscriber(
+	sub *Subscriber,
+	before map[string]bool,
+) {
+	after := m.resolveSubscriber(sub)
+
+	var added, removed []string
+	for s := range after {
+		if !before[s] {
+			added = append(added, s)
+		}
+	}
+	for s := range before {
+		if !after[s] {
+			removed = append(removed, s)
+		}
+	}
+
+	m.updateIndex(sub, added, removed)
+}
+
+// publishes a copy of the index with the subscriber added to and removed from
+// the given symbols, only copying the subscriber lists of those symbols
+// expects the caller to hold the mutex
+func (m *SubscriptionService) updateIndex(
+	sub *Subscriber,
+	added []string,
+	removed []string,
+) {
+	if len(added) == 0 && len(removed) == 0 {
+		return
+	}
+
+	current := *m.index.Load()
+	next := make(symbolIndex, len(current)+len(added))
+	for symbol, subs := range current {
+		next[symbol] = subs
+	}
+
+	for _, symbol := range added {
+		subs := make([]*Subscriber, 0, len(next[symbol])+1)
+		subs = append(subs, next[symbol]...)
+		next[symbol] = append(subs, sub)
+	}
+
+	for _, symbol := range removed {
+		subs := make([]*Subscriber, 0, len(next[symbol]))
+		for _, s := range next[symbol] {
+			if s != sub {
+				subs = append(subs, s)
+			}
+		}
+
+		if len(subs) == 0 {
+			delete(next, symbol)
+		} else {
+			next[symbol] = subs
+		}
+	}
+
+	m.index.Store(&next)
+
+	for _, symbol := range added {
+		m.metrics.SetSubscribers(symbol, len(next[symbol]))
+	}
+	for _, symbol := range removed {
+		m.metrics.SetSubscribers(symbol, len(next[symbol]))
+	}
+
+	if len(removed) != 0 && m.onUnsubscribe != nil {
+		m.onUnsubscribe(sub.ID, removed)
+	}
+}
+
+// splits the subscribed symbols into plain symbols and patterns
+func parseSymbols(
+	symbols []string,
+) (map[string]bool, map[string]*Pattern, error) {
+	symbolSet := map[string]bool{}
+	patterns := map[string]*Pattern{}
+
+	for _, s := range symbols {
+		if !IsPattern(s) {
+			symbolSet[s] = true
+			continue
+		}
+
+		p, err := ParsePattern(s)
+		if err != nil {
+			return nil, nil, err
+		}
+		patterns[s] = p
+	}
+
+	return symbolSet, patterns, nil
+}
+
+func notOwnerError(
+	subscriberId int64,
+) error {
+	return fmt.Errorf("%w - subscriber %d belongs to another client", auth.ERR_PERMISSION_DENIED, subscriberId)
+}
+
+func matchesAny(patterns map[string]*Pattern, symbol string) bool {
+	for _, p := range patterns {
+		if p.Match(symbol) {
+			return true
+		}
+	}
+	return false
+}
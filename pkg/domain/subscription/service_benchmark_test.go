@@ -0,0 +1,159 @@
+package subscription
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/ramasbeinaty/trading-chart-service/internal/testutil"
+	"github.com/ramasbeinaty/trading-chart-service/pkg/domain/base/metrics"
+)
+
+// discardSink accepts every event without doing anything
+type discardSink struct {
+	done chan struct{}
+}
+
+func (s *discardSink) Send(*CandlestickEvent) error { return nil }
+func (s *discardSink) Close()                       {}
+func (s *discardSink) Done() <-chan struct{}        { return s.done }
+
+// slowBenchmarkSink takes its time to accept every event, as a client on a
+// congested connection
+type slowBenchmarkSink struct {
+	discardSink
+}
+
+func (s *slowBenchmarkSink) Send(*CandlestickEvent) error {
+	time.Sleep(10 * time.Millisecond)
+	return nil
+}
+
+// subscribes the given number of subscribers, spread evenly over the symbols
+func newBenchmarkService(subscribers int, symbols int) *SubscriptionService {
+	return newBenchmarkServiceWithSlowSubscribers(subscribers, symbols, 0)
+}
+
+// subscribes the given number of subscribers, spread evenly over the symbols,
+// every slowEvery-th one being slow, none if 0
+func newBenchmarkServiceWithSlowSubscribers(subscribers int, symbols int, slowEvery int) *SubscriptionService {
+	ctx := context.Background()
+	service := NewSubscriptionService(testutil.NopLogger{}, metrics.NopMetrics{})
+
+	for i := 0; i < subscribers; i++ {
+		var sink Sink = &discardSink{done: make(chan struct{})}
+		if slowEvery != 0 && i%slowEvery == 0 {
+			sink = &slowBenchmarkSink{discardSink{done: make(chan struct{})}}
+		}
+		service.AddUpdateSubscriber(
+			ctx,
+			int64(i+1),
+			[]string{benchmarkSymbol(i % symbols)},
+			sink,
+		)
+	}
+
+	return service
+}
+
+func benchmarkSymbol(i int) string {
+	return fmt.Sprintf("SYM%dUSDT", i)
+}
+
+func BenchmarkBroadcastToSubscribers(b *testing.B) {
+	const subscribers = 10_000
+
+	for _, symbols := range []int{1, 10, 100, 1000} {
+		b.Run(fmt.Sprintf("subscribers=%d/symbols=%d", subscribers, symbols), func(b *testing.B) {
+			service := newBenchmarkService(subscribers, symbols)
+			ctx := context.Background()
+			event := &CandlestickEvent{
+				Symbol:         benchmarkSymbol(0),
+				Close:          100,
+				TradeTimestamp: time.Now(),
+			}
+
+			b.ReportAllocs()
+			b.ResetTimer()
+
+			for i := 0; i < b.N; i++ {
+				event.Sequence = uint64(i)
+				service.BroadcastToSubscribers(ctx, event)
+			}
+
+			b.ReportMetric(float64(b.N)/b.Elapsed().Seconds(), "broadcasts/s")
+			b.ReportMetric(
+				float64(b.N)*float64(subscribers/symbols)/b.Elapsed().Seconds(),
+				"sends/s",
+			)
+		})
+	}
+}
+
+// broadcasts while subscribers keep joining and leaving
+func BenchmarkBroadcastToSubscribersWithChurn(b *testing.B) {
+	const (
+		subscribers = 10_000
+		symbols     = 100
+	)
+
+	service := newBenchmarkService(subscribers, symbols)
+	ctx := context.Background()
+
+	stop := make(chan struct{})
+	defer close(stop)
+	go func() {
+		for id := int64(subscribers + 1); ; id++ {
+			select {
+			case <-stop:
+				return
+			default:
+			}
+			service.AddUpdateSubscriber(
+				ctx,
+				id,
+				[]string{benchmarkSymbol(int(id) % symbols)},
+				&discardSink{done: make(chan struct{})},
+			)
+			service.RemoveSubscriber(ctx, id, nil)
+		}
+	}()
+
+	event := &CandlestickEvent{Symbol: benchmarkSymbol(0), Close: 100}
+
+	b.ReportAllocs()
+	b.ResetTimer()
+
+	for i := 0; i < b.N; i++ {
+		service.BroadcastToSubscribers(ctx, event)
+	}
+
+	b.ReportMetric(float64(b.N)/b.Elapsed().Seconds(), "broadcasts/s")
+}
+
+// broadcasts while some subscribers are too slow to keep up, their updates
+// being queued or dropped rather than holding off the broadcasts
+func BenchmarkBroadcastToSubscribersWithSlowSubscribers(b *testing.B) {
+	const (
+		subscribers = 10_000
+		symbols     = 100
+	)
+
+	for _, slowEvery := range []int{100, 10} {
+		b.Run(fmt.Sprintf("slow=1/%d", slowEvery), func(b *testing.B) {
+			service := newBenchmarkServiceWithSlowSubscribers(subscribers, symbols, slowEvery)
+			ctx := context.Background()
+			event := &CandlestickEvent{Symbol: benchmarkSymbol(0), Close: 100}
+
+			b.ReportAllocs()
+			b.ResetTimer()
+
+			for i := 0; i < b.N; i++ {
+				service.BroadcastToSubscribers(ctx, event)
+			}
+
+			b.ReportMetric(float64(b.N)/b.Elapsed().Seconds(), "broadcasts/s")
+		})
+	}
+}
@@ -0,0 +1,663 @@
+package subscription
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/ramasbeinaty/trading-chart-service/internal/testutil"
+	"github.com/ramasbeinaty/trading-chart-service/pkg/domain/auth"
+	"github.com/ramasbeinaty/trading-chart-service/pkg/domain/base/metrics"
+)
+
+// fakeSink records the events sent to it, failing every send if sendErr is set
+type fakeSink struct {
+	mutex   sync.Mutex
+	events  []*CandlestickEvent
+	sendErr error
+	done    chan struct{}
+	once    sync.Once
+}
+
+func newFakeSink() *fakeSink {
+	return &fakeSink{done: make(chan struct{})}
+}
+
+func (s *fakeSink) Send(event *CandlestickEvent) error {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	if s.sendErr != nil {
+		return s.sendErr
+	}
+	s.events = append(s.events, event)
+	return nil
+}
+
+func (s *fakeSink) Close() {
+	s.once.Do(func() { close(s.done) })
+}
+
+func (s *fakeSink) Done() <-chan struct{} {
+	return s.done
+}
+
+func (s *fakeSink) received() []*CandlestickEvent {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	return append([]*CandlestickEvent{}, s.events...)
+}
+
+// waits for the writer to send the sink at least n events, returning them
+func (s *fakeSink) waitFor(t *testing.T, n int) []*CandlestickEvent {
+	t.Helper()
+	eventually(t, func() bool { return len(s.received()) >= n })
+	return s.received()
+}
+
+// waits for the condition to hold, the events being written asynchronously
+func eventually(t *testing.T, condition func() bool) {
+	t.Helper()
+	deadline := time.Now().Add(time.Second)
+	for !condition() {
+		if time.Now().After(deadline) {
+			t.Fatal("timed out waiting for the condition")
+		}
+		time.Sleep(time.Millisecond)
+	}
+}
+
+func (s *fakeSink) isClosed() bool {
+	select {
+	case <-s.done:
+		return true
+	default:
+		return false
+	}
+}
+
+func TestBroadcastToSubscribersSendsToSubscribersOfSymbol(t *testing.T) {
+	ctx := context.Background()
+	service := NewSubscriptionService(testutil.NopLogger{}, metrics.NopMetrics{})
+
+	btc, eth := newFakeSink(), newFakeSink()
+	service.AddUpdateSubscriber(ctx, 1, []string{"BTCUSDT"}, btc)
+	service.AddUpdateSubscriber(ctx, 2, []string{"ETHUSDT"}, eth)
+
+	event := &CandlestickEvent{Symbol: "BTCUSDT", Close: 100, Sequence: 1}
+	if err := service.BroadcastToSubscribers(ctx, event); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if got := btc.waitFor(t, 1); len(got) != 1 || got[0] != event {
+		t.Errorf("expected BTCUSDT subscriber to receive the event, got %v", got)
+	}
+	if got := eth.received(); len(got) != 0 {
+		t.Errorf("expected ETHUSDT subscriber to receive nothing, got %v", got)
+	}
+}
+
+func TestAddUpdateSubscriberAddsSymbolsToExistingSubscriber(t *testing.T) {
+	ctx := context.Background()
+	service := NewSubscriptionService(testutil.NopLogger{}, metrics.NopMetrics{})
+
+	sink := newFakeSink()
+	service.AddUpdateSubscriber(ctx, 1, []string{"BTCUSDT"}, sink)
+	service.AddUpdateSubscriber(ctx, 1, []string{"ETHUSDT"}, sink)
+
+	service.BroadcastToSubscribers(ctx, &CandlestickEvent{Symbol: "BTCUSDT"})
+	service.BroadcastToSubscribers(ctx, &CandlestickEvent{Symbol: "ETHUSDT"})
+
+	if got := sink.waitFor(t, 2); len(got) != 2 {
+		t.Errorf("expected 2 events, got %d", len(got))
+	}
+}
+
+func TestBroadcastToSubscribersRemovesFailingSubscriber(t *testing.T) {
+	ctx := context.Background()
+	service := NewSubscriptionService(testutil.NopLogger{}, metrics.NopMetrics{})
+
+	broken, healthy := newFakeSink(), newFakeSink()
+	broken.sendErr = errors.New("connection reset")
+	service.AddUpdateSubscriber(ctx, 1, []string{"BTCUSDT"}, broken)
+	service.AddUpdateSubscriber(ctx, 2, []string{"BTCUSDT"}, healthy)
+
+	if err := service.BroadcastToSubscribers(ctx, &CandlestickEvent{Symbol: "BTCUSDT"}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	eventually(t, broken.isClosed)
+	service.mutex.Lock()
+	_, exists := service.GetSubscriber(1)
+	service.mutex.Unlock()
+	if exists {
+		t.Error("expected failing subscriber to be removed")
+	}
+	if got := healthy.waitFor(t, 1); len(got) != 1 {
+		t.Errorf("expected healthy subscriber to still receive the event, got %d events", len(got))
+	}
+}
+
+func TestRemoveSubscriberFromSomeSymbolsKeepsSinkOpen(t *testing.T) {
+	ctx := context.Background()
+	service := NewSubscriptionService(testutil.NopLogger{}, metrics.NopMetrics{})
+
+	sink := newFakeSink()
+	service.AddUpdateSubscriber(ctx, 1, []string{"BTCUSDT", "ETHUSDT"}, sink)
+
+	service.RemoveSubscriber(ctx, 1, []string{"ETHUSDT"})
+
+	if sink.isClosed() {
+		t.Error("expected sink to stay open while subscribed to other symbols")
+	}
+
+	service.BroadcastToSubscribers(ctx, &CandlestickEvent{Symbol: "ETHUSDT"})
+	service.BroadcastToSubscribers(ctx, &CandlestickEvent{Symbol: "BTCUSDT"})
+
+	if got := sink.waitFor(t, 1); len(got) != 1 || got[0].Symbol != "BTCUSDT" {
+		t.Errorf("expected only the BTCUSDT event, got %v", got)
+	}
+}
+
+func TestRemoveSubscriberClosesSink(t *testing.T) {
+	tests := []struct {
+		name    string
+		symbols []string
+	}{
+		{"all symbols", nil},
+		{"last symbol", []string{"BTCUSDT"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := context.Background()
+			service := NewSubscriptionService(testutil.NopLogger{}, metrics.NopMetrics{})
+
+			sink := newFakeSink()
+			service.AddUpdateSubscriber(ctx, 1, []string{"BTCUSDT"}, sink)
+
+			service.RemoveSubscriber(ctx, 1, tt.symbols)
+
+			if _, exists := service.GetSubscriber(1); exists {
+				t.Error("expected subscriber to be removed")
+			}
+			if !sink.isClosed() {
+				t.Error("expected sink to be closed")
+			}
+		})
+	}
+}
+
+// goingAwaySink records whether the subscriber was told the server is going away
+type goingAwaySink struct {
+	*fakeSink
+	wentAway bool
+}
+
+func (s *goingAwaySink) GoAway() {
+	s.wentAway = true
+	s.Close()
+}
+
+func TestShutdownTellsSubscribersAndRemovesThem(t *testing.T) {
+	ctx := context.Background()
+	service := NewSubscriptionService(testutil.NopLogger{}, metrics.NopMetrics{})
+
+	plain := newFakeSink()
+	goingAway := &goingAwaySink{fakeSink: newFakeSink()}
+	service.AddUpdateSubscriber(ctx, 1, []string{"BTCUSDT"}, plain)
+	service.AddUpdateSubscriber(ctx, 2, []string{"BTCUSDT"}, goingAway)
+
+	service.Shutdown(ctx)
+
+	if !goingAway.wentAway {
+		t.Error("expected the going away sink to be told")
+	}
+	if !plain.isClosed() || !goingAway.isClosed() {
+		t.Error("expected every sink to be closed")
+	}
+	if _, exists := service.GetSubscriber(1); exists {
+		t.Error("expected subscribers to be removed")
+	}
+}
+
+func TestPatternSubscriptionReceivesMatchingTrackedSymbols(t *testing.T) {
+	ctx := context.Background()
+	service := NewSubscriptionService(testutil.NopLogger{}, metrics.NopMetrics{})
+	service.TrackSymbol(ctx, "btcusdt")
+	service.TrackSymbol(ctx, "ETHBTC")
+
+	usdt, all := newFakeSink(), newFakeSink()
+	service.AddUpdateSubscriber(ctx, 1, []string{"*USDT"}, usdt)
+	service.AddUpdateSubscriber(ctx, 2, []string{"*"}, all)
+
+	service.BroadcastToSubscribers(ctx, &CandlestickEvent{Symbol: "ETHBTC"})
+	service.BroadcastToSubscribers(ctx, &CandlestickEvent{Symbol: "BTCUSDT"})
+
+	if got := usdt.waitFor(t, 1); len(got) != 1 || got[0].Symbol != "BTCUSDT" {
+		t.Errorf("expected only the BTCUSDT event, got %v", got)
+	}
+	if got := all.waitFor(t, 2); len(got) != 2 {
+		t.Errorf("expected every event, got %d", len(got))
+	}
+}
+
+func TestPatternSubscriptionReceivesSymbolsTrackedLater(t *testing.T) {
+	ctx := context.Background()
+	service := NewSubscriptionService(testutil.NopLogger{}, metrics.NopMetrics{})
+
+	sink := newFakeSink()
+	service.AddUpdateSubscriber(ctx, 1, []string{"/^(BTC|ETH)USDT$/"}, sink)
+
+	service.TrackSymbol(ctx, "ETHUSDT")
+	service.TrackSymbol(ctx, "PEPEUSDT")
+
+	service.BroadcastToSubscribers(ctx, &CandlestickEvent{Symbol: "PEPEUSDT"})
+	service.BroadcastToSubscribers(ctx, &CandlestickEvent{Symbol: "ETHUSDT"})
+
+	if got := sink.waitFor(t, 1); len(got) != 1 || got[0].Symbol != "ETHUSDT" {
+		t.Errorf("expected only the ETHUSDT event, got %v", got)
+	}
+}
+
+func TestRemovingPatternKeepsDirectSubscriptions(t *testing.T) {
+	ctx := context.Background()
+	service := NewSubscriptionService(testutil.NopLogger{}, metrics.NopMetrics{})
+	service.TrackSymbol(ctx, "BTCUSDT")
+	service.TrackSymbol(ctx, "ETHUSDT")
+
+	sink := newFakeSink()
+	service.AddUpdateSubscriber(ctx, 1, []string{"*USDT", "BTCUSDT"}, sink)
+	service.RemoveSubscriber(ctx, 1, []string{"*USDT"})
+
+	service.BroadcastToSubscribers(ctx, &CandlestickEvent{Symbol: "ETHUSDT"})
+	service.BroadcastToSubscribers(ctx, &CandlestickEvent{Symbol: "BTCUSDT"})
+
+	if got := sink.waitFor(t, 1); len(got) != 1 || got[0].Symbol != "BTCUSDT" {
+		t.Errorf("expected only the BTCUSDT event, got %v", got)
+	}
+	if sink.isClosed() {
+		t.Error("expected sink to stay open while subscribed to BTCUSDT")
+	}
+}
+
+func TestAddUpdateSubscriberRejectsInvalidPattern(t *testing.T) {
+	service := NewSubscriptionService(testutil.NopLogger{}, metrics.NopMetrics{})
+
+	err := service.AddUpdateSubscriber(context.Background(), 1, []string{"/(/"}, newFakeSink())
+	if err == nil {
+		t.Error("expected an error for an invalid regular expression")
+	}
+}
+
+// cancelableSink records the cause the subscriber was cancelled with
+type cancelableSink struct {
+	*fakeSink
+	cause error
+}
+
+func (s *cancelableSink) Cancel(cause error) {
+	s.cause = cause
+	s.Close()
+}
+
+func TestDisconnectSubscriberTellsTheCauseAndRemovesIt(t *testing.T) {
+	service := NewSubscriptionService(testutil.NopLogger{}, metrics.NopMetrics{})
+	alice := auth.WithPrincipal(context.Background(), &auth.Principal{ID: "alice", Method: auth.METHOD_API_KEY, Symbols: []string{"*"}})
+
+	plain := newFakeSink()
+	cancelable := &cancelableSink{fakeSink: newFakeSink()}
+	service.AddUpdateSubscriber(alice, 1, []string{"BTCUSDT"}, plain)
+	service.AddUpdateSubscriber(alice, 2, []string{"BTCUSDT"}, cancelable)
+
+	// an operator disconnects subscribers regardless of their owner
+	ctx := context.Background()
+	if err := service.DisconnectSubscriber(ctx, 1, ""); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if err := service.DisconnectSubscriber(ctx, 2, "too slow"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if !plain.isClosed() || !cancelable.isClosed() {
+		t.Error("expected every sink to be terminated")
+	}
+	if !errors.Is(cancelable.cause, ERR_DISCONNECTED) {
+		t.Errorf("expected the sink to be told it was disconnected, got %v", cancelable.cause)
+	}
+	if len(service.ListSubscribers(ctx)) != 0 {
+		t.Error("expected the subscribers to be removed")
+	}
+
+	err := service.DisconnectSubscriber(ctx, 3, "")
+	if !errors.Is(err, ERR_SUBSCRIBER_NOT_FOUND) {
+		t.Errorf("expected an unknown subscriber to be reported, got %v", err)
+	}
+}
+
+// blockingCancelSink blocks being cancelled until released, as a transport
+// busy sending does
+type blockingCancelSink struct {
+	*fakeSink
+	cancelling chan struct{}
+	release    chan struct{}
+}
+
+func (s *blockingCancelSink) Cancel(error) {
+	close(s.cancelling)
+	<-s.release
+	s.Close()
+}
+
+func TestDisconnectSubscriberTerminatesTheStreamWithoutHoldingTheMutex(t *testing.T) {
+	service := NewSubscriptionService(testutil.NopLogger{}, metrics.NopMetrics{})
+	ctx := context.Background()
+
+	sink := &blockingCancelSink{fakeSink: newFakeSink(), cancelling: make(chan struct{}), release: make(chan struct{})}
+	service.AddUpdateSubscriber(ctx, 1, []string{"BTCUSDT"}, sink)
+
+	disconnected := make(chan error)
+	go func() { disconnected <- service.DisconnectSubscriber(ctx, 1, "") }()
+	<-sink.cancelling
+
+	listed := make(chan []*SubscriberInfo)
+	go func() { listed <- service.ListSubscribers(ctx) }()
+	select {
+	case infos := <-listed:
+		if len(infos) != 0 {
+			t.Errorf("expected the subscriber to be removed before its stream is terminated, got %d", len(infos))
+		}
+	case <-time.After(time.Second):
+		t.Fatal("expected the subscribers not to wait on the stream being terminated")
+	}
+
+	close(sink.release)
+	if err := <-disconnected; err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !sink.isClosed() {
+		t.Error("expected the sink to be terminated")
+	}
+}
+
+// blockingCloseSink blocks being closed until released, as a transport busy
+// sending does
+type blockingCloseSink struct {
+	*fakeSink
+	closing chan struct{}
+	release chan struct{}
+}
+
+func (s *blockingCloseSink) Close() {
+	close(s.closing)
+	<-s.release
+	s.fakeSink.Close()
+}
+
+func TestRemovingSubscribersTerminatesTheStreamsWithoutHoldingTheMutex(t *testing.T) {
+	ctx := context.Background()
+	remove := map[string]func(*SubscriptionService){
+		"remove":   func(service *SubscriptionService) { service.RemoveSubscriber(ctx, 1, nil) },
+		"symbols":  func(service *SubscriptionService) { service.RemoveSubscriber(ctx, 1, []string{"BTCUSDT"}) },
+		"shutdown": func(service *SubscriptionService) { service.Shutdown(ctx) },
+	}
+	for name, remove := range remove {
+		t.Run(name, func(t *testing.T) {
+			service := NewSubscriptionService(testutil.NopLogger{}, metrics.NopMetrics{})
+			sink := &blockingCloseSink{fakeSink: newFakeSink(), closing: make(chan struct{}), release: make(chan struct{})}
+			service.AddUpdateSubscriber(ctx, 1, []string{"BTCUSDT"}, sink)
+
+			removed := make(chan struct{})
+			go func() {
+				remove(service)
+				close(removed)
+			}()
+			<-sink.closing
+
+			listed := make(chan []*SubscriberInfo)
+			go func() { listed <- service.ListSubscribers(ctx) }()
+			select {
+			case infos := <-listed:
+				if len(infos) != 0 {
+					t.Errorf("expected the subscriber to be removed before its stream is terminated, got %d", len(infos))
+				}
+			case <-time.After(time.Second):
+				t.Fatal("expected the subscribers not to wait on the stream being terminated")
+			}
+
+			close(sink.release)
+			<-removed
+		})
+	}
+}
+
+func TestListSubscribersDescribesTheSubscribers(t *testing.T) {
+	principal := &auth.Principal{ID: "alice", Method: auth.METHOD_API_KEY, Symbols: []string{"*"}}
+	ctx := WithPeer(auth.WithPrincipal(context.Background(), principal), "10.0.0.1:4242")
+	service := NewSubscriptionService(testutil.NopLogger{}, metrics.NopMetrics{})
+
+	sink := newFakeSink()
+	service.AddUpdateSubscriber(ctx, 1, []string{"ETHUSDT", "BTC*"}, sink)
+	service.BroadcastToSubscribers(ctx, &CandlestickEvent{Symbol: "ETHUSDT"})
+	service.BroadcastToSubscribers(ctx, &CandlestickEvent{Symbol: "ETHUSDT"})
+	sink.waitFor(t, 2)
+
+	infos := service.ListSubscribers(ctx)
+	if len(infos) != 1 {
+		t.Fatalf("expected 1 subscriber, got %d", len(infos))
+	}
+	info := infos[0]
+	if info.Peer != "10.0.0.1:4242" || !info.Owner.Is(principal) {
+		t.Errorf("expected the peer and owner of the subscriber, got %s and %v", info.Peer, info.Owner)
+	}
+	if len(info.Symbols) != 2 || info.Symbols[0] != "BTC*" || info.Symbols[1] != "ETHUSDT" {
+		t.Errorf("expected the symbols as subscribed, got %v", info.Symbols)
+	}
+	if info.ConnectedAt.IsZero() {
+		t.Error("expected the connect time")
+	}
+	if info.Sent != 2 {
+		t.Errorf("expected 2 sent, got %d", info.Sent)
+	}
+}
+
+func TestOnlyTheOwnerCanChangeASubscriber(t *testing.T) {
+	service := NewSubscriptionService(testutil.NopLogger{}, metrics.NopMetrics{})
+	alice := auth.WithPrincipal(context.Background(), &auth.Principal{ID: "alice", Method: auth.METHOD_API_KEY, Symbols: []string{"*"}})
+	mallory := auth.WithPrincipal(context.Background(), &auth.Principal{ID: "mallory", Method: auth.METHOD_API_KEY, Symbols: []string{"*"}})
+
+	sink := newFakeSink()
+	if err := service.AddUpdateSubscriber(alice, 1, []string{"BTCUSDT"}, sink); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if err := service.RemoveSubscriber(mallory, 1, nil); !errors.Is(err, auth.ERR_PERMISSION_DENIED) {
+		t.Errorf("expected another client's removal to be denied, got %v", err)
+	}
+	if err := service.AddUpdateSubscriber(mallory, 1, []string{"ETHUSDT"}, newFakeSink()); !errors.Is(err, auth.ERR_PERMISSION_DENIED) {
+		t.Errorf("expected another client's update to be denied, got %v", err)
+	}
+	if sink.isClosed() {
+		t.Fatal("expected the subscriber to be kept")
+	}
+
+	if err := service.RemoveSubscriber(alice, 1, nil); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !sink.isClosed() {
+		t.Error("expected the owner to remove the subscriber")
+	}
+}
+
+func TestSubscriberOnlyReceivesEntitledSymbols(t *testing.T) {
+	service := NewSubscriptionService(testutil.NopLogger{}, metrics.NopMetrics{})
+	ctx := auth.WithPrincipal(context.Background(), &auth.Principal{ID: "desk", Method: auth.METHOD_JWT, Symbols: []string{"BTC*"}})
+	service.TrackSymbol(ctx, "BTCUSDT")
+	service.TrackSymbol(ctx, "ETHUSDT")
+
+	err := service.AddUpdateSubscriber(ctx, 1, []string{"ETHUSDT"}, newFakeSink())
+	if !errors.Is(err, auth.ERR_PERMISSION_DENIED) {
+		t.Errorf("expected an unentitled symbol to be denied, got %v", err)
+	}
+
+	sink := newFakeSink()
+	if err := service.AddUpdateSubscriber(ctx, 2, []string{"*USDT"}, sink); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	service.TrackSymbol(ctx, "BTCEUR")
+	service.TrackSymbol(ctx, "SOLUSDT")
+
+	resolved, err := service.ResolveSymbols(ctx, []string{"*"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(resolved) != 2 {
+		t.Errorf("expected the entitled BTCUSDT and BTCEUR, got %v", resolved)
+	}
+
+	for _, symbol := range []string{"ETHUSDT", "SOLUSDT", "BTCUSDT"} {
+		service.BroadcastToSubscribers(ctx, &CandlestickEvent{Symbol: symbol})
+	}
+	if got := sink.waitFor(t, 1); len(got) != 1 || got[0].Symbol != "BTCUSDT" {
+		t.Errorf("expected only the BTCUSDT event, got %v", got)
+	}
+}
+
+func TestCountSymbolsCountsTheSymbolsReceivedOnceSubscribed(t *testing.T) {
+	ctx := context.Background()
+	service := NewSubscriptionService(testutil.NopLogger{}, metrics.NopMetrics{})
+	service.TrackSymbol(ctx, "BTCUSDT")
+	service.TrackSymbol(ctx, "ETHUSDT")
+	service.TrackSymbol(ctx, "ETHBTC")
+
+	service.AddUpdateSubscriber(ctx, 1, []string{"BTCUSDT"}, newFakeSink())
+
+	count, err := service.CountSymbols(ctx, 1, []string{"*USDT", "SOLUSDT"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if count != 3 {
+		t.Errorf("expected BTCUSDT, ETHUSDT and SOLUSDT, got %d", count)
+	}
+}
+
+// fanoutMetrics records the fan-out metrics, discarding the others
+type fanoutMetrics struct {
+	metrics.NopMetrics
+	mutex       sync.Mutex
+	subscribers map[string]int
+	sent        map[string]int
+	dropped     map[string]int
+}
+
+func newFanoutMetrics() *fanoutMetrics {
+	return &fanoutMetrics{
+		subscribers: map[string]int{},
+		sent:        map[string]int{},
+		dropped:     map[string]int{},
+	}
+}
+
+func (m *fanoutMetrics) SetSubscribers(symbol string, count int) {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+	m.subscribers[symbol] = count
+}
+
+func (m *fanoutMetrics) MessageSent(symbol string) {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+	m.sent[symbol]++
+}
+
+func (m *fanoutMetrics) MessageDropped(symbol string) {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+	m.dropped[symbol]++
+}
+
+// returns the subscribers, sent and dropped messages of the symbol
+func (m *fanoutMetrics) counts(symbol string) (int, int, int) {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+	return m.subscribers[symbol], m.sent[symbol], m.dropped[symbol]
+}
+
+func TestBroadcastToSubscribersRecordsFanoutMetrics(t *testing.T) {
+	ctx := context.Background()
+	recorded := newFanoutMetrics()
+	service := NewSubscriptionService(testutil.NopLogger{}, recorded)
+
+	healthy, broken := newFakeSink(), newFakeSink()
+	broken.sendErr = errors.New("connection lost")
+	service.AddUpdateSubscriber(ctx, 1, []string{"BTCUSDT"}, healthy)
+	service.AddUpdateSubscriber(ctx, 2, []string{"BTCUSDT"}, broken)
+
+	if subscribers, _, _ := recorded.counts("BTCUSDT"); subscribers != 2 {
+		t.Fatalf("expected 2 BTCUSDT subscribers, got %d", subscribers)
+	}
+
+	service.BroadcastToSubscribers(ctx, &CandlestickEvent{Symbol: "BTCUSDT"})
+
+	eventually(t, func() bool {
+		subscribers, sent, dropped := recorded.counts("BTCUSDT")
+		return subscribers == 1 && sent == 1 && dropped == 1
+	})
+}
+
+// blockingSink blocks every send until released
+type blockingSink struct {
+	*fakeSink
+	release chan struct{}
+}
+
+func (s *blockingSink) Send(event *CandlestickEvent) error {
+	<-s.release
+	return s.fakeSink.Send(event)
+}
+
+func TestSlowSubscriberDoesNotHoldOffTheBroadcasts(t *testing.T) {
+	ctx := context.Background()
+	recorded := newFanoutMetrics()
+	service := NewSubscriptionService(testutil.NopLogger{}, recorded)
+
+	slow := &blockingSink{fakeSink: newFakeSink(), release: make(chan struct{})}
+	healthy := newFakeSink()
+	service.AddUpdateSubscriber(ctx, 1, []string{"BTCUSDT"}, slow)
+	service.AddUpdateSubscriber(ctx, 2, []string{"BTCUSDT"}, healthy)
+
+	// one is being sent, the queue holds QUEUE_SIZE more and the rest is
+	// dropped, the other subscriber receiving each broadcast meanwhile
+	const broadcasts = QUEUE_SIZE + 10
+	slowSub := service.Recipients("BTCUSDT")[0]
+	for i := 1; i <= broadcasts; i++ {
+		service.BroadcastToSubscribers(ctx, &CandlestickEvent{Symbol: "BTCUSDT", Sequence: uint64(i)})
+		healthy.waitFor(t, i)
+		if i == 1 {
+			eventually(t, func() bool { return len(slowSub.queue) == 0 })
+		}
+	}
+
+	if _, _, dropped := recorded.counts("BTCUSDT"); dropped != 9 {
+		t.Errorf("expected the events beyond the slow subscriber's queue to be dropped, got %d", dropped)
+	}
+	for _, info := range service.ListSubscribers(ctx) {
+		expected := int64(0)
+		if info.ID == 1 {
+			expected = QUEUE_SIZE
+		}
+		if info.QueueDepth != expected {
+			t.Errorf("expected subscriber %d to have %d queued, got %d", info.ID, expected, info.QueueDepth)
+		}
+	}
+
+	close(slow.release)
+	got := slow.waitFor(t, QUEUE_SIZE+1)
+	for i := 1; i < len(got); i++ {
+		if got[i].Sequence <= got[i-1].Sequence {
+			t.Fatalf("expected the queued events in order, got %d after %d", got[i].Sequence, got[i-1].Sequence)
+		}
+	}
+}
@@ -0,0 +1,66 @@
+package webhook
+
+import (
+	"context"
+	"sync"
+
+	"github.com/ramasbeinaty/trading-chart-service/pkg/domain/alert"
+	"go.uber.org/zap"
+)
+
+// AlertSink publishes the fired alerts it receives as an alert listener
+// to the webhook targets
+type AlertSink struct {
+	dispatcher *WebhookDispatcher
+	done       chan struct{}
+	once       sync.Once
+}
+
+var _ alert.Sink = &AlertSink{}
+
+func NewAlertSink(
+	dispatcher *WebhookDispatcher,
+) *AlertSink {
+	return &AlertSink{
+		dispatcher: dispatcher,
+		done:       make(chan struct{}),
+	}
+}
+
+// never fails, so the listener is kept if an alert could not be published
+func (s *AlertSink) Send(fired *alert.Fired) error {
+	err := s.dispatcher.Publish(
+		context.Background(),
+		EVENT_TYPE_ALERT_FIRED,
+		fired.Alert.Symbol,
+		AlertFired{
+			AlertID:      fired.Alert.ID,
+			Symbol:       fired.Alert.Symbol,
+			Timeframe:    string(fired.Alert.Timeframe),
+			Metric:       string(fired.Alert.Metric),
+			Condition:    string(fired.Alert.Condition),
+			Threshold:    fired.Alert.Threshold,
+			Mode:         string(fired.Alert.Mode),
+			FireCount:    fired.Alert.FireCount,
+			Value:        fired.Value,
+			BarTimestamp: fired.BarTimestamp,
+			FiredAt:      fired.FiredAt,
+		},
+	)
+	if err != nil {
+		s.dispatcher.lgr.Get(context.Background()).Error(
+			"Failed to publish fired alert to webhooks",
+			zap.Int64("alertId", fired.Alert.ID),
+			zap.Error(err),
+		)
+	}
+	return nil
+}
+
+func (s *AlertSink) Close() {
+	s.once.Do(func() { close(s.done) })
+}
+
+func (s *AlertSink) Done() <-chan struct{} {
+	return s.done
+}
@@ -0,0 +1,5 @@
+package webhook
+
+type WebhookConfig struct {
+	Targets []Target
+}
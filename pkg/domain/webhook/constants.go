@@ -0,0 +1,20 @@
+package webhook
+
+import "time"
+
+const (
+	// deliveries waiting for a worker, beyond which they go to the outbox
+	QUEUE_SIZE = 1000
+	WORKERS    = 4
+
+	// attempts made right away before a delivery goes to the outbox
+	MAX_IMMEDIATE_ATTEMPTS = 3
+	// attempts after which a delivery is given up on and kept as failed
+	MAX_DELIVERY_ATTEMPTS = 10
+
+	INITIAL_BACKOFF = 500 * time.Millisecond
+	MAX_BACKOFF     = time.Hour
+
+	OUTBOX_POLL_INTERVAL = 10 * time.Second
+	OUTBOX_BATCH_SIZE    = 100
+)
@@ -0,0 +1,9 @@
+package webhook
+
+import "time"
+
+// SetTestTimings shortens the backoff and outbox poll interval for tests
+func SetTestTimings(d *WebhookDispatcher, initialBackoff time.Duration, pollInterval time.Duration) {
+	d.initialBackoff = initialBackoff
+	d.pollInterval = pollInterval
+}
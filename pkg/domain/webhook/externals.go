@@ -0,0 +1,39 @@
+package webhook
+
+import (
+	"context"
+	"time"
+)
+
+type IRepository interface {
+	// stores the delivery in the outbox, setting its id
+	CreateDelivery(
+		ctx context.Context,
+		delivery *Delivery,
+	) error
+	// returns the pending deliveries due by the given time, oldest first
+	GetDueDeliveries(
+		ctx context.Context,
+		now time.Time,
+		limit int,
+	) ([]*Delivery, error)
+	// stores the attempts, next attempt time, last error and status
+	UpdateDelivery(
+		ctx context.Context,
+		delivery *Delivery,
+	) error
+	DeleteDelivery(
+		ctx context.Context,
+		id int64,
+	) error
+}
+
+type IClient interface {
+	// posts the delivery's signed payload to the target,
+	// failing unless it is acknowledged with a 2xx status
+	Deliver(
+		ctx context.Context,
+		target Target,
+		delivery *Delivery,
+	) error
+}
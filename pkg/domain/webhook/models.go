@@ -0,0 +1,109 @@
+package webhook
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+type EventType string
+
+const (
+	EVENT_TYPE_BAR_CLOSED  EventType = "bar.closed"
+	EVENT_TYPE_ALERT_FIRED EventType = "alert.fired"
+)
+
+// Target is an endpoint receiving the events of its symbols and types,
+// or of every symbol and type if left empty
+type Target struct {
+	Name    string      `json:"name"` // identifies the target's deliveries in the outbox
+	URL     string      `json:"url"`
+	Secret  string      `json:"secret"` // key of the payloads' HMAC-SHA256 signature
+	Symbols []string    `json:"symbols"`
+	Events  []EventType `json:"events"`
+}
+
+func (t *Target) Validate() error {
+	if t.Name == "" {
+		return fmt.Errorf("Invalid webhook target - name must not be empty")
+	}
+	if t.URL == "" {
+		return fmt.Errorf("Invalid webhook target %s - url must not be empty", t.Name)
+	}
+	if t.Secret == "" {
+		return fmt.Errorf("Invalid webhook target %s - secret must not be empty", t.Name)
+	}
+	for _, e := range t.Events {
+		if e != EVENT_TYPE_BAR_CLOSED && e != EVENT_TYPE_ALERT_FIRED {
+			return fmt.Errorf("Invalid webhook target %s - unknown event %s", t.Name, e)
+		}
+	}
+	return nil
+}
+
+func (t *Target) matches(eventType EventType, symbol string) bool {
+	if len(t.Events) != 0 {
+		found := false
+		for _, e := range t.Events {
+			found = found || e == eventType
+		}
+		if !found {
+			return false
+		}
+	}
+
+	if len(t.Symbols) != 0 {
+		for _, s := range t.Symbols {
+			if strings.EqualFold(s, symbol) {
+				return true
+			}
+		}
+		return false
+	}
+
+	return true
+}
+
+type DeliveryStatus string
+
+const (
+	DELIVERY_STATUS_PENDING DeliveryStatus = "pending"
+	// given up on after MAX_DELIVERY_ATTEMPTS
+	DELIVERY_STATUS_FAILED DeliveryStatus = "failed"
+)
+
+// Delivery is an event to deliver to a target, stored in the outbox
+// once its immediate attempts failed
+type Delivery struct {
+	ID            int64 // outbox id, 0 until stored
+	EventID       string
+	Target        string
+	EventType     EventType
+	Payload       []byte // the json envelope, as signed and sent
+	Attempts      int
+	NextAttemptAt time.Time
+	LastError     string
+	Status        DeliveryStatus
+}
+
+// Envelope is the json body delivered to the targets
+type Envelope struct {
+	ID        string    `json:"id"` // the same across the retries of a delivery
+	Type      EventType `json:"type"`
+	CreatedAt time.Time `json:"created_at"`
+	Data      any       `json:"data"`
+}
+
+type AlertFired struct {
+	AlertID      int64     `json:"alert_id"`
+	Symbol       string    `json:"symbol"`
+	Timeframe    string    `json:"timeframe"`
+	Metric       string    `json:"metric"`
+	Condition    string    `json:"condition"`
+	Threshold    float64   `json:"threshold"`
+	Mode         string    `json:"mode"`
+	FireCount    uint64    `json:"fire_count"`
+	Value        float64   `json:"value"`
+	BarTimestamp time.Time `json:"bar_timestamp"`
+	FiredAt      time.Time `json:"fired_at"`
+}
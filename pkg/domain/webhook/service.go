@@ -0,0 +1,311 @@
+package webhook
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/ramasbeinaty/trading-chart-service/pkg/domain/base/logger"
+	"go.uber.org/zap"
+)
+
+// WebhookDispatcher delivers events to the webhook targets subscribed to them
+// deliveries are attempted right away a few times, then stored in the outbox
+// and retried with an exponential backoff, surviving restarts
+type WebhookDispatcher struct {
+	targets map[string]Target // keyed by name
+	repo    IRepository
+	client  IClient
+	lgr     logger.ILogger
+	queue   chan *Delivery
+
+	initialBackoff time.Duration
+	pollInterval   time.Duration
+}
+
+func NewWebhookDispatcher(
+	cfg *WebhookConfig,
+	repo IRepository,
+	client IClient,
+	lgr logger.ILogger,
+) *WebhookDispatcher {
+	targets := make(map[string]Target, len(cfg.Targets))
+	for _, t := range cfg.Targets {
+		targets[t.Name] = t
+	}
+
+	return &WebhookDispatcher{
+		targets:        targets,
+		repo:           repo,
+		client:         client,
+		lgr:            lgr,
+		queue:          make(chan *Delivery, QUEUE_SIZE),
+		initialBackoff: INITIAL_BACKOFF,
+		pollInterval:   OUTBOX_POLL_INTERVAL,
+	}
+}
+
+// Start runs the workers delivering the published events and the outbox
+// poller, until the context is done
+// deliveries still queued by then are stored in the outbox
+// the outbox is shared by the replicas, only the leading one polls it
+func (d *WebhookDispatcher) Start(
+	ctx context.Context,
+	wg *sync.WaitGroup,
+	leading func() bool,
+) {
+	if len(d.targets) == 0 {
+		return
+	}
+
+	var workers sync.WaitGroup
+	for i := 0; i < WORKERS; i++ {
+		workers.Add(1)
+		go func() {
+			defer workers.Done()
+			for {
+				select {
+				case <-ctx.Done():
+					return
+				case delivery := <-d.queue:
+					d.deliver(ctx, delivery)
+				}
+			}
+		}()
+	}
+
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+
+		ticker := time.NewTicker(d.pollInterval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-ctx.Done():
+				workers.Wait()
+				d.flushQueue()
+				return
+			case <-ticker.C:
+				if leading() {
+					d.retryOutbox(ctx)
+				}
+			}
+		}
+	}()
+}
+
+// Publish queues the event for delivery to the targets subscribed to it,
+// without waiting for the deliveries
+func (d *WebhookDispatcher) Publish(
+	ctx context.Context,
+	eventType EventType,
+	symbol string,
+	data any,
+) error {
+	if len(d.targets) == 0 {
+		return nil
+	}
+
+	lgr := d.lgr.Get(ctx)
+
+	eventId, err := newEventID()
+	if err != nil {
+		return err
+	}
+
+	payload, err := json.Marshal(Envelope{
+		ID:        eventId,
+		Type:      eventType,
+		CreatedAt: time.Now().UTC(),
+		Data:      data,
+	})
+	if err != nil {
+		return fmt.Errorf("Failed to marshal %s webhook payload - %w", eventType, err)
+	}
+
+	for _, target := range d.targets {
+		if !target.matches(eventType, symbol) {
+			continue
+		}
+
+		delivery := &Delivery{
+			EventID:   eventId,
+			Target:    target.Name,
+			EventType: eventType,
+			Payload:   payload,
+			Status:    DELIVERY_STATUS_PENDING,
+		}
+
+		select {
+		case d.queue <- delivery:
+		default:
+			lgr.Warn(
+				"Webhook queue is full, storing delivery in the outbox",
+				zap.String("target", target.Name),
+				zap.String("eventId", eventId),
+			)
+			delivery.NextAttemptAt = time.Now().UTC()
+			if err := d.repo.CreateDelivery(ctx, delivery); err != nil {
+				return fmt.Errorf("Failed to store webhook delivery - %w", err)
+			}
+		}
+	}
+
+	return nil
+}
+
+// attempts the delivery a few times, storing it in the outbox if they fail
+func (d *WebhookDispatcher) deliver(
+	ctx context.Context,
+	delivery *Delivery,
+) {
+	lgr := d.lgr.Get(ctx)
+
+	target := d.targets[delivery.Target]
+	for {
+		err := d.client.Deliver(ctx, target, delivery)
+		delivery.Attempts++
+		if err == nil {
+			return
+		}
+		delivery.LastError = err.Error()
+
+		if delivery.Attempts >= MAX_IMMEDIATE_ATTEMPTS || !wait(ctx, d.backoff(delivery.Attempts)) {
+			break
+		}
+	}
+
+	lgr.Warn(
+		"Failed to deliver webhook, storing delivery in the outbox",
+		zap.String("target", delivery.Target),
+		zap.String("eventId", delivery.EventID),
+		zap.Int("attempts", delivery.Attempts),
+		zap.String("error", delivery.LastError),
+	)
+
+	delivery.NextAttemptAt = time.Now().UTC().Add(d.backoff(delivery.Attempts))
+	// stored even if the dispatcher is stopping
+	if err := d.repo.CreateDelivery(context.WithoutCancel(ctx), delivery); err != nil {
+		lgr.Error(
+			"Failed to store webhook delivery, dropping it",
+			zap.String("target", delivery.Target),
+			zap.String("eventId", delivery.EventID),
+			zap.Error(err),
+		)
+	}
+}
+
+// attempts the outbox deliveries that are due once more each,
+// deleting the delivered ones
+func (d *WebhookDispatcher) retryOutbox(
+	ctx context.Context,
+) {
+	lgr := d.lgr.Get(ctx)
+
+	deliveries, err := d.repo.GetDueDeliveries(ctx, time.Now().UTC(), OUTBOX_BATCH_SIZE)
+	if err != nil {
+		lgr.Error("Failed to get due webhook deliveries", zap.Error(err))
+		return
+	}
+
+	for _, delivery := range deliveries {
+		if ctx.Err() != nil {
+			return
+		}
+
+		target, exists := d.targets[delivery.Target]
+		if !exists {
+			lgr.Warn(
+				"Webhook target no longer exists, dropping delivery",
+				zap.String("target", delivery.Target),
+				zap.String("eventId", delivery.EventID),
+			)
+			if err := d.repo.DeleteDelivery(ctx, delivery.ID); err != nil {
+				lgr.Error("Failed to delete webhook delivery", zap.Error(err))
+			}
+			continue
+		}
+
+		err := d.client.Deliver(ctx, target, delivery)
+		delivery.Attempts++
+		if err == nil {
+			if err := d.repo.DeleteDelivery(ctx, delivery.ID); err != nil {
+				lgr.Error("Failed to delete delivered webhook", zap.Error(err))
+			}
+			continue
+		}
+
+		delivery.LastError = err.Error()
+		delivery.NextAttemptAt = time.Now().UTC().Add(d.backoff(delivery.Attempts))
+		if delivery.Attempts >= MAX_DELIVERY_ATTEMPTS {
+			lgr.Error(
+				"Giving up on webhook delivery",
+				zap.String("target", delivery.Target),
+				zap.String("eventId", delivery.EventID),
+				zap.String("error", delivery.LastError),
+			)
+			delivery.Status = DELIVERY_STATUS_FAILED
+		}
+
+		if err := d.repo.UpdateDelivery(ctx, delivery); err != nil {
+			lgr.Error("Failed to update webhook delivery", zap.Error(err))
+		}
+	}
+}
+
+// stores the deliveries still queued in the outbox
+func (d *WebhookDispatcher) flushQueue() {
+	ctx := context.Background()
+	for {
+		select {
+		case delivery := <-d.queue:
+			delivery.NextAttemptAt = time.Now().UTC()
+			if err := d.repo.CreateDelivery(ctx, delivery); err != nil {
+				d.lgr.Get(ctx).Error(
+					"Failed to store webhook delivery, dropping it",
+					zap.String("target", delivery.Target),
+					zap.String("eventId", delivery.EventID),
+					zap.Error(err),
+				)
+			}
+		default:
+			return
+		}
+	}
+}
+
+// doubles with every attempt, up to MAX_BACKOFF
+func (d *WebhookDispatcher) backoff(attempts int) time.Duration {
+	backoff := d.initialBackoff
+	for i := 1; i < attempts && backoff < MAX_BACKOFF; i++ {
+		backoff *= 2
+	}
+	return min(backoff, MAX_BACKOFF)
+}
+
+// returns false if the context is done before the duration elapsed
+func wait(ctx context.Context, duration time.Duration) bool {
+	timer := time.NewTimer(duration)
+	defer timer.Stop()
+
+	select {
+	case <-ctx.Done():
+		return false
+	case <-timer.C:
+		return true
+	}
+}
+
+func newEventID() (string, error) {
+	b := make([]byte, 16)
+	if _, err := rand.Read(b); err != nil {
+		return "", fmt.Errorf("Failed to generate webhook event id - %w", err)
+	}
+	return hex.EncodeToString(b), nil
+}
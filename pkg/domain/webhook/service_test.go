@@ -0,0 +1,265 @@
+package webhook_test
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/ramasbeinaty/trading-chart-service/internal/testutil"
+	"github.com/ramasbeinaty/trading-chart-service/pkg/domain/base/events"
+	"github.com/ramasbeinaty/trading-chart-service/pkg/domain/webhook"
+	"github.com/ramasbeinaty/trading-chart-service/pkg/infra/clients/webhookclient"
+)
+
+// memoryOutbox keeps the deliveries in memory
+type memoryOutbox struct {
+	mutex      sync.Mutex
+	nextId     int64
+	deliveries map[int64]*webhook.Delivery
+}
+
+func newMemoryOutbox() *memoryOutbox {
+	return &memoryOutbox{deliveries: map[int64]*webhook.Delivery{}}
+}
+
+func (o *memoryOutbox) CreateDelivery(_ context.Context, d *webhook.Delivery) error {
+	o.mutex.Lock()
+	defer o.mutex.Unlock()
+
+	o.nextId++
+	d.ID = o.nextId
+	stored := *d
+	o.deliveries[d.ID] = &stored
+	return nil
+}
+
+func (o *memoryOutbox) GetDueDeliveries(_ context.Context, now time.Time, limit int) ([]*webhook.Delivery, error) {
+	o.mutex.Lock()
+	defer o.mutex.Unlock()
+
+	due := []*webhook.Delivery{}
+	for _, d := range o.deliveries {
+		if d.Status == webhook.DELIVERY_STATUS_PENDING && !d.NextAttemptAt.After(now) && len(due) < limit {
+			stored := *d
+			due = append(due, &stored)
+		}
+	}
+	return due, nil
+}
+
+func (o *memoryOutbox) UpdateDelivery(_ context.Context, d *webhook.Delivery) error {
+	o.mutex.Lock()
+	defer o.mutex.Unlock()
+
+	stored := *d
+	o.deliveries[d.ID] = &stored
+	return nil
+}
+
+func (o *memoryOutbox) DeleteDelivery(_ context.Context, id int64) error {
+	o.mutex.Lock()
+	defer o.mutex.Unlock()
+
+	delete(o.deliveries, id)
+	return nil
+}
+
+func (o *memoryOutbox) all() []*webhook.Delivery {
+	o.mutex.Lock()
+	defer o.mutex.Unlock()
+
+	all := []*webhook.Delivery{}
+	for _, d := range o.deliveries {
+		all = append(all, d)
+	}
+	return all
+}
+
+// receiver is a webhook target recording the requests it acknowledges
+// and failing the ones received while it is down
+type receiver struct {
+	server   *httptest.Server
+	mutex    sync.Mutex
+	down     bool
+	received []*http.Request
+	bodies   [][]byte
+	failed   int
+}
+
+func newReceiver(t *testing.T) *receiver {
+	r := &receiver{}
+	r.server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		body, _ := io.ReadAll(req.Body)
+
+		r.mutex.Lock()
+		defer r.mutex.Unlock()
+
+		if r.down {
+			r.failed++
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		r.received = append(r.received, req)
+		r.bodies = append(r.bodies, body)
+	}))
+	t.Cleanup(r.server.Close)
+	return r
+}
+
+func (r *receiver) setDown(down bool) {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	r.down = down
+}
+
+func (r *receiver) count() (received int, failed int) {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	return len(r.received), r.failed
+}
+
+// waits for the condition, as deliveries are made by the workers
+func eventually(t *testing.T, condition func() bool) {
+	t.Helper()
+
+	deadline := time.Now().Add(5 * time.Second)
+	for !condition() {
+		if time.Now().After(deadline) {
+			t.Fatalf("condition not met in time")
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+}
+
+func startDispatcher(t *testing.T, outbox *memoryOutbox, targets ...webhook.Target) *webhook.WebhookDispatcher {
+	t.Helper()
+
+	dispatcher := webhook.NewWebhookDispatcher(
+		&webhook.WebhookConfig{Targets: targets},
+		outbox,
+		webhookclient.NewWebhookClient(),
+		testutil.NopLogger{},
+	)
+	webhook.SetTestTimings(dispatcher, time.Millisecond, 10*time.Millisecond)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	var wg sync.WaitGroup
+	dispatcher.Start(ctx, &wg, func() bool { return true })
+	t.Cleanup(func() {
+		cancel()
+		wg.Wait()
+	})
+
+	return dispatcher
+}
+
+var bar = events.BarClosed{
+	Symbol:         "BTCUSDT",
+	Open:           100,
+	High:           102,
+	Low:            99,
+	Close:          101,
+	TradeTimestamp: time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC),
+}
+
+func TestPublishDeliversSignedPayloadsToMatchingTargets(t *testing.T) {
+	btc, eth, alerts := newReceiver(t), newReceiver(t), newReceiver(t)
+	dispatcher := startDispatcher(t, newMemoryOutbox(),
+		webhook.Target{Name: "btc", URL: btc.server.URL, Secret: "btc-secret", Symbols: []string{"btcusdt"}},
+		webhook.Target{Name: "eth", URL: eth.server.URL, Secret: "eth-secret", Symbols: []string{"ETHUSDT"}},
+		webhook.Target{Name: "alerts", URL: alerts.server.URL, Secret: "alerts-secret", Events: []webhook.EventType{webhook.EVENT_TYPE_ALERT_FIRED}},
+	)
+
+	err := dispatcher.Publish(context.Background(), webhook.EVENT_TYPE_BAR_CLOSED, "BTCUSDT", bar)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	eventually(t, func() bool { received, _ := btc.count(); return received == 1 })
+
+	req, body := btc.received[0], btc.bodies[0]
+	timestamp, err := strconv.ParseInt(req.Header.Get(webhookclient.HEADER_TIMESTAMP), 10, 64)
+	if err != nil {
+		t.Fatalf("invalid timestamp header: %v", err)
+	}
+	if signature := req.Header.Get(webhookclient.HEADER_SIGNATURE); signature != webhookclient.Sign("btc-secret", timestamp, body) {
+		t.Fatalf("invalid signature %s", signature)
+	}
+	if req.Header.Get(webhookclient.HEADER_EVENT) != string(webhook.EVENT_TYPE_BAR_CLOSED) {
+		t.Fatalf("unexpected event header %s", req.Header.Get(webhookclient.HEADER_EVENT))
+	}
+
+	var envelope struct {
+		ID   string            `json:"id"`
+		Type webhook.EventType `json:"type"`
+		Data events.BarClosed  `json:"data"`
+	}
+	if err := json.Unmarshal(body, &envelope); err != nil {
+		t.Fatalf("invalid payload: %v", err)
+	}
+	if envelope.ID != req.Header.Get(webhookclient.HEADER_EVENT_ID) || envelope.Data != bar {
+		t.Fatalf("unexpected payload %s", body)
+	}
+
+	// give the other targets a chance to wrongly receive it
+	time.Sleep(50 * time.Millisecond)
+	if received, _ := eth.count(); received != 0 {
+		t.Fatalf("expected the eth target not to receive btc bars")
+	}
+	if received, _ := alerts.count(); received != 0 {
+		t.Fatalf("expected the alerts target not to receive bars")
+	}
+}
+
+func TestFailedDeliveriesAreRetriedFromTheOutbox(t *testing.T) {
+	target := newReceiver(t)
+	target.setDown(true)
+	outbox := newMemoryOutbox()
+	dispatcher := startDispatcher(t, outbox,
+		webhook.Target{Name: "target", URL: target.server.URL, Secret: "secret"},
+	)
+
+	dispatcher.Publish(context.Background(), webhook.EVENT_TYPE_BAR_CLOSED, "BTCUSDT", bar)
+
+	// the immediate attempts fail, then the outbox retries keep failing
+	eventually(t, func() bool { return len(outbox.all()) == 1 })
+	eventually(t, func() bool { _, failed := target.count(); return failed > webhook.MAX_IMMEDIATE_ATTEMPTS })
+
+	stored := outbox.all()[0]
+	if stored.Target != "target" || stored.LastError == "" || len(stored.Payload) == 0 {
+		t.Fatalf("unexpected stored delivery %+v", stored)
+	}
+
+	target.setDown(false)
+	eventually(t, func() bool { return len(outbox.all()) == 0 })
+
+	if received, _ := target.count(); received != 1 {
+		t.Fatalf("expected a single delivery, got %d", received)
+	}
+}
+
+func TestOutboxGivesUpAfterMaxAttempts(t *testing.T) {
+	target := newReceiver(t)
+	target.setDown(true)
+	outbox := newMemoryOutbox()
+	dispatcher := startDispatcher(t, outbox,
+		webhook.Target{Name: "target", URL: target.server.URL, Secret: "secret"},
+	)
+
+	dispatcher.Publish(context.Background(), webhook.EVENT_TYPE_BAR_CLOSED, "BTCUSDT", bar)
+
+	eventually(t, func() bool {
+		all := outbox.all()
+		return len(all) == 1 && all[0].Status == webhook.DELIVERY_STATUS_FAILED
+	})
+
+	if _, failed := target.count(); failed != webhook.MAX_DELIVERY_ATTEMPTS {
+		t.Fatalf("expected %d attempts, got %d", webhook.MAX_DELIVERY_ATTEMPTS, failed)
+	}
+}
@@ -0,0 +1,211 @@
+package certs
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"fmt"
+	"net"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/ramasbeinaty/trading-chart-service/internal"
+	"github.com/ramasbeinaty/trading-chart-service/pkg/domain/base/logger"
+	"go.uber.org/zap"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/credentials/insecure"
+)
+
+const (
+	// the files are checked for changes as often
+	RELOAD_INTERVAL = 30 * time.Second
+)
+
+// CertReloader serves the certificate and the CA from their files, reloading
+// them once changed, so they can be rotated without a restart
+type CertReloader struct {
+	cfg *internal.TLSConfig
+	lgr logger.ILogger
+
+	mutex    sync.RWMutex
+	cert     *tls.Certificate
+	caPool   *x509.CertPool
+	modTimes map[string]time.Time
+}
+
+// NewCertReloader loads the files, returning nil if TLS is disabled
+func NewCertReloader(
+	cfg *internal.TLSConfig,
+	lgr logger.ILogger,
+) (*CertReloader, error) {
+	if !cfg.Enabled() {
+		return nil, nil
+	}
+
+	r := &CertReloader{
+		cfg: cfg,
+		lgr: lgr,
+	}
+	if _, err := r.reload(); err != nil {
+		return nil, err
+	}
+	return r, nil
+}
+
+// Start reloads the files once changed until the context is done, keeping
+// the loaded ones if they are invalid
+func (r *CertReloader) Start(
+	ctx context.Context,
+	wg *sync.WaitGroup,
+) {
+	lgr := r.lgr.Get(ctx)
+
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+
+		ticker := time.NewTicker(RELOAD_INTERVAL)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+			}
+
+			reloaded, err := r.reload()
+			if err != nil {
+				lgr.Error("Failed to reload the tls certificate", zap.Error(err))
+				continue
+			}
+			if reloaded {
+				lgr.Info("Reloaded the tls certificate", zap.String("cert", r.cfg.CertFile))
+			}
+		}
+	}()
+}
+
+// ServerCredentials serves the current certificate, requiring client
+// certificates verified by the CA when client auth is enabled
+func (r *CertReloader) ServerCredentials() credentials.TransportCredentials {
+	return credentials.NewTLS(&tls.Config{
+		MinVersion: tls.VersionTLS12,
+		GetConfigForClient: func(*tls.ClientHelloInfo) (*tls.Config, error) {
+			r.mutex.RLock()
+			defer r.mutex.RUnlock()
+
+			c := &tls.Config{
+				MinVersion:   tls.VersionTLS12,
+				Certificates: []tls.Certificate{*r.cert},
+				NextProtos:   []string{"h2"},
+			}
+			if r.cfg.ClientAuth {
+				c.ClientAuth = tls.RequireAndVerifyClientCert
+				c.ClientCAs = r.caPool
+			}
+			return c, nil
+		},
+	})
+}
+
+// ClientCredentials dials the server internally, verifying it against the
+// current CA or the system roots, and presenting the current certificate when
+// client auth is enabled
+// plaintext if TLS is disabled, i.e. the reloader is nil
+func (r *CertReloader) ClientCredentials() credentials.TransportCredentials {
+	if r == nil {
+		return insecure.NewCredentials()
+	}
+	return &clientCredentials{
+		TransportCredentials: credentials.NewTLS(r.clientConfig()),
+		reloader:             r,
+	}
+}
+
+func (r *CertReloader) clientConfig() *tls.Config {
+	r.mutex.RLock()
+	defer r.mutex.RUnlock()
+
+	c := &tls.Config{
+		MinVersion: tls.VersionTLS12,
+		ServerName: r.cfg.ServerName,
+		RootCAs:    r.caPool,
+	}
+	if r.cfg.ClientAuth {
+		c.Certificates = []tls.Certificate{*r.cert}
+	}
+	return c
+}
+
+// handshakes with the files loaded at the time, as the tls credentials fix
+// them once created
+type clientCredentials struct {
+	credentials.TransportCredentials
+	reloader *CertReloader
+}
+
+func (c *clientCredentials) ClientHandshake(
+	ctx context.Context,
+	authority string,
+	conn net.Conn,
+) (net.Conn, credentials.AuthInfo, error) {
+	return credentials.NewTLS(c.reloader.clientConfig()).ClientHandshake(ctx, authority, conn)
+}
+
+func (c *clientCredentials) Clone() credentials.TransportCredentials {
+	return &clientCredentials{
+		TransportCredentials: c.TransportCredentials.Clone(),
+		reloader:             c.reloader,
+	}
+}
+
+// reloads the files if any changed since last loaded
+func (r *CertReloader) reload() (bool, error) {
+	files := []string{r.cfg.CertFile, r.cfg.KeyFile}
+	if r.cfg.CAFile != "" {
+		files = append(files, r.cfg.CAFile)
+	}
+
+	modTimes := make(map[string]time.Time, len(files))
+	changed := false
+	for _, file := range files {
+		info, err := os.Stat(file)
+		if err != nil {
+			return false, fmt.Errorf("Failed to stat %s - %w", file, err)
+		}
+		modTimes[file] = info.ModTime()
+		if !info.ModTime().Equal(r.modTimes[file]) {
+			changed = true
+		}
+	}
+	if !changed {
+		return false, nil
+	}
+
+	cert, err := tls.LoadX509KeyPair(r.cfg.CertFile, r.cfg.KeyFile)
+	if err != nil {
+		return false, fmt.Errorf("Failed to load the certificate - %w", err)
+	}
+
+	var caPool *x509.CertPool
+	if r.cfg.CAFile != "" {
+		pem, err := os.ReadFile(r.cfg.CAFile)
+		if err != nil {
+			return false, fmt.Errorf("Failed to read the ca - %w", err)
+		}
+		caPool = x509.NewCertPool()
+		if !caPool.AppendCertsFromPEM(pem) {
+			return false, fmt.Errorf("Failed to parse the ca %s", r.cfg.CAFile)
+		}
+	}
+
+	r.mutex.Lock()
+	r.cert = &cert
+	r.caPool = caPool
+	r.modTimes = modTimes
+	r.mutex.Unlock()
+
+	return true, nil
+}
@@ -0,0 +1,242 @@
+package certs
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"math/big"
+	"net"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/ramasbeinaty/trading-chart-service/internal"
+	"github.com/ramasbeinaty/trading-chart-service/internal/testutil"
+	"google.golang.org/grpc/credentials"
+)
+
+// testCA signs the certificates of the tests
+type testCA struct {
+	cert *x509.Certificate
+	key  *ecdsa.PrivateKey
+	pem  []byte
+}
+
+func newTestCA(t *testing.T) *testCA {
+	t.Helper()
+
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	template := &x509.Certificate{
+		SerialNumber:          big.NewInt(1),
+		Subject:               pkix.Name{CommonName: "test ca"},
+		NotBefore:             time.Now().Add(-time.Hour),
+		NotAfter:              time.Now().Add(time.Hour),
+		IsCA:                  true,
+		KeyUsage:              x509.KeyUsageCertSign,
+		BasicConstraintsValid: true,
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	cert, err := x509.ParseCertificate(der)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return &testCA{
+		cert: cert,
+		key:  key,
+		pem:  pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der}),
+	}
+}
+
+// writes a localhost certificate of the serial, usable by servers and
+// clients, and its key to the files of the config
+func (ca *testCA) writeCert(t *testing.T, cfg *internal.TLSConfig, serial int64) {
+	t.Helper()
+
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	template := &x509.Certificate{
+		SerialNumber: big.NewInt(serial),
+		Subject:      pkix.Name{CommonName: "localhost"},
+		DNSNames:     []string{"localhost"},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(time.Hour),
+		KeyUsage:     x509.KeyUsageDigitalSignature,
+		ExtKeyUsage:  []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth, x509.ExtKeyUsageClientAuth},
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, ca.cert, &key.PublicKey, ca.key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	keyDer, err := x509.MarshalECPrivateKey(key)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	writeFile(t, cfg.CertFile, pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der}))
+	writeFile(t, cfg.KeyFile, pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: keyDer}))
+}
+
+// writes the file with a later modification time, as a rotation would
+func writeFile(t *testing.T, path string, content []byte) {
+	t.Helper()
+
+	modTime := time.Now()
+	if info, err := os.Stat(path); err == nil {
+		modTime = info.ModTime().Add(time.Second)
+	}
+	if err := os.WriteFile(path, content, 0o600); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Chtimes(path, modTime, modTime); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func newTestConfig(t *testing.T, ca *testCA, clientAuth bool) *internal.TLSConfig {
+	t.Helper()
+
+	dir := t.TempDir()
+	cfg := &internal.TLSConfig{
+		CertFile:   filepath.Join(dir, "server.pem"),
+		KeyFile:    filepath.Join(dir, "server.key"),
+		CAFile:     filepath.Join(dir, "ca.pem"),
+		ClientAuth: clientAuth,
+	}
+	writeFile(t, cfg.CAFile, ca.pem)
+	ca.writeCert(t, cfg, 100)
+	return cfg
+}
+
+// handshakes the client with the server over the loopback, returning the
+// serial of the certificate the server presented
+func handshake(
+	t *testing.T,
+	server credentials.TransportCredentials,
+	client credentials.TransportCredentials,
+) (int64, error) {
+	t.Helper()
+
+	lis, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer lis.Close()
+
+	serverErr := make(chan error, 1)
+	go func() {
+		conn, err := lis.Accept()
+		if err != nil {
+			serverErr <- err
+			return
+		}
+		defer conn.Close()
+		conn.SetDeadline(time.Now().Add(5 * time.Second))
+
+		_, _, err = server.ServerHandshake(conn)
+		serverErr <- err
+	}()
+
+	conn, err := net.Dial("tcp", lis.Addr().String())
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer conn.Close()
+	conn.SetDeadline(time.Now().Add(5 * time.Second))
+
+	_, info, err := client.ClientHandshake(context.Background(), "localhost:50051", conn)
+	// the server verifies the client once the client is done
+	if err := <-serverErr; err != nil {
+		return 0, err
+	}
+	if err != nil {
+		return 0, err
+	}
+	return info.(credentials.TLSInfo).State.PeerCertificates[0].SerialNumber.Int64(), nil
+}
+
+func TestNewCertReloaderIsNilWithoutTLS(t *testing.T) {
+	r, err := NewCertReloader(&internal.TLSConfig{}, testutil.NopLogger{})
+	if err != nil || r != nil {
+		t.Fatalf("expected no reloader, got %v, %v", r, err)
+	}
+	if protocol := r.ClientCredentials().Info().SecurityProtocol; protocol != "insecure" {
+		t.Errorf("expected plaintext credentials, got %s", protocol)
+	}
+}
+
+func TestCertReloaderServesTheRotatedCertificate(t *testing.T) {
+	ca := newTestCA(t)
+	cfg := newTestConfig(t, ca, false)
+	r, err := NewCertReloader(cfg, testutil.NopLogger{})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if serial, err := handshake(t, r.ServerCredentials(), r.ClientCredentials()); err != nil || serial != 100 {
+		t.Fatalf("expected the certificate 100, got %d, %v", serial, err)
+	}
+
+	if reloaded, err := r.reload(); err != nil || reloaded {
+		t.Fatalf("expected the unchanged files not to be reloaded, got %v, %v", reloaded, err)
+	}
+
+	ca.writeCert(t, cfg, 200)
+	if reloaded, err := r.reload(); err != nil || !reloaded {
+		t.Fatalf("expected the rotated files to be reloaded, got %v, %v", reloaded, err)
+	}
+	if serial, err := handshake(t, r.ServerCredentials(), r.ClientCredentials()); err != nil || serial != 200 {
+		t.Fatalf("expected the certificate 200, got %d, %v", serial, err)
+	}
+}
+
+func TestCertReloaderKeepsTheCertificateIfTheFilesAreInvalid(t *testing.T) {
+	ca := newTestCA(t)
+	cfg := newTestConfig(t, ca, false)
+	r, err := NewCertReloader(cfg, testutil.NopLogger{})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// e.g. caught while being rewritten
+	writeFile(t, cfg.CertFile, []byte("-----BEGIN CERTIFICATE-----"))
+	if _, err := r.reload(); err == nil {
+		t.Fatal("expected the invalid certificate to be rejected")
+	}
+	if serial, err := handshake(t, r.ServerCredentials(), r.ClientCredentials()); err != nil || serial != 100 {
+		t.Fatalf("expected the certificate 100 to be kept, got %d, %v", serial, err)
+	}
+}
+
+func TestCertReloaderRequiresClientCertificates(t *testing.T) {
+	ca := newTestCA(t)
+	cfg := newTestConfig(t, ca, true)
+	r, err := NewCertReloader(cfg, testutil.NopLogger{})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := handshake(t, r.ServerCredentials(), r.ClientCredentials()); err != nil {
+		t.Fatalf("expected the internal client to present its certificate, got %v", err)
+	}
+
+	pool := x509.NewCertPool()
+	pool.AppendCertsFromPEM(ca.pem)
+	anonymous := credentials.NewTLS(&tls.Config{MinVersion: tls.VersionTLS12, RootCAs: pool})
+	if _, err := handshake(t, r.ServerCredentials(), anonymous); err == nil {
+		t.Fatal("expected a client without a certificate to be rejected")
+	}
+}
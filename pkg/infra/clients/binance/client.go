@@ -5,21 +5,34 @@ import (
 	"encoding/json"
 	"fmt"
 	"log"
+	"slices"
 	"strconv"
 	"strings"
+	"sync"
+	"sync/atomic"
 	"time"
 
 	"github.com/gorilla/websocket"
+	"github.com/ramasbeinaty/trading-chart-service/pkg/domain/base/metrics"
 )
 
+// BinanceClient streams the symbols' trades to TradeDataChan from a single
+// listener, reconnecting whenever the connection drops
+// TradeDataChan is closed once the client is closed and the listener stopped
 type BinanceClient struct {
 	TradeDataChan chan<- TradeMessageParsed
 	stream        string
-	symbols       []string
+	symbols       []string // guarded by connMutex
 	conn          *websocket.Conn
+	connMutex     sync.Mutex
+	requestId     int64 // of the last live subscription request
 	ctx           context.Context
 	cancel        context.CancelFunc
 	config        *BinanceConfig
+	metrics       metrics.IMetrics
+	connected     atomic.Bool
+	closeOnce     sync.Once
+	done          chan struct{}
 }
 
 func NewBinanceClient(
@@ -28,6 +41,7 @@ func NewBinanceClient(
 	symbols []string,
 	ctx context.Context,
 	config *BinanceConfig,
+	metrics metrics.IMetrics,
 ) *BinanceClient {
 	ctx, cancel := context.WithCancel(ctx)
 
@@ -38,16 +52,27 @@ func NewBinanceClient(
 		ctx:           ctx,
 		cancel:        cancel,
 		config:        config,
+		metrics:       metrics,
+		done:          make(chan struct{}),
 	}
 }
 
+// ConnectToBinance connects to the stream and starts listening to it
 func (bc *BinanceClient) ConnectToBinance() error {
-	// construct the stream path for one or more symbols
-	streamQueries := make([]string, len(bc.symbols))
-	for i, symbol := range bc.symbols {
-		streamQueries[i] = fmt.Sprintf("%s@%s", symbol, bc.stream)
+	if err := bc.dial(); err != nil {
+		return err
 	}
-	streamPath := strings.Join(streamQueries, "/")
+
+	go bc.listen()
+	return nil
+}
+
+func (bc *BinanceClient) dial() error {
+	// construct the stream path for one or more symbols
+	bc.connMutex.Lock()
+	dialed := bc.symbols
+	bc.connMutex.Unlock()
+	streamPath := strings.Join(bc.streams(dialed), "/")
 
 	addr := fmt.Sprintf(
 		"wss://%s/ws/%s",
@@ -55,92 +80,239 @@ func (bc *BinanceClient) ConnectToBinance() error {
 		streamPath,
 	)
 
-	c, _, err := websocket.DefaultDialer.Dial(addr, nil)
+	c, _, err := websocket.DefaultDialer.DialContext(bc.ctx, addr, nil)
 	if err != nil {
-		log.Fatalf("Error dialing binance %s stream - %v", bc.stream, err)
-		return err
+		return fmt.Errorf("Error dialing binance %s stream - %w", bc.stream, err)
+	}
+
+	bc.connMutex.Lock()
+	defer bc.connMutex.Unlock()
+
+	// closed meanwhile
+	if bc.ctx.Err() != nil {
+		c.Close()
+		return bc.ctx.Err()
 	}
 
 	bc.conn = c
+	bc.connected.Store(true)
 
-	go bc.listen()
+	// the symbols changed while dialing, a failed request breaking the
+	// connection for it to be dialed again
+	if !slices.Equal(dialed, bc.symbols) {
+		if err := bc.resubscribe(dialed, bc.symbols); err != nil {
+			log.Printf("Failed to stream the changed symbols - %v", err)
+		}
+	}
 	return nil
 }
 
+// dials until connected, an outage of binance only delaying the trades
+// rather than ending the stream, the readiness reporting it meanwhile
+// only fails once the client is closed
 func (bc *BinanceClient) reconnect() error {
-	attempt := 0
-	for {
-		if err := bc.ConnectToBinance(); err == nil {
+	backoff := RECONNECT_MIN_BACKOFF
+	for attempt := 1; ; attempt++ {
+		err := bc.dial()
+		if err == nil {
 			log.Println("Successfully reconnected to binance")
+			bc.metrics.WebsocketReconnected()
 			return nil
 		}
+		if bc.ctx.Err() != nil {
+			return bc.ctx.Err()
+		}
 
-		if attempt > 5 {
-			log.Printf("Failed to reconnect to binance after %d attempts", attempt)
-			return fmt.Errorf("Failed to reconnect to binance after many attempts")
+		log.Printf("Failed to reconnect to binance, attempt %d, retrying in %s - %v", attempt, backoff, err)
+
+		select {
+		case <-time.After(backoff):
+		case <-bc.ctx.Done():
+			return bc.ctx.Err()
 		}
+		backoff = min(backoff*2, RECONNECT_MAX_BACKOFF)
+	}
+}
+
+// SetSymbols changes the streamed symbols, subscribing to the added ones and
+// unsubscribing from the removed ones on the live connection
+// a reconnection streams the symbols set last
+func (bc *BinanceClient) SetSymbols(
+	symbols []string,
+) error {
+	bc.connMutex.Lock()
+	defer bc.connMutex.Unlock()
 
-		attempt++
-		backoff := time.Duration(min(attempt*2, 10)) * time.Second // capping backoff at 10 sec for simplicity
-		time.Sleep(backoff)
+	previous := bc.symbols
+	bc.symbols = append([]string{}, symbols...)
+	if bc.conn == nil {
+		return nil
 	}
+	return bc.resubscribe(previous, bc.symbols)
 }
 
-func (bc *BinanceClient) Close() error {
-	bc.cancel()
+// changes the streams of the live connection from the previous symbols
+// expects the caller to hold connMutex
+func (bc *BinanceClient) resubscribe(
+	previous []string,
+	symbols []string,
+) error {
+	current := make(map[string]bool, len(previous))
+	for _, symbol := range previous {
+		current[symbol] = true
+	}
 
-	if bc.conn != nil {
-		err := bc.conn.Close()
-		if err != nil {
-			log.Fatal("Failed to close binance connection - %w", err)
+	added := []string{}
+	for _, symbol := range symbols {
+		if !current[symbol] {
+			added = append(added, symbol)
+		}
+		delete(current, symbol)
+	}
+	removed := make([]string, 0, len(current))
+	for symbol := range current {
+		removed = append(removed, symbol)
+	}
+
+	if len(removed) != 0 {
+		if err := bc.request(METHOD_UNSUBSCRIBE, removed); err != nil {
 			return err
 		}
 	}
+	if len(added) != 0 {
+		if err := bc.request(METHOD_SUBSCRIBE, added); err != nil {
+			return err
+		}
+	}
+	return nil
+}
 
+// sends a live subscription request for the symbols' streams, binance
+// answering it with a StreamResponseDTO
+// expects the caller to hold connMutex
+func (bc *BinanceClient) request(
+	method string,
+	symbols []string,
+) error {
+	bc.requestId++
+	bc.conn.SetWriteDeadline(time.Now().Add(WRITE_TIMEOUT))
+	err := bc.conn.WriteJSON(StreamRequestDTO{
+		Method: method,
+		Params: bc.streams(symbols),
+		ID:     bc.requestId,
+	})
+	if err != nil {
+		return fmt.Errorf("Failed to %s to binance streams - %w", strings.ToLower(method), err)
+	}
 	return nil
 }
 
+func (bc *BinanceClient) streams(
+	symbols []string,
+) []string {
+	streams := make([]string, len(symbols))
+	for i, symbol := range symbols {
+		streams[i] = fmt.Sprintf("%s@%s", symbol, bc.stream)
+	}
+	return streams
+}
+
+// IsConnected returns whether the stream is connected, false while reconnecting
+func (bc *BinanceClient) IsConnected() bool {
+	return bc.connected.Load()
+}
+
+// Close stops the stream, TradeDataChan is closed once the trade being
+// received, if any, is sent
+func (bc *BinanceClient) Close() error {
+	var err error
+	bc.closeOnce.Do(func() {
+		bc.cancel()
+		bc.connected.Store(false)
+
+		bc.connMutex.Lock()
+		defer bc.connMutex.Unlock()
+
+		if bc.conn != nil {
+			if closeErr := bc.conn.Close(); closeErr != nil {
+				err = fmt.Errorf("Failed to close binance connection - %w", closeErr)
+			}
+		}
+	})
+	return err
+}
+
+// Done is closed once the listener stopped and TradeDataChan is closed
+func (bc *BinanceClient) Done() <-chan struct{} {
+	return bc.done
+}
+
 func (bc *BinanceClient) listen() {
+	defer close(bc.done)
+	defer close(bc.TradeDataChan)
 	defer bc.Close()
 
 	for {
 		// handle context cancellation or errors
 		if bc.ctx.Err() != nil {
-			log.Printf("Error with the context - %s", bc.ctx.Err().Error())
+			log.Printf("Stopped listening to binance - %s", bc.ctx.Err().Error())
 			return
 		}
 
+		bc.connMutex.Lock()
+		conn := bc.conn
+		bc.connMutex.Unlock()
+
 		// handle incoming messages from binance
-		messageType, message, err := bc.conn.ReadMessage()
+		messageType, message, err := conn.ReadMessage()
 		if err != nil {
-			log.Println("Error reading message - %w", err)
+			bc.connected.Store(false)
+			if bc.ctx.Err() != nil {
+				continue
+			}
 
 			// binance connections disconnect after 24 hrs
 			// network issues may occur
-			if websocket.IsUnexpectedCloseError(err) {
-				log.Printf("Connection closed unexpectedly - %v", err)
-				bc.reconnect()
-				continue
+			log.Printf("Connection to binance lost - %v", err)
+			conn.Close()
+			if err := bc.reconnect(); err != nil {
+				log.Printf("Stopped listening to binance - %v", err)
+				return
 			}
-			return
+			continue
 		}
 
 		switch messageType {
 		case websocket.PingMessage:
 			log.Println("PONG!")
-			bc.conn.WriteMessage(websocket.PongMessage, nil)
+			bc.connMutex.Lock()
+			conn.WriteMessage(websocket.PongMessage, nil)
+			bc.connMutex.Unlock()
 		case websocket.TextMessage:
+			// answers to the live subscription requests carry their id
+			var response StreamResponseDTO
+			if err := json.Unmarshal(message, &response); err == nil && response.ID != 0 {
+				if response.Code != 0 {
+					log.Printf("Binance rejected request %d - %d %s", response.ID, response.Code, response.Msg)
+				}
+				continue
+			}
+
 			var msg TradeMessageDTO
 			if err := json.Unmarshal(message, &msg); err != nil {
 				log.Println("Error unmarshaling message - %w", err)
+				bc.metrics.TradeReceived(UNKNOWN_SYMBOL)
+				bc.metrics.TradeFailed(UNKNOWN_SYMBOL)
 				continue
 			}
+			bc.metrics.TradeReceived(msg.Symbol)
 
 			price := 0.0
 			if msg.Price != "" {
 				price, err = strconv.ParseFloat(msg.Price, 64)
 				if err != nil {
 					log.Println("Error parsing price - %w", err)
+					bc.metrics.TradeFailed(msg.Symbol)
 					continue
 				}
 			}
@@ -150,6 +322,7 @@ func (bc *BinanceClient) listen() {
 				qty, err = strconv.ParseFloat(msg.Quantity, 64)
 				if err != nil {
 					log.Println("Error parsing quantity - %w", err)
+					bc.metrics.TradeFailed(msg.Symbol)
 					continue
 				}
 			}
@@ -168,10 +341,29 @@ func (bc *BinanceClient) listen() {
 				msg.Ignore,
 			}
 
-			bc.TradeDataChan <- parsedMsg
+			bc.metrics.TradeParsed(msg.Symbol)
+			select {
+			case bc.TradeDataChan <- parsedMsg:
+			case <-bc.ctx.Done():
+				return
+			}
 		default:
 			log.Printf("Unhandled incoming message type, %d", messageType)
 			continue
 		}
 	}
 }
+
+// IsValidSymbol reports whether the symbol can be streamed, i.e. is made of
+// lowercase letters and digits
+func IsValidSymbol(symbol string) bool {
+	if symbol == "" {
+		return false
+	}
+	for _, r := range symbol {
+		if (r < 'a' || r > 'z') && (r < '0' || r > '9') {
+			return false
+		}
+	}
+	return true
+}
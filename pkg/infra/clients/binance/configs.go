@@ -2,4 +2,8 @@ package binance
 
 type BinanceConfig struct {
 	BaseEndpoint string
+	// base url of the REST api, e.g. https://api.binance.com
+	RestEndpoint string
+	// lowercase symbols whose trades are streamed, e.g. btcusdt
+	Symbols []string
 }
@@ -1,8 +1,29 @@
 package binance
 
+import "time"
+
 // TODO: A single connection to stream.binance.com is only valid for 24 hours
 // 		Handle being disconnected at the 24 hour mark
 
 const (
 	AGG_TRADE_STREAM_NAME = "aggTrade"
+	// metrics label of the messages that failed before their symbol was read
+	UNKNOWN_SYMBOL = "unknown"
+
+	// methods of the live subscription requests
+	METHOD_SUBSCRIBE   = "SUBSCRIBE"
+	METHOD_UNSUBSCRIBE = "UNSUBSCRIBE"
+	WRITE_TIMEOUT      = 10 * time.Second
+
+	// the delay before dialing again doubles after each failed attempt, up
+	// to the max
+	RECONNECT_MIN_BACKOFF = time.Second
+	RECONNECT_MAX_BACKOFF = 30 * time.Second
+
+	DEFAULT_REST_ENDPOINT = "https://api.binance.com"
+	KLINES_PATH           = "/api/v3/klines"
+	KLINES_INTERVAL       = "1m"
+	// most klines served by a request
+	MAX_KLINES      = 1000
+	REQUEST_TIMEOUT = 30 * time.Second
 )
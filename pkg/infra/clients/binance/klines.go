@@ -0,0 +1,131 @@
+package binance
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/ramasbeinaty/trading-chart-service/pkg/domain/backfill"
+	"github.com/ramasbeinaty/trading-chart-service/pkg/domain/candlestick"
+	"go.opentelemetry.io/contrib/instrumentation/net/http/otelhttp"
+)
+
+// KlinesClient reads the historical 1 minute klines of the REST api
+// https://developers.binance.com/docs/binance-spot-api-docs/rest-api/market-data-endpoints#klinecandlestick-data
+type KlinesClient struct {
+	cfg        *BinanceConfig
+	httpClient *http.Client
+}
+
+var _ backfill.IBarSource = (*KlinesClient)(nil)
+
+func NewKlinesClient(
+	cfg *BinanceConfig,
+) *KlinesClient {
+	return &KlinesClient{
+		cfg: cfg,
+		httpClient: &http.Client{
+			Timeout:   REQUEST_TIMEOUT,
+			Transport: otelhttp.NewTransport(http.DefaultTransport),
+		},
+	}
+}
+
+// GetBars serves up to MAX_KLINES minutes, skipping the ones without trades
+// as the live ingestion doesn't make bars of them
+func (c *KlinesClient) GetBars(
+	ctx context.Context,
+	symbol string,
+	from time.Time,
+	to time.Time,
+) ([]*candlestick.Candlestick, time.Time, error) {
+	symbol = strings.ToUpper(symbol)
+
+	query := url.Values{}
+	query.Set("symbol", symbol)
+	query.Set("interval", KLINES_INTERVAL)
+	query.Set("startTime", strconv.FormatInt(from.UnixMilli(), 10))
+	query.Set("endTime", strconv.FormatInt(to.UnixMilli(), 10))
+	query.Set("limit", strconv.Itoa(MAX_KLINES))
+
+	req, err := http.NewRequestWithContext(
+		ctx,
+		http.MethodGet,
+		strings.TrimSuffix(c.cfg.RestEndpoint, "/")+KLINES_PATH+"?"+query.Encode(),
+		nil,
+	)
+	if err != nil {
+		return nil, time.Time{}, fmt.Errorf("Failed to create klines request - %w", err)
+	}
+
+	res, err := c.httpClient.Do(req)
+	if err != nil {
+		return nil, time.Time{}, fmt.Errorf("Failed to get klines - %w", err)
+	}
+	defer res.Body.Close()
+
+	body, err := io.ReadAll(res.Body)
+	if err != nil {
+		return nil, time.Time{}, fmt.Errorf("Failed to read klines - %w", err)
+	}
+	if res.StatusCode != http.StatusOK {
+		var apiErr StreamResponseDTO
+		if json.Unmarshal(body, &apiErr) == nil && apiErr.Msg != "" {
+			return nil, time.Time{}, fmt.Errorf("Failed to get klines - %s (%d)", apiErr.Msg, apiErr.Code)
+		}
+		return nil, time.Time{}, fmt.Errorf("Failed to get klines - binance responded with %s", res.Status)
+	}
+
+	var klines []KlineDTO
+	if err := json.Unmarshal(body, &klines); err != nil {
+		return nil, time.Time{}, fmt.Errorf("Failed to parse klines - %w", err)
+	}
+
+	bars := make([]*candlestick.Candlestick, 0, len(klines))
+	for _, kline := range klines {
+		if kline.Trades == 0 {
+			continue
+		}
+		bar, err := kline.toCandlestick(symbol)
+		if err != nil {
+			return nil, time.Time{}, err
+		}
+		bars = append(bars, bar)
+	}
+
+	// fewer klines than the limit means none is left within the range
+	next := to.Add(time.Minute)
+	if len(klines) == MAX_KLINES {
+		next = time.UnixMilli(klines[len(klines)-1].OpenTime).UTC().Add(time.Minute)
+	}
+
+	return bars, next, nil
+}
+
+func (k *KlineDTO) toCandlestick(
+	symbol string,
+) (*candlestick.Candlestick, error) {
+	prices := make([]float64, 4)
+	for i, raw := range []string{k.Open, k.High, k.Low, k.Close} {
+		price, err := strconv.ParseFloat(raw, 64)
+		if err != nil {
+			return nil, fmt.Errorf("Failed to parse the kline opened at %d - %w", k.OpenTime, err)
+		}
+		prices[i] = price
+	}
+
+	return &candlestick.Candlestick{
+		Symbol:         symbol,
+		Open:           prices[0],
+		High:           prices[1],
+		Low:            prices[2],
+		Close:          prices[3],
+		TradeTimestamp: time.UnixMilli(k.OpenTime).UTC(),
+	}, nil
+}
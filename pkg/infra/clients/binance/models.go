@@ -1,5 +1,10 @@
 package binance
 
+import (
+	"encoding/json"
+	"fmt"
+)
+
 type TradeMessageDTO struct {
 	EventType          string `json:"e"`
 	EventTime          int64  `json:"E"`
@@ -27,3 +32,52 @@ type TradeMessageParsed struct {
 	IsBuyerMarketMaker bool    `json:"m"`
 	Ignore             bool    `json:"M"`
 }
+
+// StreamRequestDTO subscribes to or unsubscribes from streams of the live
+// connection, e.g. {"method": "SUBSCRIBE", "params": ["btcusdt@aggTrade"], "id": 1}
+type StreamRequestDTO struct {
+	Method string   `json:"method"`
+	Params []string `json:"params"`
+	ID     int64    `json:"id"`
+}
+
+// StreamResponseDTO answers the request of the same id, with an error code
+// and message if it failed
+type StreamResponseDTO struct {
+	ID   int64  `json:"id"`
+	Code int    `json:"code"`
+	Msg  string `json:"msg"`
+}
+
+// KlineDTO is a kline of the REST api, served as an array of
+// [open time, open, high, low, close, volume, close time, quote volume,
+// trades, taker buy volume, taker buy quote volume, ignore]
+type KlineDTO struct {
+	OpenTime int64
+	Open     string
+	High     string
+	Low      string
+	Close    string
+	Trades   int64
+}
+
+func (k *KlineDTO) UnmarshalJSON(data []byte) error {
+	var fields []json.RawMessage
+	if err := json.Unmarshal(data, &fields); err != nil {
+		return err
+	}
+	if len(fields) < 9 {
+		return fmt.Errorf("expected at least 9 fields, got %d", len(fields))
+	}
+
+	targets := []any{&k.OpenTime, &k.Open, &k.High, &k.Low, &k.Close}
+	for i, target := range targets {
+		if err := json.Unmarshal(fields[i], target); err != nil {
+			return fmt.Errorf("field %d is invalid - %w", i, err)
+		}
+	}
+	if err := json.Unmarshal(fields[8], &k.Trades); err != nil {
+		return fmt.Errorf("field 8 is invalid - %w", err)
+	}
+	return nil
+}
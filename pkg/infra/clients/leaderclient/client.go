@@ -0,0 +1,110 @@
+package leaderclient
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+
+	"github.com/ramasbeinaty/trading-chart-service/internal"
+	"github.com/ramasbeinaty/trading-chart-service/pkg/domain/replication"
+	"github.com/ramasbeinaty/trading-chart-service/pkg/domain/subscription"
+	candlestickpb "github.com/ramasbeinaty/trading-chart-service/proto/candlestick/contracts"
+	replicationpb "github.com/ramasbeinaty/trading-chart-service/proto/replication/contracts"
+	"go.opentelemetry.io/contrib/instrumentation/google.golang.org/grpc/otelgrpc"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/keepalive"
+	"google.golang.org/grpc/metadata"
+)
+
+// see middlewares.REPLICA_TOKEN_HEADER
+const REPLICA_TOKEN_HEADER = "x-replica-token"
+
+var eventKinds = map[candlestickpb.CandlestickEventKind]subscription.EventKind{
+	candlestickpb.CandlestickEventKind_CANDLESTICK_EVENT_KIND_LIVE:     subscription.EVENT_KIND_LIVE,
+	candlestickpb.CandlestickEventKind_CANDLESTICK_EVENT_KIND_SNAPSHOT: subscription.EVENT_KIND_SNAPSHOT,
+	candlestickpb.CandlestickEventKind_CANDLESTICK_EVENT_KIND_REPLAY:   subscription.EVENT_KIND_REPLAY,
+	candlestickpb.CandlestickEventKind_CANDLESTICK_EVENT_KIND_GAP:      subscription.EVENT_KIND_GAP,
+}
+
+// LeaderClient streams the bar updates of the leader over its internal
+// grpc service
+type LeaderClient struct {
+	creds     credentials.TransportCredentials
+	keepalive *internal.KeepaliveConfig
+	// presented to the leader if set, see auth.AuthConfig.ReplicaToken
+	replicaToken string
+}
+
+var _ replication.ILeaderClient = (*LeaderClient)(nil)
+
+func NewLeaderClient(
+	creds credentials.TransportCredentials,
+	keepalive *internal.KeepaliveConfig,
+	replicaToken string,
+) *LeaderClient {
+	return &LeaderClient{
+		creds:        creds,
+		keepalive:    keepalive,
+		replicaToken: replicaToken,
+	}
+}
+
+func (c *LeaderClient) StreamBarUpdates(
+	ctx context.Context,
+	address string,
+	follower string,
+	onUpdate func(event *subscription.CandlestickEvent),
+) error {
+	conn, err := grpc.NewClient(
+		address,
+		grpc.WithTransportCredentials(c.creds),
+		// detects a leader gone silent, pinging as often as the servers allow
+		grpc.WithKeepaliveParams(keepalive.ClientParameters{
+			Time:                max(c.keepalive.Time, c.keepalive.MinTime),
+			Timeout:             c.keepalive.Timeout,
+			PermitWithoutStream: c.keepalive.PermitWithoutStream,
+		}),
+		grpc.WithStatsHandler(otelgrpc.NewClientHandler()),
+	)
+	if err != nil {
+		return fmt.Errorf("Failed to connect to the leader at %s - %w", address, err)
+	}
+	defer conn.Close()
+
+	if c.replicaToken != "" {
+		ctx = metadata.AppendToOutgoingContext(ctx, REPLICA_TOKEN_HEADER, c.replicaToken)
+	}
+
+	stream, err := replicationpb.NewReplicationServiceClient(conn).StreamBarUpdates(
+		ctx,
+		&replicationpb.StreamBarUpdatesRequest{
+			Follower: follower,
+		},
+	)
+	if err != nil {
+		return fmt.Errorf("Failed to stream the bar updates of the leader - %w", err)
+	}
+
+	for {
+		bar, err := stream.Recv()
+		if errors.Is(err, io.EOF) {
+			return nil
+		}
+		if err != nil {
+			return fmt.Errorf("Failed to receive a bar update of the leader - %w", err)
+		}
+
+		onUpdate(&subscription.CandlestickEvent{
+			Symbol:         bar.Symbol,
+			Open:           bar.OpenPrice,
+			High:           bar.HighPrice,
+			Low:            bar.LowPrice,
+			Close:          bar.ClosePrice,
+			TradeTimestamp: bar.TradeTimestamp.AsTime(),
+			Sequence:       bar.Sequence,
+			Kind:           eventKinds[bar.Kind],
+		})
+	}
+}
@@ -0,0 +1,116 @@
+package natsbus
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+
+	"github.com/nats-io/nats.go"
+	"github.com/ramasbeinaty/trading-chart-service/pkg/domain/bus"
+	"github.com/ramasbeinaty/trading-chart-service/pkg/infra/tracing"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/propagation"
+	semconv "go.opentelemetry.io/otel/semconv/v1.26.0"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// NatsPublisher publishes the bus messages to nats
+// each message carries its outbox id as the Nats-Msg-Id header, letting
+// jetstream drop the duplicates of messages published again
+type NatsPublisher struct {
+	conn   *nats.Conn
+	js     nats.JetStreamContext
+	config *NatsConfig
+}
+
+var _ bus.IPublisher = (*NatsPublisher)(nil)
+
+var tracer = otel.Tracer("github.com/ramasbeinaty/trading-chart-service/pkg/infra/clients/natsbus")
+
+func NewNatsPublisher(
+	config *NatsConfig,
+) (*NatsPublisher, error) {
+	conn, err := nats.Connect(
+		config.URL,
+		nats.Name("trading-chart-service"),
+		nats.MaxReconnects(-1),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("Failed to connect to nats - %w", err)
+	}
+
+	p := &NatsPublisher{
+		conn:   conn,
+		config: config,
+	}
+
+	if config.Stream == "" {
+		return p, nil
+	}
+
+	p.js, err = conn.JetStream()
+	if err != nil {
+		conn.Close()
+		return nil, fmt.Errorf("Failed to get jetstream context - %w", err)
+	}
+
+	_, err = p.js.StreamInfo(config.Stream)
+	if errors.Is(err, nats.ErrStreamNotFound) {
+		_, err = p.js.AddStream(&nats.StreamConfig{
+			Name:     config.Stream,
+			Subjects: config.Subjects,
+		})
+	}
+	if err != nil {
+		conn.Close()
+		return nil, fmt.Errorf("Failed to ensure jetstream stream %s - %w", config.Stream, err)
+	}
+
+	return p, nil
+}
+
+func (p *NatsPublisher) Publish(
+	ctx context.Context,
+	message *bus.Message,
+) (err error) {
+	ctx, span := tracer.Start(
+		ctx,
+		"natsbus.Publish",
+		trace.WithSpanKind(trace.SpanKindProducer),
+		trace.WithAttributes(
+			semconv.MessagingSystemKey.String("nats"),
+			semconv.MessagingDestinationName(message.Subject),
+		),
+	)
+	defer func() { tracing.EndSpan(span, err) }()
+
+	msg := nats.NewMsg(message.Subject)
+	msg.Data = message.Payload
+	msg.Header.Set(nats.MsgIdHdr, strconv.FormatInt(message.ID, 10))
+	// the W3C trace context is sent along in the headers
+	otel.GetTextMapPropagator().Inject(ctx, propagation.HeaderCarrier(msg.Header))
+
+	if p.js != nil {
+		if _, err = p.js.PublishMsg(msg, nats.Context(ctx)); err != nil {
+			return fmt.Errorf("Failed to publish message %d to jetstream - %w", message.ID, err)
+		}
+		return nil
+	}
+
+	if err = p.conn.PublishMsg(msg); err != nil {
+		return fmt.Errorf("Failed to publish message %d to nats - %w", message.ID, err)
+	}
+	// waits for the server to have received it
+	if err = p.conn.FlushWithContext(ctx); err != nil {
+		return fmt.Errorf("Failed to flush message %d to nats - %w", message.ID, err)
+	}
+	return nil
+}
+
+func (p *NatsPublisher) Close() error {
+	if err := p.conn.Drain(); err != nil {
+		return fmt.Errorf("Failed to drain nats connection - %w", err)
+	}
+	return nil
+}
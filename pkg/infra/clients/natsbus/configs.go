@@ -0,0 +1,11 @@
+package natsbus
+
+type NatsConfig struct {
+	URL string
+	// messages are published through this jetstream stream, acknowledged once
+	// stored, if provided; otherwise they are published to core nats and
+	// only acknowledged once the server received them
+	Stream string
+	// subjects the stream is created with, if it does not exist
+	Subjects []string
+}
@@ -0,0 +1,94 @@
+package webhookclient
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/ramasbeinaty/trading-chart-service/pkg/domain/webhook"
+	"go.opentelemetry.io/contrib/instrumentation/net/http/otelhttp"
+)
+
+const (
+	HEADER_EVENT_ID  = "X-Webhook-Id"
+	HEADER_EVENT     = "X-Webhook-Event"
+	HEADER_TIMESTAMP = "X-Webhook-Timestamp"
+	// hex encoded HMAC-SHA256 of "<timestamp>.<body>" keyed by the target's
+	// secret, prefixed with "sha256="
+	HEADER_SIGNATURE = "X-Webhook-Signature"
+
+	REQUEST_TIMEOUT = 10 * time.Second
+)
+
+type WebhookClient struct {
+	httpClient *http.Client
+}
+
+var _ webhook.IClient = (*WebhookClient)(nil)
+
+func NewWebhookClient() *WebhookClient {
+	return &WebhookClient{
+		httpClient: &http.Client{
+			Timeout: REQUEST_TIMEOUT,
+			// spans every delivery and sends the W3C trace context along
+			Transport: otelhttp.NewTransport(http.DefaultTransport),
+		},
+	}
+}
+
+func (c *WebhookClient) Deliver(
+	ctx context.Context,
+	target webhook.Target,
+	delivery *webhook.Delivery,
+) error {
+	req, err := http.NewRequestWithContext(
+		ctx,
+		http.MethodPost,
+		target.URL,
+		bytes.NewReader(delivery.Payload),
+	)
+	if err != nil {
+		return fmt.Errorf("Failed to create webhook request - %w", err)
+	}
+
+	timestamp := time.Now().Unix()
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set(HEADER_EVENT_ID, delivery.EventID)
+	req.Header.Set(HEADER_EVENT, string(delivery.EventType))
+	req.Header.Set(HEADER_TIMESTAMP, strconv.FormatInt(timestamp, 10))
+	req.Header.Set(HEADER_SIGNATURE, Sign(target.Secret, timestamp, delivery.Payload))
+
+	res, err := c.httpClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("Failed to post webhook - %w", err)
+	}
+	defer res.Body.Close()
+	io.Copy(io.Discard, io.LimitReader(res.Body, 4096))
+
+	if res.StatusCode < 200 || res.StatusCode > 299 {
+		return fmt.Errorf("Failed to post webhook - target responded with %s", res.Status)
+	}
+
+	return nil
+}
+
+// Sign returns the signature of the body sent at the timestamp, as sent
+// in the HEADER_SIGNATURE header
+func Sign(
+	secret string,
+	timestamp int64,
+	body []byte,
+) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(strconv.FormatInt(timestamp, 10)))
+	mac.Write([]byte("."))
+	mac.Write(body)
+	return "sha256=" + hex.EncodeToString(mac.Sum(nil))
+}
@@ -0,0 +1,199 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"strings"
+	"sync"
+
+	"github.com/fsnotify/fsnotify"
+	"github.com/joho/godotenv"
+	"github.com/ramasbeinaty/trading-chart-service/internal"
+	"github.com/ramasbeinaty/trading-chart-service/pkg/domain/auth"
+	"github.com/ramasbeinaty/trading-chart-service/pkg/domain/base/logger"
+	"github.com/ramasbeinaty/trading-chart-service/pkg/domain/bus"
+	"github.com/ramasbeinaty/trading-chart-service/pkg/domain/candlestick"
+	"github.com/ramasbeinaty/trading-chart-service/pkg/domain/export"
+	"github.com/ramasbeinaty/trading-chart-service/pkg/domain/health"
+	"github.com/ramasbeinaty/trading-chart-service/pkg/domain/ratelimit"
+	"github.com/ramasbeinaty/trading-chart-service/pkg/domain/replication"
+	"github.com/ramasbeinaty/trading-chart-service/pkg/domain/webhook"
+	"github.com/ramasbeinaty/trading-chart-service/pkg/infra/clients/binance"
+	"github.com/ramasbeinaty/trading-chart-service/pkg/infra/clients/natsbus"
+	"github.com/ramasbeinaty/trading-chart-service/pkg/infra/clients/snowflake"
+	"github.com/ramasbeinaty/trading-chart-service/pkg/infra/db"
+	infralogger "github.com/ramasbeinaty/trading-chart-service/pkg/infra/logger"
+	"github.com/ramasbeinaty/trading-chart-service/pkg/infra/tracing"
+	"github.com/spf13/viper"
+	"go.uber.org/zap"
+)
+
+// env var of the config file, yaml or toml
+const CONFIG_FILE_ENV = "CONFIG_FILE"
+
+// Config is the whole configuration of the service
+type Config struct {
+	Env         *internal.EnvConfig
+	Log         *infralogger.LoggerConfig
+	Server      *internal.ServerConfig
+	Binance     *binance.BinanceConfig
+	DB          *db.DBConfigs
+	Snowflake   *snowflake.SnowflakeConfig
+	Candlestick *candlestick.CandlestickConfig
+	Webhook     *webhook.WebhookConfig
+	Bus         *bus.BusConfig
+	Nats        *natsbus.NatsConfig
+	Tracing     *tracing.TracingConfig
+	Health      *health.HealthConfig
+	Replication *replication.ReplicationConfig
+	Auth        *auth.AuthConfig
+	RateLimit   *ratelimit.RateLimitConfig
+	Export      *export.ExportConfig
+}
+
+// NewConfig loads the .env file if any, then the config file set by
+// CONFIG_FILE if any, the env vars overriding the values of the file
+func NewConfig() (*viper.Viper, error) {
+	if err := godotenv.Load(); err != nil && !errors.Is(err, fs.ErrNotExist) {
+		return nil, fmt.Errorf("Failed to load the .env file - %w", err)
+	}
+
+	return newViper(os.Getenv(CONFIG_FILE_ENV))
+}
+
+// the keys of the file are written as section.field, overridden by the
+// SECTION_FIELD env vars
+func newViper(
+	file string,
+) (*viper.Viper, error) {
+	v := viper.New()
+	v.SetEnvKeyReplacer(strings.NewReplacer(".", "_"))
+	v.AutomaticEnv()
+
+	if file == "" {
+		return v, nil
+	}
+
+	v.SetConfigFile(file)
+	if err := v.ReadInConfig(); err != nil {
+		return nil, fmt.Errorf("Failed to read the config file - %w", err)
+	}
+	// e.g. caught while being rewritten, which would reset every value
+	if len(v.AllKeys()) == 0 {
+		return nil, fmt.Errorf("Failed to read the config file - %s is empty", file)
+	}
+
+	return v, nil
+}
+
+// LoadConfig reads and validates the whole config, reporting every invalid
+// value at once
+func LoadConfig(
+	cfg *viper.Viper,
+) (*Config, error) {
+	var errs []error
+
+	c := &Config{
+		Env:         load(cfg, &errs, NewInternalEnvConfig),
+		Log:         load(cfg, &errs, NewLoggerConfig),
+		Server:      load(cfg, &errs, NewServerConfig),
+		Binance:     load(cfg, &errs, NewBinanceConfig),
+		DB:          load(cfg, &errs, NewDBConfig),
+		Snowflake:   load(cfg, &errs, NewSnowflakeConfig),
+		Candlestick: load(cfg, &errs, NewCandlestickConfig),
+		Webhook:     load(cfg, &errs, NewWebhookConfig),
+		Bus:         load(cfg, &errs, NewBusConfig),
+		Tracing:     load(cfg, &errs, NewTracingConfig),
+		Health:      load(cfg, &errs, NewHealthConfig),
+		Replication: load(cfg, &errs, NewReplicationConfig),
+		Auth:        load(cfg, &errs, NewAuthConfig),
+		RateLimit:   load(cfg, &errs, NewRateLimitConfig),
+		Export:      load(cfg, &errs, NewExportConfig),
+	}
+	if len(errs) == 0 {
+		errs = append(errs, c.validate()...)
+	}
+	if len(errs) != 0 {
+		return nil, fmt.Errorf("Invalid config - %w", errors.Join(errs...))
+	}
+	c.Nats = NewNatsConfig(cfg, c.Bus)
+
+	return c, nil
+}
+
+// validates the values depending on several sections
+func (c *Config) validate() []error {
+	var errs []error
+
+	// the replicas follow the leader once authenticated, by their client
+	// certificate or the replica token
+	if c.Auth.Enabled && c.Replication.Enabled && !c.Server.TLS.ClientAuth && c.Auth.ReplicaToken == "" {
+		errs = append(errs, fmt.Errorf(
+			"auth.replicatoken (AUTH_REPLICATOKEN) not provided - the replicas must authenticate with it unless server.tlsclientauth is set",
+		))
+	}
+
+	return errs
+}
+
+func load[T any](
+	cfg *viper.Viper,
+	errs *[]error,
+	newConfig func(cfg *viper.Viper) (T, error),
+) T {
+	c, err := newConfig(cfg)
+	if err != nil {
+		*errs = append(*errs, err)
+	}
+	return c
+}
+
+// WatchConfig reloads the whole config once the config file or the rate
+// limits file changes, passing it on if valid, the current one being kept
+// otherwise
+// does nothing without any of the files
+func WatchConfig(
+	cfg *viper.Viper,
+	lgrInstance logger.ILogger,
+	onChange func(c *Config),
+) {
+	file := cfg.ConfigFileUsed()
+
+	files := []string{}
+	if file != "" {
+		files = append(files, file)
+	}
+	if path := cfg.GetString("ratelimit.file"); path != "" {
+		files = append(files, path)
+	}
+
+	lgr := lgrInstance.Get(nil)
+	var mutex sync.Mutex
+	reload := func(e fsnotify.Event) {
+		mutex.Lock()
+		defer mutex.Unlock()
+
+		v, err := newViper(file)
+		if err != nil {
+			lgr.Error("Failed to reload the config, keeping the current one", zap.Error(err))
+			return
+		}
+		c, err := LoadConfig(v)
+		if err != nil {
+			lgr.Error("Failed to reload the config, keeping the current one", zap.Error(err))
+			return
+		}
+
+		lgr.Info("Reloaded the config", zap.String("file", e.Name))
+		onChange(c)
+	}
+
+	for _, f := range files {
+		w := viper.New()
+		w.SetConfigFile(f)
+		w.OnConfigChange(reload)
+		w.WatchConfig()
+	}
+}
@@ -0,0 +1,171 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/ramasbeinaty/trading-chart-service/internal/testutil"
+)
+
+const yamlConfig = `
+log:
+  level: debug
+server:
+  httpport: "8080"
+binance:
+  baseendpoint: stream.binance.com:9443
+  symbols: [btcusdt, ETHUSDT, btcusdt]
+db:
+  host: localhost
+  port: "5432"
+  user: postgres
+  password: postgres
+  dbname: candles
+candlestick:
+  checkpointinterval: 10s
+`
+
+const tomlConfig = `
+[log]
+level = "debug"
+
+[server]
+httpport = "8080"
+
+[binance]
+baseendpoint = "stream.binance.com:9443"
+symbols = ["btcusdt", "ETHUSDT", "btcusdt"]
+
+[db]
+host = "localhost"
+port = "5432"
+user = "postgres"
+password = "postgres"
+dbname = "candles"
+
+[candlestick]
+checkpointinterval = "10s"
+`
+
+func writeFile(t *testing.T, path string, content string) {
+	t.Helper()
+	if err := os.WriteFile(path, []byte(content), 0o600); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestLoadConfigReadsTheFile(t *testing.T) {
+	cases := []struct {
+		file    string
+		content string
+	}{
+		{file: "config.yaml", content: yamlConfig},
+		{file: "config.toml", content: tomlConfig},
+	}
+
+	for _, c := range cases {
+		t.Run(c.file, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), c.file)
+			writeFile(t, path, c.content)
+			t.Setenv("DB_HOST", "postgres.internal")
+
+			v, err := newViper(path)
+			if err != nil {
+				t.Fatal(err)
+			}
+			config, err := LoadConfig(v)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if config.Log.Level != "debug" {
+				t.Errorf("expected the log level of the file, got %s", config.Log.Level)
+			}
+			if strings.Join(config.Binance.Symbols, ",") != "btcusdt,ethusdt" {
+				t.Errorf("expected the symbols deduplicated and lowercased, got %v", config.Binance.Symbols)
+			}
+			if config.Candlestick.CheckpointInterval != 10*time.Second {
+				t.Errorf("expected the checkpoint interval of the file, got %s", config.Candlestick.CheckpointInterval)
+			}
+			if config.DB.Host != "postgres.internal" {
+				t.Errorf("expected the env var to override the file, got %s", config.DB.Host)
+			}
+		})
+	}
+}
+
+func TestLoadConfigReportsEveryError(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "config.yaml")
+	writeFile(t, path, yamlConfig)
+	t.Setenv("LOG_LEVEL", "verbose")
+	t.Setenv("BINANCE_SYMBOLS", "btc-usdt")
+	t.Setenv("SERVER_MAXRECVMSGSIZE", "large")
+
+	v, err := newViper(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, err = LoadConfig(v)
+	if err == nil {
+		t.Fatal("expected the config to be invalid")
+	}
+	for _, expected := range []string{
+		"log.level (LOG_LEVEL) is invalid",
+		`binance symbol "btc-usdt" is invalid`,
+		"server.maxrecvmsgsize (SERVER_MAXRECVMSGSIZE) is invalid",
+	} {
+		if !strings.Contains(err.Error(), expected) {
+			t.Errorf("expected %q to be reported, got %v", expected, err)
+		}
+	}
+}
+
+func TestLoadConfigValidatesAcrossTheSections(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "config.yaml")
+	writeFile(t, path, yamlConfig)
+	t.Setenv("AUTH_APIKEYS", `[{"name": "desk-1", "key": "secret", "symbols": ["*"]}]`)
+	t.Setenv("REPLICATION_ADVERTISEADDRESS", "replica-1:50051")
+
+	v, err := newViper(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := LoadConfig(v); err == nil || !strings.Contains(err.Error(), "auth.replicatoken (AUTH_REPLICATOKEN) not provided") {
+		t.Fatalf("expected the replicas to require a token, got %v", err)
+	}
+}
+
+func TestWatchConfigReloadsTheValidChanges(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "config.yaml")
+	writeFile(t, path, yamlConfig)
+
+	v, err := newViper(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	reloaded := make(chan *Config, 10)
+	WatchConfig(v, testutil.NopLogger{}, func(c *Config) {
+		reloaded <- c
+	})
+
+	// the current config is kept
+	writeFile(t, path, strings.Replace(yamlConfig, "level: debug", "level: verbose", 1))
+	select {
+	case c := <-reloaded:
+		t.Fatalf("expected the invalid config to be rejected, got the log level %s", c.Log.Level)
+	case <-time.After(300 * time.Millisecond):
+	}
+
+	writeFile(t, path, strings.Replace(yamlConfig, "level: debug", "level: warn", 1))
+	select {
+	case c := <-reloaded:
+		if c.Log.Level != "warn" {
+			t.Fatalf("expected the reloaded log level, got %s", c.Log.Level)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("expected the config to be reloaded")
+	}
+}
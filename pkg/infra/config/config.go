@@ -1,87 +1,399 @@
 package config
 
 import (
-	"fmt"
+	"net/url"
+	"strings"
 
-	"github.com/joho/godotenv"
 	"github.com/ramasbeinaty/trading-chart-service/internal"
+	"github.com/ramasbeinaty/trading-chart-service/pkg/domain/auth"
+	"github.com/ramasbeinaty/trading-chart-service/pkg/domain/bus"
+	"github.com/ramasbeinaty/trading-chart-service/pkg/domain/candlestick"
+	"github.com/ramasbeinaty/trading-chart-service/pkg/domain/export"
+	"github.com/ramasbeinaty/trading-chart-service/pkg/domain/health"
+	"github.com/ramasbeinaty/trading-chart-service/pkg/domain/ratelimit"
+	"github.com/ramasbeinaty/trading-chart-service/pkg/domain/replication"
+	"github.com/ramasbeinaty/trading-chart-service/pkg/domain/webhook"
 	"github.com/ramasbeinaty/trading-chart-service/pkg/infra/clients/binance"
+	"github.com/ramasbeinaty/trading-chart-service/pkg/infra/clients/natsbus"
 	"github.com/ramasbeinaty/trading-chart-service/pkg/infra/clients/snowflake"
 	"github.com/ramasbeinaty/trading-chart-service/pkg/infra/db"
+	infralogger "github.com/ramasbeinaty/trading-chart-service/pkg/infra/logger"
+	"github.com/ramasbeinaty/trading-chart-service/pkg/infra/tracing"
+	"github.com/spf13/cast"
 	"github.com/spf13/viper"
+	"go.uber.org/zap/zapcore"
 )
 
 func NewInternalEnvConfig(
 	cfg *viper.Viper,
-) *internal.EnvConfig {
+) (*internal.EnvConfig, error) {
+	r := newReader(cfg)
 	c := internal.EnvConfig{
-		IsDevMode: cfg.GetBool("ENV_ISDEVMODE"),
+		IsDevMode: r.bool("env.isdevmode", false),
 	}
-	return &c
+	return &c, r.err()
+}
+
+func NewLoggerConfig(
+	cfg *viper.Viper,
+) (*infralogger.LoggerConfig, error) {
+	r := newReader(cfg)
+	c := &infralogger.LoggerConfig{
+		Level: r.string("log.level"),
+	}
+	if c.Level == "" {
+		c.Level = infralogger.DEFAULT_LEVEL
+	}
+	if _, err := zapcore.ParseLevel(c.Level); err != nil {
+		r.fail("%s is invalid - %w", name("log.level"), err)
+	}
+	return c, r.err()
+}
+
+// reflection is only on by default in dev mode, as it lists the whole api
+func NewServerConfig(
+	cfg *viper.Viper,
+) (*internal.ServerConfig, error) {
+	r := newReader(cfg)
+	// an invalid value is reported with the env section
+	isDevMode, _ := cast.ToBoolE(cfg.Get("env.isdevmode"))
+	c := &internal.ServerConfig{
+		HttpPort:    r.required("server.httpport"),
+		GrpcAddress: r.string("server.grpcaddress"),
+		TLS: internal.TLSConfig{
+			CertFile:   r.string("server.tlscertfile"),
+			KeyFile:    r.string("server.tlskeyfile"),
+			CAFile:     r.string("server.tlscafile"),
+			ClientAuth: r.bool("server.tlsclientauth", false),
+			ServerName: r.string("server.tlsservername"),
+		},
+		Keepalive: internal.KeepaliveConfig{
+			Time:                  r.duration("server.keepalivetime", internal.DEFAULT_KEEPALIVE_TIME),
+			Timeout:               r.duration("server.keepalivetimeout", internal.DEFAULT_KEEPALIVE_TIMEOUT),
+			MaxConnectionIdle:     r.duration("server.keepalivemaxconnectionidle", 0),
+			MaxConnectionAge:      r.duration("server.keepalivemaxconnectionage", 0),
+			MaxConnectionAgeGrace: r.duration("server.keepalivemaxconnectionagegrace", 0),
+			MinTime:               r.duration("server.keepalivemintime", internal.DEFAULT_KEEPALIVE_MIN_TIME),
+			PermitWithoutStream:   r.bool("server.keepalivepermitwithoutstream", true),
+		},
+		MaxConcurrentStreams: r.uint32("server.maxconcurrentstreams", internal.DEFAULT_MAX_CONCURRENT_STREAMS),
+		MaxRecvMsgSize:       r.int("server.maxrecvmsgsize", internal.DEFAULT_MAX_RECV_MSG_SIZE),
+		MaxSendMsgSize:       r.int("server.maxsendmsgsize", internal.DEFAULT_MAX_SEND_MSG_SIZE),
+		Reflection:           r.bool("server.reflection", isDevMode),
+		AllowedOrigins:       r.list("server.allowedorigins", nil),
+	}
+	if c.GrpcAddress == "" {
+		c.GrpcAddress = internal.DEFAULT_GRPC_ADDRESS
+	}
+
+	if (c.TLS.CertFile == "") != (c.TLS.KeyFile == "") {
+		r.fail("server tls certificate and key must be provided together")
+	}
+	if c.TLS.ClientAuth && (!c.TLS.Enabled() || c.TLS.CAFile == "") {
+		r.fail("server tls client auth requires a certificate and a ca")
+	}
+	if c.Keepalive.Time <= 0 || c.Keepalive.Timeout <= 0 || c.Keepalive.MinTime < 0 {
+		r.fail("server keepalive is invalid - %+v", c.Keepalive)
+	}
+	for _, origin := range c.AllowedOrigins {
+		if origin == "*" {
+			continue
+		}
+		if u, err := url.Parse(origin); err != nil || u.Scheme == "" || u.Host == "" || u.Path != "" {
+			r.fail("server allowed origin %q is invalid - expected a scheme and a host, e.g. https://trading.example.com", origin)
+		}
+	}
+	if c.MaxConcurrentStreams == 0 || c.MaxRecvMsgSize <= 0 || c.MaxSendMsgSize <= 0 {
+		r.fail(
+			"server limits must be positive - streams %d, recv %d, send %d",
+			c.MaxConcurrentStreams,
+			c.MaxRecvMsgSize,
+			c.MaxSendMsgSize,
+		)
+	}
+
+	return c, r.err()
 }
 
 func NewSnowflakeConfig(
 	cfg *viper.Viper,
-) *snowflake.SnowflakeConfig {
+) (*snowflake.SnowflakeConfig, error) {
+	r := newReader(cfg)
 	c := snowflake.SnowflakeConfig{
-		NodeNumber: cfg.GetInt64("SNOWFLAKE_NODENUMBER"),
+		NodeNumber: r.int64("snowflake.nodenumber", 0),
 	}
-	return &c
+	return &c, r.err()
 }
 
+// the symbols are a list in the config file, or a comma separated env var,
+// e.g. BINANCE_SYMBOLS=btcusdt,ethusdt
 func NewBinanceConfig(
 	cfg *viper.Viper,
-) *binance.BinanceConfig {
+) (*binance.BinanceConfig, error) {
+	r := newReader(cfg)
 	c := &binance.BinanceConfig{
-		BaseEndpoint: cfg.GetString("BINANCE_BASEENDPOINT"),
+		BaseEndpoint: r.required("binance.baseendpoint"),
+		RestEndpoint: r.string("binance.restendpoint"),
+		Symbols:      []string{},
+	}
+
+	if c.RestEndpoint == "" {
+		c.RestEndpoint = binance.DEFAULT_REST_ENDPOINT
 	}
-	if c.BaseEndpoint == "" {
-		panic("binance base endpoint not provided")
+	if u, err := url.Parse(c.RestEndpoint); err != nil || u.Scheme == "" || u.Host == "" {
+		r.fail("%s must be an absolute url, got %q", name("binance.restendpoint"), c.RestEndpoint)
 	}
-	return c
+
+	seen := map[string]bool{}
+	for _, symbol := range r.list("binance.symbols", internal.TRADE_SYMBOLS) {
+		symbol = strings.ToLower(symbol)
+		if !binance.IsValidSymbol(symbol) {
+			r.fail("binance symbol %q is invalid - expected letters and digits only", symbol)
+			continue
+		}
+		if !seen[symbol] {
+			seen[symbol] = true
+			c.Symbols = append(c.Symbols, symbol)
+		}
+	}
+	if len(c.Symbols) == 0 {
+		r.fail("%s must not be empty", name("binance.symbols"))
+	}
+
+	return c, r.err()
 }
 
 func NewDBConfig(
 	cfg *viper.Viper,
-) *db.DBConfigs {
+) (*db.DBConfigs, error) {
+	r := newReader(cfg)
 	c := &db.DBConfigs{
-		Host:     cfg.GetString("DB_HOST"),
-		Port:     cfg.GetString("DB_PORT"),
-		User:     cfg.GetString("DB_USER"),
-		Password: cfg.GetString("DB_PASSWORD"),
-		DBName:   cfg.GetString("DB_DBNAME"),
+		Host:     r.required("db.host"),
+		Port:     r.required("db.port"),
+		User:     r.required("db.user"),
+		Password: r.required("db.password"),
+		DBName:   r.required("db.dbname"),
+		ReadOnly: r.bool("db.readonly", false),
+	}
+	return c, r.err()
+}
+
+func NewCandlestickConfig(
+	cfg *viper.Viper,
+) (*candlestick.CandlestickConfig, error) {
+	r := newReader(cfg)
+	c := &candlestick.CandlestickConfig{
+		CheckpointInterval: r.duration(
+			"candlestick.checkpointinterval",
+			candlestick.DEFAULT_CHECKPOINT_INTERVAL,
+		),
+	}
+	if c.CheckpointInterval <= 0 {
+		r.fail("%s must be positive, got %s", name("candlestick.checkpointinterval"), c.CheckpointInterval)
+	}
+	return c, r.err()
+}
+
+// targets are optional, given as a list in the config file or a json array
+// env var, e.g.
+// [{"name": "analytics", "url": "https://...", "secret": "...", "symbols": ["BTCUSDT"], "events": ["bar.closed"]}]
+func NewWebhookConfig(
+	cfg *viper.Viper,
+) (*webhook.WebhookConfig, error) {
+	r := newReader(cfg)
+	c := &webhook.WebhookConfig{
+		Targets: []webhook.Target{},
+	}
+	r.json("webhook.targets", &c.Targets)
+
+	names := map[string]bool{}
+	for _, t := range c.Targets {
+		if err := t.Validate(); err != nil {
+			r.errs = append(r.errs, err)
+		}
+		if names[t.Name] {
+			r.fail("webhook target %s is provided more than once", t.Name)
+		}
+		names[t.Name] = true
+	}
+
+	return c, r.err()
+}
+
+func NewBusConfig(
+	cfg *viper.Viper,
+) (*bus.BusConfig, error) {
+	r := newReader(cfg)
+	c := &bus.BusConfig{
+		Enabled:       r.string("bus.natsurl") != "",
+		SubjectPrefix: r.string("bus.subjectprefix"),
 	}
-	if c.Host == "" {
-		panic("db host not provided")
+	if c.SubjectPrefix == "" {
+		c.SubjectPrefix = "candlestick"
 	}
-	if c.Port == "" {
-		panic("db port not provided")
+
+	return c, r.err()
+}
+
+func NewNatsConfig(
+	cfg *viper.Viper,
+	busConfig *bus.BusConfig,
+) *natsbus.NatsConfig {
+	return &natsbus.NatsConfig{
+		URL:      cfg.GetString("bus.natsurl"),
+		Stream:   cfg.GetString("bus.stream"),
+		Subjects: []string{busConfig.SubjectPrefix + ".>"},
+	}
+}
+
+func NewTracingConfig(
+	cfg *viper.Viper,
+) (*tracing.TracingConfig, error) {
+	r := newReader(cfg)
+	c := &tracing.TracingConfig{
+		OtlpEndpoint: r.string("tracing.otlpendpoint"),
+		Insecure:     r.bool("tracing.insecure", false),
+		SampleRatio:  r.float64("tracing.sampleratio", 1),
+	}
+
+	if c.SampleRatio < 0 || c.SampleRatio > 1 {
+		r.fail("tracing sample ratio must be between 0 and 1, got %v", c.SampleRatio)
 	}
-	if c.User == "" {
-		panic("db user not provided")
+
+	return c, r.err()
+}
+
+func NewHealthConfig(
+	cfg *viper.Viper,
+) (*health.HealthConfig, error) {
+	r := newReader(cfg)
+	c := &health.HealthConfig{
+		MaxTradeAge:      r.duration("health.maxtradeage", health.DEFAULT_MAX_TRADE_AGE),
+		MaxCommitBacklog: r.int("health.maxcommitbacklog", health.DEFAULT_MAX_COMMIT_BACKLOG),
+	}
+
+	if c.MaxTradeAge <= 0 || c.MaxCommitBacklog < 0 {
+		r.fail("health thresholds are invalid - %+v", c)
+	}
+
+	return c, r.err()
+}
+
+func NewExportConfig(
+	cfg *viper.Viper,
+) (*export.ExportConfig, error) {
+	r := newReader(cfg)
+	c := &export.ExportConfig{
+		MaxRange:      r.duration("export.maxrange", export.DEFAULT_MAX_RANGE),
+		MaxConcurrent: r.int("export.maxconcurrent", export.DEFAULT_MAX_CONCURRENT),
 	}
-	if c.Password == "" {
-		panic("db password not provided")
+
+	if c.MaxRange <= 0 || c.MaxConcurrent <= 0 {
+		r.fail("export limits are invalid - %+v", c)
 	}
-	if c.DBName == "" {
-		panic("db name not provided")
+
+	return c, r.err()
+}
+
+// replication is enabled once the address the other replicas reach this one
+// on is set
+func NewReplicationConfig(
+	cfg *viper.Viper,
+) (*replication.ReplicationConfig, error) {
+	r := newReader(cfg)
+	c := &replication.ReplicationConfig{
+		AdvertiseAddress: r.string("replication.advertiseaddress"),
+		LockKey:          r.int64("replication.lockkey", replication.DEFAULT_LOCK_KEY),
 	}
-	return c
+	c.Enabled = c.AdvertiseAddress != ""
+
+	return c, r.err()
 }
 
-func InitializeConfig() error {
-	if err := godotenv.Load(); err != nil {
-		return fmt.Errorf("Error loading .env file - %w", err)
+// api keys are given as a list in the config file or a json array env var, e.g.
+// [{"name": "desk-1", "key": "...", "symbols": ["BTC*", "ETHUSDT"]}]
+// admins as a list of principals, or a comma separated env var,
+// e.g. "api_key:ops,jwt:alice"
+// authentication is enabled once api keys or a jwks are provided
+func NewAuthConfig(
+	cfg *viper.Viper,
+) (*auth.AuthConfig, error) {
+	r := newReader(cfg)
+	c := &auth.AuthConfig{
+		APIKeys: []*auth.APIKey{},
+		JWT: auth.JWTConfig{
+			JWKSFile:     r.string("auth.jwksfile"),
+			Issuer:       r.string("auth.jwtissuer"),
+			Audience:     r.string("auth.jwtaudience"),
+			SymbolsClaim: r.string("auth.jwtsymbolsclaim"),
+		},
+	}
+	c.JWT.Enabled = c.JWT.JWKSFile != ""
+	if c.JWT.SymbolsClaim == "" {
+		c.JWT.SymbolsClaim = auth.DEFAULT_SYMBOLS_CLAIM
+	}
+
+	r.json("auth.apikeys", &c.APIKeys)
+
+	names := map[string]bool{}
+	keys := map[string]bool{}
+	for _, k := range c.APIKeys {
+		if err := k.Validate(); err != nil {
+			r.errs = append(r.errs, err)
+		}
+		if names[k.Name] || keys[k.Key] {
+			r.fail("api key %s is provided more than once", k.Name)
+		}
+		names[k.Name] = true
+		keys[k.Key] = true
 	}
 
-	viper.AutomaticEnv()
-	return nil
+	for _, admin := range r.list("auth.admins", nil) {
+		method, id, _ := strings.Cut(admin, ":")
+		switch auth.Method(method) {
+		case auth.METHOD_API_KEY, auth.METHOD_JWT:
+		default:
+			id = ""
+		}
+		if id == "" {
+			r.fail("admin %q is invalid - expected api_key:NAME or jwt:SUBJECT", admin)
+			continue
+		}
+		c.Admins = append(c.Admins, admin)
+	}
+
+	c.Enabled = len(c.APIKeys) != 0 || c.JWT.Enabled
+	c.ReplicaToken = r.string("auth.replicatoken")
+
+	return c, r.err()
 }
 
-func NewConfig() *viper.Viper {
-	if err := InitializeConfig(); err != nil {
-		panic(err)
+// the limits of the rate limits file if set, over the ones of the config
+func NewRateLimitConfig(
+	cfg *viper.Viper,
+) (*ratelimit.RateLimitConfig, error) {
+	r := newReader(cfg)
+	base := ratelimit.Limits{
+		UnaryRate:  r.float64("ratelimit.unaryrate", ratelimit.DEFAULT_UNARY_RATE),
+		UnaryBurst: r.int("ratelimit.unaryburst", ratelimit.DEFAULT_UNARY_BURST),
+		MaxStreams: r.int("ratelimit.maxstreams", ratelimit.DEFAULT_MAX_STREAMS),
+		MaxSymbols: r.int("ratelimit.maxsymbols", ratelimit.DEFAULT_MAX_SYMBOLS),
+	}
+
+	if path := r.string("ratelimit.file"); path != "" {
+		c, err := loadRateLimitFile(path, base)
+		if err != nil {
+			r.errs = append(r.errs, err)
+		}
+		return c, r.err()
 	}
 
-	return viper.GetViper()
+	c := &ratelimit.RateLimitConfig{
+		Default:   base,
+		Overrides: map[string]ratelimit.Limits{},
+	}
+	if err := c.Validate(); err != nil {
+		r.errs = append(r.errs, err)
+	}
+	return c, r.err()
 }
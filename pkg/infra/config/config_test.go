@@ -0,0 +1,88 @@
+package config
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/spf13/viper"
+)
+
+func TestNewServerConfig(t *testing.T) {
+	cases := []struct {
+		name       string
+		values     map[string]any
+		err        string
+		reflection bool
+	}{
+		{
+			name:   "defaults",
+			values: map[string]any{},
+		},
+		{
+			name:       "reflection in dev mode",
+			values:     map[string]any{"env.isdevmode": "true"},
+			reflection: true,
+		},
+		{
+			name:       "reflection enabled",
+			values:     map[string]any{"server.reflection": "true"},
+			reflection: true,
+		},
+		{
+			name:   "reflection disabled in dev mode",
+			values: map[string]any{"env.isdevmode": "true", "server.reflection": "false"},
+		},
+		{
+			name:   "certificate without its key",
+			values: map[string]any{"server.tlscertfile": "server.pem"},
+			err:    "server tls certificate and key must be provided together",
+		},
+		{
+			name:   "client auth without a ca",
+			values: map[string]any{"server.tlscertfile": "server.pem", "server.tlskeyfile": "server.key", "server.tlsclientauth": "true"},
+			err:    "server tls client auth requires a certificate and a ca",
+		},
+		{
+			name:   "keepalive without a timeout",
+			values: map[string]any{"server.keepalivetimeout": "0s"},
+			err:    "server keepalive is invalid",
+		},
+		{
+			name:   "origin without a scheme",
+			values: map[string]any{"server.allowedorigins": "https://trading.example.com,trading.example.com"},
+			err:    `server allowed origin "trading.example.com" is invalid`,
+		},
+		{
+			name:   "limit off",
+			values: map[string]any{"server.maxrecvmsgsize": "0"},
+			err:    "server limits must be positive",
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			v := viper.New()
+			v.Set("server.httpport", "8080")
+			for key, value := range c.values {
+				v.Set(key, value)
+			}
+
+			config, err := NewServerConfig(v)
+			if c.err != "" {
+				if err == nil || !strings.Contains(err.Error(), c.err) {
+					t.Fatalf("expected %q, got %v", c.err, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if config.Reflection != c.reflection {
+				t.Errorf("expected the reflection %v, got %v", c.reflection, config.Reflection)
+			}
+			if config.GrpcAddress != ":50051" {
+				t.Errorf("expected the default grpc address, got %s", config.GrpcAddress)
+			}
+		})
+	}
+}
@@ -0,0 +1,83 @@
+package config
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/ramasbeinaty/trading-chart-service/pkg/domain/ratelimit"
+	"github.com/spf13/viper"
+)
+
+// the limits file, yaml or json, e.g.
+//
+//	default:
+//	  unaryRate: 20
+//	  maxStreams: 16
+//	overrides:
+//	  api_key:desk-1:
+//	    maxStreams: 100
+//
+// the limits left out of the default are the env ones, and the ones left
+// out of an override are the default ones, each override being keyed by
+// the client, i.e. api_key:<name>, jwt:<subject> or the peer ip
+type rateLimitFile struct {
+	Default   limitsFile            `mapstructure:"default"`
+	Overrides map[string]limitsFile `mapstructure:"overrides"`
+}
+
+type limitsFile struct {
+	UnaryRate  *float64 `mapstructure:"unaryRate"`
+	UnaryBurst *int     `mapstructure:"unaryBurst"`
+	MaxStreams *int     `mapstructure:"maxStreams"`
+	MaxSymbols *int     `mapstructure:"maxSymbols"`
+}
+
+func (f *limitsFile) over(base ratelimit.Limits) ratelimit.Limits {
+	if f.UnaryRate != nil {
+		base.UnaryRate = *f.UnaryRate
+	}
+	if f.UnaryBurst != nil {
+		base.UnaryBurst = *f.UnaryBurst
+	}
+	if f.MaxStreams != nil {
+		base.MaxStreams = *f.MaxStreams
+	}
+	if f.MaxSymbols != nil {
+		base.MaxSymbols = *f.MaxSymbols
+	}
+	return base
+}
+
+func loadRateLimitFile(
+	path string,
+	base ratelimit.Limits,
+) (*ratelimit.RateLimitConfig, error) {
+	// the overrides are keyed by ips, which the default "." would split
+	v := viper.NewWithOptions(viper.KeyDelimiter("/"))
+	v.SetConfigFile(path)
+	if err := v.ReadInConfig(); err != nil {
+		return nil, fmt.Errorf("Failed to read the rate limits file - %w", err)
+	}
+	// e.g. caught while being rewritten, which would reset every limit
+	if len(v.AllKeys()) == 0 {
+		return nil, fmt.Errorf("Failed to read the rate limits file - %s is empty", path)
+	}
+
+	var f rateLimitFile
+	if err := v.Unmarshal(&f); err != nil {
+		return nil, fmt.Errorf("Failed to parse the rate limits file - %w", err)
+	}
+
+	c := &ratelimit.RateLimitConfig{
+		Default:   f.Default.over(base),
+		Overrides: make(map[string]ratelimit.Limits, len(f.Overrides)),
+	}
+	for client, limits := range f.Overrides {
+		c.Overrides[strings.ToLower(client)] = limits.over(c.Default)
+	}
+
+	if err := c.Validate(); err != nil {
+		return nil, err
+	}
+	return c, nil
+}
@@ -0,0 +1,44 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/ramasbeinaty/trading-chart-service/pkg/domain/ratelimit"
+)
+
+func TestLoadRateLimitFileKeysTheOverridesByClient(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "limits.yaml")
+	content := `
+default:
+  maxStreams: 16
+overrides:
+  api_key:Desk-1:
+    maxStreams: 100
+  10.0.0.5:
+    unaryRate: 1
+    unaryBurst: 1
+`
+	if err := os.WriteFile(path, []byte(content), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	c, err := loadRateLimitFile(path, ratelimit.Limits{UnaryRate: 20, UnaryBurst: 40})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected := map[string]ratelimit.Limits{
+		"api_key:desk-1": {UnaryRate: 20, UnaryBurst: 40, MaxStreams: 100},
+		"10.0.0.5":       {UnaryRate: 1, UnaryBurst: 1, MaxStreams: 16},
+	}
+	if len(c.Overrides) != len(expected) {
+		t.Fatalf("expected the overrides %v, got %v", expected, c.Overrides)
+	}
+	for client, limits := range expected {
+		if c.Overrides[client] != limits {
+			t.Errorf("expected %+v for %s, got %+v", limits, client, c.Overrides[client])
+		}
+	}
+}
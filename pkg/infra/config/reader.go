@@ -0,0 +1,140 @@
+package config
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/spf13/cast"
+	"github.com/spf13/viper"
+)
+
+// reader reads the typed values of the config, collecting the errors of the
+// invalid ones for them to be reported at once
+// keys are written as section.field, e.g. server.httpport, and are overridden
+// by their env var, e.g. SERVER_HTTPPORT
+type reader struct {
+	cfg  *viper.Viper
+	errs []error
+}
+
+func newReader(
+	cfg *viper.Viper,
+) *reader {
+	return &reader{cfg: cfg}
+}
+
+// fail records an invalid value of the config
+func (r *reader) fail(format string, args ...any) {
+	r.errs = append(r.errs, fmt.Errorf(format, args...))
+}
+
+func (r *reader) err() error {
+	return errors.Join(r.errs...)
+}
+
+func (r *reader) string(key string) string {
+	return r.cfg.GetString(key)
+}
+
+// fails if the value is empty
+func (r *reader) required(key string) string {
+	value := r.cfg.GetString(key)
+	if value == "" {
+		r.fail("%s not provided", name(key))
+	}
+	return value
+}
+
+func (r *reader) bool(key string, def bool) bool {
+	return read(r, key, def, cast.ToBoolE)
+}
+
+func (r *reader) int(key string, def int) int {
+	return read(r, key, def, cast.ToIntE)
+}
+
+func (r *reader) int64(key string, def int64) int64 {
+	return read(r, key, def, cast.ToInt64E)
+}
+
+func (r *reader) uint32(key string, def uint32) uint32 {
+	return read(r, key, def, cast.ToUint32E)
+}
+
+func (r *reader) float64(key string, def float64) float64 {
+	return read(r, key, def, cast.ToFloat64E)
+}
+
+func (r *reader) duration(key string, def time.Duration) time.Duration {
+	return read(r, key, def, cast.ToDurationE)
+}
+
+// a list of the config file, or a comma separated env var
+func (r *reader) list(key string, def []string) []string {
+	return read(r, key, def, func(value any) ([]string, error) {
+		raw, ok := value.(string)
+		if !ok {
+			return cast.ToStringSliceE(value)
+		}
+
+		list := []string{}
+		for _, item := range strings.Split(raw, ",") {
+			if item = strings.TrimSpace(item); item != "" {
+				list = append(list, item)
+			}
+		}
+		return list, nil
+	})
+}
+
+// decodes a structured value of the config file, or a json env var, into out
+// with its json tags, leaving out untouched if the value isn't set
+func (r *reader) json(key string, out any) {
+	if !r.cfg.IsSet(key) {
+		return
+	}
+
+	data, ok := r.cfg.Get(key).(string)
+	if !ok {
+		encoded, err := json.Marshal(r.cfg.Get(key))
+		if err != nil {
+			r.fail("%s is invalid - %w", name(key), err)
+			return
+		}
+		data = string(encoded)
+	}
+	if data == "" {
+		return
+	}
+
+	if err := json.Unmarshal([]byte(data), out); err != nil {
+		r.fail("%s is invalid - %w", name(key), err)
+	}
+}
+
+// returns the default if the value isn't set or is invalid
+func read[T any](
+	r *reader,
+	key string,
+	def T,
+	parse func(value any) (T, error),
+) T {
+	if !r.cfg.IsSet(key) {
+		return def
+	}
+
+	value, err := parse(r.cfg.Get(key))
+	if err != nil {
+		r.fail("%s is invalid - %w", name(key), err)
+		return def
+	}
+	return value
+}
+
+// the key along with its env var, e.g. server.httpport (SERVER_HTTPPORT)
+func name(key string) string {
+	return fmt.Sprintf("%s (%s)", key, strings.ToUpper(strings.ReplaceAll(key, ".", "_")))
+}
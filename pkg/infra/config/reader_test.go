@@ -0,0 +1,106 @@
+package config
+
+import (
+	"slices"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/spf13/viper"
+)
+
+func TestReaderReadsTheTypedValues(t *testing.T) {
+	v := viper.New()
+	v.Set("server.reflection", "false")
+	v.Set("server.maxrecvmsgsize", "1024")
+	v.Set("candlestick.checkpointinterval", "10s")
+	v.Set("binance.symbols", " btcusdt, ,ethusdt ")
+	v.Set("auth.admins", []any{"api_key:ops", "jwt:alice"})
+
+	r := newReader(v)
+	if r.bool("server.reflection", true) {
+		t.Error("expected the bool to be read")
+	}
+	if got := r.int("server.maxrecvmsgsize", 0); got != 1024 {
+		t.Errorf("expected 1024, got %d", got)
+	}
+	if got := r.duration("candlestick.checkpointinterval", 0); got != 10*time.Second {
+		t.Errorf("expected 10s, got %s", got)
+	}
+	if got := r.list("binance.symbols", nil); !slices.Equal(got, []string{"btcusdt", "ethusdt"}) {
+		t.Errorf("expected the comma separated list, got %v", got)
+	}
+	if got := r.list("auth.admins", nil); !slices.Equal(got, []string{"api_key:ops", "jwt:alice"}) {
+		t.Errorf("expected the list of the file, got %v", got)
+	}
+	if got := r.int("server.maxsendmsgsize", 42); got != 42 {
+		t.Errorf("expected the default of an unset value, got %d", got)
+	}
+	if err := r.err(); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestReaderCollectsEveryInvalidValue(t *testing.T) {
+	v := viper.New()
+	v.Set("server.maxrecvmsgsize", "large")
+	v.Set("candlestick.checkpointinterval", "soon")
+
+	r := newReader(v)
+	if got := r.int("server.maxrecvmsgsize", 42); got != 42 {
+		t.Errorf("expected the default of an invalid value, got %d", got)
+	}
+	r.duration("candlestick.checkpointinterval", time.Second)
+	r.required("db.host")
+
+	err := r.err()
+	for _, expected := range []string{
+		"server.maxrecvmsgsize (SERVER_MAXRECVMSGSIZE) is invalid",
+		"candlestick.checkpointinterval (CANDLESTICK_CHECKPOINTINTERVAL) is invalid",
+		"db.host (DB_HOST) not provided",
+	} {
+		if err == nil || !strings.Contains(err.Error(), expected) {
+			t.Errorf("expected %q to be reported, got %v", expected, err)
+		}
+	}
+}
+
+func TestReaderDecodesTheStructuredValues(t *testing.T) {
+	type target struct {
+		Name string `json:"name"`
+	}
+
+	cases := []struct {
+		name  string
+		value any
+	}{
+		{name: "json env var", value: `[{"name": "desk-1"}]`},
+		{name: "file", value: []any{map[string]any{"name": "desk-1"}}},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			v := viper.New()
+			v.Set("webhook.targets", c.value)
+
+			var out []target
+			r := newReader(v)
+			r.json("webhook.targets", &out)
+			if err := r.err(); err != nil {
+				t.Fatal(err)
+			}
+			if len(out) != 1 || out[0].Name != "desk-1" {
+				t.Errorf("expected the decoded targets, got %+v", out)
+			}
+		})
+	}
+
+	v := viper.New()
+	v.Set("webhook.targets", `[{"name":`)
+	var out []target
+	r := newReader(v)
+	r.json("webhook.targets", &out)
+	if err := r.err(); err == nil || !strings.Contains(err.Error(), "webhook.targets (WEBHOOK_TARGETS) is invalid") {
+		t.Errorf("expected the invalid json to be reported, got %v", err)
+	}
+}
@@ -0,0 +1,105 @@
+package db
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"sync"
+)
+
+const (
+	queryTryAdvisoryLock = `SELECT pg_try_advisory_lock($1)`
+	queryAdvisoryUnlock  = `SELECT pg_advisory_unlock($1)`
+	queryCheckConnection = `SELECT 1`
+)
+
+// AdvisoryLock is a postgres session advisory lock, held on a connection of
+// its own for as long as the connection lives
+type AdvisoryLock struct {
+	db    *sql.DB
+	key   int64
+	mutex sync.Mutex
+	conn  *sql.Conn // set while the lock is held
+}
+
+func NewAdvisoryLock(
+	db *sql.DB,
+	key int64,
+) *AdvisoryLock {
+	return &AdvisoryLock{
+		db:  db,
+		key: key,
+	}
+}
+
+func (l *AdvisoryLock) TryAcquire(
+	ctx context.Context,
+) (bool, error) {
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+
+	if l.conn != nil {
+		return true, nil
+	}
+
+	conn, err := l.db.Conn(ctx)
+	if err != nil {
+		return false, fmt.Errorf("Failed to get a connection for the advisory lock - %w", err)
+	}
+
+	var acquired bool
+	err = conn.QueryRowContext(ctx, queryTryAdvisoryLock, l.key).Scan(&acquired)
+	if err != nil || !acquired {
+		conn.Close()
+		if err != nil {
+			return false, fmt.Errorf("Failed to try the advisory lock - %w", err)
+		}
+		return false, nil
+	}
+
+	l.conn = conn
+	return true, nil
+}
+
+// Check fails once the connection holding the lock is lost, and
+// the lock with it
+func (l *AdvisoryLock) Check(
+	ctx context.Context,
+) error {
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+
+	if l.conn == nil {
+		return fmt.Errorf("Advisory lock is not held")
+	}
+
+	if _, err := l.conn.ExecContext(ctx, queryCheckConnection); err != nil {
+		return fmt.Errorf("Failed to check the advisory lock connection - %w", err)
+	}
+	return nil
+}
+
+// Release unlocks and closes the connection, which releases the lock even
+// if the unlock fails
+func (l *AdvisoryLock) Release(
+	ctx context.Context,
+) error {
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+
+	if l.conn == nil {
+		return nil
+	}
+
+	_, unlockErr := l.conn.ExecContext(ctx, queryAdvisoryUnlock, l.key)
+	closeErr := l.conn.Close()
+	l.conn = nil
+
+	if unlockErr != nil {
+		return fmt.Errorf("Failed to release the advisory lock - %w", unlockErr)
+	}
+	if closeErr != nil {
+		return fmt.Errorf("Failed to close the advisory lock connection - %w", closeErr)
+	}
+	return nil
+}
@@ -0,0 +1,149 @@
+package db
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/lib/pq"
+	"github.com/ramasbeinaty/trading-chart-service/pkg/domain/base/logger"
+	"github.com/ramasbeinaty/trading-chart-service/pkg/domain/candlestick"
+	"go.uber.org/zap"
+)
+
+const (
+	// notified by the candlestick_notify trigger on commit of every closed bar
+	CHANGEFEED_CHANNEL = "candlestick_bars"
+
+	CHANGEFEED_MIN_RECONNECT = time.Second
+	CHANGEFEED_MAX_RECONNECT = 30 * time.Second
+	// the connection is pinged when idle for longer, to detect it is lost
+	CHANGEFEED_PING_INTERVAL = 90 * time.Second
+
+	CHANGE_KIND_CLOSED     = "closed"
+	CHANGE_KIND_CORRECTION = "correction"
+)
+
+// payload of the candlestick_bars notifications
+type barNotification struct {
+	Kind           string    `json:"kind"`
+	Symbol         string    `json:"symbol"`
+	Open           float64   `json:"open"`
+	High           float64   `json:"high"`
+	Low            float64   `json:"low"`
+	Close          float64   `json:"close"`
+	TradeTimestamp time.Time `json:"tradeTimestamp"`
+}
+
+// ChangeFeed listens to the closed bars committed to the db, by this
+// instance or another one, and passes them on as they are committed
+type ChangeFeed struct {
+	cfg       *DBConfigs
+	lgr       logger.ILogger
+	connected atomic.Bool
+}
+
+func NewChangeFeed(
+	cfg *DBConfigs,
+	lgr logger.ILogger,
+) *ChangeFeed {
+	return &ChangeFeed{
+		cfg: cfg,
+		lgr: lgr,
+	}
+}
+
+// Start listens until the context is done, passing every change to onChange
+// notifications sent while reconnecting are missed
+func (f *ChangeFeed) Start(
+	ctx context.Context,
+	wg *sync.WaitGroup,
+	onChange func(ctx context.Context, change *candlestick.BarChange),
+) error {
+	lgr := f.lgr.Get(ctx)
+
+	listener := pq.NewListener(
+		connectionString(f.cfg),
+		CHANGEFEED_MIN_RECONNECT,
+		CHANGEFEED_MAX_RECONNECT,
+		func(event pq.ListenerEventType, err error) {
+			switch event {
+			case pq.ListenerEventConnected, pq.ListenerEventReconnected:
+				f.connected.Store(true)
+			case pq.ListenerEventDisconnected, pq.ListenerEventConnectionAttemptFailed:
+				f.connected.Store(false)
+				lgr.Warn("Change feed is disconnected", zap.Error(err))
+			}
+		},
+	)
+	if err := listener.Listen(CHANGEFEED_CHANNEL); err != nil {
+		listener.Close()
+		return fmt.Errorf("Failed to listen to %s - %w", CHANGEFEED_CHANNEL, err)
+	}
+
+	lgr.Info("Listening to the change feed", zap.String("channel", CHANGEFEED_CHANNEL))
+
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		defer listener.Close()
+
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case notification := <-listener.Notify:
+				// sent once reconnected
+				if notification == nil {
+					lgr.Warn("Change feed reconnected, closed bars may have been missed")
+					continue
+				}
+				change, err := parseBarChange(notification.Extra)
+				if err != nil {
+					lgr.Error(
+						"Failed to parse the change feed notification",
+						zap.String("payload", notification.Extra),
+						zap.Error(err),
+					)
+					continue
+				}
+				onChange(ctx, change)
+			case <-time.After(CHANGEFEED_PING_INTERVAL):
+				if err := listener.Ping(); err != nil {
+					lgr.Warn("Failed to ping the change feed", zap.Error(err))
+				}
+			}
+		}
+	}()
+
+	return nil
+}
+
+// IsConnected reports whether the feed is listening
+func (f *ChangeFeed) IsConnected() bool {
+	return f.connected.Load()
+}
+
+func parseBarChange(
+	payload string,
+) (*candlestick.BarChange, error) {
+	var n barNotification
+	if err := json.Unmarshal([]byte(payload), &n); err != nil {
+		return nil, err
+	}
+
+	return &candlestick.BarChange{
+		Bar: &candlestick.Candlestick{
+			Symbol:         n.Symbol,
+			Open:           n.Open,
+			High:           n.High,
+			Low:            n.Low,
+			Close:          n.Close,
+			TradeTimestamp: n.TradeTimestamp.UTC(),
+		},
+		Correction: n.Kind == CHANGE_KIND_CORRECTION,
+	}, nil
+}
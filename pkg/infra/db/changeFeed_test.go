@@ -0,0 +1,62 @@
+package db
+
+import (
+	"testing"
+	"time"
+)
+
+func TestParseBarChange(t *testing.T) {
+	cases := []struct {
+		name       string
+		payload    string
+		correction bool
+	}{
+		{
+			name:    "closed",
+			payload: `{"kind" : "closed", "symbol" : "BTCUSDT", "open" : 43000.1, "high" : 43020.5, "low" : 42990, "close" : 43010.2, "tradeTimestamp" : "2024-01-01T00:00:00+00:00"}`,
+		},
+		{
+			name:       "correction",
+			payload:    `{"kind" : "correction", "symbol" : "BTCUSDT", "open" : 43000.1, "high" : 43020.5, "low" : 42990, "close" : 43010.2, "tradeTimestamp" : "2024-01-01T00:00:00+00:00"}`,
+			correction: true,
+		},
+		{
+			// the timestamp is written in the session's time zone
+			name:    "other time zone",
+			payload: `{"kind" : "closed", "symbol" : "BTCUSDT", "open" : 43000.1, "high" : 43020.5, "low" : 42990, "close" : 43010.2, "tradeTimestamp" : "2024-01-01T02:00:00+02:00"}`,
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			change, err := parseBarChange(c.payload)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			bar := change.Bar
+			if bar.Symbol != "BTCUSDT" || bar.Open != 43000.1 || bar.High != 43020.5 || bar.Low != 42990 || bar.Close != 43010.2 {
+				t.Errorf("unexpected bar %+v", bar)
+			}
+			if !bar.TradeTimestamp.Equal(time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)) || bar.TradeTimestamp.Location() != time.UTC {
+				t.Errorf("expected the bar's minute in UTC, got %s", bar.TradeTimestamp)
+			}
+			if change.Correction != c.correction {
+				t.Errorf("expected correction to be %t, got %t", c.correction, change.Correction)
+			}
+		})
+	}
+}
+
+func TestParseBarChangeRejectsInvalidPayloads(t *testing.T) {
+	for _, payload := range []string{
+		``,
+		`not json`,
+		`{"kind" : "closed", "open" : "43000.1"}`,
+		`{"kind" : "closed", "tradeTimestamp" : "yesterday"}`,
+	} {
+		if _, err := parseBarChange(payload); err == nil {
+			t.Errorf("expected %q to be rejected", payload)
+		}
+	}
+}
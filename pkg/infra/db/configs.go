@@ -6,4 +6,7 @@ type DBConfigs struct {
 	User     string
 	Password string
 	DBName   string
+	// the instance doesn't ingest, it streams the bars committed by
+	// another one from the change feed
+	ReadOnly bool
 }
@@ -33,6 +33,152 @@ func GetMigrationScripts() []MigrationScript {
 				DROP TABLE IF EXISTS candlestick;
 		`,
 		},
+		{
+			key: "alerts",
+			up: `
+				CREATE TABLE IF NOT EXISTS alert (
+					id BIGINT PRIMARY KEY,
+					symbol VARCHAR(20) NOT NULL,
+					timeframe VARCHAR(5) NOT NULL,
+					metric VARCHAR(20) NOT NULL,
+					condition VARCHAR(20) NOT NULL,
+					threshold NUMERIC NOT NULL,
+					mode VARCHAR(10) NOT NULL,
+					on_close BOOLEAN NOT NULL DEFAULT FALSE,
+					status VARCHAR(10) NOT NULL DEFAULT 'active',
+					fire_count BIGINT NOT NULL DEFAULT 0,
+					last_fired_at TIMESTAMP WITH TIME ZONE,
+					owner VARCHAR(200) NOT NULL DEFAULT '',
+					created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+					updated_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW()
+				);
+
+				CREATE INDEX IF NOT EXISTS alert_status_symbol_idx ON alert (status, symbol);
+
+				CREATE TRIGGER alert_set_update_at
+				BEFORE UPDATE ON alert
+				FOR EACH ROW
+				EXECUTE PROCEDURE trigger_set_update_at();
+		`,
+			down: `
+				DROP TABLE IF EXISTS alert;
+		`,
+		},
+		{
+			key: "webhook_outbox",
+			up: `
+				CREATE TABLE IF NOT EXISTS webhook_outbox (
+					id BIGSERIAL PRIMARY KEY,
+					event_id VARCHAR(64) NOT NULL,
+					target VARCHAR(100) NOT NULL,
+					event_type VARCHAR(30) NOT NULL,
+					payload JSONB NOT NULL,
+					attempts INT NOT NULL DEFAULT 0,
+					next_attempt_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+					last_error TEXT NOT NULL DEFAULT '',
+					status VARCHAR(10) NOT NULL DEFAULT 'pending',
+					created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+					updated_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW()
+				);
+
+				CREATE INDEX IF NOT EXISTS webhook_outbox_status_next_attempt_at_idx
+				ON webhook_outbox (status, next_attempt_at);
+
+				CREATE TRIGGER webhook_outbox_set_update_at
+				BEFORE UPDATE ON webhook_outbox
+				FOR EACH ROW
+				EXECUTE PROCEDURE trigger_set_update_at();
+		`,
+			down: `
+				DROP TABLE IF EXISTS webhook_outbox;
+		`,
+		},
+		{
+			key: "bus_outbox",
+			up: `
+				CREATE TABLE IF NOT EXISTS bus_outbox (
+					id BIGSERIAL PRIMARY KEY,
+					subject VARCHAR(200) NOT NULL,
+					payload BYTEA NOT NULL,
+					created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW()
+				);
+		`,
+			down: `
+				DROP TABLE IF EXISTS bus_outbox;
+		`,
+		},
+		{
+			key: "candlestick_inprogress",
+			up: `
+				CREATE TABLE IF NOT EXISTS candlestick_inprogress (
+					symbol VARCHAR(20) NOT NULL,
+					open_price NUMERIC NOT NULL,
+					high_price NUMERIC NOT NULL,
+					low_price NUMERIC NOT NULL,
+					close_price NUMERIC NOT NULL,
+					trade_timestamp TIMESTAMP WITH TIME ZONE NOT NULL,
+					sequence BIGINT NOT NULL DEFAULT 0,
+					created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+					PRIMARY KEY (symbol, trade_timestamp)
+				);
+		`,
+			down: `
+				DROP TABLE IF EXISTS candlestick_inprogress;
+		`,
+		},
+		{
+			key: "replication_leader",
+			up: `
+				CREATE TABLE IF NOT EXISTS replication_leader (
+					id INT PRIMARY KEY DEFAULT 1 CHECK (id = 1),
+					address VARCHAR(200) NOT NULL,
+					elected_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW()
+				);
+		`,
+			down: `
+				DROP TABLE IF EXISTS replication_leader;
+		`,
+		},
+		{
+			// a bar committed again unchanged isn't notified as a correction
+			key: "candlestick_notify",
+			up: `
+				CREATE OR REPLACE FUNCTION trigger_notify_candlestick()
+				RETURNS TRIGGER AS $$
+				BEGIN
+					PERFORM pg_notify(
+						'candlestick_bars',
+						json_build_object(
+							'kind', CASE TG_OP WHEN 'INSERT' THEN 'closed' ELSE 'correction' END,
+							'symbol', NEW.symbol,
+							'open', NEW.open_price,
+							'high', NEW.high_price,
+							'low', NEW.low_price,
+							'close', NEW.close_price,
+							'tradeTimestamp', NEW.trade_timestamp
+						)::text
+					);
+					RETURN NEW;
+				END;
+				$$ LANGUAGE plpgsql;
+
+				CREATE TRIGGER candlestick_notify
+				AFTER INSERT ON candlestick
+				FOR EACH ROW
+				EXECUTE PROCEDURE trigger_notify_candlestick();
+
+				CREATE TRIGGER candlestick_notify_update
+				AFTER UPDATE ON candlestick
+				FOR EACH ROW
+				WHEN (OLD.* IS DISTINCT FROM NEW.*)
+				EXECUTE PROCEDURE trigger_notify_candlestick();
+		`,
+			down: `
+				DROP TRIGGER IF EXISTS candlestick_notify_update ON candlestick;
+				DROP TRIGGER IF EXISTS candlestick_notify ON candlestick;
+				DROP FUNCTION IF EXISTS trigger_notify_candlestick();
+		`,
+		},
 	}
 
 	return migrationScripts
@@ -16,12 +16,7 @@ import (
 func InitializeDB(
 	cfg *DBConfigs,
 ) (*sql.DB, error) {
-	connectionStr := fmt.Sprintf(
-		"host=%s port=%s user=%s password=%s dbname=%s sslmode=disable",
-		cfg.Host, cfg.Port, cfg.User, cfg.Password, cfg.DBName,
-	)
-
-	db, err := sql.Open("postgres", connectionStr)
+	db, err := sql.Open("postgres", connectionString(cfg))
 	if err != nil {
 		log.Fatalf("Failed to open database: %v", err)
 		return nil, err
@@ -39,6 +34,15 @@ func InitializeDB(
 	return db, nil
 }
 
+func connectionString(
+	cfg *DBConfigs,
+) string {
+	return fmt.Sprintf(
+		"host=%s port=%s user=%s password=%s dbname=%s sslmode=disable",
+		cfg.Host, cfg.Port, cfg.User, cfg.Password, cfg.DBName,
+	)
+}
+
 func RunMigrations(
 	ctx context.Context,
 	lgr *zap.Logger,
@@ -53,6 +57,11 @@ func RunMigrations(
 		panic("RunMigrations: Failed to begin a db transaction")
 	}
 
+	_, err = dbtx.Exec(queryLockMigrations, MIGRATIONS_LOCK_KEY)
+	if err != nil {
+		panic(fmt.Errorf("Failed to lock the migrations - %w", err))
+	}
+
 	err = dbtx.QueryRow(queryCheckMigrationsExist).Scan(&entityExists)
 	if err != nil {
 		panic(fmt.Errorf("Failed to check if migrations exist - %w", err))
@@ -112,6 +121,39 @@ func RunMigrations(
 	return dbtx.Commit()
 }
 
+// CheckMigrations returns an error unless every migration has been applied
+func CheckMigrations(
+	ctx context.Context,
+	db *sql.DB,
+	migrations []MigrationScript,
+) error {
+	rows, err := db.QueryContext(ctx, queryAllMigrations)
+	if err != nil {
+		return fmt.Errorf("Failed to fetch migrations history - %w", err)
+	}
+	defer rows.Close()
+
+	applied := map[string]bool{}
+	for rows.Next() {
+		var m migrationEntity
+		if err := rows.Scan(&m.Index, &m.Key, &m.CreatedAt); err != nil {
+			return fmt.Errorf("Failed to scan migration - %w", err)
+		}
+		applied[m.Key] = true
+	}
+	if err := rows.Err(); err != nil {
+		return fmt.Errorf("Error in row iteration - %w", err)
+	}
+
+	for _, m := range migrations {
+		if !applied[m.key] {
+			return fmt.Errorf("migration %s is not applied", m.key)
+		}
+	}
+
+	return nil
+}
+
 type migrationEntity struct {
 	Index     int        `db:"index"`
 	Key       string     `db:"key"`
@@ -152,4 +194,11 @@ const (
 	queryAddMigration = `
 		INSERT INTO migrations(key) VALUES ($1)
 	`
+	// held until the migrations transaction ends
+	queryLockMigrations = `
+		SELECT pg_advisory_xact_lock($1)
+	`
 )
+
+// replicas starting together run the migrations one after the other
+const MIGRATIONS_LOCK_KEY = 0x6d6967 // "mig" in ascii
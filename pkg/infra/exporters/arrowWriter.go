@@ -0,0 +1,90 @@
+package exporters
+
+import (
+	"fmt"
+	"io"
+
+	"github.com/apache/arrow-go/v18/arrow/ipc"
+	"github.com/apache/arrow-go/v18/arrow/memory"
+	"github.com/ramasbeinaty/trading-chart-service/pkg/domain/candlestick"
+	"github.com/ramasbeinaty/trading-chart-service/pkg/domain/export"
+)
+
+// rows buffered before being written as a record batch, bounding the memory
+// used whatever the number of bars exported
+const ARROW_BATCH_ROWS = 64 * 1024
+
+// ArrowWriter writes the bars as an Apache Arrow IPC file, a.k.a. Feather
+// V2, in record batches of ARROW_BATCH_ROWS rows of uncompressed columns
+type ArrowWriter struct {
+	w       *ipc.FileWriter
+	records *recordBuilder
+	err     error
+}
+
+var _ export.IBarWriter = (*ArrowWriter)(nil)
+
+func NewArrowWriter(
+	w io.Writer,
+	columns []export.Column,
+) export.IBarWriter {
+	records := newRecordBuilder(columns)
+	fw, err := ipc.NewFileWriter(
+		w,
+		ipc.WithSchema(records.schema),
+		ipc.WithAllocator(memory.DefaultAllocator),
+	)
+	if err != nil {
+		err = fmt.Errorf("Failed to start the arrow file - %w", err)
+	}
+
+	return &ArrowWriter{
+		w:       fw,
+		records: records,
+		err:     err,
+	}
+}
+
+func (a *ArrowWriter) WriteBar(
+	bar *candlestick.Candlestick,
+) error {
+	if a.err != nil {
+		return a.err
+	}
+
+	a.records.append(bar)
+	if a.records.rows == ARROW_BATCH_ROWS {
+		a.writeRecordBatch()
+	}
+	return a.err
+}
+
+// writes the rows left and the footer
+// a file without rows holds the schema only
+func (a *ArrowWriter) Close() error {
+	defer a.records.release()
+	if a.err != nil {
+		return a.err
+	}
+
+	if a.records.rows != 0 {
+		a.writeRecordBatch()
+		if a.err != nil {
+			return a.err
+		}
+	}
+
+	if err := a.w.Close(); err != nil {
+		return fmt.Errorf("Failed to end the arrow file - %w", err)
+	}
+	return nil
+}
+
+func (a *ArrowWriter) writeRecordBatch() {
+	record := a.records.newRecord()
+	defer record.Release()
+
+	if err := a.w.Write(record); err != nil {
+		a.err = fmt.Errorf("Failed to write an arrow record batch - %w", err)
+	}
+}
@@ -0,0 +1,86 @@
+package exporters
+
+import (
+	"encoding/csv"
+	"io"
+	"strconv"
+	"time"
+
+	"github.com/ramasbeinaty/trading-chart-service/pkg/domain/candlestick"
+	"github.com/ramasbeinaty/trading-chart-service/pkg/domain/export"
+)
+
+// the value of each column, as written in a csv cell
+var csvValues = map[export.Column]func(bar *candlestick.Candlestick) string{
+	export.COLUMN_SYMBOL: func(bar *candlestick.Candlestick) string {
+		return bar.Symbol
+	},
+	export.COLUMN_TIMESTAMP: func(bar *candlestick.Candlestick) string {
+		return bar.TradeTimestamp.UTC().Format(time.RFC3339)
+	},
+	export.COLUMN_OPEN:  func(bar *candlestick.Candlestick) string { return formatPrice(bar.Open) },
+	export.COLUMN_HIGH:  func(bar *candlestick.Candlestick) string { return formatPrice(bar.High) },
+	export.COLUMN_LOW:   func(bar *candlestick.Candlestick) string { return formatPrice(bar.Low) },
+	export.COLUMN_CLOSE: func(bar *candlestick.Candlestick) string { return formatPrice(bar.Close) },
+}
+
+// CSVWriter writes the bars as csv rows, after a header row
+// timestamps are written in RFC 3339, in UTC
+type CSVWriter struct {
+	w             *csv.Writer
+	columns       []export.Column
+	record        []string
+	headerWritten bool
+}
+
+var _ export.IBarWriter = (*CSVWriter)(nil)
+
+func NewCSVWriter(
+	w io.Writer,
+	columns []export.Column,
+) export.IBarWriter {
+	return &CSVWriter{
+		w:       csv.NewWriter(w),
+		columns: columns,
+		record:  make([]string, len(columns)),
+	}
+}
+
+func (c *CSVWriter) WriteBar(
+	bar *candlestick.Candlestick,
+) error {
+	if err := c.writeHeader(); err != nil {
+		return err
+	}
+
+	for i, column := range c.columns {
+		c.record[i] = csvValues[column](bar)
+	}
+	return c.w.Write(c.record)
+}
+
+// the header is written even without any bar
+func (c *CSVWriter) Close() error {
+	if err := c.writeHeader(); err != nil {
+		return err
+	}
+
+	c.w.Flush()
+	return c.w.Error()
+}
+
+func (c *CSVWriter) writeHeader() error {
+	if c.headerWritten {
+		return nil
+	}
+	c.headerWritten = true
+
+	for i, column := range c.columns {
+		c.record[i] = string(column)
+	}
+	return c.w.Write(c.record)
+}
+
+func formatPrice(price float64) string {
+	return strconv.FormatFloat(price, 'f', -1, 64)
+}
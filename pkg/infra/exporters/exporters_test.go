@@ -0,0 +1,288 @@
+package exporters_test
+
+import (
+	"bytes"
+	"context"
+	"encoding/csv"
+	"reflect"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/apache/arrow-go/v18/arrow"
+	"github.com/apache/arrow-go/v18/arrow/array"
+	"github.com/apache/arrow-go/v18/arrow/ipc"
+	"github.com/apache/arrow-go/v18/arrow/memory"
+	"github.com/apache/arrow-go/v18/parquet/file"
+	"github.com/apache/arrow-go/v18/parquet/pqarrow"
+	"github.com/ramasbeinaty/trading-chart-service/pkg/domain/candlestick"
+	"github.com/ramasbeinaty/trading-chart-service/pkg/domain/export"
+	"github.com/ramasbeinaty/trading-chart-service/pkg/infra/exporters"
+)
+
+var start = time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+
+func bars(n int) []*candlestick.Candlestick {
+	bars := make([]*candlestick.Candlestick, 0, n)
+	for i := range n {
+		price := 100 + float64(i)
+		bars = append(bars, &candlestick.Candlestick{
+			Symbol:         "BTCUSDT",
+			Open:           price,
+			High:           price + 2.5,
+			Low:            price - 1.25,
+			Close:          price + 0.5,
+			TradeTimestamp: start.Add(time.Duration(i) * time.Minute),
+		})
+	}
+	return bars
+}
+
+func write(t *testing.T, newWriter export.NewBarWriter, columns []export.Column, bars []*candlestick.Candlestick) []byte {
+	t.Helper()
+
+	var buf bytes.Buffer
+	w := newWriter(&buf, columns)
+	for _, bar := range bars {
+		if err := w.WriteBar(bar); err != nil {
+			t.Fatalf("unexpected error writing a bar: %v", err)
+		}
+	}
+	if err := w.Close(); err != nil {
+		t.Fatalf("unexpected error closing the writer: %v", err)
+	}
+	return buf.Bytes()
+}
+
+// the value of the column of the bar, as read back
+func values(column export.Column, bar *candlestick.Candlestick) any {
+	switch column {
+	case export.COLUMN_SYMBOL:
+		return bar.Symbol
+	case export.COLUMN_TIMESTAMP:
+		return bar.TradeTimestamp.UnixMilli()
+	case export.COLUMN_OPEN:
+		return bar.Open
+	case export.COLUMN_HIGH:
+		return bar.High
+	case export.COLUMN_LOW:
+		return bar.Low
+	default:
+		return bar.Close
+	}
+}
+
+// reads the columns of the table back, by name then row
+func readTable(t *testing.T, table arrow.Table) map[string][]any {
+	t.Helper()
+
+	read := map[string][]any{}
+	for i := range int(table.NumCols()) {
+		column := table.Column(i)
+		name := column.Name()
+		for _, chunk := range column.Data().Chunks() {
+			for row := range chunk.Len() {
+				if chunk.IsNull(row) {
+					t.Fatalf("expected %s to have no null", name)
+				}
+				switch values := chunk.(type) {
+				case *array.String:
+					read[name] = append(read[name], values.Value(row))
+				case *array.Timestamp:
+					read[name] = append(read[name], int64(values.Value(row)))
+				case *array.Float64:
+					read[name] = append(read[name], values.Value(row))
+				default:
+					t.Fatalf("unexpected type %s of %s", chunk.DataType(), name)
+				}
+			}
+		}
+	}
+	return read
+}
+
+func expected(columns []export.Column, bars []*candlestick.Candlestick) map[string][]any {
+	expected := map[string][]any{}
+	for _, column := range columns {
+		for _, bar := range bars {
+			expected[string(column)] = append(expected[string(column)], values(column, bar))
+		}
+	}
+	return expected
+}
+
+func fieldNames(schema *arrow.Schema) []string {
+	names := []string{}
+	for _, field := range schema.Fields() {
+		names = append(names, field.Name)
+	}
+	return names
+}
+
+func columnNames(columns []export.Column) []string {
+	names := []string{}
+	for _, column := range columns {
+		names = append(names, string(column))
+	}
+	return names
+}
+
+var selections = map[string][]export.Column{
+	"every column": export.COLUMNS,
+	"selected":     {export.COLUMN_CLOSE, export.COLUMN_TIMESTAMP},
+}
+
+func TestCSVWriter(t *testing.T) {
+	written := bars(3)
+	for name, columns := range selections {
+		t.Run(name, func(t *testing.T) {
+			records, err := csv.NewReader(bytes.NewReader(write(t, exporters.NewCSVWriter, columns, written))).ReadAll()
+			if err != nil {
+				t.Fatalf("failed to read the csv: %v", err)
+			}
+			if len(records) != len(written)+1 {
+				t.Fatalf("expected a header and %d rows, got %d records", len(written), len(records))
+			}
+			if !reflect.DeepEqual(records[0], columnNames(columns)) {
+				t.Errorf("expected the header %v, got %v", columnNames(columns), records[0])
+			}
+
+			read := map[string][]any{}
+			for _, record := range records[1:] {
+				for i, column := range columns {
+					read[string(column)] = append(read[string(column)], parseCSV(t, column, record[i]))
+				}
+			}
+			if !reflect.DeepEqual(read, expected(columns, written)) {
+				t.Errorf("expected the bars written to be read back, got %v", read)
+			}
+		})
+	}
+}
+
+// parses a csv cell as the value of the column, the timestamps being in
+// RFC 3339
+func parseCSV(t *testing.T, column export.Column, cell string) any {
+	t.Helper()
+
+	switch column {
+	case export.COLUMN_SYMBOL:
+		return cell
+	case export.COLUMN_TIMESTAMP:
+		timestamp, err := time.Parse(time.RFC3339, cell)
+		if err != nil {
+			t.Fatalf("expected an RFC 3339 timestamp, got %s", cell)
+		}
+		return timestamp.UnixMilli()
+	default:
+		price, err := strconv.ParseFloat(cell, 64)
+		if err != nil {
+			t.Fatalf("expected a price, got %s", cell)
+		}
+		return price
+	}
+}
+
+func TestArrowWriter(t *testing.T) {
+	// more than a record batch
+	written := bars(exporters.ARROW_BATCH_ROWS + 10)
+	for name, columns := range selections {
+		t.Run(name, func(t *testing.T) {
+			reader, err := ipc.NewFileReader(
+				bytes.NewReader(write(t, exporters.NewArrowWriter, columns, written)),
+				ipc.WithAllocator(memory.DefaultAllocator),
+			)
+			if err != nil {
+				t.Fatalf("failed to read the arrow file: %v", err)
+			}
+			defer reader.Close()
+
+			if !reflect.DeepEqual(fieldNames(reader.Schema()), columnNames(columns)) {
+				t.Fatalf("expected the fields %v, got %v", columnNames(columns), fieldNames(reader.Schema()))
+			}
+			if reader.NumRecords() != 2 {
+				t.Errorf("expected 2 record batches, got %d", reader.NumRecords())
+			}
+
+			records := []arrow.Record{}
+			for i := range reader.NumRecords() {
+				record, err := reader.Record(i)
+				if err != nil {
+					t.Fatalf("failed to read record batch %d: %v", i, err)
+				}
+				// the reader releases its records once the next is read
+				record.Retain()
+				defer record.Release()
+				records = append(records, record)
+			}
+			table := array.NewTableFromRecords(reader.Schema(), records)
+			defer table.Release()
+
+			if read := readTable(t, table); !reflect.DeepEqual(read, expected(columns, written)) {
+				t.Errorf("expected the bars written to be read back")
+			}
+		})
+	}
+}
+
+func TestArrowWriterWithoutBars(t *testing.T) {
+	reader, err := ipc.NewFileReader(bytes.NewReader(write(t, exporters.NewArrowWriter, export.COLUMNS, nil)))
+	if err != nil {
+		t.Fatalf("failed to read the arrow file: %v", err)
+	}
+	defer reader.Close()
+
+	if reader.NumRecords() != 0 || len(reader.Schema().Fields()) != len(export.COLUMNS) {
+		t.Errorf("expected the schema only, got %d record batches", reader.NumRecords())
+	}
+}
+
+func TestParquetWriter(t *testing.T) {
+	// more than a row group
+	written := bars(exporters.PARQUET_ROW_GROUP_ROWS + 10)
+	for name, columns := range selections {
+		t.Run(name, func(t *testing.T) {
+			pf, err := file.NewParquetReader(bytes.NewReader(write(t, exporters.NewParquetWriter, columns, written)))
+			if err != nil {
+				t.Fatalf("failed to read the parquet file: %v", err)
+			}
+			defer pf.Close()
+
+			if pf.NumRowGroups() != 2 {
+				t.Errorf("expected 2 row groups, got %d", pf.NumRowGroups())
+			}
+			if pf.MetaData().GetCreatedBy() != exporters.PARQUET_CREATED_BY {
+				t.Errorf("expected the file to be created by %s, got %s", exporters.PARQUET_CREATED_BY, pf.MetaData().GetCreatedBy())
+			}
+
+			reader, err := pqarrow.NewFileReader(pf, pqarrow.ArrowReadProperties{}, memory.DefaultAllocator)
+			if err != nil {
+				t.Fatalf("failed to read the parquet file as arrow: %v", err)
+			}
+			table, err := reader.ReadTable(context.Background())
+			if err != nil {
+				t.Fatalf("failed to read the parquet table: %v", err)
+			}
+			defer table.Release()
+
+			if !reflect.DeepEqual(fieldNames(table.Schema()), columnNames(columns)) {
+				t.Fatalf("expected the columns %v, got %v", columnNames(columns), fieldNames(table.Schema()))
+			}
+			if read := readTable(t, table); !reflect.DeepEqual(read, expected(columns, written)) {
+				t.Errorf("expected the bars written to be read back")
+			}
+		})
+	}
+}
+
+func TestParquetWriterWithoutBars(t *testing.T) {
+	pf, err := file.NewParquetReader(bytes.NewReader(write(t, exporters.NewParquetWriter, export.COLUMNS, nil)))
+	if err != nil {
+		t.Fatalf("failed to read the parquet file: %v", err)
+	}
+	defer pf.Close()
+
+	if pf.NumRows() != 0 || pf.MetaData().Schema.NumColumns() != len(export.COLUMNS) {
+		t.Errorf("expected the schema only, got %d rows", pf.NumRows())
+	}
+}
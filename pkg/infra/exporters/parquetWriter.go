@@ -0,0 +1,100 @@
+package exporters
+
+import (
+	"fmt"
+	"io"
+
+	"github.com/apache/arrow-go/v18/parquet"
+	"github.com/apache/arrow-go/v18/parquet/compress"
+	"github.com/apache/arrow-go/v18/parquet/pqarrow"
+	"github.com/ramasbeinaty/trading-chart-service/pkg/domain/candlestick"
+	"github.com/ramasbeinaty/trading-chart-service/pkg/domain/export"
+)
+
+const (
+	// rows buffered before being written as a row group, bounding the memory
+	// used whatever the number of bars exported
+	PARQUET_ROW_GROUP_ROWS = 64 * 1024
+	PARQUET_CREATED_BY     = "trading-chart-service"
+)
+
+// ParquetWriter writes the bars as an Apache Parquet file, in row groups of
+// PARQUET_ROW_GROUP_ROWS rows of required and uncompressed columns
+type ParquetWriter struct {
+	w       *pqarrow.FileWriter
+	records *recordBuilder
+	err     error
+}
+
+var _ export.IBarWriter = (*ParquetWriter)(nil)
+
+func NewParquetWriter(
+	w io.Writer,
+	columns []export.Column,
+) export.IBarWriter {
+	records := newRecordBuilder(columns)
+	fw, err := pqarrow.NewFileWriter(
+		records.schema,
+		// the parquet writer closes the writers that are closers, which
+		// belong to the caller
+		struct{ io.Writer }{w},
+		parquet.NewWriterProperties(
+			parquet.WithCreatedBy(PARQUET_CREATED_BY),
+			parquet.WithCompression(compress.Codecs.Uncompressed),
+			parquet.WithMaxRowGroupLength(PARQUET_ROW_GROUP_ROWS),
+		),
+		pqarrow.DefaultWriterProps(),
+	)
+	if err != nil {
+		err = fmt.Errorf("Failed to start the parquet file - %w", err)
+	}
+
+	return &ParquetWriter{
+		w:       fw,
+		records: records,
+		err:     err,
+	}
+}
+
+func (p *ParquetWriter) WriteBar(
+	bar *candlestick.Candlestick,
+) error {
+	if p.err != nil {
+		return p.err
+	}
+
+	p.records.append(bar)
+	if p.records.rows == PARQUET_ROW_GROUP_ROWS {
+		p.writeRowGroup()
+	}
+	return p.err
+}
+
+// writes the rows left and the footer
+func (p *ParquetWriter) Close() error {
+	defer p.records.release()
+	if p.err != nil {
+		return p.err
+	}
+
+	if p.records.rows != 0 {
+		p.writeRowGroup()
+		if p.err != nil {
+			return p.err
+		}
+	}
+
+	if err := p.w.Close(); err != nil {
+		return fmt.Errorf("Failed to end the parquet file - %w", err)
+	}
+	return nil
+}
+
+func (p *ParquetWriter) writeRowGroup() {
+	record := p.records.newRecord()
+	defer record.Release()
+
+	if err := p.w.Write(record); err != nil {
+		p.err = fmt.Errorf("Failed to write a parquet row group - %w", err)
+	}
+}
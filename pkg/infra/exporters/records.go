@@ -0,0 +1,93 @@
+package exporters
+
+import (
+	"github.com/apache/arrow-go/v18/arrow"
+	"github.com/apache/arrow-go/v18/arrow/array"
+	"github.com/apache/arrow-go/v18/arrow/memory"
+	"github.com/ramasbeinaty/trading-chart-service/pkg/domain/candlestick"
+	"github.com/ramasbeinaty/trading-chart-service/pkg/domain/export"
+)
+
+// a column of the arrow records the arrow and parquet files are written from
+type recordColumn struct {
+	typ arrow.DataType
+	// appends the bar's value to the builder of the column
+	append func(b array.Builder, bar *candlestick.Candlestick)
+}
+
+var recordColumns = map[export.Column]recordColumn{
+	export.COLUMN_SYMBOL: {
+		typ: arrow.BinaryTypes.String,
+		append: func(b array.Builder, bar *candlestick.Candlestick) {
+			b.(*array.StringBuilder).Append(bar.Symbol)
+		},
+	},
+	// milliseconds since the epoch, in UTC
+	export.COLUMN_TIMESTAMP: {
+		typ: &arrow.TimestampType{Unit: arrow.Millisecond, TimeZone: "UTC"},
+		append: func(b array.Builder, bar *candlestick.Candlestick) {
+			b.(*array.TimestampBuilder).Append(arrow.Timestamp(bar.TradeTimestamp.UnixMilli()))
+		},
+	},
+	export.COLUMN_OPEN:  priceColumn(func(bar *candlestick.Candlestick) float64 { return bar.Open }),
+	export.COLUMN_HIGH:  priceColumn(func(bar *candlestick.Candlestick) float64 { return bar.High }),
+	export.COLUMN_LOW:   priceColumn(func(bar *candlestick.Candlestick) float64 { return bar.Low }),
+	export.COLUMN_CLOSE: priceColumn(func(bar *candlestick.Candlestick) float64 { return bar.Close }),
+}
+
+func priceColumn(
+	value func(bar *candlestick.Candlestick) float64,
+) recordColumn {
+	return recordColumn{
+		typ: arrow.PrimitiveTypes.Float64,
+		append: func(b array.Builder, bar *candlestick.Candlestick) {
+			b.(*array.Float64Builder).Append(value(bar))
+		},
+	}
+}
+
+// recordBuilder buffers the bars as an arrow record of the selected columns,
+// every field being non nullable
+type recordBuilder struct {
+	schema  *arrow.Schema
+	columns []recordColumn
+	builder *array.RecordBuilder
+	rows    int
+}
+
+func newRecordBuilder(
+	columns []export.Column,
+) *recordBuilder {
+	r := &recordBuilder{}
+
+	fields := make([]arrow.Field, 0, len(columns))
+	for _, column := range columns {
+		c := recordColumns[column]
+		r.columns = append(r.columns, c)
+		fields = append(fields, arrow.Field{Name: string(column), Type: c.typ})
+	}
+	r.schema = arrow.NewSchema(fields, nil)
+	r.builder = array.NewRecordBuilder(memory.DefaultAllocator, r.schema)
+
+	return r
+}
+
+func (r *recordBuilder) append(
+	bar *candlestick.Candlestick,
+) {
+	for i, column := range r.columns {
+		column.append(r.builder.Field(i), bar)
+	}
+	r.rows++
+}
+
+// returns the record of the buffered bars, the builder starting over
+// the caller releases the record
+func (r *recordBuilder) newRecord() arrow.Record {
+	r.rows = 0
+	return r.builder.NewRecord()
+}
+
+func (r *recordBuilder) release() {
+	r.builder.Release()
+}
@@ -0,0 +1,146 @@
+package jwks
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/go-jose/go-jose/v4"
+	"github.com/go-jose/go-jose/v4/jwt"
+	"github.com/ramasbeinaty/trading-chart-service/pkg/domain/auth"
+)
+
+// the asymmetric algorithms the keys of a jwks may sign with
+var signatureAlgorithms = []jose.SignatureAlgorithm{
+	jose.RS256, jose.RS384, jose.RS512,
+	jose.PS256, jose.PS384, jose.PS512,
+	jose.ES256, jose.ES384, jose.ES512,
+	jose.EdDSA,
+}
+
+// JWKSVerifier verifies JWTs signed by a key of a locally configured
+// json web key set
+type JWKSVerifier struct {
+	cfg  *auth.JWTConfig
+	keys jose.JSONWebKeySet
+}
+
+var _ auth.ITokenVerifier = (*JWKSVerifier)(nil)
+
+func NewJWKSVerifier(
+	cfg *auth.JWTConfig,
+) (*JWKSVerifier, error) {
+	data, err := os.ReadFile(cfg.JWKSFile)
+	if err != nil {
+		return nil, fmt.Errorf("Failed to read the jwks - %w", err)
+	}
+
+	v := &JWKSVerifier{cfg: cfg}
+	if err := json.Unmarshal(data, &v.keys); err != nil {
+		return nil, fmt.Errorf("Failed to parse the jwks - %w", err)
+	}
+	if len(v.keys.Keys) == 0 {
+		return nil, fmt.Errorf("Failed to load the jwks - %s has no keys", cfg.JWKSFile)
+	}
+
+	return v, nil
+}
+
+func (v *JWKSVerifier) Verify(
+	ctx context.Context,
+	token string,
+) (*auth.Principal, error) {
+	tok, err := jwt.ParseSigned(token, signatureAlgorithms)
+	if err != nil {
+		return nil, fmt.Errorf("%w - malformed token - %v", auth.ERR_UNAUTHENTICATED, err)
+	}
+
+	key, err := v.key(tok.Headers[0].KeyID)
+	if err != nil {
+		return nil, err
+	}
+
+	var (
+		claims jwt.Claims
+		extra  map[string]any
+	)
+	if err := tok.Claims(key.Key, &claims, &extra); err != nil {
+		return nil, fmt.Errorf("%w - invalid signature - %v", auth.ERR_UNAUTHENTICATED, err)
+	}
+
+	expected := jwt.Expected{
+		Issuer: v.cfg.Issuer,
+		Time:   time.Now(),
+	}
+	if v.cfg.Audience != "" {
+		expected.AnyAudience = jwt.Audience{v.cfg.Audience}
+	}
+	if err := claims.ValidateWithLeeway(expected, auth.JWT_LEEWAY); err != nil {
+		return nil, fmt.Errorf("%w - %v", auth.ERR_UNAUTHENTICATED, err)
+	}
+	if claims.Expiry == nil {
+		return nil, fmt.Errorf("%w - token has no expiry", auth.ERR_UNAUTHENTICATED)
+	}
+	if claims.Subject == "" {
+		return nil, fmt.Errorf("%w - token has no subject", auth.ERR_UNAUTHENTICATED)
+	}
+
+	symbols, err := parseSymbols(extra[v.cfg.SymbolsClaim])
+	if err != nil {
+		return nil, fmt.Errorf("%w - %s claim is invalid - %v", auth.ERR_UNAUTHENTICATED, v.cfg.SymbolsClaim, err)
+	}
+
+	return &auth.Principal{
+		ID:      claims.Subject,
+		Method:  auth.METHOD_JWT,
+		Symbols: symbols,
+	}, nil
+}
+
+// returns the key identified in the token, or the only key of the set if
+// the token doesn't identify one
+func (v *JWKSVerifier) key(
+	kid string,
+) (*jose.JSONWebKey, error) {
+	if kid == "" {
+		if len(v.keys.Keys) == 1 {
+			return &v.keys.Keys[0], nil
+		}
+		return nil, fmt.Errorf("%w - token has no kid", auth.ERR_UNAUTHENTICATED)
+	}
+
+	keys := v.keys.Key(kid)
+	if len(keys) == 0 {
+		return nil, fmt.Errorf("%w - unknown kid %s", auth.ERR_UNAUTHENTICATED, kid)
+	}
+	return &keys[0], nil
+}
+
+// the claim lists the symbol globs as an array, or a space separated string
+// like the scope claim, and must be present, a token entitled to every symbol
+// listing "*"
+func parseSymbols(
+	claim any,
+) ([]string, error) {
+	switch value := claim.(type) {
+	case nil:
+		return nil, fmt.Errorf("claim is missing, list %q for every symbol", auth.ALL_SYMBOLS)
+	case string:
+		return strings.Fields(value), nil
+	case []any:
+		symbols := make([]string, 0, len(value))
+		for _, v := range value {
+			symbol, ok := v.(string)
+			if !ok {
+				return nil, fmt.Errorf("expected strings, got %v", v)
+			}
+			symbols = append(symbols, symbol)
+		}
+		return symbols, nil
+	default:
+		return nil, fmt.Errorf("expected an array or a string, got %v", value)
+	}
+}
@@ -0,0 +1,40 @@
+package jwks
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestParseSymbols(t *testing.T) {
+	cases := []struct {
+		name    string
+		claim   any
+		symbols []string
+		fails   bool
+	}{
+		{name: "array", claim: []any{"BTC*", "ETHUSDT"}, symbols: []string{"BTC*", "ETHUSDT"}},
+		{name: "space separated", claim: "BTC* ETHUSDT", symbols: []string{"BTC*", "ETHUSDT"}},
+		{name: "every symbol", claim: "*", symbols: []string{"*"}},
+		{name: "missing", claim: nil, fails: true},
+		{name: "not strings", claim: []any{"BTCUSDT", 1.0}, fails: true},
+		{name: "number", claim: 1.0, fails: true},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			symbols, err := parseSymbols(c.claim)
+			if c.fails {
+				if err == nil {
+					t.Fatalf("expected an error, got the symbols %v", symbols)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error %v", err)
+			}
+			if !reflect.DeepEqual(symbols, c.symbols) {
+				t.Fatalf("expected %v, got %v", c.symbols, symbols)
+			}
+		})
+	}
+}
@@ -0,0 +1,8 @@
+package logger
+
+const DEFAULT_LEVEL = "info"
+
+type LoggerConfig struct {
+	// debug, info, warn or error
+	Level string
+}
@@ -4,15 +4,26 @@ import (
 	"context"
 	"fmt"
 
+	"go.opentelemetry.io/otel/trace"
 	"go.uber.org/zap"
 )
 
 type Logger struct {
-	lgr *zap.Logger
+	lgr   *zap.Logger
+	level zap.AtomicLevel
 }
 
-func NewLogger() (*Logger, error) {
-	lgr, err := zap.NewProduction(
+func NewLogger(
+	cfg *LoggerConfig,
+) (*Logger, error) {
+	level, err := zap.ParseAtomicLevel(cfg.Level)
+	if err != nil {
+		return nil, fmt.Errorf("Failed to parse the log level - %w", err)
+	}
+
+	zapConfig := zap.NewProductionConfig()
+	zapConfig.Level = level
+	lgr, err := zapConfig.Build(
 		zap.AddCaller(),
 	)
 	if err != nil {
@@ -23,16 +34,41 @@ func NewLogger() (*Logger, error) {
 	}
 	zap.ReplaceGlobals(lgr)
 	return &Logger{
-		lgr: lgr,
+		lgr:   lgr,
+		level: level,
 	}, nil
 }
 
+// SetLevel changes the level of the logs from now on
+func (l *Logger) SetLevel(
+	level string,
+) error {
+	if err := l.level.UnmarshalText([]byte(level)); err != nil {
+		return fmt.Errorf("Failed to parse the log level - %w", err)
+	}
+	return nil
+}
+
 func (l *Logger) Close() {
 	l.lgr.Sync()
 }
 
+// Get returns the logger, annotated with the trace and span ids
+// of the span in the context, if any
 func (l *Logger) Get(
 	ctx context.Context,
 ) *zap.Logger {
-	return l.lgr
+	if ctx == nil {
+		return l.lgr
+	}
+
+	spanContext := trace.SpanContextFromContext(ctx)
+	if !spanContext.IsValid() {
+		return l.lgr
+	}
+
+	return l.lgr.With(
+		zap.String("trace_id", spanContext.TraceID().String()),
+		zap.String("span_id", spanContext.SpanID().String()),
+	)
 }
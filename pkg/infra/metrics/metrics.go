@@ -0,0 +1,200 @@
+package metrics
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/collectors"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+	"github.com/ramasbeinaty/trading-chart-service/pkg/domain/base/metrics"
+)
+
+const (
+	NAMESPACE = "tcs"
+)
+
+// PrometheusMetrics records the service's metrics in its own registry,
+// along with the go runtime and process ones
+type PrometheusMetrics struct {
+	registry *prometheus.Registry
+
+	tradesReceived      *prometheus.CounterVec
+	tradesParsed        *prometheus.CounterVec
+	tradesFailed        *prometheus.CounterVec
+	websocketReconnects prometheus.Counter
+	tradeLag            *prometheus.HistogramVec
+
+	barsCommitted      *prometheus.CounterVec
+	barCommitErrors    *prometheus.CounterVec
+	barCommitLatency   *prometheus.HistogramVec
+	subscribers        *prometheus.GaugeVec
+	messagesSent       *prometheus.CounterVec
+	messagesDropped    *prometheus.CounterVec
+	grpcRequests       *prometheus.CounterVec
+	grpcRequestLatency *prometheus.HistogramVec
+}
+
+var _ metrics.IMetrics = (*PrometheusMetrics)(nil)
+
+func NewPrometheusMetrics() *PrometheusMetrics {
+	m := &PrometheusMetrics{
+		registry: prometheus.NewRegistry(),
+
+		tradesReceived: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: NAMESPACE,
+			Subsystem: "ingest",
+			Name:      "trades_received_total",
+			Help:      "Trade messages received from binance.",
+		}, []string{"symbol"}),
+		tradesParsed: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: NAMESPACE,
+			Subsystem: "ingest",
+			Name:      "trades_parsed_total",
+			Help:      "Trade messages parsed successfully.",
+		}, []string{"symbol"}),
+		tradesFailed: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: NAMESPACE,
+			Subsystem: "ingest",
+			Name:      "trades_failed_total",
+			Help:      "Trade messages that failed to parse.",
+		}, []string{"symbol"}),
+		websocketReconnects: prometheus.NewCounter(prometheus.CounterOpts{
+			Namespace: NAMESPACE,
+			Subsystem: "ingest",
+			Name:      "websocket_reconnects_total",
+			Help:      "Reconnections to the binance websocket.",
+		}),
+		tradeLag: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Namespace: NAMESPACE,
+			Subsystem: "ingest",
+			Name:      "trade_lag_seconds",
+			Help:      "Time from the binance event time to the trade being processed.",
+			Buckets:   []float64{.005, .01, .025, .05, .1, .25, .5, 1, 2.5, 5, 10},
+		}, []string{"symbol"}),
+
+		barsCommitted: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: NAMESPACE,
+			Subsystem: "aggregation",
+			Name:      "bars_committed_total",
+			Help:      "Closed candlestick bars committed to the db.",
+		}, []string{"symbol"}),
+		barCommitErrors: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: NAMESPACE,
+			Subsystem: "aggregation",
+			Name:      "bar_commit_errors_total",
+			Help:      "Closed candlestick bars that failed to be committed.",
+		}, []string{"symbol"}),
+		barCommitLatency: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Namespace: NAMESPACE,
+			Subsystem: "aggregation",
+			Name:      "bar_commit_duration_seconds",
+			Help:      "Time taken to commit a closed candlestick bar.",
+			Buckets:   prometheus.DefBuckets,
+		}, []string{"symbol"}),
+
+		subscribers: prometheus.NewGaugeVec(prometheus.GaugeOpts{
+			Namespace: NAMESPACE,
+			Subsystem: "fanout",
+			Name:      "subscribers",
+			Help:      "Active subscribers per symbol.",
+		}, []string{"symbol"}),
+		messagesSent: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: NAMESPACE,
+			Subsystem: "fanout",
+			Name:      "messages_sent_total",
+			Help:      "Candlestick updates sent to subscribers.",
+		}, []string{"symbol"}),
+		messagesDropped: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: NAMESPACE,
+			Subsystem: "fanout",
+			Name:      "messages_dropped_total",
+			Help:      "Candlestick updates that failed to reach a subscriber.",
+		}, []string{"symbol"}),
+
+		grpcRequests: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: NAMESPACE,
+			Subsystem: "grpc",
+			Name:      "requests_total",
+			Help:      "Completed grpc requests and streams.",
+		}, []string{"method", "code"}),
+		grpcRequestLatency: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Namespace: NAMESPACE,
+			Subsystem: "grpc",
+			Name:      "request_duration_seconds",
+			Help:      "Time taken to complete grpc requests and streams.",
+			Buckets:   prometheus.DefBuckets,
+		}, []string{"method"}),
+	}
+
+	m.registry.MustRegister(
+		collectors.NewGoCollector(),
+		collectors.NewProcessCollector(collectors.ProcessCollectorOpts{}),
+		m.tradesReceived,
+		m.tradesParsed,
+		m.tradesFailed,
+		m.websocketReconnects,
+		m.tradeLag,
+		m.barsCommitted,
+		m.barCommitErrors,
+		m.barCommitLatency,
+		m.subscribers,
+		m.messagesSent,
+		m.messagesDropped,
+		m.grpcRequests,
+		m.grpcRequestLatency,
+	)
+
+	return m
+}
+
+// Handler serves the metrics in the prometheus exposition format
+func (m *PrometheusMetrics) Handler() http.Handler {
+	return promhttp.HandlerFor(m.registry, promhttp.HandlerOpts{})
+}
+
+func (m *PrometheusMetrics) TradeReceived(symbol string) {
+	m.tradesReceived.WithLabelValues(symbol).Inc()
+}
+
+func (m *PrometheusMetrics) TradeParsed(symbol string) {
+	m.tradesParsed.WithLabelValues(symbol).Inc()
+}
+
+func (m *PrometheusMetrics) TradeFailed(symbol string) {
+	m.tradesFailed.WithLabelValues(symbol).Inc()
+}
+
+func (m *PrometheusMetrics) WebsocketReconnected() {
+	m.websocketReconnects.Inc()
+}
+
+func (m *PrometheusMetrics) ObserveTradeLag(symbol string, lag time.Duration) {
+	m.tradeLag.WithLabelValues(symbol).Observe(lag.Seconds())
+}
+
+func (m *PrometheusMetrics) BarCommitted(symbol string, latency time.Duration) {
+	m.barsCommitted.WithLabelValues(symbol).Inc()
+	m.barCommitLatency.WithLabelValues(symbol).Observe(latency.Seconds())
+}
+
+func (m *PrometheusMetrics) BarCommitFailed(symbol string) {
+	m.barCommitErrors.WithLabelValues(symbol).Inc()
+}
+
+func (m *PrometheusMetrics) SetSubscribers(symbol string, count int) {
+	m.subscribers.WithLabelValues(symbol).Set(float64(count))
+}
+
+func (m *PrometheusMetrics) MessageSent(symbol string) {
+	m.messagesSent.WithLabelValues(symbol).Inc()
+}
+
+func (m *PrometheusMetrics) MessageDropped(symbol string) {
+	m.messagesDropped.WithLabelValues(symbol).Inc()
+}
+
+func (m *PrometheusMetrics) ObserveGrpcRequest(method string, code string, duration time.Duration) {
+	m.grpcRequests.WithLabelValues(method, code).Inc()
+	m.grpcRequestLatency.WithLabelValues(method).Observe(duration.Seconds())
+}
@@ -0,0 +1,35 @@
+package alertrepo
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/ramasbeinaty/trading-chart-service/pkg/domain/alert"
+)
+
+func (repo *_alertrepo) CreateAlert(
+	ctx context.Context,
+	a *alert.Alert,
+) error {
+	_, err := repo.db.ExecContext(
+		ctx,
+		queryCreateAlert,
+		a.ID,
+		a.Symbol,
+		a.Timeframe,
+		a.Metric,
+		a.Condition,
+		a.Threshold,
+		a.Mode,
+		a.OnClose,
+		a.Owner,
+		a.Status,
+		a.CreatedAt,
+		a.UpdatedAt,
+	)
+	if err != nil {
+		return fmt.Errorf("Error: failed to create alert - %w", err)
+	}
+
+	return nil
+}
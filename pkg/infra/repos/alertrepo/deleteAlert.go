@@ -0,0 +1,18 @@
+package alertrepo
+
+import (
+	"context"
+	"fmt"
+)
+
+func (repo *_alertrepo) DeleteAlert(
+	ctx context.Context,
+	id int64,
+) (bool, error) {
+	res, err := repo.db.ExecContext(ctx, queryDeleteAlert, id)
+	if err != nil {
+		return false, fmt.Errorf("Error: failed to delete alert - %w", err)
+	}
+
+	return affectedAny(res)
+}
@@ -0,0 +1,98 @@
+package alertrepo
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+
+	"github.com/ramasbeinaty/trading-chart-service/pkg/domain/alert"
+)
+
+func (repo *_alertrepo) GetAlert(
+	ctx context.Context,
+	id int64,
+) (*alert.Alert, error) {
+	a, err := scanAlert(repo.db.QueryRowContext(ctx, queryGetAlert, id))
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("Error: failed to get alert - %w", err)
+	}
+
+	return a, nil
+}
+
+func (repo *_alertrepo) GetAlerts(
+	ctx context.Context,
+	symbol string,
+) ([]*alert.Alert, error) {
+	return repo.queryAlerts(ctx, queryGetAlerts, symbol)
+}
+
+func (repo *_alertrepo) GetActiveAlerts(
+	ctx context.Context,
+) ([]*alert.Alert, error) {
+	return repo.queryAlerts(ctx, queryGetActiveAlerts)
+}
+
+func (repo *_alertrepo) queryAlerts(
+	ctx context.Context,
+	query string,
+	args ...any,
+) ([]*alert.Alert, error) {
+	rows, err := repo.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("Error: failed to get alerts - %w", err)
+	}
+	defer rows.Close()
+
+	alerts := []*alert.Alert{}
+	for rows.Next() {
+		a, err := scanAlert(rows)
+		if err != nil {
+			return nil, fmt.Errorf("Error: failed to scan alert - %w", err)
+		}
+		alerts = append(alerts, a)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("Error: failed to get alerts - %w", err)
+	}
+
+	return alerts, nil
+}
+
+// scans a row selected with alertColumns
+func scanAlert(row interface{ Scan(dest ...any) error }) (*alert.Alert, error) {
+	var (
+		a           alert.Alert
+		lastFiredAt sql.NullTime
+	)
+
+	err := row.Scan(
+		&a.ID,
+		&a.Symbol,
+		&a.Timeframe,
+		&a.Metric,
+		&a.Condition,
+		&a.Threshold,
+		&a.Mode,
+		&a.OnClose,
+		&a.Owner,
+		&a.Status,
+		&a.FireCount,
+		&lastFiredAt,
+		&a.CreatedAt,
+		&a.UpdatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	if lastFiredAt.Valid {
+		a.LastFiredAt = lastFiredAt.Time
+	}
+
+	return &a, nil
+}
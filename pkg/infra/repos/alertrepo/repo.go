@@ -0,0 +1,115 @@
+package alertrepo
+
+import (
+	"database/sql"
+
+	"github.com/ramasbeinaty/trading-chart-service/pkg/domain/alert"
+)
+
+type _alertrepo struct {
+	db *sql.DB
+}
+
+var _ alert.IRepository = (*_alertrepo)(nil)
+
+func NewAlertRepository(db *sql.DB) *_alertrepo {
+	return &_alertrepo{
+		db: db,
+	}
+}
+
+// Queries
+const (
+	alertColumns = `
+		id, 
+		symbol, 
+		timeframe, 
+		metric, 
+		condition, 
+		threshold, 
+		mode, 
+		on_close, 
+		owner, 
+		status, 
+		fire_count, 
+		last_fired_at, 
+		created_at, 
+		updated_at
+	`
+
+	queryCreateAlert = `
+	INSERT INTO alert (
+		id, 
+		symbol, 
+		timeframe, 
+		metric, 
+		condition, 
+		threshold, 
+		mode, 
+		on_close, 
+		owner, 
+		status, 
+		created_at, 
+		updated_at
+		)
+	VALUES (
+		$1, 
+		$2, 
+		$3, 
+		$4, 
+		$5, 
+		$6, 
+		$7, 
+		$8, 
+		$9, 
+		$10, 
+		$11, 
+		$12
+		)
+	`
+
+	queryGetAlert = `
+	SELECT ` + alertColumns + `
+	FROM alert
+	WHERE id = $1
+	`
+
+	queryGetAlerts = `
+	SELECT ` + alertColumns + `
+	FROM alert
+	WHERE $1 = '' OR symbol = $1
+	ORDER BY created_at
+	`
+
+	queryGetActiveAlerts = `
+	SELECT ` + alertColumns + `
+	FROM alert
+	WHERE status = 'active'
+	`
+
+	queryUpdateAlert = `
+	UPDATE alert
+	SET symbol = $2,
+		timeframe = $3,
+		metric = $4,
+		condition = $5,
+		threshold = $6,
+		mode = $7,
+		on_close = $8,
+		status = $9
+	WHERE id = $1
+	`
+
+	queryUpdateAlertFired = `
+	UPDATE alert
+	SET status = $2,
+		fire_count = $3,
+		last_fired_at = $4
+	WHERE id = $1
+	`
+
+	queryDeleteAlert = `
+	DELETE FROM alert
+	WHERE id = $1
+	`
+)
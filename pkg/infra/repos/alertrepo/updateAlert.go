@@ -0,0 +1,60 @@
+package alertrepo
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+
+	"github.com/ramasbeinaty/trading-chart-service/pkg/domain/alert"
+)
+
+func (repo *_alertrepo) UpdateAlert(
+	ctx context.Context,
+	a *alert.Alert,
+) (bool, error) {
+	res, err := repo.db.ExecContext(
+		ctx,
+		queryUpdateAlert,
+		a.ID,
+		a.Symbol,
+		a.Timeframe,
+		a.Metric,
+		a.Condition,
+		a.Threshold,
+		a.Mode,
+		a.OnClose,
+		a.Status,
+	)
+	if err != nil {
+		return false, fmt.Errorf("Error: failed to update alert - %w", err)
+	}
+
+	return affectedAny(res)
+}
+
+func (repo *_alertrepo) UpdateAlertFired(
+	ctx context.Context,
+	a *alert.Alert,
+) error {
+	_, err := repo.db.ExecContext(
+		ctx,
+		queryUpdateAlertFired,
+		a.ID,
+		a.Status,
+		a.FireCount,
+		a.LastFiredAt,
+	)
+	if err != nil {
+		return fmt.Errorf("Error: failed to update fired alert - %w", err)
+	}
+
+	return nil
+}
+
+func affectedAny(res sql.Result) (bool, error) {
+	n, err := res.RowsAffected()
+	if err != nil {
+		return false, fmt.Errorf("Error: failed to get affected rows - %w", err)
+	}
+	return n > 0, nil
+}
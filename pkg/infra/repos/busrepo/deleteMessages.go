@@ -0,0 +1,20 @@
+package busrepo
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/lib/pq"
+)
+
+func (repo *_busrepo) DeleteMessages(
+	ctx context.Context,
+	ids []int64,
+) error {
+	_, err := repo.db.ExecContext(ctx, queryDeleteMessages, pq.Array(ids))
+	if err != nil {
+		return fmt.Errorf("Error: failed to delete bus messages - %w", err)
+	}
+
+	return nil
+}
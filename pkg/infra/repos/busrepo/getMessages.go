@@ -0,0 +1,33 @@
+package busrepo
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/ramasbeinaty/trading-chart-service/pkg/domain/bus"
+)
+
+func (repo *_busrepo) GetPendingMessages(
+	ctx context.Context,
+	limit int,
+) ([]*bus.Message, error) {
+	rows, err := repo.db.QueryContext(ctx, queryGetPendingMessages, limit)
+	if err != nil {
+		return nil, fmt.Errorf("Error: failed to get bus messages - %w", err)
+	}
+	defer rows.Close()
+
+	messages := []*bus.Message{}
+	for rows.Next() {
+		m := &bus.Message{}
+		if err := rows.Scan(&m.ID, &m.Subject, &m.Payload, &m.CreatedAt); err != nil {
+			return nil, fmt.Errorf("Error: failed to scan bus message - %w", err)
+		}
+		messages = append(messages, m)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("Error: failed to get bus messages - %w", err)
+	}
+
+	return messages, nil
+}
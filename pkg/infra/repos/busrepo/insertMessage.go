@@ -0,0 +1,30 @@
+package busrepo
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+
+	"github.com/ramasbeinaty/trading-chart-service/pkg/domain/bus"
+)
+
+// InsertMessage writes the message to the outbox within the transaction
+// writing the data it describes, setting its id
+func InsertMessage(
+	ctx context.Context,
+	tx *sql.Tx,
+	message *bus.Message,
+) error {
+	err := tx.QueryRowContext(
+		ctx,
+		queryInsertMessage,
+		message.Subject,
+		message.Payload,
+		message.CreatedAt,
+	).Scan(&message.ID)
+	if err != nil {
+		return fmt.Errorf("Error: failed to insert bus message - %w", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,52 @@
+package busrepo
+
+import (
+	"database/sql"
+
+	"github.com/ramasbeinaty/trading-chart-service/pkg/domain/bus"
+)
+
+type _busrepo struct {
+	db *sql.DB
+}
+
+var _ bus.IRepository = (*_busrepo)(nil)
+
+func NewBusRepository(db *sql.DB) *_busrepo {
+	return &_busrepo{
+		db: db,
+	}
+}
+
+// Queries
+const (
+	queryInsertMessage = `
+	INSERT INTO bus_outbox (
+		subject, 
+		payload, 
+		created_at
+		)
+	VALUES (
+		$1, 
+		$2, 
+		$3
+		)
+	RETURNING id
+	`
+
+	queryGetPendingMessages = `
+	SELECT 
+		id, 
+		subject, 
+		payload, 
+		created_at
+	FROM bus_outbox
+	ORDER BY id
+	LIMIT $1
+	`
+
+	queryDeleteMessages = `
+	DELETE FROM bus_outbox
+	WHERE id = ANY($1)
+	`
+)
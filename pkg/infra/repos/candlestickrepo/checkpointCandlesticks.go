@@ -0,0 +1,90 @@
+package candlestickrepo
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/ramasbeinaty/trading-chart-service/pkg/domain/candlestick"
+	"github.com/ramasbeinaty/trading-chart-service/pkg/infra/tracing"
+	"go.opentelemetry.io/otel/trace"
+)
+
+func (repo *_candlestickrepo) SaveInProgressBars(
+	ctx context.Context,
+	bars []*candlestick.Candlestick,
+) (err error) {
+	ctx, span := tracer.Start(
+		ctx,
+		"candlestickrepo.SaveInProgressBars",
+		trace.WithSpanKind(trace.SpanKindClient),
+	)
+	defer func() { tracing.EndSpan(span, err) }()
+
+	tx, err := repo.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("Error: failed to begin candlestickBars in progress transaction - %w", err)
+	}
+	defer tx.Rollback()
+
+	// the checkpoint replaces the previous one
+	_, err = tx.ExecContext(ctx, queryDeleteInProgressBars)
+	if err != nil {
+		return fmt.Errorf("Error: failed to delete candlestickBars in progress - %w", err)
+	}
+
+	for _, bar := range bars {
+		_, err = tx.ExecContext(
+			ctx,
+			queryInsertInProgressBar,
+			bar.Symbol,
+			bar.Open,
+			bar.High,
+			bar.Low,
+			bar.Close,
+			bar.TradeTimestamp,
+			bar.Sequence,
+		)
+		if err != nil {
+			return fmt.Errorf("Error: failed to insert candlestickBar in progress - %w", err)
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("Error: failed to commit candlestickBars in progress - %w", err)
+	}
+
+	return nil
+}
+
+func (repo *_candlestickrepo) GetInProgressBars(
+	ctx context.Context,
+) ([]*candlestick.Candlestick, error) {
+	rows, err := repo.db.QueryContext(ctx, queryGetInProgressBars)
+	if err != nil {
+		return nil, fmt.Errorf("Error: failed to get candlestickBars in progress - %w", err)
+	}
+	defer rows.Close()
+
+	bars := []*candlestick.Candlestick{}
+	for rows.Next() {
+		bar := &candlestick.Candlestick{}
+		err := rows.Scan(
+			&bar.Symbol,
+			&bar.Open,
+			&bar.High,
+			&bar.Low,
+			&bar.Close,
+			&bar.TradeTimestamp,
+			&bar.Sequence,
+		)
+		if err != nil {
+			return nil, fmt.Errorf("Error: failed to scan candlestickBar in progress - %w", err)
+		}
+		bars = append(bars, bar)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("Error: failed to get candlestickBars in progress - %w", err)
+	}
+
+	return bars, nil
+}
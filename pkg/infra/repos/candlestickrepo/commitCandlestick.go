@@ -0,0 +1,63 @@
+package candlestickrepo
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/ramasbeinaty/trading-chart-service/pkg/domain/bus"
+	"github.com/ramasbeinaty/trading-chart-service/pkg/domain/candlestick"
+	"github.com/ramasbeinaty/trading-chart-service/pkg/infra/repos/busrepo"
+	"github.com/ramasbeinaty/trading-chart-service/pkg/infra/tracing"
+)
+
+func (repo *_candlestickrepo) CommitCandlestickBar(
+	ctx context.Context,
+	bar *candlestick.Candlestick,
+	message *bus.Message,
+) (err error) {
+	ctx, span := startQuerySpan(ctx, "candlestickrepo.CommitCandlestickBar", bar.Symbol)
+	defer func() { tracing.EndSpan(span, err) }()
+
+	tx, err := repo.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("Error: failed to begin candlestickBar transaction - %w", err)
+	}
+	defer tx.Rollback()
+
+	_, err = tx.ExecContext(
+		ctx,
+		queryUpsertCandlestickBar,
+		bar.Symbol,
+		bar.Open,
+		bar.High,
+		bar.Low,
+		bar.Close,
+		bar.TradeTimestamp,
+	)
+	if err != nil {
+		return fmt.Errorf("Error: failed to upsert candlestickBar - %w", err)
+	}
+
+	// the bar is no longer in progress once committed
+	_, err = tx.ExecContext(
+		ctx,
+		queryDeleteInProgressBar,
+		bar.Symbol,
+		bar.TradeTimestamp,
+	)
+	if err != nil {
+		return fmt.Errorf("Error: failed to delete candlestickBar in progress - %w", err)
+	}
+
+	if message != nil {
+		if err := busrepo.InsertMessage(ctx, tx, message); err != nil {
+			return err
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("Error: failed to commit candlestickBar - %w", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,50 @@
+package candlestickrepo
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/ramasbeinaty/trading-chart-service/pkg/domain/candlestick"
+)
+
+func (repo *_candlestickrepo) GetCandlestickBars(
+	ctx context.Context,
+	symbol string,
+	from time.Time,
+	to time.Time,
+) ([]*candlestick.Candlestick, error) {
+	rows, err := repo.db.QueryContext(
+		ctx,
+		queryGetCandlestickBars,
+		symbol,
+		from,
+		to,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("Error: failed to get candlestickBars - %w", err)
+	}
+	defer rows.Close()
+
+	bars := []*candlestick.Candlestick{}
+	for rows.Next() {
+		bar := &candlestick.Candlestick{}
+		err := rows.Scan(
+			&bar.Symbol,
+			&bar.Open,
+			&bar.High,
+			&bar.Low,
+			&bar.Close,
+			&bar.TradeTimestamp,
+		)
+		if err != nil {
+			return nil, fmt.Errorf("Error: failed to scan candlestickBar - %w", err)
+		}
+		bars = append(bars, bar)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("Error: failed to get candlestickBars - %w", err)
+	}
+
+	return bars, nil
+}
@@ -0,0 +1,61 @@
+package candlestickrepo
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/ramasbeinaty/trading-chart-service/pkg/domain/candlestick"
+	"github.com/ramasbeinaty/trading-chart-service/pkg/domain/export"
+	"github.com/ramasbeinaty/trading-chart-service/pkg/infra/tracing"
+)
+
+var _ export.IRepository = (*_candlestickrepo)(nil)
+
+// GetCandlestickBarsPage reads the page in full before returning, releasing
+// the connection before the bars are used
+func (repo *_candlestickrepo) GetCandlestickBarsPage(
+	ctx context.Context,
+	symbol string,
+	from time.Time,
+	to time.Time,
+	limit int,
+) (bars []*candlestick.Candlestick, err error) {
+	ctx, span := startQuerySpan(ctx, "candlestickrepo.GetCandlestickBarsPage", symbol)
+	defer func() { tracing.EndSpan(span, err) }()
+
+	rows, err := repo.db.QueryContext(
+		ctx,
+		queryGetCandlestickBarsPage,
+		symbol,
+		from,
+		to,
+		limit,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("Error: failed to get candlestickBars page - %w", err)
+	}
+	defer rows.Close()
+
+	bars = make([]*candlestick.Candlestick, 0, limit)
+	for rows.Next() {
+		bar := &candlestick.Candlestick{}
+		err := rows.Scan(
+			&bar.Symbol,
+			&bar.Open,
+			&bar.High,
+			&bar.Low,
+			&bar.Close,
+			&bar.TradeTimestamp,
+		)
+		if err != nil {
+			return nil, fmt.Errorf("Error: failed to scan candlestickBar - %w", err)
+		}
+		bars = append(bars, bar)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("Error: failed to get candlestickBars page - %w", err)
+	}
+
+	return bars, nil
+}
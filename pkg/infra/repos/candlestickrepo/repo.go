@@ -1,9 +1,14 @@
 package candlestickrepo
 
 import (
+	"context"
 	"database/sql"
 
 	"github.com/ramasbeinaty/trading-chart-service/pkg/domain/candlestick"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	semconv "go.opentelemetry.io/otel/semconv/v1.26.0"
+	"go.opentelemetry.io/otel/trace"
 )
 
 type _candlestickrepo struct {
@@ -12,6 +17,25 @@ type _candlestickrepo struct {
 
 var _ candlestick.IRepository = (*_candlestickrepo)(nil)
 
+var tracer = otel.Tracer("github.com/ramasbeinaty/trading-chart-service/pkg/infra/repos/candlestickrepo")
+
+// starts a client span for the query on the bar's symbol
+func startQuerySpan(
+	ctx context.Context,
+	name string,
+	symbol string,
+) (context.Context, trace.Span) {
+	return tracer.Start(
+		ctx,
+		name,
+		trace.WithSpanKind(trace.SpanKindClient),
+		trace.WithAttributes(
+			semconv.DBSystemPostgreSQL,
+			attribute.String("symbol", symbol),
+		),
+	)
+}
+
 func NewCandlestickRepository(db *sql.DB) *_candlestickrepo {
 	return &_candlestickrepo{
 		db: db,
@@ -43,4 +67,79 @@ const (
     	low_price = EXCLUDED.low_price,
         close_price = EXCLUDED.close_price
 	`
+
+	queryGetCandlestickBars = `
+	SELECT 
+		symbol, 
+		open_price, 
+		high_price, 
+		low_price, 
+		close_price, 
+		trade_timestamp
+	FROM candlestick
+	WHERE symbol = $1
+		AND trade_timestamp >= $2
+		AND trade_timestamp <= $3
+	ORDER BY trade_timestamp
+	`
+
+	queryGetCandlestickBarsPage = `
+	SELECT 
+		symbol, 
+		open_price, 
+		high_price, 
+		low_price, 
+		close_price, 
+		trade_timestamp
+	FROM candlestick
+	WHERE symbol = $1
+		AND trade_timestamp >= $2
+		AND trade_timestamp <= $3
+	ORDER BY trade_timestamp
+	LIMIT $4
+	`
+
+	queryDeleteInProgressBars = `
+	DELETE FROM candlestick_inprogress
+	`
+
+	queryDeleteInProgressBar = `
+	DELETE FROM candlestick_inprogress
+	WHERE symbol = $1
+		AND trade_timestamp = $2
+	`
+
+	queryInsertInProgressBar = `
+	INSERT INTO candlestick_inprogress (
+		symbol,
+		open_price,
+		high_price,
+		low_price,
+		close_price,
+		trade_timestamp,
+		sequence
+		)
+	VALUES (
+		$1,
+		$2,
+		$3,
+		$4,
+		$5,
+		$6,
+		$7
+		)
+	`
+
+	queryGetInProgressBars = `
+	SELECT
+		symbol,
+		open_price,
+		high_price,
+		low_price,
+		close_price,
+		trade_timestamp,
+		sequence
+	FROM candlestick_inprogress
+	ORDER BY trade_timestamp
+	`
 )
@@ -5,13 +5,18 @@ import (
 	"fmt"
 
 	"github.com/ramasbeinaty/trading-chart-service/pkg/domain/candlestick"
+	"github.com/ramasbeinaty/trading-chart-service/pkg/infra/tracing"
 )
 
 func (repo *_candlestickrepo) UpsertCandlestickBar(
 	ctx context.Context,
 	bar *candlestick.Candlestick,
-) error {
-	_, err := repo.db.Exec(
+) (err error) {
+	ctx, span := startQuerySpan(ctx, "candlestickrepo.UpsertCandlestickBar", bar.Symbol)
+	defer func() { tracing.EndSpan(span, err) }()
+
+	_, err = repo.db.ExecContext(
+		ctx,
 		queryUpsertCandlestickBar,
 		bar.Symbol,
 		bar.Open,
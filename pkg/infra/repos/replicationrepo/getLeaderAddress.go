@@ -0,0 +1,26 @@
+package replicationrepo
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+)
+
+func (repo *_replicationrepo) GetLeaderAddress(
+	ctx context.Context,
+) (string, error) {
+	var address string
+	err := repo.db.QueryRowContext(
+		ctx,
+		queryGetLeaderAddress,
+	).Scan(&address)
+	if errors.Is(err, sql.ErrNoRows) {
+		return "", nil
+	}
+	if err != nil {
+		return "", fmt.Errorf("Error: failed to get leader address - %w", err)
+	}
+
+	return address, nil
+}
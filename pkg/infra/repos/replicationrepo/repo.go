@@ -0,0 +1,47 @@
+package replicationrepo
+
+import (
+	"database/sql"
+
+	"github.com/ramasbeinaty/trading-chart-service/pkg/domain/replication"
+)
+
+type _replicationrepo struct {
+	db *sql.DB
+}
+
+var _ replication.IRepository = (*_replicationrepo)(nil)
+
+func NewReplicationRepository(db *sql.DB) *_replicationrepo {
+	return &_replicationrepo{
+		db: db,
+	}
+}
+
+// Queries
+const (
+	// the table holds a single row
+	querySetLeaderAddress = `
+	INSERT INTO replication_leader (
+		id, 
+		address, 
+		elected_at
+		)
+	VALUES (
+		1, 
+		$1, 
+		NOW()
+		)
+	ON CONFLICT (id) 
+	DO UPDATE
+	SET address = EXCLUDED.address,
+		elected_at = EXCLUDED.elected_at
+	`
+
+	queryGetLeaderAddress = `
+	SELECT 
+		address
+	FROM replication_leader
+	WHERE id = 1
+	`
+)
@@ -0,0 +1,22 @@
+package replicationrepo
+
+import (
+	"context"
+	"fmt"
+)
+
+func (repo *_replicationrepo) SetLeaderAddress(
+	ctx context.Context,
+	address string,
+) error {
+	_, err := repo.db.ExecContext(
+		ctx,
+		querySetLeaderAddress,
+		address,
+	)
+	if err != nil {
+		return fmt.Errorf("Error: failed to set leader address - %w", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,31 @@
+package webhookrepo
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/ramasbeinaty/trading-chart-service/pkg/domain/webhook"
+)
+
+func (repo *_webhookrepo) CreateDelivery(
+	ctx context.Context,
+	delivery *webhook.Delivery,
+) error {
+	err := repo.db.QueryRowContext(
+		ctx,
+		queryCreateDelivery,
+		delivery.EventID,
+		delivery.Target,
+		delivery.EventType,
+		string(delivery.Payload), // sent as text, as []byte is sent as bytea
+		delivery.Attempts,
+		delivery.NextAttemptAt,
+		delivery.LastError,
+		delivery.Status,
+	).Scan(&delivery.ID)
+	if err != nil {
+		return fmt.Errorf("Error: failed to create webhook delivery - %w", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,18 @@
+package webhookrepo
+
+import (
+	"context"
+	"fmt"
+)
+
+func (repo *_webhookrepo) DeleteDelivery(
+	ctx context.Context,
+	id int64,
+) error {
+	_, err := repo.db.ExecContext(ctx, queryDeleteDelivery, id)
+	if err != nil {
+		return fmt.Errorf("Error: failed to delete webhook delivery - %w", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,46 @@
+package webhookrepo
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/ramasbeinaty/trading-chart-service/pkg/domain/webhook"
+)
+
+func (repo *_webhookrepo) GetDueDeliveries(
+	ctx context.Context,
+	now time.Time,
+	limit int,
+) ([]*webhook.Delivery, error) {
+	rows, err := repo.db.QueryContext(ctx, queryGetDueDeliveries, now, limit)
+	if err != nil {
+		return nil, fmt.Errorf("Error: failed to get webhook deliveries - %w", err)
+	}
+	defer rows.Close()
+
+	deliveries := []*webhook.Delivery{}
+	for rows.Next() {
+		d := &webhook.Delivery{}
+		err := rows.Scan(
+			&d.ID,
+			&d.EventID,
+			&d.Target,
+			&d.EventType,
+			&d.Payload,
+			&d.Attempts,
+			&d.NextAttemptAt,
+			&d.LastError,
+			&d.Status,
+		)
+		if err != nil {
+			return nil, fmt.Errorf("Error: failed to scan webhook delivery - %w", err)
+		}
+		deliveries = append(deliveries, d)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("Error: failed to get webhook deliveries - %w", err)
+	}
+
+	return deliveries, nil
+}
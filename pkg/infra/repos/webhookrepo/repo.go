@@ -0,0 +1,78 @@
+package webhookrepo
+
+import (
+	"database/sql"
+
+	"github.com/ramasbeinaty/trading-chart-service/pkg/domain/webhook"
+)
+
+type _webhookrepo struct {
+	db *sql.DB
+}
+
+var _ webhook.IRepository = (*_webhookrepo)(nil)
+
+func NewWebhookRepository(db *sql.DB) *_webhookrepo {
+	return &_webhookrepo{
+		db: db,
+	}
+}
+
+// Queries
+const (
+	queryCreateDelivery = `
+	INSERT INTO webhook_outbox (
+		event_id, 
+		target, 
+		event_type, 
+		payload, 
+		attempts, 
+		next_attempt_at, 
+		last_error, 
+		status
+		)
+	VALUES (
+		$1, 
+		$2, 
+		$3, 
+		$4, 
+		$5, 
+		$6, 
+		$7, 
+		$8
+		)
+	RETURNING id
+	`
+
+	queryGetDueDeliveries = `
+	SELECT 
+		id, 
+		event_id, 
+		target, 
+		event_type, 
+		payload, 
+		attempts, 
+		next_attempt_at, 
+		last_error, 
+		status
+	FROM webhook_outbox
+	WHERE status = 'pending'
+		AND next_attempt_at <= $1
+	ORDER BY next_attempt_at
+	LIMIT $2
+	`
+
+	queryUpdateDelivery = `
+	UPDATE webhook_outbox
+	SET attempts = $2,
+		next_attempt_at = $3,
+		last_error = $4,
+		status = $5
+	WHERE id = $1
+	`
+
+	queryDeleteDelivery = `
+	DELETE FROM webhook_outbox
+	WHERE id = $1
+	`
+)
@@ -0,0 +1,28 @@
+package webhookrepo
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/ramasbeinaty/trading-chart-service/pkg/domain/webhook"
+)
+
+func (repo *_webhookrepo) UpdateDelivery(
+	ctx context.Context,
+	delivery *webhook.Delivery,
+) error {
+	_, err := repo.db.ExecContext(
+		ctx,
+		queryUpdateDelivery,
+		delivery.ID,
+		delivery.Attempts,
+		delivery.NextAttemptAt,
+		delivery.LastError,
+		delivery.Status,
+	)
+	if err != nil {
+		return fmt.Errorf("Error: failed to update webhook delivery - %w", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,12 @@
+package tracing
+
+type TracingConfig struct {
+	// spans are exported to this otlp grpc collector, e.g. localhost:4317,
+	// or not exported at all if empty
+	// they are still created for the trace ids to be propagated and logged
+	OtlpEndpoint string
+	Insecure     bool
+	// ratio of the new traces sampled, traces continued from a caller
+	// follow its sampling decision
+	SampleRatio float64
+}
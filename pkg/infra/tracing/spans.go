@@ -0,0 +1,18 @@
+package tracing
+
+import (
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// EndSpan ends the span, marking it as failed with the error if any
+func EndSpan(
+	span trace.Span,
+	err error,
+) {
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+	}
+	span.End()
+}
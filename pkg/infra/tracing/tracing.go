@@ -0,0 +1,82 @@
+package tracing
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/ramasbeinaty/trading-chart-service/internal"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracegrpc"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/sdk/resource"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	semconv "go.opentelemetry.io/otel/semconv/v1.26.0"
+)
+
+// Tracer sets up the global opentelemetry tracer provider
+// and the W3C trace context and baggage propagators
+type Tracer struct {
+	provider *sdktrace.TracerProvider
+}
+
+func NewTracer(
+	ctx context.Context,
+	config *TracingConfig,
+) (*Tracer, error) {
+	res, err := resource.Merge(
+		resource.Default(),
+		resource.NewWithAttributes(
+			semconv.SchemaURL,
+			semconv.ServiceName(internal.SERVICE_NAME),
+		),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("Failed to create tracing resource - %w", err)
+	}
+
+	opts := []sdktrace.TracerProviderOption{
+		sdktrace.WithResource(res),
+		sdktrace.WithSampler(
+			sdktrace.ParentBased(sdktrace.TraceIDRatioBased(config.SampleRatio)),
+		),
+	}
+
+	if config.OtlpEndpoint != "" {
+		exporterOpts := []otlptracegrpc.Option{
+			otlptracegrpc.WithEndpoint(config.OtlpEndpoint),
+		}
+		if config.Insecure {
+			exporterOpts = append(exporterOpts, otlptracegrpc.WithInsecure())
+		}
+
+		exporter, err := otlptracegrpc.New(ctx, exporterOpts...)
+		if err != nil {
+			return nil, fmt.Errorf("Failed to create otlp trace exporter - %w", err)
+		}
+		opts = append(opts, sdktrace.WithBatcher(exporter))
+	}
+
+	provider := sdktrace.NewTracerProvider(opts...)
+
+	otel.SetTracerProvider(provider)
+	otel.SetTextMapPropagator(
+		propagation.NewCompositeTextMapPropagator(
+			propagation.TraceContext{},
+			propagation.Baggage{},
+		),
+	)
+
+	return &Tracer{
+		provider: provider,
+	}, nil
+}
+
+// Shutdown exports the remaining spans and stops the exporter
+func (t *Tracer) Shutdown(
+	ctx context.Context,
+) error {
+	if err := t.provider.Shutdown(ctx); err != nil {
+		return fmt.Errorf("Failed to shutdown tracer provider - %w", err)
+	}
+	return nil
+}
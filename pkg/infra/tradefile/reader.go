@@ -0,0 +1,89 @@
+package tradefile
+
+import (
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/ramasbeinaty/trading-chart-service/pkg/domain/replay"
+)
+
+// the columns of the file, in order
+var columns = []string{"symbol", "price", "timestamp"}
+
+// TradeFileReader reads the trades recorded in a csv file of
+// symbol,price,timestamp rows, the header row being optional
+// timestamps are written in unix milliseconds, or in RFC 3339
+type TradeFileReader struct {
+	file *os.File
+	csv  *csv.Reader
+}
+
+var _ replay.ITradeReader = (*TradeFileReader)(nil)
+
+func NewTradeFileReader(
+	path string,
+) (*TradeFileReader, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("Failed to open the trades file - %w", err)
+	}
+
+	reader := csv.NewReader(file)
+	reader.FieldsPerRecord = len(columns)
+	reader.TrimLeadingSpace = true
+	reader.ReuseRecord = true
+
+	return &TradeFileReader{
+		file: file,
+		csv:  reader,
+	}, nil
+}
+
+func (r *TradeFileReader) Next() (*replay.Trade, error) {
+	record, err := r.csv.Read()
+	if err != nil {
+		if errors.Is(err, io.EOF) {
+			return nil, io.EOF
+		}
+		return nil, fmt.Errorf("Failed to read the trades file - %w", err)
+	}
+
+	line, _ := r.csv.FieldPos(0)
+	if line == 1 && strings.EqualFold(record[0], columns[0]) {
+		return r.Next()
+	}
+
+	price, err := strconv.ParseFloat(record[1], 64)
+	if err != nil {
+		return nil, fmt.Errorf("Failed to parse the price of line %d - %w", line, err)
+	}
+	timestamp, err := parseTimestamp(record[2])
+	if err != nil {
+		return nil, fmt.Errorf("Failed to parse the timestamp of line %d - %w", line, err)
+	}
+
+	return &replay.Trade{
+		Symbol:    strings.ToUpper(record[0]),
+		Price:     price,
+		Timestamp: timestamp,
+	}, nil
+}
+
+func (r *TradeFileReader) Close() error {
+	return r.file.Close()
+}
+
+func parseTimestamp(
+	raw string,
+) (time.Time, error) {
+	if millis, err := strconv.ParseInt(raw, 10, 64); err == nil {
+		return time.UnixMilli(millis).UTC(), nil
+	}
+	return time.Parse(time.RFC3339Nano, raw)
+}
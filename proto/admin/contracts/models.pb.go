@@ -0,0 +1,704 @@
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// versions:
+// 	protoc-gen-go v1.34.2
+// 	protoc        v4.24.0--rc2
+// source: proto/admin/contracts/models.proto
+
+package contracts
+
+import (
+	contracts "github.com/ramasbeinaty/trading-chart-service/proto/candlestick/contracts"
+	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
+	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	timestamppb "google.golang.org/protobuf/types/known/timestamppb"
+	reflect "reflect"
+	sync "sync"
+)
+
+const (
+	// Verify that this generated code is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(20 - protoimpl.MinVersion)
+	// Verify that runtime/protoimpl is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
+)
+
+type Subscriber struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Id int64 `protobuf:"varint,1,opt,name=id,proto3" json:"id,omitempty"`
+	// remote address of the subscriber
+	Peer string `protobuf:"bytes,2,opt,name=peer,proto3" json:"peer,omitempty"`
+	// METHOD:ID of the client that subscribed, empty if authentication is disabled
+	Principal string `protobuf:"bytes,3,opt,name=principal,proto3" json:"principal,omitempty"`
+	// symbols and patterns, as subscribed
+	Symbols     []string               `protobuf:"bytes,4,rep,name=symbols,proto3" json:"symbols,omitempty"`
+	ConnectedAt *timestamppb.Timestamp `protobuf:"bytes,5,opt,name=connected_at,json=connectedAt,proto3" json:"connected_at,omitempty"`
+	// live updates queued for the subscriber, waiting on its transport
+	QueueDepth int64 `protobuf:"varint,6,opt,name=queue_depth,json=queueDepth,proto3" json:"queue_depth,omitempty"`
+	// events delivered to the subscriber
+	MessagesSent uint64 `protobuf:"varint,7,opt,name=messages_sent,json=messagesSent,proto3" json:"messages_sent,omitempty"`
+}
+
+func (x *Subscriber) Reset() {
+	*x = Subscriber{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_proto_admin_contracts_models_proto_msgTypes[0]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *Subscriber) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Subscriber) ProtoMessage() {}
+
+func (x *Subscriber) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_admin_contracts_models_proto_msgTypes[0]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Subscriber.ProtoReflect.Descriptor instead.
+func (*Subscriber) Descriptor() ([]byte, []int) {
+	return file_proto_admin_contracts_models_proto_rawDescGZIP(), []int{0}
+}
+
+func (x *Subscriber) GetId() int64 {
+	if x != nil {
+		return x.Id
+	}
+	return 0
+}
+
+func (x *Subscriber) GetPeer() string {
+	if x != nil {
+		return x.Peer
+	}
+	return ""
+}
+
+func (x *Subscriber) GetPrincipal() string {
+	if x != nil {
+		return x.Principal
+	}
+	return ""
+}
+
+func (x *Subscriber) GetSymbols() []string {
+	if x != nil {
+		return x.Symbols
+	}
+	return nil
+}
+
+func (x *Subscriber) GetConnectedAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.ConnectedAt
+	}
+	return nil
+}
+
+func (x *Subscriber) GetQueueDepth() int64 {
+	if x != nil {
+		return x.QueueDepth
+	}
+	return 0
+}
+
+func (x *Subscriber) GetMessagesSent() uint64 {
+	if x != nil {
+		return x.MessagesSent
+	}
+	return 0
+}
+
+type ListSubscribersRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+}
+
+func (x *ListSubscribersRequest) Reset() {
+	*x = ListSubscribersRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_proto_admin_contracts_models_proto_msgTypes[1]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *ListSubscribersRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListSubscribersRequest) ProtoMessage() {}
+
+func (x *ListSubscribersRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_admin_contracts_models_proto_msgTypes[1]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListSubscribersRequest.ProtoReflect.Descriptor instead.
+func (*ListSubscribersRequest) Descriptor() ([]byte, []int) {
+	return file_proto_admin_contracts_models_proto_rawDescGZIP(), []int{1}
+}
+
+type ListSubscribersResponse struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Subscribers []*Subscriber `protobuf:"bytes,1,rep,name=subscribers,proto3" json:"subscribers,omitempty"`
+}
+
+func (x *ListSubscribersResponse) Reset() {
+	*x = ListSubscribersResponse{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_proto_admin_contracts_models_proto_msgTypes[2]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *ListSubscribersResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListSubscribersResponse) ProtoMessage() {}
+
+func (x *ListSubscribersResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_admin_contracts_models_proto_msgTypes[2]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListSubscribersResponse.ProtoReflect.Descriptor instead.
+func (*ListSubscribersResponse) Descriptor() ([]byte, []int) {
+	return file_proto_admin_contracts_models_proto_rawDescGZIP(), []int{2}
+}
+
+func (x *ListSubscribersResponse) GetSubscribers() []*Subscriber {
+	if x != nil {
+		return x.Subscribers
+	}
+	return nil
+}
+
+type DisconnectSubscriberRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Id int64 `protobuf:"varint,1,opt,name=id,proto3" json:"id,omitempty"`
+	// told to the subscriber along with the disconnection
+	Reason string `protobuf:"bytes,2,opt,name=reason,proto3" json:"reason,omitempty"`
+}
+
+func (x *DisconnectSubscriberRequest) Reset() {
+	*x = DisconnectSubscriberRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_proto_admin_contracts_models_proto_msgTypes[3]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *DisconnectSubscriberRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DisconnectSubscriberRequest) ProtoMessage() {}
+
+func (x *DisconnectSubscriberRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_admin_contracts_models_proto_msgTypes[3]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DisconnectSubscriberRequest.ProtoReflect.Descriptor instead.
+func (*DisconnectSubscriberRequest) Descriptor() ([]byte, []int) {
+	return file_proto_admin_contracts_models_proto_rawDescGZIP(), []int{3}
+}
+
+func (x *DisconnectSubscriberRequest) GetId() int64 {
+	if x != nil {
+		return x.Id
+	}
+	return 0
+}
+
+func (x *DisconnectSubscriberRequest) GetReason() string {
+	if x != nil {
+		return x.Reason
+	}
+	return ""
+}
+
+type DisconnectSubscriberResponse struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+}
+
+func (x *DisconnectSubscriberResponse) Reset() {
+	*x = DisconnectSubscriberResponse{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_proto_admin_contracts_models_proto_msgTypes[4]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *DisconnectSubscriberResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DisconnectSubscriberResponse) ProtoMessage() {}
+
+func (x *DisconnectSubscriberResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_admin_contracts_models_proto_msgTypes[4]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DisconnectSubscriberResponse.ProtoReflect.Descriptor instead.
+func (*DisconnectSubscriberResponse) Descriptor() ([]byte, []int) {
+	return file_proto_admin_contracts_models_proto_rawDescGZIP(), []int{4}
+}
+
+type GetInProgressBarsRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+}
+
+func (x *GetInProgressBarsRequest) Reset() {
+	*x = GetInProgressBarsRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_proto_admin_contracts_models_proto_msgTypes[5]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *GetInProgressBarsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetInProgressBarsRequest) ProtoMessage() {}
+
+func (x *GetInProgressBarsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_admin_contracts_models_proto_msgTypes[5]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetInProgressBarsRequest.ProtoReflect.Descriptor instead.
+func (*GetInProgressBarsRequest) Descriptor() ([]byte, []int) {
+	return file_proto_admin_contracts_models_proto_rawDescGZIP(), []int{5}
+}
+
+type GetInProgressBarsResponse struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// ordered by symbol then time
+	Bars []*contracts.Candlestick `protobuf:"bytes,1,rep,name=bars,proto3" json:"bars,omitempty"`
+}
+
+func (x *GetInProgressBarsResponse) Reset() {
+	*x = GetInProgressBarsResponse{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_proto_admin_contracts_models_proto_msgTypes[6]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *GetInProgressBarsResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetInProgressBarsResponse) ProtoMessage() {}
+
+func (x *GetInProgressBarsResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_admin_contracts_models_proto_msgTypes[6]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetInProgressBarsResponse.ProtoReflect.Descriptor instead.
+func (*GetInProgressBarsResponse) Descriptor() ([]byte, []int) {
+	return file_proto_admin_contracts_models_proto_rawDescGZIP(), []int{6}
+}
+
+func (x *GetInProgressBarsResponse) GetBars() []*contracts.Candlestick {
+	if x != nil {
+		return x.Bars
+	}
+	return nil
+}
+
+type CommitBarsRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+}
+
+func (x *CommitBarsRequest) Reset() {
+	*x = CommitBarsRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_proto_admin_contracts_models_proto_msgTypes[7]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *CommitBarsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CommitBarsRequest) ProtoMessage() {}
+
+func (x *CommitBarsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_admin_contracts_models_proto_msgTypes[7]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CommitBarsRequest.ProtoReflect.Descriptor instead.
+func (*CommitBarsRequest) Descriptor() ([]byte, []int) {
+	return file_proto_admin_contracts_models_proto_rawDescGZIP(), []int{7}
+}
+
+type CommitBarsResponse struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// bars left in memory once committed, i.e. the ones in progress
+	InProgressBars int32 `protobuf:"varint,1,opt,name=in_progress_bars,json=inProgressBars,proto3" json:"in_progress_bars,omitempty"`
+}
+
+func (x *CommitBarsResponse) Reset() {
+	*x = CommitBarsResponse{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_proto_admin_contracts_models_proto_msgTypes[8]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *CommitBarsResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CommitBarsResponse) ProtoMessage() {}
+
+func (x *CommitBarsResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_admin_contracts_models_proto_msgTypes[8]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CommitBarsResponse.ProtoReflect.Descriptor instead.
+func (*CommitBarsResponse) Descriptor() ([]byte, []int) {
+	return file_proto_admin_contracts_models_proto_rawDescGZIP(), []int{8}
+}
+
+func (x *CommitBarsResponse) GetInProgressBars() int32 {
+	if x != nil {
+		return x.InProgressBars
+	}
+	return 0
+}
+
+var File_proto_admin_contracts_models_proto protoreflect.FileDescriptor
+
+var file_proto_admin_contracts_models_proto_rawDesc = []byte{
+	0x0a, 0x22, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x2f, 0x61, 0x64, 0x6d, 0x69, 0x6e, 0x2f, 0x63, 0x6f,
+	0x6e, 0x74, 0x72, 0x61, 0x63, 0x74, 0x73, 0x2f, 0x6d, 0x6f, 0x64, 0x65, 0x6c, 0x73, 0x2e, 0x70,
+	0x72, 0x6f, 0x74, 0x6f, 0x12, 0x05, 0x61, 0x64, 0x6d, 0x69, 0x6e, 0x1a, 0x1f, 0x67, 0x6f, 0x6f,
+	0x67, 0x6c, 0x65, 0x2f, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x2f, 0x74, 0x69, 0x6d,
+	0x65, 0x73, 0x74, 0x61, 0x6d, 0x70, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x1a, 0x28, 0x70, 0x72,
+	0x6f, 0x74, 0x6f, 0x2f, 0x63, 0x61, 0x6e, 0x64, 0x6c, 0x65, 0x73, 0x74, 0x69, 0x63, 0x6b, 0x2f,
+	0x63, 0x6f, 0x6e, 0x74, 0x72, 0x61, 0x63, 0x74, 0x73, 0x2f, 0x6d, 0x6f, 0x64, 0x65, 0x6c, 0x73,
+	0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x22, 0xed, 0x01, 0x0a, 0x0a, 0x53, 0x75, 0x62, 0x73, 0x63,
+	0x72, 0x69, 0x62, 0x65, 0x72, 0x12, 0x0e, 0x0a, 0x02, 0x69, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28,
+	0x03, 0x52, 0x02, 0x69, 0x64, 0x12, 0x12, 0x0a, 0x04, 0x70, 0x65, 0x65, 0x72, 0x18, 0x02, 0x20,
+	0x01, 0x28, 0x09, 0x52, 0x04, 0x70, 0x65, 0x65, 0x72, 0x12, 0x1c, 0x0a, 0x09, 0x70, 0x72, 0x69,
+	0x6e, 0x63, 0x69, 0x70, 0x61, 0x6c, 0x18, 0x03, 0x20, 0x01, 0x28, 0x09, 0x52, 0x09, 0x70, 0x72,
+	0x69, 0x6e, 0x63, 0x69, 0x70, 0x61, 0x6c, 0x12, 0x18, 0x0a, 0x07, 0x73, 0x79, 0x6d, 0x62, 0x6f,
+	0x6c, 0x73, 0x18, 0x04, 0x20, 0x03, 0x28, 0x09, 0x52, 0x07, 0x73, 0x79, 0x6d, 0x62, 0x6f, 0x6c,
+	0x73, 0x12, 0x3d, 0x0a, 0x0c, 0x63, 0x6f, 0x6e, 0x6e, 0x65, 0x63, 0x74, 0x65, 0x64, 0x5f, 0x61,
+	0x74, 0x18, 0x05, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x1a, 0x2e, 0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65,
+	0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x2e, 0x54, 0x69, 0x6d, 0x65, 0x73, 0x74,
+	0x61, 0x6d, 0x70, 0x52, 0x0b, 0x63, 0x6f, 0x6e, 0x6e, 0x65, 0x63, 0x74, 0x65, 0x64, 0x41, 0x74,
+	0x12, 0x1f, 0x0a, 0x0b, 0x71, 0x75, 0x65, 0x75, 0x65, 0x5f, 0x64, 0x65, 0x70, 0x74, 0x68, 0x18,
+	0x06, 0x20, 0x01, 0x28, 0x03, 0x52, 0x0a, 0x71, 0x75, 0x65, 0x75, 0x65, 0x44, 0x65, 0x70, 0x74,
+	0x68, 0x12, 0x23, 0x0a, 0x0d, 0x6d, 0x65, 0x73, 0x73, 0x61, 0x67, 0x65, 0x73, 0x5f, 0x73, 0x65,
+	0x6e, 0x74, 0x18, 0x07, 0x20, 0x01, 0x28, 0x04, 0x52, 0x0c, 0x6d, 0x65, 0x73, 0x73, 0x61, 0x67,
+	0x65, 0x73, 0x53, 0x65, 0x6e, 0x74, 0x22, 0x18, 0x0a, 0x16, 0x4c, 0x69, 0x73, 0x74, 0x53, 0x75,
+	0x62, 0x73, 0x63, 0x72, 0x69, 0x62, 0x65, 0x72, 0x73, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74,
+	0x22, 0x4e, 0x0a, 0x17, 0x4c, 0x69, 0x73, 0x74, 0x53, 0x75, 0x62, 0x73, 0x63, 0x72, 0x69, 0x62,
+	0x65, 0x72, 0x73, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x33, 0x0a, 0x0b, 0x73,
+	0x75, 0x62, 0x73, 0x63, 0x72, 0x69, 0x62, 0x65, 0x72, 0x73, 0x18, 0x01, 0x20, 0x03, 0x28, 0x0b,
+	0x32, 0x11, 0x2e, 0x61, 0x64, 0x6d, 0x69, 0x6e, 0x2e, 0x53, 0x75, 0x62, 0x73, 0x63, 0x72, 0x69,
+	0x62, 0x65, 0x72, 0x52, 0x0b, 0x73, 0x75, 0x62, 0x73, 0x63, 0x72, 0x69, 0x62, 0x65, 0x72, 0x73,
+	0x22, 0x45, 0x0a, 0x1b, 0x44, 0x69, 0x73, 0x63, 0x6f, 0x6e, 0x6e, 0x65, 0x63, 0x74, 0x53, 0x75,
+	0x62, 0x73, 0x63, 0x72, 0x69, 0x62, 0x65, 0x72, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12,
+	0x0e, 0x0a, 0x02, 0x69, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x03, 0x52, 0x02, 0x69, 0x64, 0x12,
+	0x16, 0x0a, 0x06, 0x72, 0x65, 0x61, 0x73, 0x6f, 0x6e, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52,
+	0x06, 0x72, 0x65, 0x61, 0x73, 0x6f, 0x6e, 0x22, 0x1e, 0x0a, 0x1c, 0x44, 0x69, 0x73, 0x63, 0x6f,
+	0x6e, 0x6e, 0x65, 0x63, 0x74, 0x53, 0x75, 0x62, 0x73, 0x63, 0x72, 0x69, 0x62, 0x65, 0x72, 0x52,
+	0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x22, 0x1a, 0x0a, 0x18, 0x47, 0x65, 0x74, 0x49, 0x6e,
+	0x50, 0x72, 0x6f, 0x67, 0x72, 0x65, 0x73, 0x73, 0x42, 0x61, 0x72, 0x73, 0x52, 0x65, 0x71, 0x75,
+	0x65, 0x73, 0x74, 0x22, 0x49, 0x0a, 0x19, 0x47, 0x65, 0x74, 0x49, 0x6e, 0x50, 0x72, 0x6f, 0x67,
+	0x72, 0x65, 0x73, 0x73, 0x42, 0x61, 0x72, 0x73, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65,
+	0x12, 0x2c, 0x0a, 0x04, 0x62, 0x61, 0x72, 0x73, 0x18, 0x01, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x18,
+	0x2e, 0x63, 0x61, 0x6e, 0x64, 0x6c, 0x65, 0x73, 0x74, 0x69, 0x63, 0x6b, 0x2e, 0x43, 0x61, 0x6e,
+	0x64, 0x6c, 0x65, 0x73, 0x74, 0x69, 0x63, 0x6b, 0x52, 0x04, 0x62, 0x61, 0x72, 0x73, 0x22, 0x13,
+	0x0a, 0x11, 0x43, 0x6f, 0x6d, 0x6d, 0x69, 0x74, 0x42, 0x61, 0x72, 0x73, 0x52, 0x65, 0x71, 0x75,
+	0x65, 0x73, 0x74, 0x22, 0x3e, 0x0a, 0x12, 0x43, 0x6f, 0x6d, 0x6d, 0x69, 0x74, 0x42, 0x61, 0x72,
+	0x73, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x28, 0x0a, 0x10, 0x69, 0x6e, 0x5f,
+	0x70, 0x72, 0x6f, 0x67, 0x72, 0x65, 0x73, 0x73, 0x5f, 0x62, 0x61, 0x72, 0x73, 0x18, 0x01, 0x20,
+	0x01, 0x28, 0x05, 0x52, 0x0e, 0x69, 0x6e, 0x50, 0x72, 0x6f, 0x67, 0x72, 0x65, 0x73, 0x73, 0x42,
+	0x61, 0x72, 0x73, 0x42, 0x45, 0x5a, 0x43, 0x67, 0x69, 0x74, 0x68, 0x75, 0x62, 0x2e, 0x63, 0x6f,
+	0x6d, 0x2f, 0x72, 0x61, 0x6d, 0x61, 0x73, 0x62, 0x65, 0x69, 0x6e, 0x61, 0x74, 0x79, 0x2f, 0x74,
+	0x72, 0x61, 0x64, 0x69, 0x6e, 0x67, 0x2d, 0x63, 0x68, 0x61, 0x72, 0x74, 0x2d, 0x73, 0x65, 0x72,
+	0x76, 0x69, 0x63, 0x65, 0x2f, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x2f, 0x61, 0x64, 0x6d, 0x69, 0x6e,
+	0x2f, 0x63, 0x6f, 0x6e, 0x74, 0x72, 0x61, 0x63, 0x74, 0x73, 0x62, 0x06, 0x70, 0x72, 0x6f, 0x74,
+	0x6f, 0x33,
+}
+
+var (
+	file_proto_admin_contracts_models_proto_rawDescOnce sync.Once
+	file_proto_admin_contracts_models_proto_rawDescData = file_proto_admin_contracts_models_proto_rawDesc
+)
+
+func file_proto_admin_contracts_models_proto_rawDescGZIP() []byte {
+	file_proto_admin_contracts_models_proto_rawDescOnce.Do(func() {
+		file_proto_admin_contracts_models_proto_rawDescData = protoimpl.X.CompressGZIP(file_proto_admin_contracts_models_proto_rawDescData)
+	})
+	return file_proto_admin_contracts_models_proto_rawDescData
+}
+
+var file_proto_admin_contracts_models_proto_msgTypes = make([]protoimpl.MessageInfo, 9)
+var file_proto_admin_contracts_models_proto_goTypes = []any{
+	(*Subscriber)(nil),                   // 0: admin.Subscriber
+	(*ListSubscribersRequest)(nil),       // 1: admin.ListSubscribersRequest
+	(*ListSubscribersResponse)(nil),      // 2: admin.ListSubscribersResponse
+	(*DisconnectSubscriberRequest)(nil),  // 3: admin.DisconnectSubscriberRequest
+	(*DisconnectSubscriberResponse)(nil), // 4: admin.DisconnectSubscriberResponse
+	(*GetInProgressBarsRequest)(nil),     // 5: admin.GetInProgressBarsRequest
+	(*GetInProgressBarsResponse)(nil),    // 6: admin.GetInProgressBarsResponse
+	(*CommitBarsRequest)(nil),            // 7: admin.CommitBarsRequest
+	(*CommitBarsResponse)(nil),           // 8: admin.CommitBarsResponse
+	(*timestamppb.Timestamp)(nil),        // 9: google.protobuf.Timestamp
+	(*contracts.Candlestick)(nil),        // 10: candlestick.Candlestick
+}
+var file_proto_admin_contracts_models_proto_depIdxs = []int32{
+	9,  // 0: admin.Subscriber.connected_at:type_name -> google.protobuf.Timestamp
+	0,  // 1: admin.ListSubscribersResponse.subscribers:type_name -> admin.Subscriber
+	10, // 2: admin.GetInProgressBarsResponse.bars:type_name -> candlestick.Candlestick
+	3,  // [3:3] is the sub-list for method output_type
+	3,  // [3:3] is the sub-list for method input_type
+	3,  // [3:3] is the sub-list for extension type_name
+	3,  // [3:3] is the sub-list for extension extendee
+	0,  // [0:3] is the sub-list for field type_name
+}
+
+func init() { file_proto_admin_contracts_models_proto_init() }
+func file_proto_admin_contracts_models_proto_init() {
+	if File_proto_admin_contracts_models_proto != nil {
+		return
+	}
+	if !protoimpl.UnsafeEnabled {
+		file_proto_admin_contracts_models_proto_msgTypes[0].Exporter = func(v any, i int) any {
+			switch v := v.(*Subscriber); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_proto_admin_contracts_models_proto_msgTypes[1].Exporter = func(v any, i int) any {
+			switch v := v.(*ListSubscribersRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_proto_admin_contracts_models_proto_msgTypes[2].Exporter = func(v any, i int) any {
+			switch v := v.(*ListSubscribersResponse); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_proto_admin_contracts_models_proto_msgTypes[3].Exporter = func(v any, i int) any {
+			switch v := v.(*DisconnectSubscriberRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_proto_admin_contracts_models_proto_msgTypes[4].Exporter = func(v any, i int) any {
+			switch v := v.(*DisconnectSubscriberResponse); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_proto_admin_contracts_models_proto_msgTypes[5].Exporter = func(v any, i int) any {
+			switch v := v.(*GetInProgressBarsRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_proto_admin_contracts_models_proto_msgTypes[6].Exporter = func(v any, i int) any {
+			switch v := v.(*GetInProgressBarsResponse); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_proto_admin_contracts_models_proto_msgTypes[7].Exporter = func(v any, i int) any {
+			switch v := v.(*CommitBarsRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_proto_admin_contracts_models_proto_msgTypes[8].Exporter = func(v any, i int) any {
+			switch v := v.(*CommitBarsResponse); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+	}
+	type x struct{}
+	out := protoimpl.TypeBuilder{
+		File: protoimpl.DescBuilder{
+			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
+			RawDescriptor: file_proto_admin_contracts_models_proto_rawDesc,
+			NumEnums:      0,
+			NumMessages:   9,
+			NumExtensions: 0,
+			NumServices:   0,
+		},
+		GoTypes:           file_proto_admin_contracts_models_proto_goTypes,
+		DependencyIndexes: file_proto_admin_contracts_models_proto_depIdxs,
+		MessageInfos:      file_proto_admin_contracts_models_proto_msgTypes,
+	}.Build()
+	File_proto_admin_contracts_models_proto = out.File
+	file_proto_admin_contracts_models_proto_rawDesc = nil
+	file_proto_admin_contracts_models_proto_goTypes = nil
+	file_proto_admin_contracts_models_proto_depIdxs = nil
+}
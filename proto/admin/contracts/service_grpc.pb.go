@@ -0,0 +1,251 @@
+// Code generated by protoc-gen-go-grpc. DO NOT EDIT.
+// versions:
+// - protoc-gen-go-grpc v1.5.1
+// - protoc             v4.24.0--rc2
+// source: proto/admin/contracts/service.proto
+
+package contracts
+
+import (
+	context "context"
+	grpc "google.golang.org/grpc"
+	codes "google.golang.org/grpc/codes"
+	status "google.golang.org/grpc/status"
+)
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the grpc package it is being compiled against.
+// Requires gRPC-Go v1.64.0 or later.
+const _ = grpc.SupportPackageIsVersion9
+
+const (
+	AdminService_ListSubscribers_FullMethodName      = "/admin.AdminService/ListSubscribers"
+	AdminService_DisconnectSubscriber_FullMethodName = "/admin.AdminService/DisconnectSubscriber"
+	AdminService_GetInProgressBars_FullMethodName    = "/admin.AdminService/GetInProgressBars"
+	AdminService_CommitBars_FullMethodName           = "/admin.AdminService/CommitBars"
+)
+
+// AdminServiceClient is the client API for AdminService service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
+//
+// introspects and manages the live state of an instance, for the operators
+// only the admins may call it once authentication is enabled
+type AdminServiceClient interface {
+	// lists the live subscribers of the instance
+	ListSubscribers(ctx context.Context, in *ListSubscribersRequest, opts ...grpc.CallOption) (*ListSubscribersResponse, error)
+	// terminates the subscriber's stream, telling it the reason
+	DisconnectSubscriber(ctx context.Context, in *DisconnectSubscriberRequest, opts ...grpc.CallOption) (*DisconnectSubscriberResponse, error)
+	// dumps the bars held in memory, in progress or waiting to be committed
+	GetInProgressBars(ctx context.Context, in *GetInProgressBarsRequest, opts ...grpc.CallOption) (*GetInProgressBarsResponse, error)
+	// commits the bars that ended now, without waiting for the next minute,
+	// served by the ingesting leader only
+	CommitBars(ctx context.Context, in *CommitBarsRequest, opts ...grpc.CallOption) (*CommitBarsResponse, error)
+}
+
+type adminServiceClient struct {
+	cc grpc.ClientConnInterface
+}
+
+func NewAdminServiceClient(cc grpc.ClientConnInterface) AdminServiceClient {
+	return &adminServiceClient{cc}
+}
+
+func (c *adminServiceClient) ListSubscribers(ctx context.Context, in *ListSubscribersRequest, opts ...grpc.CallOption) (*ListSubscribersResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ListSubscribersResponse)
+	err := c.cc.Invoke(ctx, AdminService_ListSubscribers_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *adminServiceClient) DisconnectSubscriber(ctx context.Context, in *DisconnectSubscriberRequest, opts ...grpc.CallOption) (*DisconnectSubscriberResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(DisconnectSubscriberResponse)
+	err := c.cc.Invoke(ctx, AdminService_DisconnectSubscriber_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *adminServiceClient) GetInProgressBars(ctx context.Context, in *GetInProgressBarsRequest, opts ...grpc.CallOption) (*GetInProgressBarsResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(GetInProgressBarsResponse)
+	err := c.cc.Invoke(ctx, AdminService_GetInProgressBars_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *adminServiceClient) CommitBars(ctx context.Context, in *CommitBarsRequest, opts ...grpc.CallOption) (*CommitBarsResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(CommitBarsResponse)
+	err := c.cc.Invoke(ctx, AdminService_CommitBars_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// AdminServiceServer is the server API for AdminService service.
+// All implementations must embed UnimplementedAdminServiceServer
+// for forward compatibility.
+//
+// introspects and manages the live state of an instance, for the operators
+// only the admins may call it once authentication is enabled
+type AdminServiceServer interface {
+	// lists the live subscribers of the instance
+	ListSubscribers(context.Context, *ListSubscribersRequest) (*ListSubscribersResponse, error)
+	// terminates the subscriber's stream, telling it the reason
+	DisconnectSubscriber(context.Context, *DisconnectSubscriberRequest) (*DisconnectSubscriberResponse, error)
+	// dumps the bars held in memory, in progress or waiting to be committed
+	GetInProgressBars(context.Context, *GetInProgressBarsRequest) (*GetInProgressBarsResponse, error)
+	// commits the bars that ended now, without waiting for the next minute,
+	// served by the ingesting leader only
+	CommitBars(context.Context, *CommitBarsRequest) (*CommitBarsResponse, error)
+	mustEmbedUnimplementedAdminServiceServer()
+}
+
+// UnimplementedAdminServiceServer must be embedded to have
+// forward compatible implementations.
+//
+// NOTE: this should be embedded by value instead of pointer to avoid a nil
+// pointer dereference when methods are called.
+type UnimplementedAdminServiceServer struct{}
+
+func (UnimplementedAdminServiceServer) ListSubscribers(context.Context, *ListSubscribersRequest) (*ListSubscribersResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListSubscribers not implemented")
+}
+func (UnimplementedAdminServiceServer) DisconnectSubscriber(context.Context, *DisconnectSubscriberRequest) (*DisconnectSubscriberResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method DisconnectSubscriber not implemented")
+}
+func (UnimplementedAdminServiceServer) GetInProgressBars(context.Context, *GetInProgressBarsRequest) (*GetInProgressBarsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetInProgressBars not implemented")
+}
+func (UnimplementedAdminServiceServer) CommitBars(context.Context, *CommitBarsRequest) (*CommitBarsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method CommitBars not implemented")
+}
+func (UnimplementedAdminServiceServer) mustEmbedUnimplementedAdminServiceServer() {}
+func (UnimplementedAdminServiceServer) testEmbeddedByValue()                      {}
+
+// UnsafeAdminServiceServer may be embedded to opt out of forward compatibility for this service.
+// Use of this interface is not recommended, as added methods to AdminServiceServer will
+// result in compilation errors.
+type UnsafeAdminServiceServer interface {
+	mustEmbedUnimplementedAdminServiceServer()
+}
+
+func RegisterAdminServiceServer(s grpc.ServiceRegistrar, srv AdminServiceServer) {
+	// If the following call pancis, it indicates UnimplementedAdminServiceServer was
+	// embedded by pointer and is nil.  This will cause panics if an
+	// unimplemented method is ever invoked, so we test this at initialization
+	// time to prevent it from happening at runtime later due to I/O.
+	if t, ok := srv.(interface{ testEmbeddedByValue() }); ok {
+		t.testEmbeddedByValue()
+	}
+	s.RegisterService(&AdminService_ServiceDesc, srv)
+}
+
+func _AdminService_ListSubscribers_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListSubscribersRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AdminServiceServer).ListSubscribers(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: AdminService_ListSubscribers_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AdminServiceServer).ListSubscribers(ctx, req.(*ListSubscribersRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _AdminService_DisconnectSubscriber_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(DisconnectSubscriberRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AdminServiceServer).DisconnectSubscriber(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: AdminService_DisconnectSubscriber_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AdminServiceServer).DisconnectSubscriber(ctx, req.(*DisconnectSubscriberRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _AdminService_GetInProgressBars_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetInProgressBarsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AdminServiceServer).GetInProgressBars(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: AdminService_GetInProgressBars_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AdminServiceServer).GetInProgressBars(ctx, req.(*GetInProgressBarsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _AdminService_CommitBars_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(CommitBarsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AdminServiceServer).CommitBars(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: AdminService_CommitBars_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AdminServiceServer).CommitBars(ctx, req.(*CommitBarsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+// AdminService_ServiceDesc is the grpc.ServiceDesc for AdminService service.
+// It's only intended for direct use with grpc.RegisterService,
+// and not to be introspected or modified (even as a copy)
+var AdminService_ServiceDesc = grpc.ServiceDesc{
+	ServiceName: "admin.AdminService",
+	HandlerType: (*AdminServiceServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "ListSubscribers",
+			Handler:    _AdminService_ListSubscribers_Handler,
+		},
+		{
+			MethodName: "DisconnectSubscriber",
+			Handler:    _AdminService_DisconnectSubscriber_Handler,
+		},
+		{
+			MethodName: "GetInProgressBars",
+			Handler:    _AdminService_GetInProgressBars_Handler,
+		},
+		{
+			MethodName: "CommitBars",
+			Handler:    _AdminService_CommitBars_Handler,
+		},
+	},
+	Streams:  []grpc.StreamDesc{},
+	Metadata: "proto/admin/contracts/service.proto",
+}
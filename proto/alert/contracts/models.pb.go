@@ -0,0 +1,1279 @@
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// versions:
+// 	protoc-gen-go v1.34.2
+// 	protoc        v4.24.0--rc2
+// source: proto/alert/contracts/models.proto
+
+package contracts
+
+import (
+	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
+	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	timestamppb "google.golang.org/protobuf/types/known/timestamppb"
+	reflect "reflect"
+	sync "sync"
+)
+
+const (
+	// Verify that this generated code is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(20 - protoimpl.MinVersion)
+	// Verify that runtime/protoimpl is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
+)
+
+type AlertMetric int32
+
+const (
+	// the close price of the bar
+	AlertMetric_ALERT_METRIC_CLOSE AlertMetric = 0
+	// the range of the bar, (high - low) / low, in percent
+	AlertMetric_ALERT_METRIC_RANGE_PERCENT AlertMetric = 1
+	// the change of the bar, (close - open) / open, in percent
+	AlertMetric_ALERT_METRIC_CHANGE_PERCENT AlertMetric = 2
+)
+
+// Enum value maps for AlertMetric.
+var (
+	AlertMetric_name = map[int32]string{
+		0: "ALERT_METRIC_CLOSE",
+		1: "ALERT_METRIC_RANGE_PERCENT",
+		2: "ALERT_METRIC_CHANGE_PERCENT",
+	}
+	AlertMetric_value = map[string]int32{
+		"ALERT_METRIC_CLOSE":          0,
+		"ALERT_METRIC_RANGE_PERCENT":  1,
+		"ALERT_METRIC_CHANGE_PERCENT": 2,
+	}
+)
+
+func (x AlertMetric) Enum() *AlertMetric {
+	p := new(AlertMetric)
+	*p = x
+	return p
+}
+
+func (x AlertMetric) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (AlertMetric) Descriptor() protoreflect.EnumDescriptor {
+	return file_proto_alert_contracts_models_proto_enumTypes[0].Descriptor()
+}
+
+func (AlertMetric) Type() protoreflect.EnumType {
+	return &file_proto_alert_contracts_models_proto_enumTypes[0]
+}
+
+func (x AlertMetric) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use AlertMetric.Descriptor instead.
+func (AlertMetric) EnumDescriptor() ([]byte, []int) {
+	return file_proto_alert_contracts_models_proto_rawDescGZIP(), []int{0}
+}
+
+type AlertCondition int32
+
+const (
+	// the metric goes from below the threshold to at or above it
+	AlertCondition_ALERT_CONDITION_CROSSES_ABOVE AlertCondition = 0
+	// the metric goes from above the threshold to at or below it
+	AlertCondition_ALERT_CONDITION_CROSSES_BELOW AlertCondition = 1
+	// the metric is above the threshold
+	AlertCondition_ALERT_CONDITION_ABOVE AlertCondition = 2
+	// the metric is below the threshold
+	AlertCondition_ALERT_CONDITION_BELOW AlertCondition = 3
+)
+
+// Enum value maps for AlertCondition.
+var (
+	AlertCondition_name = map[int32]string{
+		0: "ALERT_CONDITION_CROSSES_ABOVE",
+		1: "ALERT_CONDITION_CROSSES_BELOW",
+		2: "ALERT_CONDITION_ABOVE",
+		3: "ALERT_CONDITION_BELOW",
+	}
+	AlertCondition_value = map[string]int32{
+		"ALERT_CONDITION_CROSSES_ABOVE": 0,
+		"ALERT_CONDITION_CROSSES_BELOW": 1,
+		"ALERT_CONDITION_ABOVE":         2,
+		"ALERT_CONDITION_BELOW":         3,
+	}
+)
+
+func (x AlertCondition) Enum() *AlertCondition {
+	p := new(AlertCondition)
+	*p = x
+	return p
+}
+
+func (x AlertCondition) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (AlertCondition) Descriptor() protoreflect.EnumDescriptor {
+	return file_proto_alert_contracts_models_proto_enumTypes[1].Descriptor()
+}
+
+func (AlertCondition) Type() protoreflect.EnumType {
+	return &file_proto_alert_contracts_models_proto_enumTypes[1]
+}
+
+func (x AlertCondition) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use AlertCondition.Descriptor instead.
+func (AlertCondition) EnumDescriptor() ([]byte, []int) {
+	return file_proto_alert_contracts_models_proto_rawDescGZIP(), []int{1}
+}
+
+type AlertMode int32
+
+const (
+	// the alert fires once, then stays triggered
+	AlertMode_ALERT_MODE_ONCE AlertMode = 0
+	// the alert fires again once its condition stopped holding and holds again
+	AlertMode_ALERT_MODE_REARM AlertMode = 1
+)
+
+// Enum value maps for AlertMode.
+var (
+	AlertMode_name = map[int32]string{
+		0: "ALERT_MODE_ONCE",
+		1: "ALERT_MODE_REARM",
+	}
+	AlertMode_value = map[string]int32{
+		"ALERT_MODE_ONCE":  0,
+		"ALERT_MODE_REARM": 1,
+	}
+)
+
+func (x AlertMode) Enum() *AlertMode {
+	p := new(AlertMode)
+	*p = x
+	return p
+}
+
+func (x AlertMode) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (AlertMode) Descriptor() protoreflect.EnumDescriptor {
+	return file_proto_alert_contracts_models_proto_enumTypes[2].Descriptor()
+}
+
+func (AlertMode) Type() protoreflect.EnumType {
+	return &file_proto_alert_contracts_models_proto_enumTypes[2]
+}
+
+func (x AlertMode) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use AlertMode.Descriptor instead.
+func (AlertMode) EnumDescriptor() ([]byte, []int) {
+	return file_proto_alert_contracts_models_proto_rawDescGZIP(), []int{2}
+}
+
+type AlertStatus int32
+
+const (
+	AlertStatus_ALERT_STATUS_ACTIVE AlertStatus = 0
+	// a once alert that fired, it is no longer evaluated
+	AlertStatus_ALERT_STATUS_TRIGGERED AlertStatus = 1
+)
+
+// Enum value maps for AlertStatus.
+var (
+	AlertStatus_name = map[int32]string{
+		0: "ALERT_STATUS_ACTIVE",
+		1: "ALERT_STATUS_TRIGGERED",
+	}
+	AlertStatus_value = map[string]int32{
+		"ALERT_STATUS_ACTIVE":    0,
+		"ALERT_STATUS_TRIGGERED": 1,
+	}
+)
+
+func (x AlertStatus) Enum() *AlertStatus {
+	p := new(AlertStatus)
+	*p = x
+	return p
+}
+
+func (x AlertStatus) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (AlertStatus) Descriptor() protoreflect.EnumDescriptor {
+	return file_proto_alert_contracts_models_proto_enumTypes[3].Descriptor()
+}
+
+func (AlertStatus) Type() protoreflect.EnumType {
+	return &file_proto_alert_contracts_models_proto_enumTypes[3]
+}
+
+func (x AlertStatus) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use AlertStatus.Descriptor instead.
+func (AlertStatus) EnumDescriptor() ([]byte, []int) {
+	return file_proto_alert_contracts_models_proto_rawDescGZIP(), []int{3}
+}
+
+type Alert struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Id     int64  `protobuf:"varint,1,opt,name=id,proto3" json:"id,omitempty"`
+	Symbol string `protobuf:"bytes,2,opt,name=symbol,proto3" json:"symbol,omitempty"`
+	// timeframe of the bars the metric is computed on, e.g. "1m", "5m", "15m" or "1h"
+	Timeframe string         `protobuf:"bytes,3,opt,name=timeframe,proto3" json:"timeframe,omitempty"`
+	Metric    AlertMetric    `protobuf:"varint,4,opt,name=metric,proto3,enum=alert.AlertMetric" json:"metric,omitempty"`
+	Condition AlertCondition `protobuf:"varint,5,opt,name=condition,proto3,enum=alert.AlertCondition" json:"condition,omitempty"`
+	Threshold float64        `protobuf:"fixed64,6,opt,name=threshold,proto3" json:"threshold,omitempty"`
+	Mode      AlertMode      `protobuf:"varint,7,opt,name=mode,proto3,enum=alert.AlertMode" json:"mode,omitempty"`
+	// only evaluate closed bars, otherwise every bar update is evaluated
+	OnClose     bool                   `protobuf:"varint,8,opt,name=on_close,json=onClose,proto3" json:"on_close,omitempty"`
+	Status      AlertStatus            `protobuf:"varint,9,opt,name=status,proto3,enum=alert.AlertStatus" json:"status,omitempty"`
+	FireCount   uint64                 `protobuf:"varint,10,opt,name=fire_count,json=fireCount,proto3" json:"fire_count,omitempty"`
+	LastFiredAt *timestamppb.Timestamp `protobuf:"bytes,11,opt,name=last_fired_at,json=lastFiredAt,proto3" json:"last_fired_at,omitempty"`
+	CreatedAt   *timestamppb.Timestamp `protobuf:"bytes,12,opt,name=created_at,json=createdAt,proto3" json:"created_at,omitempty"`
+	UpdatedAt   *timestamppb.Timestamp `protobuf:"bytes,13,opt,name=updated_at,json=updatedAt,proto3" json:"updated_at,omitempty"`
+}
+
+func (x *Alert) Reset() {
+	*x = Alert{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_proto_alert_contracts_models_proto_msgTypes[0]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *Alert) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Alert) ProtoMessage() {}
+
+func (x *Alert) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_alert_contracts_models_proto_msgTypes[0]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Alert.ProtoReflect.Descriptor instead.
+func (*Alert) Descriptor() ([]byte, []int) {
+	return file_proto_alert_contracts_models_proto_rawDescGZIP(), []int{0}
+}
+
+func (x *Alert) GetId() int64 {
+	if x != nil {
+		return x.Id
+	}
+	return 0
+}
+
+func (x *Alert) GetSymbol() string {
+	if x != nil {
+		return x.Symbol
+	}
+	return ""
+}
+
+func (x *Alert) GetTimeframe() string {
+	if x != nil {
+		return x.Timeframe
+	}
+	return ""
+}
+
+func (x *Alert) GetMetric() AlertMetric {
+	if x != nil {
+		return x.Metric
+	}
+	return AlertMetric_ALERT_METRIC_CLOSE
+}
+
+func (x *Alert) GetCondition() AlertCondition {
+	if x != nil {
+		return x.Condition
+	}
+	return AlertCondition_ALERT_CONDITION_CROSSES_ABOVE
+}
+
+func (x *Alert) GetThreshold() float64 {
+	if x != nil {
+		return x.Threshold
+	}
+	return 0
+}
+
+func (x *Alert) GetMode() AlertMode {
+	if x != nil {
+		return x.Mode
+	}
+	return AlertMode_ALERT_MODE_ONCE
+}
+
+func (x *Alert) GetOnClose() bool {
+	if x != nil {
+		return x.OnClose
+	}
+	return false
+}
+
+func (x *Alert) GetStatus() AlertStatus {
+	if x != nil {
+		return x.Status
+	}
+	return AlertStatus_ALERT_STATUS_ACTIVE
+}
+
+func (x *Alert) GetFireCount() uint64 {
+	if x != nil {
+		return x.FireCount
+	}
+	return 0
+}
+
+func (x *Alert) GetLastFiredAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.LastFiredAt
+	}
+	return nil
+}
+
+func (x *Alert) GetCreatedAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.CreatedAt
+	}
+	return nil
+}
+
+func (x *Alert) GetUpdatedAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.UpdatedAt
+	}
+	return nil
+}
+
+type CreateAlertRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Symbol string `protobuf:"bytes,1,opt,name=symbol,proto3" json:"symbol,omitempty"`
+	// defaults to "1m"
+	Timeframe string         `protobuf:"bytes,2,opt,name=timeframe,proto3" json:"timeframe,omitempty"`
+	Metric    AlertMetric    `protobuf:"varint,3,opt,name=metric,proto3,enum=alert.AlertMetric" json:"metric,omitempty"`
+	Condition AlertCondition `protobuf:"varint,4,opt,name=condition,proto3,enum=alert.AlertCondition" json:"condition,omitempty"`
+	Threshold float64        `protobuf:"fixed64,5,opt,name=threshold,proto3" json:"threshold,omitempty"`
+	Mode      AlertMode      `protobuf:"varint,6,opt,name=mode,proto3,enum=alert.AlertMode" json:"mode,omitempty"`
+	OnClose   bool           `protobuf:"varint,7,opt,name=on_close,json=onClose,proto3" json:"on_close,omitempty"`
+}
+
+func (x *CreateAlertRequest) Reset() {
+	*x = CreateAlertRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_proto_alert_contracts_models_proto_msgTypes[1]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *CreateAlertRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CreateAlertRequest) ProtoMessage() {}
+
+func (x *CreateAlertRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_alert_contracts_models_proto_msgTypes[1]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CreateAlertRequest.ProtoReflect.Descriptor instead.
+func (*CreateAlertRequest) Descriptor() ([]byte, []int) {
+	return file_proto_alert_contracts_models_proto_rawDescGZIP(), []int{1}
+}
+
+func (x *CreateAlertRequest) GetSymbol() string {
+	if x != nil {
+		return x.Symbol
+	}
+	return ""
+}
+
+func (x *CreateAlertRequest) GetTimeframe() string {
+	if x != nil {
+		return x.Timeframe
+	}
+	return ""
+}
+
+func (x *CreateAlertRequest) GetMetric() AlertMetric {
+	if x != nil {
+		return x.Metric
+	}
+	return AlertMetric_ALERT_METRIC_CLOSE
+}
+
+func (x *CreateAlertRequest) GetCondition() AlertCondition {
+	if x != nil {
+		return x.Condition
+	}
+	return AlertCondition_ALERT_CONDITION_CROSSES_ABOVE
+}
+
+func (x *CreateAlertRequest) GetThreshold() float64 {
+	if x != nil {
+		return x.Threshold
+	}
+	return 0
+}
+
+func (x *CreateAlertRequest) GetMode() AlertMode {
+	if x != nil {
+		return x.Mode
+	}
+	return AlertMode_ALERT_MODE_ONCE
+}
+
+func (x *CreateAlertRequest) GetOnClose() bool {
+	if x != nil {
+		return x.OnClose
+	}
+	return false
+}
+
+// replaces the rule of the alert, re-activating it
+type UpdateAlertRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Id     int64  `protobuf:"varint,1,opt,name=id,proto3" json:"id,omitempty"`
+	Symbol string `protobuf:"bytes,2,opt,name=symbol,proto3" json:"symbol,omitempty"`
+	// defaults to "1m"
+	Timeframe string         `protobuf:"bytes,3,opt,name=timeframe,proto3" json:"timeframe,omitempty"`
+	Metric    AlertMetric    `protobuf:"varint,4,opt,name=metric,proto3,enum=alert.AlertMetric" json:"metric,omitempty"`
+	Condition AlertCondition `protobuf:"varint,5,opt,name=condition,proto3,enum=alert.AlertCondition" json:"condition,omitempty"`
+	Threshold float64        `protobuf:"fixed64,6,opt,name=threshold,proto3" json:"threshold,omitempty"`
+	Mode      AlertMode      `protobuf:"varint,7,opt,name=mode,proto3,enum=alert.AlertMode" json:"mode,omitempty"`
+	OnClose   bool           `protobuf:"varint,8,opt,name=on_close,json=onClose,proto3" json:"on_close,omitempty"`
+}
+
+func (x *UpdateAlertRequest) Reset() {
+	*x = UpdateAlertRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_proto_alert_contracts_models_proto_msgTypes[2]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *UpdateAlertRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*UpdateAlertRequest) ProtoMessage() {}
+
+func (x *UpdateAlertRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_alert_contracts_models_proto_msgTypes[2]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use UpdateAlertRequest.ProtoReflect.Descriptor instead.
+func (*UpdateAlertRequest) Descriptor() ([]byte, []int) {
+	return file_proto_alert_contracts_models_proto_rawDescGZIP(), []int{2}
+}
+
+func (x *UpdateAlertRequest) GetId() int64 {
+	if x != nil {
+		return x.Id
+	}
+	return 0
+}
+
+func (x *UpdateAlertRequest) GetSymbol() string {
+	if x != nil {
+		return x.Symbol
+	}
+	return ""
+}
+
+func (x *UpdateAlertRequest) GetTimeframe() string {
+	if x != nil {
+		return x.Timeframe
+	}
+	return ""
+}
+
+func (x *UpdateAlertRequest) GetMetric() AlertMetric {
+	if x != nil {
+		return x.Metric
+	}
+	return AlertMetric_ALERT_METRIC_CLOSE
+}
+
+func (x *UpdateAlertRequest) GetCondition() AlertCondition {
+	if x != nil {
+		return x.Condition
+	}
+	return AlertCondition_ALERT_CONDITION_CROSSES_ABOVE
+}
+
+func (x *UpdateAlertRequest) GetThreshold() float64 {
+	if x != nil {
+		return x.Threshold
+	}
+	return 0
+}
+
+func (x *UpdateAlertRequest) GetMode() AlertMode {
+	if x != nil {
+		return x.Mode
+	}
+	return AlertMode_ALERT_MODE_ONCE
+}
+
+func (x *UpdateAlertRequest) GetOnClose() bool {
+	if x != nil {
+		return x.OnClose
+	}
+	return false
+}
+
+type GetAlertRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Id int64 `protobuf:"varint,1,opt,name=id,proto3" json:"id,omitempty"`
+}
+
+func (x *GetAlertRequest) Reset() {
+	*x = GetAlertRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_proto_alert_contracts_models_proto_msgTypes[3]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *GetAlertRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetAlertRequest) ProtoMessage() {}
+
+func (x *GetAlertRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_alert_contracts_models_proto_msgTypes[3]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetAlertRequest.ProtoReflect.Descriptor instead.
+func (*GetAlertRequest) Descriptor() ([]byte, []int) {
+	return file_proto_alert_contracts_models_proto_rawDescGZIP(), []int{3}
+}
+
+func (x *GetAlertRequest) GetId() int64 {
+	if x != nil {
+		return x.Id
+	}
+	return 0
+}
+
+type ListAlertsRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// lists the alerts of every symbol if empty
+	Symbol string `protobuf:"bytes,1,opt,name=symbol,proto3" json:"symbol,omitempty"`
+}
+
+func (x *ListAlertsRequest) Reset() {
+	*x = ListAlertsRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_proto_alert_contracts_models_proto_msgTypes[4]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *ListAlertsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListAlertsRequest) ProtoMessage() {}
+
+func (x *ListAlertsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_alert_contracts_models_proto_msgTypes[4]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListAlertsRequest.ProtoReflect.Descriptor instead.
+func (*ListAlertsRequest) Descriptor() ([]byte, []int) {
+	return file_proto_alert_contracts_models_proto_rawDescGZIP(), []int{4}
+}
+
+func (x *ListAlertsRequest) GetSymbol() string {
+	if x != nil {
+		return x.Symbol
+	}
+	return ""
+}
+
+type ListAlertsResponse struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Alerts []*Alert `protobuf:"bytes,1,rep,name=alerts,proto3" json:"alerts,omitempty"`
+}
+
+func (x *ListAlertsResponse) Reset() {
+	*x = ListAlertsResponse{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_proto_alert_contracts_models_proto_msgTypes[5]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *ListAlertsResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListAlertsResponse) ProtoMessage() {}
+
+func (x *ListAlertsResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_alert_contracts_models_proto_msgTypes[5]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListAlertsResponse.ProtoReflect.Descriptor instead.
+func (*ListAlertsResponse) Descriptor() ([]byte, []int) {
+	return file_proto_alert_contracts_models_proto_rawDescGZIP(), []int{5}
+}
+
+func (x *ListAlertsResponse) GetAlerts() []*Alert {
+	if x != nil {
+		return x.Alerts
+	}
+	return nil
+}
+
+type DeleteAlertRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Id int64 `protobuf:"varint,1,opt,name=id,proto3" json:"id,omitempty"`
+}
+
+func (x *DeleteAlertRequest) Reset() {
+	*x = DeleteAlertRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_proto_alert_contracts_models_proto_msgTypes[6]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *DeleteAlertRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DeleteAlertRequest) ProtoMessage() {}
+
+func (x *DeleteAlertRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_alert_contracts_models_proto_msgTypes[6]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DeleteAlertRequest.ProtoReflect.Descriptor instead.
+func (*DeleteAlertRequest) Descriptor() ([]byte, []int) {
+	return file_proto_alert_contracts_models_proto_rawDescGZIP(), []int{6}
+}
+
+func (x *DeleteAlertRequest) GetId() int64 {
+	if x != nil {
+		return x.Id
+	}
+	return 0
+}
+
+type DeleteAlertResponse struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Message string `protobuf:"bytes,1,opt,name=message,proto3" json:"message,omitempty"`
+}
+
+func (x *DeleteAlertResponse) Reset() {
+	*x = DeleteAlertResponse{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_proto_alert_contracts_models_proto_msgTypes[7]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *DeleteAlertResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DeleteAlertResponse) ProtoMessage() {}
+
+func (x *DeleteAlertResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_alert_contracts_models_proto_msgTypes[7]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DeleteAlertResponse.ProtoReflect.Descriptor instead.
+func (*DeleteAlertResponse) Descriptor() ([]byte, []int) {
+	return file_proto_alert_contracts_models_proto_rawDescGZIP(), []int{7}
+}
+
+func (x *DeleteAlertResponse) GetMessage() string {
+	if x != nil {
+		return x.Message
+	}
+	return ""
+}
+
+type StreamAlertsRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// streams the alerts of every symbol if both are empty,
+	// otherwise the alerts matching either
+	AlertIds []int64  `protobuf:"varint,1,rep,packed,name=alert_ids,json=alertIds,proto3" json:"alert_ids,omitempty"`
+	Symbols  []string `protobuf:"bytes,2,rep,name=symbols,proto3" json:"symbols,omitempty"`
+}
+
+func (x *StreamAlertsRequest) Reset() {
+	*x = StreamAlertsRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_proto_alert_contracts_models_proto_msgTypes[8]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *StreamAlertsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*StreamAlertsRequest) ProtoMessage() {}
+
+func (x *StreamAlertsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_alert_contracts_models_proto_msgTypes[8]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use StreamAlertsRequest.ProtoReflect.Descriptor instead.
+func (*StreamAlertsRequest) Descriptor() ([]byte, []int) {
+	return file_proto_alert_contracts_models_proto_rawDescGZIP(), []int{8}
+}
+
+func (x *StreamAlertsRequest) GetAlertIds() []int64 {
+	if x != nil {
+		return x.AlertIds
+	}
+	return nil
+}
+
+func (x *StreamAlertsRequest) GetSymbols() []string {
+	if x != nil {
+		return x.Symbols
+	}
+	return nil
+}
+
+type AlertFired struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// the alert as of after it fired
+	Alert *Alert `protobuf:"bytes,1,opt,name=alert,proto3" json:"alert,omitempty"`
+	// the value of the metric that fired the alert
+	Value float64 `protobuf:"fixed64,2,opt,name=value,proto3" json:"value,omitempty"`
+	// open time of the bar the alert fired on
+	BarTimestamp *timestamppb.Timestamp `protobuf:"bytes,3,opt,name=bar_timestamp,json=barTimestamp,proto3" json:"bar_timestamp,omitempty"`
+	FiredAt      *timestamppb.Timestamp `protobuf:"bytes,4,opt,name=fired_at,json=firedAt,proto3" json:"fired_at,omitempty"`
+}
+
+func (x *AlertFired) Reset() {
+	*x = AlertFired{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_proto_alert_contracts_models_proto_msgTypes[9]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *AlertFired) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*AlertFired) ProtoMessage() {}
+
+func (x *AlertFired) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_alert_contracts_models_proto_msgTypes[9]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use AlertFired.ProtoReflect.Descriptor instead.
+func (*AlertFired) Descriptor() ([]byte, []int) {
+	return file_proto_alert_contracts_models_proto_rawDescGZIP(), []int{9}
+}
+
+func (x *AlertFired) GetAlert() *Alert {
+	if x != nil {
+		return x.Alert
+	}
+	return nil
+}
+
+func (x *AlertFired) GetValue() float64 {
+	if x != nil {
+		return x.Value
+	}
+	return 0
+}
+
+func (x *AlertFired) GetBarTimestamp() *timestamppb.Timestamp {
+	if x != nil {
+		return x.BarTimestamp
+	}
+	return nil
+}
+
+func (x *AlertFired) GetFiredAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.FiredAt
+	}
+	return nil
+}
+
+var File_proto_alert_contracts_models_proto protoreflect.FileDescriptor
+
+var file_proto_alert_contracts_models_proto_rawDesc = []byte{
+	0x0a, 0x22, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x2f, 0x61, 0x6c, 0x65, 0x72, 0x74, 0x2f, 0x63, 0x6f,
+	0x6e, 0x74, 0x72, 0x61, 0x63, 0x74, 0x73, 0x2f, 0x6d, 0x6f, 0x64, 0x65, 0x6c, 0x73, 0x2e, 0x70,
+	0x72, 0x6f, 0x74, 0x6f, 0x12, 0x05, 0x61, 0x6c, 0x65, 0x72, 0x74, 0x1a, 0x1f, 0x67, 0x6f, 0x6f,
+	0x67, 0x6c, 0x65, 0x2f, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x2f, 0x74, 0x69, 0x6d,
+	0x65, 0x73, 0x74, 0x61, 0x6d, 0x70, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x22, 0x8e, 0x04, 0x0a,
+	0x05, 0x41, 0x6c, 0x65, 0x72, 0x74, 0x12, 0x0e, 0x0a, 0x02, 0x69, 0x64, 0x18, 0x01, 0x20, 0x01,
+	0x28, 0x03, 0x52, 0x02, 0x69, 0x64, 0x12, 0x16, 0x0a, 0x06, 0x73, 0x79, 0x6d, 0x62, 0x6f, 0x6c,
+	0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x06, 0x73, 0x79, 0x6d, 0x62, 0x6f, 0x6c, 0x12, 0x1c,
+	0x0a, 0x09, 0x74, 0x69, 0x6d, 0x65, 0x66, 0x72, 0x61, 0x6d, 0x65, 0x18, 0x03, 0x20, 0x01, 0x28,
+	0x09, 0x52, 0x09, 0x74, 0x69, 0x6d, 0x65, 0x66, 0x72, 0x61, 0x6d, 0x65, 0x12, 0x2a, 0x0a, 0x06,
+	0x6d, 0x65, 0x74, 0x72, 0x69, 0x63, 0x18, 0x04, 0x20, 0x01, 0x28, 0x0e, 0x32, 0x12, 0x2e, 0x61,
+	0x6c, 0x65, 0x72, 0x74, 0x2e, 0x41, 0x6c, 0x65, 0x72, 0x74, 0x4d, 0x65, 0x74, 0x72, 0x69, 0x63,
+	0x52, 0x06, 0x6d, 0x65, 0x74, 0x72, 0x69, 0x63, 0x12, 0x33, 0x0a, 0x09, 0x63, 0x6f, 0x6e, 0x64,
+	0x69, 0x74, 0x69, 0x6f, 0x6e, 0x18, 0x05, 0x20, 0x01, 0x28, 0x0e, 0x32, 0x15, 0x2e, 0x61, 0x6c,
+	0x65, 0x72, 0x74, 0x2e, 0x41, 0x6c, 0x65, 0x72, 0x74, 0x43, 0x6f, 0x6e, 0x64, 0x69, 0x74, 0x69,
+	0x6f, 0x6e, 0x52, 0x09, 0x63, 0x6f, 0x6e, 0x64, 0x69, 0x74, 0x69, 0x6f, 0x6e, 0x12, 0x1c, 0x0a,
+	0x09, 0x74, 0x68, 0x72, 0x65, 0x73, 0x68, 0x6f, 0x6c, 0x64, 0x18, 0x06, 0x20, 0x01, 0x28, 0x01,
+	0x52, 0x09, 0x74, 0x68, 0x72, 0x65, 0x73, 0x68, 0x6f, 0x6c, 0x64, 0x12, 0x24, 0x0a, 0x04, 0x6d,
+	0x6f, 0x64, 0x65, 0x18, 0x07, 0x20, 0x01, 0x28, 0x0e, 0x32, 0x10, 0x2e, 0x61, 0x6c, 0x65, 0x72,
+	0x74, 0x2e, 0x41, 0x6c, 0x65, 0x72, 0x74, 0x4d, 0x6f, 0x64, 0x65, 0x52, 0x04, 0x6d, 0x6f, 0x64,
+	0x65, 0x12, 0x19, 0x0a, 0x08, 0x6f, 0x6e, 0x5f, 0x63, 0x6c, 0x6f, 0x73, 0x65, 0x18, 0x08, 0x20,
+	0x01, 0x28, 0x08, 0x52, 0x07, 0x6f, 0x6e, 0x43, 0x6c, 0x6f, 0x73, 0x65, 0x12, 0x2a, 0x0a, 0x06,
+	0x73, 0x74, 0x61, 0x74, 0x75, 0x73, 0x18, 0x09, 0x20, 0x01, 0x28, 0x0e, 0x32, 0x12, 0x2e, 0x61,
+	0x6c, 0x65, 0x72, 0x74, 0x2e, 0x41, 0x6c, 0x65, 0x72, 0x74, 0x53, 0x74, 0x61, 0x74, 0x75, 0x73,
+	0x52, 0x06, 0x73, 0x74, 0x61, 0x74, 0x75, 0x73, 0x12, 0x1d, 0x0a, 0x0a, 0x66, 0x69, 0x72, 0x65,
+	0x5f, 0x63, 0x6f, 0x75, 0x6e, 0x74, 0x18, 0x0a, 0x20, 0x01, 0x28, 0x04, 0x52, 0x09, 0x66, 0x69,
+	0x72, 0x65, 0x43, 0x6f, 0x75, 0x6e, 0x74, 0x12, 0x3e, 0x0a, 0x0d, 0x6c, 0x61, 0x73, 0x74, 0x5f,
+	0x66, 0x69, 0x72, 0x65, 0x64, 0x5f, 0x61, 0x74, 0x18, 0x0b, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x1a,
+	0x2e, 0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66,
+	0x2e, 0x54, 0x69, 0x6d, 0x65, 0x73, 0x74, 0x61, 0x6d, 0x70, 0x52, 0x0b, 0x6c, 0x61, 0x73, 0x74,
+	0x46, 0x69, 0x72, 0x65, 0x64, 0x41, 0x74, 0x12, 0x39, 0x0a, 0x0a, 0x63, 0x72, 0x65, 0x61, 0x74,
+	0x65, 0x64, 0x5f, 0x61, 0x74, 0x18, 0x0c, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x1a, 0x2e, 0x67, 0x6f,
+	0x6f, 0x67, 0x6c, 0x65, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x2e, 0x54, 0x69,
+	0x6d, 0x65, 0x73, 0x74, 0x61, 0x6d, 0x70, 0x52, 0x09, 0x63, 0x72, 0x65, 0x61, 0x74, 0x65, 0x64,
+	0x41, 0x74, 0x12, 0x39, 0x0a, 0x0a, 0x75, 0x70, 0x64, 0x61, 0x74, 0x65, 0x64, 0x5f, 0x61, 0x74,
+	0x18, 0x0d, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x1a, 0x2e, 0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2e,
+	0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x2e, 0x54, 0x69, 0x6d, 0x65, 0x73, 0x74, 0x61,
+	0x6d, 0x70, 0x52, 0x09, 0x75, 0x70, 0x64, 0x61, 0x74, 0x65, 0x64, 0x41, 0x74, 0x22, 0x8a, 0x02,
+	0x0a, 0x12, 0x43, 0x72, 0x65, 0x61, 0x74, 0x65, 0x41, 0x6c, 0x65, 0x72, 0x74, 0x52, 0x65, 0x71,
+	0x75, 0x65, 0x73, 0x74, 0x12, 0x16, 0x0a, 0x06, 0x73, 0x79, 0x6d, 0x62, 0x6f, 0x6c, 0x18, 0x01,
+	0x20, 0x01, 0x28, 0x09, 0x52, 0x06, 0x73, 0x79, 0x6d, 0x62, 0x6f, 0x6c, 0x12, 0x1c, 0x0a, 0x09,
+	0x74, 0x69, 0x6d, 0x65, 0x66, 0x72, 0x61, 0x6d, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52,
+	0x09, 0x74, 0x69, 0x6d, 0x65, 0x66, 0x72, 0x61, 0x6d, 0x65, 0x12, 0x2a, 0x0a, 0x06, 0x6d, 0x65,
+	0x74, 0x72, 0x69, 0x63, 0x18, 0x03, 0x20, 0x01, 0x28, 0x0e, 0x32, 0x12, 0x2e, 0x61, 0x6c, 0x65,
+	0x72, 0x74, 0x2e, 0x41, 0x6c, 0x65, 0x72, 0x74, 0x4d, 0x65, 0x74, 0x72, 0x69, 0x63, 0x52, 0x06,
+	0x6d, 0x65, 0x74, 0x72, 0x69, 0x63, 0x12, 0x33, 0x0a, 0x09, 0x63, 0x6f, 0x6e, 0x64, 0x69, 0x74,
+	0x69, 0x6f, 0x6e, 0x18, 0x04, 0x20, 0x01, 0x28, 0x0e, 0x32, 0x15, 0x2e, 0x61, 0x6c, 0x65, 0x72,
+	0x74, 0x2e, 0x41, 0x6c, 0x65, 0x72, 0x74, 0x43, 0x6f, 0x6e, 0x64, 0x69, 0x74, 0x69, 0x6f, 0x6e,
+	0x52, 0x09, 0x63, 0x6f, 0x6e, 0x64, 0x69, 0x74, 0x69, 0x6f, 0x6e, 0x12, 0x1c, 0x0a, 0x09, 0x74,
+	0x68, 0x72, 0x65, 0x73, 0x68, 0x6f, 0x6c, 0x64, 0x18, 0x05, 0x20, 0x01, 0x28, 0x01, 0x52, 0x09,
+	0x74, 0x68, 0x72, 0x65, 0x73, 0x68, 0x6f, 0x6c, 0x64, 0x12, 0x24, 0x0a, 0x04, 0x6d, 0x6f, 0x64,
+	0x65, 0x18, 0x06, 0x20, 0x01, 0x28, 0x0e, 0x32, 0x10, 0x2e, 0x61, 0x6c, 0x65, 0x72, 0x74, 0x2e,
+	0x41, 0x6c, 0x65, 0x72, 0x74, 0x4d, 0x6f, 0x64, 0x65, 0x52, 0x04, 0x6d, 0x6f, 0x64, 0x65, 0x12,
+	0x19, 0x0a, 0x08, 0x6f, 0x6e, 0x5f, 0x63, 0x6c, 0x6f, 0x73, 0x65, 0x18, 0x07, 0x20, 0x01, 0x28,
+	0x08, 0x52, 0x07, 0x6f, 0x6e, 0x43, 0x6c, 0x6f, 0x73, 0x65, 0x22, 0x9a, 0x02, 0x0a, 0x12, 0x55,
+	0x70, 0x64, 0x61, 0x74, 0x65, 0x41, 0x6c, 0x65, 0x72, 0x74, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73,
+	0x74, 0x12, 0x0e, 0x0a, 0x02, 0x69, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x03, 0x52, 0x02, 0x69,
+	0x64, 0x12, 0x16, 0x0a, 0x06, 0x73, 0x79, 0x6d, 0x62, 0x6f, 0x6c, 0x18, 0x02, 0x20, 0x01, 0x28,
+	0x09, 0x52, 0x06, 0x73, 0x79, 0x6d, 0x62, 0x6f, 0x6c, 0x12, 0x1c, 0x0a, 0x09, 0x74, 0x69, 0x6d,
+	0x65, 0x66, 0x72, 0x61, 0x6d, 0x65, 0x18, 0x03, 0x20, 0x01, 0x28, 0x09, 0x52, 0x09, 0x74, 0x69,
+	0x6d, 0x65, 0x66, 0x72, 0x61, 0x6d, 0x65, 0x12, 0x2a, 0x0a, 0x06, 0x6d, 0x65, 0x74, 0x72, 0x69,
+	0x63, 0x18, 0x04, 0x20, 0x01, 0x28, 0x0e, 0x32, 0x12, 0x2e, 0x61, 0x6c, 0x65, 0x72, 0x74, 0x2e,
+	0x41, 0x6c, 0x65, 0x72, 0x74, 0x4d, 0x65, 0x74, 0x72, 0x69, 0x63, 0x52, 0x06, 0x6d, 0x65, 0x74,
+	0x72, 0x69, 0x63, 0x12, 0x33, 0x0a, 0x09, 0x63, 0x6f, 0x6e, 0x64, 0x69, 0x74, 0x69, 0x6f, 0x6e,
+	0x18, 0x05, 0x20, 0x01, 0x28, 0x0e, 0x32, 0x15, 0x2e, 0x61, 0x6c, 0x65, 0x72, 0x74, 0x2e, 0x41,
+	0x6c, 0x65, 0x72, 0x74, 0x43, 0x6f, 0x6e, 0x64, 0x69, 0x74, 0x69, 0x6f, 0x6e, 0x52, 0x09, 0x63,
+	0x6f, 0x6e, 0x64, 0x69, 0x74, 0x69, 0x6f, 0x6e, 0x12, 0x1c, 0x0a, 0x09, 0x74, 0x68, 0x72, 0x65,
+	0x73, 0x68, 0x6f, 0x6c, 0x64, 0x18, 0x06, 0x20, 0x01, 0x28, 0x01, 0x52, 0x09, 0x74, 0x68, 0x72,
+	0x65, 0x73, 0x68, 0x6f, 0x6c, 0x64, 0x12, 0x24, 0x0a, 0x04, 0x6d, 0x6f, 0x64, 0x65, 0x18, 0x07,
+	0x20, 0x01, 0x28, 0x0e, 0x32, 0x10, 0x2e, 0x61, 0x6c, 0x65, 0x72, 0x74, 0x2e, 0x41, 0x6c, 0x65,
+	0x72, 0x74, 0x4d, 0x6f, 0x64, 0x65, 0x52, 0x04, 0x6d, 0x6f, 0x64, 0x65, 0x12, 0x19, 0x0a, 0x08,
+	0x6f, 0x6e, 0x5f, 0x63, 0x6c, 0x6f, 0x73, 0x65, 0x18, 0x08, 0x20, 0x01, 0x28, 0x08, 0x52, 0x07,
+	0x6f, 0x6e, 0x43, 0x6c, 0x6f, 0x73, 0x65, 0x22, 0x21, 0x0a, 0x0f, 0x47, 0x65, 0x74, 0x41, 0x6c,
+	0x65, 0x72, 0x74, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x0e, 0x0a, 0x02, 0x69, 0x64,
+	0x18, 0x01, 0x20, 0x01, 0x28, 0x03, 0x52, 0x02, 0x69, 0x64, 0x22, 0x2b, 0x0a, 0x11, 0x4c, 0x69,
+	0x73, 0x74, 0x41, 0x6c, 0x65, 0x72, 0x74, 0x73, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12,
+	0x16, 0x0a, 0x06, 0x73, 0x79, 0x6d, 0x62, 0x6f, 0x6c, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52,
+	0x06, 0x73, 0x79, 0x6d, 0x62, 0x6f, 0x6c, 0x22, 0x3a, 0x0a, 0x12, 0x4c, 0x69, 0x73, 0x74, 0x41,
+	0x6c, 0x65, 0x72, 0x74, 0x73, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x24, 0x0a,
+	0x06, 0x61, 0x6c, 0x65, 0x72, 0x74, 0x73, 0x18, 0x01, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x0c, 0x2e,
+	0x61, 0x6c, 0x65, 0x72, 0x74, 0x2e, 0x41, 0x6c, 0x65, 0x72, 0x74, 0x52, 0x06, 0x61, 0x6c, 0x65,
+	0x72, 0x74, 0x73, 0x22, 0x24, 0x0a, 0x12, 0x44, 0x65, 0x6c, 0x65, 0x74, 0x65, 0x41, 0x6c, 0x65,
+	0x72, 0x74, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x0e, 0x0a, 0x02, 0x69, 0x64, 0x18,
+	0x01, 0x20, 0x01, 0x28, 0x03, 0x52, 0x02, 0x69, 0x64, 0x22, 0x2f, 0x0a, 0x13, 0x44, 0x65, 0x6c,
+	0x65, 0x74, 0x65, 0x41, 0x6c, 0x65, 0x72, 0x74, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65,
+	0x12, 0x18, 0x0a, 0x07, 0x6d, 0x65, 0x73, 0x73, 0x61, 0x67, 0x65, 0x18, 0x01, 0x20, 0x01, 0x28,
+	0x09, 0x52, 0x07, 0x6d, 0x65, 0x73, 0x73, 0x61, 0x67, 0x65, 0x22, 0x4c, 0x0a, 0x13, 0x53, 0x74,
+	0x72, 0x65, 0x61, 0x6d, 0x41, 0x6c, 0x65, 0x72, 0x74, 0x73, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73,
+	0x74, 0x12, 0x1b, 0x0a, 0x09, 0x61, 0x6c, 0x65, 0x72, 0x74, 0x5f, 0x69, 0x64, 0x73, 0x18, 0x01,
+	0x20, 0x03, 0x28, 0x03, 0x52, 0x08, 0x61, 0x6c, 0x65, 0x72, 0x74, 0x49, 0x64, 0x73, 0x12, 0x18,
+	0x0a, 0x07, 0x73, 0x79, 0x6d, 0x62, 0x6f, 0x6c, 0x73, 0x18, 0x02, 0x20, 0x03, 0x28, 0x09, 0x52,
+	0x07, 0x73, 0x79, 0x6d, 0x62, 0x6f, 0x6c, 0x73, 0x22, 0xbe, 0x01, 0x0a, 0x0a, 0x41, 0x6c, 0x65,
+	0x72, 0x74, 0x46, 0x69, 0x72, 0x65, 0x64, 0x12, 0x22, 0x0a, 0x05, 0x61, 0x6c, 0x65, 0x72, 0x74,
+	0x18, 0x01, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x0c, 0x2e, 0x61, 0x6c, 0x65, 0x72, 0x74, 0x2e, 0x41,
+	0x6c, 0x65, 0x72, 0x74, 0x52, 0x05, 0x61, 0x6c, 0x65, 0x72, 0x74, 0x12, 0x14, 0x0a, 0x05, 0x76,
+	0x61, 0x6c, 0x75, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x01, 0x52, 0x05, 0x76, 0x61, 0x6c, 0x75,
+	0x65, 0x12, 0x3f, 0x0a, 0x0d, 0x62, 0x61, 0x72, 0x5f, 0x74, 0x69, 0x6d, 0x65, 0x73, 0x74, 0x61,
+	0x6d, 0x70, 0x18, 0x03, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x1a, 0x2e, 0x67, 0x6f, 0x6f, 0x67, 0x6c,
+	0x65, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x2e, 0x54, 0x69, 0x6d, 0x65, 0x73,
+	0x74, 0x61, 0x6d, 0x70, 0x52, 0x0c, 0x62, 0x61, 0x72, 0x54, 0x69, 0x6d, 0x65, 0x73, 0x74, 0x61,
+	0x6d, 0x70, 0x12, 0x35, 0x0a, 0x08, 0x66, 0x69, 0x72, 0x65, 0x64, 0x5f, 0x61, 0x74, 0x18, 0x04,
+	0x20, 0x01, 0x28, 0x0b, 0x32, 0x1a, 0x2e, 0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2e, 0x70, 0x72,
+	0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x2e, 0x54, 0x69, 0x6d, 0x65, 0x73, 0x74, 0x61, 0x6d, 0x70,
+	0x52, 0x07, 0x66, 0x69, 0x72, 0x65, 0x64, 0x41, 0x74, 0x2a, 0x66, 0x0a, 0x0b, 0x41, 0x6c, 0x65,
+	0x72, 0x74, 0x4d, 0x65, 0x74, 0x72, 0x69, 0x63, 0x12, 0x16, 0x0a, 0x12, 0x41, 0x4c, 0x45, 0x52,
+	0x54, 0x5f, 0x4d, 0x45, 0x54, 0x52, 0x49, 0x43, 0x5f, 0x43, 0x4c, 0x4f, 0x53, 0x45, 0x10, 0x00,
+	0x12, 0x1e, 0x0a, 0x1a, 0x41, 0x4c, 0x45, 0x52, 0x54, 0x5f, 0x4d, 0x45, 0x54, 0x52, 0x49, 0x43,
+	0x5f, 0x52, 0x41, 0x4e, 0x47, 0x45, 0x5f, 0x50, 0x45, 0x52, 0x43, 0x45, 0x4e, 0x54, 0x10, 0x01,
+	0x12, 0x1f, 0x0a, 0x1b, 0x41, 0x4c, 0x45, 0x52, 0x54, 0x5f, 0x4d, 0x45, 0x54, 0x52, 0x49, 0x43,
+	0x5f, 0x43, 0x48, 0x41, 0x4e, 0x47, 0x45, 0x5f, 0x50, 0x45, 0x52, 0x43, 0x45, 0x4e, 0x54, 0x10,
+	0x02, 0x2a, 0x8c, 0x01, 0x0a, 0x0e, 0x41, 0x6c, 0x65, 0x72, 0x74, 0x43, 0x6f, 0x6e, 0x64, 0x69,
+	0x74, 0x69, 0x6f, 0x6e, 0x12, 0x21, 0x0a, 0x1d, 0x41, 0x4c, 0x45, 0x52, 0x54, 0x5f, 0x43, 0x4f,
+	0x4e, 0x44, 0x49, 0x54, 0x49, 0x4f, 0x4e, 0x5f, 0x43, 0x52, 0x4f, 0x53, 0x53, 0x45, 0x53, 0x5f,
+	0x41, 0x42, 0x4f, 0x56, 0x45, 0x10, 0x00, 0x12, 0x21, 0x0a, 0x1d, 0x41, 0x4c, 0x45, 0x52, 0x54,
+	0x5f, 0x43, 0x4f, 0x4e, 0x44, 0x49, 0x54, 0x49, 0x4f, 0x4e, 0x5f, 0x43, 0x52, 0x4f, 0x53, 0x53,
+	0x45, 0x53, 0x5f, 0x42, 0x45, 0x4c, 0x4f, 0x57, 0x10, 0x01, 0x12, 0x19, 0x0a, 0x15, 0x41, 0x4c,
+	0x45, 0x52, 0x54, 0x5f, 0x43, 0x4f, 0x4e, 0x44, 0x49, 0x54, 0x49, 0x4f, 0x4e, 0x5f, 0x41, 0x42,
+	0x4f, 0x56, 0x45, 0x10, 0x02, 0x12, 0x19, 0x0a, 0x15, 0x41, 0x4c, 0x45, 0x52, 0x54, 0x5f, 0x43,
+	0x4f, 0x4e, 0x44, 0x49, 0x54, 0x49, 0x4f, 0x4e, 0x5f, 0x42, 0x45, 0x4c, 0x4f, 0x57, 0x10, 0x03,
+	0x2a, 0x36, 0x0a, 0x09, 0x41, 0x6c, 0x65, 0x72, 0x74, 0x4d, 0x6f, 0x64, 0x65, 0x12, 0x13, 0x0a,
+	0x0f, 0x41, 0x4c, 0x45, 0x52, 0x54, 0x5f, 0x4d, 0x4f, 0x44, 0x45, 0x5f, 0x4f, 0x4e, 0x43, 0x45,
+	0x10, 0x00, 0x12, 0x14, 0x0a, 0x10, 0x41, 0x4c, 0x45, 0x52, 0x54, 0x5f, 0x4d, 0x4f, 0x44, 0x45,
+	0x5f, 0x52, 0x45, 0x41, 0x52, 0x4d, 0x10, 0x01, 0x2a, 0x42, 0x0a, 0x0b, 0x41, 0x6c, 0x65, 0x72,
+	0x74, 0x53, 0x74, 0x61, 0x74, 0x75, 0x73, 0x12, 0x17, 0x0a, 0x13, 0x41, 0x4c, 0x45, 0x52, 0x54,
+	0x5f, 0x53, 0x54, 0x41, 0x54, 0x55, 0x53, 0x5f, 0x41, 0x43, 0x54, 0x49, 0x56, 0x45, 0x10, 0x00,
+	0x12, 0x1a, 0x0a, 0x16, 0x41, 0x4c, 0x45, 0x52, 0x54, 0x5f, 0x53, 0x54, 0x41, 0x54, 0x55, 0x53,
+	0x5f, 0x54, 0x52, 0x49, 0x47, 0x47, 0x45, 0x52, 0x45, 0x44, 0x10, 0x01, 0x42, 0x45, 0x5a, 0x43,
+	0x67, 0x69, 0x74, 0x68, 0x75, 0x62, 0x2e, 0x63, 0x6f, 0x6d, 0x2f, 0x72, 0x61, 0x6d, 0x61, 0x73,
+	0x62, 0x65, 0x69, 0x6e, 0x61, 0x74, 0x79, 0x2f, 0x74, 0x72, 0x61, 0x64, 0x69, 0x6e, 0x67, 0x2d,
+	0x63, 0x68, 0x61, 0x72, 0x74, 0x2d, 0x73, 0x65, 0x72, 0x76, 0x69, 0x63, 0x65, 0x2f, 0x70, 0x72,
+	0x6f, 0x74, 0x6f, 0x2f, 0x61, 0x6c, 0x65, 0x72, 0x74, 0x2f, 0x63, 0x6f, 0x6e, 0x74, 0x72, 0x61,
+	0x63, 0x74, 0x73, 0x62, 0x06, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x33,
+}
+
+var (
+	file_proto_alert_contracts_models_proto_rawDescOnce sync.Once
+	file_proto_alert_contracts_models_proto_rawDescData = file_proto_alert_contracts_models_proto_rawDesc
+)
+
+func file_proto_alert_contracts_models_proto_rawDescGZIP() []byte {
+	file_proto_alert_contracts_models_proto_rawDescOnce.Do(func() {
+		file_proto_alert_contracts_models_proto_rawDescData = protoimpl.X.CompressGZIP(file_proto_alert_contracts_models_proto_rawDescData)
+	})
+	return file_proto_alert_contracts_models_proto_rawDescData
+}
+
+var file_proto_alert_contracts_models_proto_enumTypes = make([]protoimpl.EnumInfo, 4)
+var file_proto_alert_contracts_models_proto_msgTypes = make([]protoimpl.MessageInfo, 10)
+var file_proto_alert_contracts_models_proto_goTypes = []any{
+	(AlertMetric)(0),              // 0: alert.AlertMetric
+	(AlertCondition)(0),           // 1: alert.AlertCondition
+	(AlertMode)(0),                // 2: alert.AlertMode
+	(AlertStatus)(0),              // 3: alert.AlertStatus
+	(*Alert)(nil),                 // 4: alert.Alert
+	(*CreateAlertRequest)(nil),    // 5: alert.CreateAlertRequest
+	(*UpdateAlertRequest)(nil),    // 6: alert.UpdateAlertRequest
+	(*GetAlertRequest)(nil),       // 7: alert.GetAlertRequest
+	(*ListAlertsRequest)(nil),     // 8: alert.ListAlertsRequest
+	(*ListAlertsResponse)(nil),    // 9: alert.ListAlertsResponse
+	(*DeleteAlertRequest)(nil),    // 10: alert.DeleteAlertRequest
+	(*DeleteAlertResponse)(nil),   // 11: alert.DeleteAlertResponse
+	(*StreamAlertsRequest)(nil),   // 12: alert.StreamAlertsRequest
+	(*AlertFired)(nil),            // 13: alert.AlertFired
+	(*timestamppb.Timestamp)(nil), // 14: google.protobuf.Timestamp
+}
+var file_proto_alert_contracts_models_proto_depIdxs = []int32{
+	0,  // 0: alert.Alert.metric:type_name -> alert.AlertMetric
+	1,  // 1: alert.Alert.condition:type_name -> alert.AlertCondition
+	2,  // 2: alert.Alert.mode:type_name -> alert.AlertMode
+	3,  // 3: alert.Alert.status:type_name -> alert.AlertStatus
+	14, // 4: alert.Alert.last_fired_at:type_name -> google.protobuf.Timestamp
+	14, // 5: alert.Alert.created_at:type_name -> google.protobuf.Timestamp
+	14, // 6: alert.Alert.updated_at:type_name -> google.protobuf.Timestamp
+	0,  // 7: alert.CreateAlertRequest.metric:type_name -> alert.AlertMetric
+	1,  // 8: alert.CreateAlertRequest.condition:type_name -> alert.AlertCondition
+	2,  // 9: alert.CreateAlertRequest.mode:type_name -> alert.AlertMode
+	0,  // 10: alert.UpdateAlertRequest.metric:type_name -> alert.AlertMetric
+	1,  // 11: alert.UpdateAlertRequest.condition:type_name -> alert.AlertCondition
+	2,  // 12: alert.UpdateAlertRequest.mode:type_name -> alert.AlertMode
+	4,  // 13: alert.ListAlertsResponse.alerts:type_name -> alert.Alert
+	4,  // 14: alert.AlertFired.alert:type_name -> alert.Alert
+	14, // 15: alert.AlertFired.bar_timestamp:type_name -> google.protobuf.Timestamp
+	14, // 16: alert.AlertFired.fired_at:type_name -> google.protobuf.Timestamp
+	17, // [17:17] is the sub-list for method output_type
+	17, // [17:17] is the sub-list for method input_type
+	17, // [17:17] is the sub-list for extension type_name
+	17, // [17:17] is the sub-list for extension extendee
+	0,  // [0:17] is the sub-list for field type_name
+}
+
+func init() { file_proto_alert_contracts_models_proto_init() }
+func file_proto_alert_contracts_models_proto_init() {
+	if File_proto_alert_contracts_models_proto != nil {
+		return
+	}
+	if !protoimpl.UnsafeEnabled {
+		file_proto_alert_contracts_models_proto_msgTypes[0].Exporter = func(v any, i int) any {
+			switch v := v.(*Alert); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_proto_alert_contracts_models_proto_msgTypes[1].Exporter = func(v any, i int) any {
+			switch v := v.(*CreateAlertRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_proto_alert_contracts_models_proto_msgTypes[2].Exporter = func(v any, i int) any {
+			switch v := v.(*UpdateAlertRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_proto_alert_contracts_models_proto_msgTypes[3].Exporter = func(v any, i int) any {
+			switch v := v.(*GetAlertRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_proto_alert_contracts_models_proto_msgTypes[4].Exporter = func(v any, i int) any {
+			switch v := v.(*ListAlertsRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_proto_alert_contracts_models_proto_msgTypes[5].Exporter = func(v any, i int) any {
+			switch v := v.(*ListAlertsResponse); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_proto_alert_contracts_models_proto_msgTypes[6].Exporter = func(v any, i int) any {
+			switch v := v.(*DeleteAlertRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_proto_alert_contracts_models_proto_msgTypes[7].Exporter = func(v any, i int) any {
+			switch v := v.(*DeleteAlertResponse); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_proto_alert_contracts_models_proto_msgTypes[8].Exporter = func(v any, i int) any {
+			switch v := v.(*StreamAlertsRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_proto_alert_contracts_models_proto_msgTypes[9].Exporter = func(v any, i int) any {
+			switch v := v.(*AlertFired); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+	}
+	type x struct{}
+	out := protoimpl.TypeBuilder{
+		File: protoimpl.DescBuilder{
+			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
+			RawDescriptor: file_proto_alert_contracts_models_proto_rawDesc,
+			NumEnums:      4,
+			NumMessages:   10,
+			NumExtensions: 0,
+			NumServices:   0,
+		},
+		GoTypes:           file_proto_alert_contracts_models_proto_goTypes,
+		DependencyIndexes: file_proto_alert_contracts_models_proto_depIdxs,
+		EnumInfos:         file_proto_alert_contracts_models_proto_enumTypes,
+		MessageInfos:      file_proto_alert_contracts_models_proto_msgTypes,
+	}.Build()
+	File_proto_alert_contracts_models_proto = out.File
+	file_proto_alert_contracts_models_proto_rawDesc = nil
+	file_proto_alert_contracts_models_proto_goTypes = nil
+	file_proto_alert_contracts_models_proto_depIdxs = nil
+}
@@ -21,6 +21,63 @@ const (
 	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
 )
 
+type CandlestickEventKind int32
+
+const (
+	// a live update of the symbol's current bar
+	CandlestickEventKind_CANDLESTICK_EVENT_KIND_LIVE CandlestickEventKind = 0
+	// a bar sent as part of the initial snapshot of a subscription
+	CandlestickEventKind_CANDLESTICK_EVENT_KIND_SNAPSHOT CandlestickEventKind = 1
+	// a missed update replayed to a resumed subscription
+	CandlestickEventKind_CANDLESTICK_EVENT_KIND_REPLAY CandlestickEventKind = 2
+	// the missed updates are no longer available, history should be refetched;
+	// carries the latest state and sequence of the symbol's bar
+	CandlestickEventKind_CANDLESTICK_EVENT_KIND_GAP CandlestickEventKind = 3
+)
+
+// Enum value maps for CandlestickEventKind.
+var (
+	CandlestickEventKind_name = map[int32]string{
+		0: "CANDLESTICK_EVENT_KIND_LIVE",
+		1: "CANDLESTICK_EVENT_KIND_SNAPSHOT",
+		2: "CANDLESTICK_EVENT_KIND_REPLAY",
+		3: "CANDLESTICK_EVENT_KIND_GAP",
+	}
+	CandlestickEventKind_value = map[string]int32{
+		"CANDLESTICK_EVENT_KIND_LIVE":     0,
+		"CANDLESTICK_EVENT_KIND_SNAPSHOT": 1,
+		"CANDLESTICK_EVENT_KIND_REPLAY":   2,
+		"CANDLESTICK_EVENT_KIND_GAP":      3,
+	}
+)
+
+func (x CandlestickEventKind) Enum() *CandlestickEventKind {
+	p := new(CandlestickEventKind)
+	*p = x
+	return p
+}
+
+func (x CandlestickEventKind) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (CandlestickEventKind) Descriptor() protoreflect.EnumDescriptor {
+	return file_proto_candlestick_contracts_models_proto_enumTypes[0].Descriptor()
+}
+
+func (CandlestickEventKind) Type() protoreflect.EnumType {
+	return &file_proto_candlestick_contracts_models_proto_enumTypes[0]
+}
+
+func (x CandlestickEventKind) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use CandlestickEventKind.Descriptor instead.
+func (CandlestickEventKind) EnumDescriptor() ([]byte, []int) {
+	return file_proto_candlestick_contracts_models_proto_rawDescGZIP(), []int{0}
+}
+
 type Candlestick struct {
 	state         protoimpl.MessageState
 	sizeCache     protoimpl.SizeCache
@@ -32,6 +89,11 @@ type Candlestick struct {
 	LowPrice       float64                `protobuf:"fixed64,4,opt,name=low_price,json=lowPrice,proto3" json:"low_price,omitempty"`
 	ClosePrice     float64                `protobuf:"fixed64,5,opt,name=close_price,json=closePrice,proto3" json:"close_price,omitempty"`
 	TradeTimestamp *timestamppb.Timestamp `protobuf:"bytes,6,opt,name=trade_timestamp,json=tradeTimestamp,proto3" json:"trade_timestamp,omitempty"`
+	// per-symbol sequence number of the last update applied to this bar
+	Sequence uint64               `protobuf:"varint,7,opt,name=sequence,proto3" json:"sequence,omitempty"`
+	Kind     CandlestickEventKind `protobuf:"varint,8,opt,name=kind,proto3,enum=candlestick.CandlestickEventKind" json:"kind,omitempty"`
+	// values of the indicators requested by the subscriber for this bar
+	Indicators []*IndicatorValue `protobuf:"bytes,9,rep,name=indicators,proto3" json:"indicators,omitempty"`
 }
 
 func (x *Candlestick) Reset() {
@@ -108,18 +170,125 @@ func (x *Candlestick) GetTradeTimestamp() *timestamppb.Timestamp {
 	return nil
 }
 
+func (x *Candlestick) GetSequence() uint64 {
+	if x != nil {
+		return x.Sequence
+	}
+	return 0
+}
+
+func (x *Candlestick) GetKind() CandlestickEventKind {
+	if x != nil {
+		return x.Kind
+	}
+	return CandlestickEventKind_CANDLESTICK_EVENT_KIND_LIVE
+}
+
+func (x *Candlestick) GetIndicators() []*IndicatorValue {
+	if x != nil {
+		return x.Indicators
+	}
+	return nil
+}
+
+type IndicatorValue struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// the indicator with its parameters and timeframe, e.g. "MACD(12,26,9)@5m"
+	Name string `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
+	// "value" for single valued indicators, otherwise
+	// MACD: "macd", "signal" and "histogram"
+	// BB: "upper", "middle" and "lower"
+	Values map[string]float64 `protobuf:"bytes,2,rep,name=values,proto3" json:"values,omitempty" protobuf_key:"bytes,1,opt,name=key,proto3" protobuf_val:"fixed64,2,opt,name=value,proto3"`
+	// false until enough bars were seen for the indicator to have a value
+	Ready bool `protobuf:"varint,3,opt,name=ready,proto3" json:"ready,omitempty"`
+	// open time of the indicator's timeframe bar
+	Timestamp *timestamppb.Timestamp `protobuf:"bytes,4,opt,name=timestamp,proto3" json:"timestamp,omitempty"`
+}
+
+func (x *IndicatorValue) Reset() {
+	*x = IndicatorValue{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_proto_candlestick_contracts_models_proto_msgTypes[1]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *IndicatorValue) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*IndicatorValue) ProtoMessage() {}
+
+func (x *IndicatorValue) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_candlestick_contracts_models_proto_msgTypes[1]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use IndicatorValue.ProtoReflect.Descriptor instead.
+func (*IndicatorValue) Descriptor() ([]byte, []int) {
+	return file_proto_candlestick_contracts_models_proto_rawDescGZIP(), []int{1}
+}
+
+func (x *IndicatorValue) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *IndicatorValue) GetValues() map[string]float64 {
+	if x != nil {
+		return x.Values
+	}
+	return nil
+}
+
+func (x *IndicatorValue) GetReady() bool {
+	if x != nil {
+		return x.Ready
+	}
+	return false
+}
+
+func (x *IndicatorValue) GetTimestamp() *timestamppb.Timestamp {
+	if x != nil {
+		return x.Timestamp
+	}
+	return nil
+}
+
 type SubscribeToStreamRequest struct {
 	state         protoimpl.MessageState
 	sizeCache     protoimpl.SizeCache
 	unknownFields protoimpl.UnknownFields
 
 	Symbols []string `protobuf:"bytes,1,rep,name=symbols,proto3" json:"symbols,omitempty"`
+	// send the current in-progress bar of each symbol before streaming live updates
+	Snapshot bool `protobuf:"varint,2,opt,name=snapshot,proto3" json:"snapshot,omitempty"`
+	// number of closed bars to include in the snapshot, per symbol
+	SnapshotBars int32 `protobuf:"varint,3,opt,name=snapshot_bars,json=snapshotBars,proto3" json:"snapshot_bars,omitempty"`
+	// last sequence received per symbol, to replay the updates missed since
+	ResumeFrom map[string]uint64 `protobuf:"bytes,4,rep,name=resume_from,json=resumeFrom,proto3" json:"resume_from,omitempty" protobuf_key:"bytes,1,opt,name=key,proto3" protobuf_val:"varint,2,opt,name=value,proto3"`
+	// indicators to compute on the bars, written as TYPE(PARAMS)@TIMEFRAME,
+	// e.g. "SMA(20)", "RSI(14)@5m", "MACD(12,26,9)@15m" or "BB(20,2)@1h"
+	Indicators []string `protobuf:"bytes,5,rep,name=indicators,proto3" json:"indicators,omitempty"`
 }
 
 func (x *SubscribeToStreamRequest) Reset() {
 	*x = SubscribeToStreamRequest{}
 	if protoimpl.UnsafeEnabled {
-		mi := &file_proto_candlestick_contracts_models_proto_msgTypes[1]
+		mi := &file_proto_candlestick_contracts_models_proto_msgTypes[2]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		ms.StoreMessageInfo(mi)
 	}
@@ -132,7 +301,7 @@ func (x *SubscribeToStreamRequest) String() string {
 func (*SubscribeToStreamRequest) ProtoMessage() {}
 
 func (x *SubscribeToStreamRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_proto_candlestick_contracts_models_proto_msgTypes[1]
+	mi := &file_proto_candlestick_contracts_models_proto_msgTypes[2]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -145,7 +314,7 @@ func (x *SubscribeToStreamRequest) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use SubscribeToStreamRequest.ProtoReflect.Descriptor instead.
 func (*SubscribeToStreamRequest) Descriptor() ([]byte, []int) {
-	return file_proto_candlestick_contracts_models_proto_rawDescGZIP(), []int{1}
+	return file_proto_candlestick_contracts_models_proto_rawDescGZIP(), []int{2}
 }
 
 func (x *SubscribeToStreamRequest) GetSymbols() []string {
@@ -155,6 +324,34 @@ func (x *SubscribeToStreamRequest) GetSymbols() []string {
 	return nil
 }
 
+func (x *SubscribeToStreamRequest) GetSnapshot() bool {
+	if x != nil {
+		return x.Snapshot
+	}
+	return false
+}
+
+func (x *SubscribeToStreamRequest) GetSnapshotBars() int32 {
+	if x != nil {
+		return x.SnapshotBars
+	}
+	return 0
+}
+
+func (x *SubscribeToStreamRequest) GetResumeFrom() map[string]uint64 {
+	if x != nil {
+		return x.ResumeFrom
+	}
+	return nil
+}
+
+func (x *SubscribeToStreamRequest) GetIndicators() []string {
+	if x != nil {
+		return x.Indicators
+	}
+	return nil
+}
+
 type UnsubscribeFromStreamRequest struct {
 	state         protoimpl.MessageState
 	sizeCache     protoimpl.SizeCache
@@ -167,7 +364,7 @@ type UnsubscribeFromStreamRequest struct {
 func (x *UnsubscribeFromStreamRequest) Reset() {
 	*x = UnsubscribeFromStreamRequest{}
 	if protoimpl.UnsafeEnabled {
-		mi := &file_proto_candlestick_contracts_models_proto_msgTypes[2]
+		mi := &file_proto_candlestick_contracts_models_proto_msgTypes[3]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		ms.StoreMessageInfo(mi)
 	}
@@ -180,7 +377,7 @@ func (x *UnsubscribeFromStreamRequest) String() string {
 func (*UnsubscribeFromStreamRequest) ProtoMessage() {}
 
 func (x *UnsubscribeFromStreamRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_proto_candlestick_contracts_models_proto_msgTypes[2]
+	mi := &file_proto_candlestick_contracts_models_proto_msgTypes[3]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -193,7 +390,7 @@ func (x *UnsubscribeFromStreamRequest) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use UnsubscribeFromStreamRequest.ProtoReflect.Descriptor instead.
 func (*UnsubscribeFromStreamRequest) Descriptor() ([]byte, []int) {
-	return file_proto_candlestick_contracts_models_proto_rawDescGZIP(), []int{2}
+	return file_proto_candlestick_contracts_models_proto_rawDescGZIP(), []int{3}
 }
 
 func (x *UnsubscribeFromStreamRequest) GetSymbols() []string {
@@ -221,7 +418,7 @@ type GenericResponse struct {
 func (x *GenericResponse) Reset() {
 	*x = GenericResponse{}
 	if protoimpl.UnsafeEnabled {
-		mi := &file_proto_candlestick_contracts_models_proto_msgTypes[3]
+		mi := &file_proto_candlestick_contracts_models_proto_msgTypes[4]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		ms.StoreMessageInfo(mi)
 	}
@@ -234,7 +431,7 @@ func (x *GenericResponse) String() string {
 func (*GenericResponse) ProtoMessage() {}
 
 func (x *GenericResponse) ProtoReflect() protoreflect.Message {
-	mi := &file_proto_candlestick_contracts_models_proto_msgTypes[3]
+	mi := &file_proto_candlestick_contracts_models_proto_msgTypes[4]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -247,7 +444,7 @@ func (x *GenericResponse) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use GenericResponse.ProtoReflect.Descriptor instead.
 func (*GenericResponse) Descriptor() ([]byte, []int) {
-	return file_proto_candlestick_contracts_models_proto_rawDescGZIP(), []int{3}
+	return file_proto_candlestick_contracts_models_proto_rawDescGZIP(), []int{4}
 }
 
 func (x *GenericResponse) GetMessage() string {
@@ -257,6 +454,125 @@ func (x *GenericResponse) GetMessage() string {
 	return ""
 }
 
+type GetIndicatorHistoryRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Symbol string `protobuf:"bytes,1,opt,name=symbol,proto3" json:"symbol,omitempty"`
+	// written as TYPE(PARAMS)@TIMEFRAME, e.g. "EMA(50)@1h"
+	Indicator string                 `protobuf:"bytes,2,opt,name=indicator,proto3" json:"indicator,omitempty"`
+	From      *timestamppb.Timestamp `protobuf:"bytes,3,opt,name=from,proto3" json:"from,omitempty"`
+	To        *timestamppb.Timestamp `protobuf:"bytes,4,opt,name=to,proto3" json:"to,omitempty"`
+}
+
+func (x *GetIndicatorHistoryRequest) Reset() {
+	*x = GetIndicatorHistoryRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_proto_candlestick_contracts_models_proto_msgTypes[5]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *GetIndicatorHistoryRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetIndicatorHistoryRequest) ProtoMessage() {}
+
+func (x *GetIndicatorHistoryRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_candlestick_contracts_models_proto_msgTypes[5]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetIndicatorHistoryRequest.ProtoReflect.Descriptor instead.
+func (*GetIndicatorHistoryRequest) Descriptor() ([]byte, []int) {
+	return file_proto_candlestick_contracts_models_proto_rawDescGZIP(), []int{5}
+}
+
+func (x *GetIndicatorHistoryRequest) GetSymbol() string {
+	if x != nil {
+		return x.Symbol
+	}
+	return ""
+}
+
+func (x *GetIndicatorHistoryRequest) GetIndicator() string {
+	if x != nil {
+		return x.Indicator
+	}
+	return ""
+}
+
+func (x *GetIndicatorHistoryRequest) GetFrom() *timestamppb.Timestamp {
+	if x != nil {
+		return x.From
+	}
+	return nil
+}
+
+func (x *GetIndicatorHistoryRequest) GetTo() *timestamppb.Timestamp {
+	if x != nil {
+		return x.To
+	}
+	return nil
+}
+
+type GetIndicatorHistoryResponse struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Values []*IndicatorValue `protobuf:"bytes,1,rep,name=values,proto3" json:"values,omitempty"`
+}
+
+func (x *GetIndicatorHistoryResponse) Reset() {
+	*x = GetIndicatorHistoryResponse{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_proto_candlestick_contracts_models_proto_msgTypes[6]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *GetIndicatorHistoryResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetIndicatorHistoryResponse) ProtoMessage() {}
+
+func (x *GetIndicatorHistoryResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_candlestick_contracts_models_proto_msgTypes[6]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetIndicatorHistoryResponse.ProtoReflect.Descriptor instead.
+func (*GetIndicatorHistoryResponse) Descriptor() ([]byte, []int) {
+	return file_proto_candlestick_contracts_models_proto_rawDescGZIP(), []int{6}
+}
+
+func (x *GetIndicatorHistoryResponse) GetValues() []*IndicatorValue {
+	if x != nil {
+		return x.Values
+	}
+	return nil
+}
+
 var File_proto_candlestick_contracts_models_proto protoreflect.FileDescriptor
 
 var file_proto_candlestick_contracts_models_proto_rawDesc = []byte{
@@ -265,7 +581,7 @@ var file_proto_candlestick_contracts_models_proto_rawDesc = []byte{
 	0x64, 0x65, 0x6c, 0x73, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x12, 0x0b, 0x63, 0x61, 0x6e, 0x64,
 	0x6c, 0x65, 0x73, 0x74, 0x69, 0x63, 0x6b, 0x1a, 0x1f, 0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2f,
 	0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x2f, 0x74, 0x69, 0x6d, 0x65, 0x73, 0x74, 0x61,
-	0x6d, 0x70, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x22, 0xe6, 0x01, 0x0a, 0x0b, 0x43, 0x61, 0x6e,
+	0x6d, 0x70, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x22, 0xf6, 0x02, 0x0a, 0x0b, 0x43, 0x61, 0x6e,
 	0x64, 0x6c, 0x65, 0x73, 0x74, 0x69, 0x63, 0x6b, 0x12, 0x16, 0x0a, 0x06, 0x73, 0x79, 0x6d, 0x62,
 	0x6f, 0x6c, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x06, 0x73, 0x79, 0x6d, 0x62, 0x6f, 0x6c,
 	0x12, 0x1d, 0x0a, 0x0a, 0x6f, 0x70, 0x65, 0x6e, 0x5f, 0x70, 0x72, 0x69, 0x63, 0x65, 0x18, 0x02,
@@ -280,24 +596,90 @@ var file_proto_candlestick_contracts_models_proto_rawDesc = []byte{
 	0x06, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x1a, 0x2e, 0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2e, 0x70,
 	0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x2e, 0x54, 0x69, 0x6d, 0x65, 0x73, 0x74, 0x61, 0x6d,
 	0x70, 0x52, 0x0e, 0x74, 0x72, 0x61, 0x64, 0x65, 0x54, 0x69, 0x6d, 0x65, 0x73, 0x74, 0x61, 0x6d,
-	0x70, 0x22, 0x34, 0x0a, 0x18, 0x53, 0x75, 0x62, 0x73, 0x63, 0x72, 0x69, 0x62, 0x65, 0x54, 0x6f,
-	0x53, 0x74, 0x72, 0x65, 0x61, 0x6d, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x18, 0x0a,
-	0x07, 0x73, 0x79, 0x6d, 0x62, 0x6f, 0x6c, 0x73, 0x18, 0x01, 0x20, 0x03, 0x28, 0x09, 0x52, 0x07,
-	0x73, 0x79, 0x6d, 0x62, 0x6f, 0x6c, 0x73, 0x22, 0x5d, 0x0a, 0x1c, 0x55, 0x6e, 0x73, 0x75, 0x62,
-	0x73, 0x63, 0x72, 0x69, 0x62, 0x65, 0x46, 0x72, 0x6f, 0x6d, 0x53, 0x74, 0x72, 0x65, 0x61, 0x6d,
-	0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x18, 0x0a, 0x07, 0x73, 0x79, 0x6d, 0x62, 0x6f,
-	0x6c, 0x73, 0x18, 0x01, 0x20, 0x03, 0x28, 0x09, 0x52, 0x07, 0x73, 0x79, 0x6d, 0x62, 0x6f, 0x6c,
-	0x73, 0x12, 0x23, 0x0a, 0x0d, 0x73, 0x75, 0x62, 0x73, 0x63, 0x72, 0x69, 0x62, 0x65, 0x72, 0x5f,
-	0x69, 0x64, 0x18, 0x02, 0x20, 0x01, 0x28, 0x03, 0x52, 0x0c, 0x73, 0x75, 0x62, 0x73, 0x63, 0x72,
-	0x69, 0x62, 0x65, 0x72, 0x49, 0x64, 0x22, 0x2b, 0x0a, 0x0f, 0x47, 0x65, 0x6e, 0x65, 0x72, 0x69,
-	0x63, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x18, 0x0a, 0x07, 0x6d, 0x65, 0x73,
-	0x73, 0x61, 0x67, 0x65, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x07, 0x6d, 0x65, 0x73, 0x73,
-	0x61, 0x67, 0x65, 0x42, 0x4b, 0x5a, 0x49, 0x67, 0x69, 0x74, 0x68, 0x75, 0x62, 0x2e, 0x63, 0x6f,
-	0x6d, 0x2f, 0x72, 0x61, 0x6d, 0x61, 0x73, 0x62, 0x65, 0x69, 0x6e, 0x61, 0x74, 0x79, 0x2f, 0x74,
-	0x72, 0x61, 0x64, 0x69, 0x6e, 0x67, 0x2d, 0x63, 0x68, 0x61, 0x72, 0x74, 0x2d, 0x73, 0x65, 0x72,
-	0x76, 0x69, 0x63, 0x65, 0x2f, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x2f, 0x63, 0x61, 0x6e, 0x64, 0x6c,
-	0x65, 0x73, 0x74, 0x69, 0x63, 0x6b, 0x2f, 0x63, 0x6f, 0x6e, 0x74, 0x72, 0x61, 0x63, 0x74, 0x73,
-	0x62, 0x06, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x33,
+	0x70, 0x12, 0x1a, 0x0a, 0x08, 0x73, 0x65, 0x71, 0x75, 0x65, 0x6e, 0x63, 0x65, 0x18, 0x07, 0x20,
+	0x01, 0x28, 0x04, 0x52, 0x08, 0x73, 0x65, 0x71, 0x75, 0x65, 0x6e, 0x63, 0x65, 0x12, 0x35, 0x0a,
+	0x04, 0x6b, 0x69, 0x6e, 0x64, 0x18, 0x08, 0x20, 0x01, 0x28, 0x0e, 0x32, 0x21, 0x2e, 0x63, 0x61,
+	0x6e, 0x64, 0x6c, 0x65, 0x73, 0x74, 0x69, 0x63, 0x6b, 0x2e, 0x43, 0x61, 0x6e, 0x64, 0x6c, 0x65,
+	0x73, 0x74, 0x69, 0x63, 0x6b, 0x45, 0x76, 0x65, 0x6e, 0x74, 0x4b, 0x69, 0x6e, 0x64, 0x52, 0x04,
+	0x6b, 0x69, 0x6e, 0x64, 0x12, 0x3b, 0x0a, 0x0a, 0x69, 0x6e, 0x64, 0x69, 0x63, 0x61, 0x74, 0x6f,
+	0x72, 0x73, 0x18, 0x09, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x1b, 0x2e, 0x63, 0x61, 0x6e, 0x64, 0x6c,
+	0x65, 0x73, 0x74, 0x69, 0x63, 0x6b, 0x2e, 0x49, 0x6e, 0x64, 0x69, 0x63, 0x61, 0x74, 0x6f, 0x72,
+	0x56, 0x61, 0x6c, 0x75, 0x65, 0x52, 0x0a, 0x69, 0x6e, 0x64, 0x69, 0x63, 0x61, 0x74, 0x6f, 0x72,
+	0x73, 0x22, 0xf0, 0x01, 0x0a, 0x0e, 0x49, 0x6e, 0x64, 0x69, 0x63, 0x61, 0x74, 0x6f, 0x72, 0x56,
+	0x61, 0x6c, 0x75, 0x65, 0x12, 0x12, 0x0a, 0x04, 0x6e, 0x61, 0x6d, 0x65, 0x18, 0x01, 0x20, 0x01,
+	0x28, 0x09, 0x52, 0x04, 0x6e, 0x61, 0x6d, 0x65, 0x12, 0x3f, 0x0a, 0x06, 0x76, 0x61, 0x6c, 0x75,
+	0x65, 0x73, 0x18, 0x02, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x27, 0x2e, 0x63, 0x61, 0x6e, 0x64, 0x6c,
+	0x65, 0x73, 0x74, 0x69, 0x63, 0x6b, 0x2e, 0x49, 0x6e, 0x64, 0x69, 0x63, 0x61, 0x74, 0x6f, 0x72,
+	0x56, 0x61, 0x6c, 0x75, 0x65, 0x2e, 0x56, 0x61, 0x6c, 0x75, 0x65, 0x73, 0x45, 0x6e, 0x74, 0x72,
+	0x79, 0x52, 0x06, 0x76, 0x61, 0x6c, 0x75, 0x65, 0x73, 0x12, 0x14, 0x0a, 0x05, 0x72, 0x65, 0x61,
+	0x64, 0x79, 0x18, 0x03, 0x20, 0x01, 0x28, 0x08, 0x52, 0x05, 0x72, 0x65, 0x61, 0x64, 0x79, 0x12,
+	0x38, 0x0a, 0x09, 0x74, 0x69, 0x6d, 0x65, 0x73, 0x74, 0x61, 0x6d, 0x70, 0x18, 0x04, 0x20, 0x01,
+	0x28, 0x0b, 0x32, 0x1a, 0x2e, 0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2e, 0x70, 0x72, 0x6f, 0x74,
+	0x6f, 0x62, 0x75, 0x66, 0x2e, 0x54, 0x69, 0x6d, 0x65, 0x73, 0x74, 0x61, 0x6d, 0x70, 0x52, 0x09,
+	0x74, 0x69, 0x6d, 0x65, 0x73, 0x74, 0x61, 0x6d, 0x70, 0x1a, 0x39, 0x0a, 0x0b, 0x56, 0x61, 0x6c,
+	0x75, 0x65, 0x73, 0x45, 0x6e, 0x74, 0x72, 0x79, 0x12, 0x10, 0x0a, 0x03, 0x6b, 0x65, 0x79, 0x18,
+	0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x03, 0x6b, 0x65, 0x79, 0x12, 0x14, 0x0a, 0x05, 0x76, 0x61,
+	0x6c, 0x75, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x01, 0x52, 0x05, 0x76, 0x61, 0x6c, 0x75, 0x65,
+	0x3a, 0x02, 0x38, 0x01, 0x22, 0xac, 0x02, 0x0a, 0x18, 0x53, 0x75, 0x62, 0x73, 0x63, 0x72, 0x69,
+	0x62, 0x65, 0x54, 0x6f, 0x53, 0x74, 0x72, 0x65, 0x61, 0x6d, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73,
+	0x74, 0x12, 0x18, 0x0a, 0x07, 0x73, 0x79, 0x6d, 0x62, 0x6f, 0x6c, 0x73, 0x18, 0x01, 0x20, 0x03,
+	0x28, 0x09, 0x52, 0x07, 0x73, 0x79, 0x6d, 0x62, 0x6f, 0x6c, 0x73, 0x12, 0x1a, 0x0a, 0x08, 0x73,
+	0x6e, 0x61, 0x70, 0x73, 0x68, 0x6f, 0x74, 0x18, 0x02, 0x20, 0x01, 0x28, 0x08, 0x52, 0x08, 0x73,
+	0x6e, 0x61, 0x70, 0x73, 0x68, 0x6f, 0x74, 0x12, 0x23, 0x0a, 0x0d, 0x73, 0x6e, 0x61, 0x70, 0x73,
+	0x68, 0x6f, 0x74, 0x5f, 0x62, 0x61, 0x72, 0x73, 0x18, 0x03, 0x20, 0x01, 0x28, 0x05, 0x52, 0x0c,
+	0x73, 0x6e, 0x61, 0x70, 0x73, 0x68, 0x6f, 0x74, 0x42, 0x61, 0x72, 0x73, 0x12, 0x56, 0x0a, 0x0b,
+	0x72, 0x65, 0x73, 0x75, 0x6d, 0x65, 0x5f, 0x66, 0x72, 0x6f, 0x6d, 0x18, 0x04, 0x20, 0x03, 0x28,
+	0x0b, 0x32, 0x35, 0x2e, 0x63, 0x61, 0x6e, 0x64, 0x6c, 0x65, 0x73, 0x74, 0x69, 0x63, 0x6b, 0x2e,
+	0x53, 0x75, 0x62, 0x73, 0x63, 0x72, 0x69, 0x62, 0x65, 0x54, 0x6f, 0x53, 0x74, 0x72, 0x65, 0x61,
+	0x6d, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x2e, 0x52, 0x65, 0x73, 0x75, 0x6d, 0x65, 0x46,
+	0x72, 0x6f, 0x6d, 0x45, 0x6e, 0x74, 0x72, 0x79, 0x52, 0x0a, 0x72, 0x65, 0x73, 0x75, 0x6d, 0x65,
+	0x46, 0x72, 0x6f, 0x6d, 0x12, 0x1e, 0x0a, 0x0a, 0x69, 0x6e, 0x64, 0x69, 0x63, 0x61, 0x74, 0x6f,
+	0x72, 0x73, 0x18, 0x05, 0x20, 0x03, 0x28, 0x09, 0x52, 0x0a, 0x69, 0x6e, 0x64, 0x69, 0x63, 0x61,
+	0x74, 0x6f, 0x72, 0x73, 0x1a, 0x3d, 0x0a, 0x0f, 0x52, 0x65, 0x73, 0x75, 0x6d, 0x65, 0x46, 0x72,
+	0x6f, 0x6d, 0x45, 0x6e, 0x74, 0x72, 0x79, 0x12, 0x10, 0x0a, 0x03, 0x6b, 0x65, 0x79, 0x18, 0x01,
+	0x20, 0x01, 0x28, 0x09, 0x52, 0x03, 0x6b, 0x65, 0x79, 0x12, 0x14, 0x0a, 0x05, 0x76, 0x61, 0x6c,
+	0x75, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x04, 0x52, 0x05, 0x76, 0x61, 0x6c, 0x75, 0x65, 0x3a,
+	0x02, 0x38, 0x01, 0x22, 0x5d, 0x0a, 0x1c, 0x55, 0x6e, 0x73, 0x75, 0x62, 0x73, 0x63, 0x72, 0x69,
+	0x62, 0x65, 0x46, 0x72, 0x6f, 0x6d, 0x53, 0x74, 0x72, 0x65, 0x61, 0x6d, 0x52, 0x65, 0x71, 0x75,
+	0x65, 0x73, 0x74, 0x12, 0x18, 0x0a, 0x07, 0x73, 0x79, 0x6d, 0x62, 0x6f, 0x6c, 0x73, 0x18, 0x01,
+	0x20, 0x03, 0x28, 0x09, 0x52, 0x07, 0x73, 0x79, 0x6d, 0x62, 0x6f, 0x6c, 0x73, 0x12, 0x23, 0x0a,
+	0x0d, 0x73, 0x75, 0x62, 0x73, 0x63, 0x72, 0x69, 0x62, 0x65, 0x72, 0x5f, 0x69, 0x64, 0x18, 0x02,
+	0x20, 0x01, 0x28, 0x03, 0x52, 0x0c, 0x73, 0x75, 0x62, 0x73, 0x63, 0x72, 0x69, 0x62, 0x65, 0x72,
+	0x49, 0x64, 0x22, 0x2b, 0x0a, 0x0f, 0x47, 0x65, 0x6e, 0x65, 0x72, 0x69, 0x63, 0x52, 0x65, 0x73,
+	0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x18, 0x0a, 0x07, 0x6d, 0x65, 0x73, 0x73, 0x61, 0x67, 0x65,
+	0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x07, 0x6d, 0x65, 0x73, 0x73, 0x61, 0x67, 0x65, 0x22,
+	0xae, 0x01, 0x0a, 0x1a, 0x47, 0x65, 0x74, 0x49, 0x6e, 0x64, 0x69, 0x63, 0x61, 0x74, 0x6f, 0x72,
+	0x48, 0x69, 0x73, 0x74, 0x6f, 0x72, 0x79, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x16,
+	0x0a, 0x06, 0x73, 0x79, 0x6d, 0x62, 0x6f, 0x6c, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x06,
+	0x73, 0x79, 0x6d, 0x62, 0x6f, 0x6c, 0x12, 0x1c, 0x0a, 0x09, 0x69, 0x6e, 0x64, 0x69, 0x63, 0x61,
+	0x74, 0x6f, 0x72, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x09, 0x69, 0x6e, 0x64, 0x69, 0x63,
+	0x61, 0x74, 0x6f, 0x72, 0x12, 0x2e, 0x0a, 0x04, 0x66, 0x72, 0x6f, 0x6d, 0x18, 0x03, 0x20, 0x01,
+	0x28, 0x0b, 0x32, 0x1a, 0x2e, 0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2e, 0x70, 0x72, 0x6f, 0x74,
+	0x6f, 0x62, 0x75, 0x66, 0x2e, 0x54, 0x69, 0x6d, 0x65, 0x73, 0x74, 0x61, 0x6d, 0x70, 0x52, 0x04,
+	0x66, 0x72, 0x6f, 0x6d, 0x12, 0x2a, 0x0a, 0x02, 0x74, 0x6f, 0x18, 0x04, 0x20, 0x01, 0x28, 0x0b,
+	0x32, 0x1a, 0x2e, 0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62,
+	0x75, 0x66, 0x2e, 0x54, 0x69, 0x6d, 0x65, 0x73, 0x74, 0x61, 0x6d, 0x70, 0x52, 0x02, 0x74, 0x6f,
+	0x22, 0x52, 0x0a, 0x1b, 0x47, 0x65, 0x74, 0x49, 0x6e, 0x64, 0x69, 0x63, 0x61, 0x74, 0x6f, 0x72,
+	0x48, 0x69, 0x73, 0x74, 0x6f, 0x72, 0x79, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12,
+	0x33, 0x0a, 0x06, 0x76, 0x61, 0x6c, 0x75, 0x65, 0x73, 0x18, 0x01, 0x20, 0x03, 0x28, 0x0b, 0x32,
+	0x1b, 0x2e, 0x63, 0x61, 0x6e, 0x64, 0x6c, 0x65, 0x73, 0x74, 0x69, 0x63, 0x6b, 0x2e, 0x49, 0x6e,
+	0x64, 0x69, 0x63, 0x61, 0x74, 0x6f, 0x72, 0x56, 0x61, 0x6c, 0x75, 0x65, 0x52, 0x06, 0x76, 0x61,
+	0x6c, 0x75, 0x65, 0x73, 0x2a, 0x9f, 0x01, 0x0a, 0x14, 0x43, 0x61, 0x6e, 0x64, 0x6c, 0x65, 0x73,
+	0x74, 0x69, 0x63, 0x6b, 0x45, 0x76, 0x65, 0x6e, 0x74, 0x4b, 0x69, 0x6e, 0x64, 0x12, 0x1f, 0x0a,
+	0x1b, 0x43, 0x41, 0x4e, 0x44, 0x4c, 0x45, 0x53, 0x54, 0x49, 0x43, 0x4b, 0x5f, 0x45, 0x56, 0x45,
+	0x4e, 0x54, 0x5f, 0x4b, 0x49, 0x4e, 0x44, 0x5f, 0x4c, 0x49, 0x56, 0x45, 0x10, 0x00, 0x12, 0x23,
+	0x0a, 0x1f, 0x43, 0x41, 0x4e, 0x44, 0x4c, 0x45, 0x53, 0x54, 0x49, 0x43, 0x4b, 0x5f, 0x45, 0x56,
+	0x45, 0x4e, 0x54, 0x5f, 0x4b, 0x49, 0x4e, 0x44, 0x5f, 0x53, 0x4e, 0x41, 0x50, 0x53, 0x48, 0x4f,
+	0x54, 0x10, 0x01, 0x12, 0x21, 0x0a, 0x1d, 0x43, 0x41, 0x4e, 0x44, 0x4c, 0x45, 0x53, 0x54, 0x49,
+	0x43, 0x4b, 0x5f, 0x45, 0x56, 0x45, 0x4e, 0x54, 0x5f, 0x4b, 0x49, 0x4e, 0x44, 0x5f, 0x52, 0x45,
+	0x50, 0x4c, 0x41, 0x59, 0x10, 0x02, 0x12, 0x1e, 0x0a, 0x1a, 0x43, 0x41, 0x4e, 0x44, 0x4c, 0x45,
+	0x53, 0x54, 0x49, 0x43, 0x4b, 0x5f, 0x45, 0x56, 0x45, 0x4e, 0x54, 0x5f, 0x4b, 0x49, 0x4e, 0x44,
+	0x5f, 0x47, 0x41, 0x50, 0x10, 0x03, 0x42, 0x4b, 0x5a, 0x49, 0x67, 0x69, 0x74, 0x68, 0x75, 0x62,
+	0x2e, 0x63, 0x6f, 0x6d, 0x2f, 0x72, 0x61, 0x6d, 0x61, 0x73, 0x62, 0x65, 0x69, 0x6e, 0x61, 0x74,
+	0x79, 0x2f, 0x74, 0x72, 0x61, 0x64, 0x69, 0x6e, 0x67, 0x2d, 0x63, 0x68, 0x61, 0x72, 0x74, 0x2d,
+	0x73, 0x65, 0x72, 0x76, 0x69, 0x63, 0x65, 0x2f, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x2f, 0x63, 0x61,
+	0x6e, 0x64, 0x6c, 0x65, 0x73, 0x74, 0x69, 0x63, 0x6b, 0x2f, 0x63, 0x6f, 0x6e, 0x74, 0x72, 0x61,
+	0x63, 0x74, 0x73, 0x62, 0x06, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x33,
 }
 
 var (
@@ -312,21 +694,36 @@ func file_proto_candlestick_contracts_models_proto_rawDescGZIP() []byte {
 	return file_proto_candlestick_contracts_models_proto_rawDescData
 }
 
-var file_proto_candlestick_contracts_models_proto_msgTypes = make([]protoimpl.MessageInfo, 4)
+var file_proto_candlestick_contracts_models_proto_enumTypes = make([]protoimpl.EnumInfo, 1)
+var file_proto_candlestick_contracts_models_proto_msgTypes = make([]protoimpl.MessageInfo, 9)
 var file_proto_candlestick_contracts_models_proto_goTypes = []any{
-	(*Candlestick)(nil),                  // 0: candlestick.Candlestick
-	(*SubscribeToStreamRequest)(nil),     // 1: candlestick.SubscribeToStreamRequest
-	(*UnsubscribeFromStreamRequest)(nil), // 2: candlestick.UnsubscribeFromStreamRequest
-	(*GenericResponse)(nil),              // 3: candlestick.GenericResponse
-	(*timestamppb.Timestamp)(nil),        // 4: google.protobuf.Timestamp
+	(CandlestickEventKind)(0),            // 0: candlestick.CandlestickEventKind
+	(*Candlestick)(nil),                  // 1: candlestick.Candlestick
+	(*IndicatorValue)(nil),               // 2: candlestick.IndicatorValue
+	(*SubscribeToStreamRequest)(nil),     // 3: candlestick.SubscribeToStreamRequest
+	(*UnsubscribeFromStreamRequest)(nil), // 4: candlestick.UnsubscribeFromStreamRequest
+	(*GenericResponse)(nil),              // 5: candlestick.GenericResponse
+	(*GetIndicatorHistoryRequest)(nil),   // 6: candlestick.GetIndicatorHistoryRequest
+	(*GetIndicatorHistoryResponse)(nil),  // 7: candlestick.GetIndicatorHistoryResponse
+	nil,                                  // 8: candlestick.IndicatorValue.ValuesEntry
+	nil,                                  // 9: candlestick.SubscribeToStreamRequest.ResumeFromEntry
+	(*timestamppb.Timestamp)(nil),        // 10: google.protobuf.Timestamp
 }
 var file_proto_candlestick_contracts_models_proto_depIdxs = []int32{
-	4, // 0: candlestick.Candlestick.trade_timestamp:type_name -> google.protobuf.Timestamp
-	1, // [1:1] is the sub-list for method output_type
-	1, // [1:1] is the sub-list for method input_type
-	1, // [1:1] is the sub-list for extension type_name
-	1, // [1:1] is the sub-list for extension extendee
-	0, // [0:1] is the sub-list for field type_name
+	10, // 0: candlestick.Candlestick.trade_timestamp:type_name -> google.protobuf.Timestamp
+	0,  // 1: candlestick.Candlestick.kind:type_name -> candlestick.CandlestickEventKind
+	2,  // 2: candlestick.Candlestick.indicators:type_name -> candlestick.IndicatorValue
+	8,  // 3: candlestick.IndicatorValue.values:type_name -> candlestick.IndicatorValue.ValuesEntry
+	10, // 4: candlestick.IndicatorValue.timestamp:type_name -> google.protobuf.Timestamp
+	9,  // 5: candlestick.SubscribeToStreamRequest.resume_from:type_name -> candlestick.SubscribeToStreamRequest.ResumeFromEntry
+	10, // 6: candlestick.GetIndicatorHistoryRequest.from:type_name -> google.protobuf.Timestamp
+	10, // 7: candlestick.GetIndicatorHistoryRequest.to:type_name -> google.protobuf.Timestamp
+	2,  // 8: candlestick.GetIndicatorHistoryResponse.values:type_name -> candlestick.IndicatorValue
+	9,  // [9:9] is the sub-list for method output_type
+	9,  // [9:9] is the sub-list for method input_type
+	9,  // [9:9] is the sub-list for extension type_name
+	9,  // [9:9] is the sub-list for extension extendee
+	0,  // [0:9] is the sub-list for field type_name
 }
 
 func init() { file_proto_candlestick_contracts_models_proto_init() }
@@ -348,7 +745,7 @@ func file_proto_candlestick_contracts_models_proto_init() {
 			}
 		}
 		file_proto_candlestick_contracts_models_proto_msgTypes[1].Exporter = func(v any, i int) any {
-			switch v := v.(*SubscribeToStreamRequest); i {
+			switch v := v.(*IndicatorValue); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -360,7 +757,7 @@ func file_proto_candlestick_contracts_models_proto_init() {
 			}
 		}
 		file_proto_candlestick_contracts_models_proto_msgTypes[2].Exporter = func(v any, i int) any {
-			switch v := v.(*UnsubscribeFromStreamRequest); i {
+			switch v := v.(*SubscribeToStreamRequest); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -372,6 +769,18 @@ func file_proto_candlestick_contracts_models_proto_init() {
 			}
 		}
 		file_proto_candlestick_contracts_models_proto_msgTypes[3].Exporter = func(v any, i int) any {
+			switch v := v.(*UnsubscribeFromStreamRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_proto_candlestick_contracts_models_proto_msgTypes[4].Exporter = func(v any, i int) any {
 			switch v := v.(*GenericResponse); i {
 			case 0:
 				return &v.state
@@ -383,19 +792,44 @@ func file_proto_candlestick_contracts_models_proto_init() {
 				return nil
 			}
 		}
+		file_proto_candlestick_contracts_models_proto_msgTypes[5].Exporter = func(v any, i int) any {
+			switch v := v.(*GetIndicatorHistoryRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_proto_candlestick_contracts_models_proto_msgTypes[6].Exporter = func(v any, i int) any {
+			switch v := v.(*GetIndicatorHistoryResponse); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
 	}
 	type x struct{}
 	out := protoimpl.TypeBuilder{
 		File: protoimpl.DescBuilder{
 			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
 			RawDescriptor: file_proto_candlestick_contracts_models_proto_rawDesc,
-			NumEnums:      0,
-			NumMessages:   4,
+			NumEnums:      1,
+			NumMessages:   9,
 			NumExtensions: 0,
 			NumServices:   0,
 		},
 		GoTypes:           file_proto_candlestick_contracts_models_proto_goTypes,
 		DependencyIndexes: file_proto_candlestick_contracts_models_proto_depIdxs,
+		EnumInfos:         file_proto_candlestick_contracts_models_proto_enumTypes,
 		MessageInfos:      file_proto_candlestick_contracts_models_proto_msgTypes,
 	}.Build()
 	File_proto_candlestick_contracts_models_proto = out.File
@@ -21,6 +21,7 @@ const _ = grpc.SupportPackageIsVersion9
 const (
 	CandlestickService_SubscribeToCandlesticks_FullMethodName     = "/candlestick.CandlestickService/SubscribeToCandlesticks"
 	CandlestickService_UnsubscribeFromCandlesticks_FullMethodName = "/candlestick.CandlestickService/UnsubscribeFromCandlesticks"
+	CandlestickService_GetIndicatorHistory_FullMethodName         = "/candlestick.CandlestickService/GetIndicatorHistory"
 )
 
 // CandlestickServiceClient is the client API for CandlestickService service.
@@ -29,6 +30,7 @@ const (
 type CandlestickServiceClient interface {
 	SubscribeToCandlesticks(ctx context.Context, in *SubscribeToStreamRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[Candlestick], error)
 	UnsubscribeFromCandlesticks(ctx context.Context, in *UnsubscribeFromStreamRequest, opts ...grpc.CallOption) (*GenericResponse, error)
+	GetIndicatorHistory(ctx context.Context, in *GetIndicatorHistoryRequest, opts ...grpc.CallOption) (*GetIndicatorHistoryResponse, error)
 }
 
 type candlestickServiceClient struct {
@@ -68,12 +70,23 @@ func (c *candlestickServiceClient) UnsubscribeFromCandlesticks(ctx context.Conte
 	return out, nil
 }
 
+func (c *candlestickServiceClient) GetIndicatorHistory(ctx context.Context, in *GetIndicatorHistoryRequest, opts ...grpc.CallOption) (*GetIndicatorHistoryResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(GetIndicatorHistoryResponse)
+	err := c.cc.Invoke(ctx, CandlestickService_GetIndicatorHistory_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 // CandlestickServiceServer is the server API for CandlestickService service.
 // All implementations must embed UnimplementedCandlestickServiceServer
 // for forward compatibility.
 type CandlestickServiceServer interface {
 	SubscribeToCandlesticks(*SubscribeToStreamRequest, grpc.ServerStreamingServer[Candlestick]) error
 	UnsubscribeFromCandlesticks(context.Context, *UnsubscribeFromStreamRequest) (*GenericResponse, error)
+	GetIndicatorHistory(context.Context, *GetIndicatorHistoryRequest) (*GetIndicatorHistoryResponse, error)
 	mustEmbedUnimplementedCandlestickServiceServer()
 }
 
@@ -90,6 +103,9 @@ func (UnimplementedCandlestickServiceServer) SubscribeToCandlesticks(*SubscribeT
 func (UnimplementedCandlestickServiceServer) UnsubscribeFromCandlesticks(context.Context, *UnsubscribeFromStreamRequest) (*GenericResponse, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method UnsubscribeFromCandlesticks not implemented")
 }
+func (UnimplementedCandlestickServiceServer) GetIndicatorHistory(context.Context, *GetIndicatorHistoryRequest) (*GetIndicatorHistoryResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetIndicatorHistory not implemented")
+}
 func (UnimplementedCandlestickServiceServer) mustEmbedUnimplementedCandlestickServiceServer() {}
 func (UnimplementedCandlestickServiceServer) testEmbeddedByValue()                            {}
 
@@ -140,6 +156,24 @@ func _CandlestickService_UnsubscribeFromCandlesticks_Handler(srv interface{}, ct
 	return interceptor(ctx, in, info, handler)
 }
 
+func _CandlestickService_GetIndicatorHistory_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetIndicatorHistoryRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(CandlestickServiceServer).GetIndicatorHistory(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: CandlestickService_GetIndicatorHistory_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(CandlestickServiceServer).GetIndicatorHistory(ctx, req.(*GetIndicatorHistoryRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 // CandlestickService_ServiceDesc is the grpc.ServiceDesc for CandlestickService service.
 // It's only intended for direct use with grpc.RegisterService,
 // and not to be introspected or modified (even as a copy)
@@ -151,6 +185,10 @@ var CandlestickService_ServiceDesc = grpc.ServiceDesc{
 			MethodName: "UnsubscribeFromCandlesticks",
 			Handler:    _CandlestickService_UnsubscribeFromCandlesticks_Handler,
 		},
+		{
+			MethodName: "GetIndicatorHistory",
+			Handler:    _CandlestickService_GetIndicatorHistory_Handler,
+		},
 	},
 	Streams: []grpc.StreamDesc{
 		{
@@ -0,0 +1,134 @@
+// Code generated by protoc-gen-go-grpc. DO NOT EDIT.
+// versions:
+// - protoc-gen-go-grpc v1.5.1
+// - protoc             v4.24.0--rc2
+// source: proto/export/contracts/service.proto
+
+package contracts
+
+import (
+	context "context"
+	grpc "google.golang.org/grpc"
+	codes "google.golang.org/grpc/codes"
+	status "google.golang.org/grpc/status"
+)
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the grpc package it is being compiled against.
+// Requires gRPC-Go v1.64.0 or later.
+const _ = grpc.SupportPackageIsVersion9
+
+const (
+	ExportService_ExportCandlesticks_FullMethodName = "/export.ExportService/ExportCandlesticks"
+)
+
+// ExportServiceClient is the client API for ExportService service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
+//
+// exports the committed bars in bulk, e.g. to load months of bars in pandas
+type ExportServiceClient interface {
+	// streams the bars of the range as a file of the requested format, in
+	// chunks to be concatenated in order. the bars are read from the db as
+	// they are sent, so any range is exported with constant memory
+	ExportCandlesticks(ctx context.Context, in *ExportCandlesticksRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[ExportCandlesticksResponse], error)
+}
+
+type exportServiceClient struct {
+	cc grpc.ClientConnInterface
+}
+
+func NewExportServiceClient(cc grpc.ClientConnInterface) ExportServiceClient {
+	return &exportServiceClient{cc}
+}
+
+func (c *exportServiceClient) ExportCandlesticks(ctx context.Context, in *ExportCandlesticksRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[ExportCandlesticksResponse], error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	stream, err := c.cc.NewStream(ctx, &ExportService_ServiceDesc.Streams[0], ExportService_ExportCandlesticks_FullMethodName, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &grpc.GenericClientStream[ExportCandlesticksRequest, ExportCandlesticksResponse]{ClientStream: stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type ExportService_ExportCandlesticksClient = grpc.ServerStreamingClient[ExportCandlesticksResponse]
+
+// ExportServiceServer is the server API for ExportService service.
+// All implementations must embed UnimplementedExportServiceServer
+// for forward compatibility.
+//
+// exports the committed bars in bulk, e.g. to load months of bars in pandas
+type ExportServiceServer interface {
+	// streams the bars of the range as a file of the requested format, in
+	// chunks to be concatenated in order. the bars are read from the db as
+	// they are sent, so any range is exported with constant memory
+	ExportCandlesticks(*ExportCandlesticksRequest, grpc.ServerStreamingServer[ExportCandlesticksResponse]) error
+	mustEmbedUnimplementedExportServiceServer()
+}
+
+// UnimplementedExportServiceServer must be embedded to have
+// forward compatible implementations.
+//
+// NOTE: this should be embedded by value instead of pointer to avoid a nil
+// pointer dereference when methods are called.
+type UnimplementedExportServiceServer struct{}
+
+func (UnimplementedExportServiceServer) ExportCandlesticks(*ExportCandlesticksRequest, grpc.ServerStreamingServer[ExportCandlesticksResponse]) error {
+	return status.Errorf(codes.Unimplemented, "method ExportCandlesticks not implemented")
+}
+func (UnimplementedExportServiceServer) mustEmbedUnimplementedExportServiceServer() {}
+func (UnimplementedExportServiceServer) testEmbeddedByValue()                       {}
+
+// UnsafeExportServiceServer may be embedded to opt out of forward compatibility for this service.
+// Use of this interface is not recommended, as added methods to ExportServiceServer will
+// result in compilation errors.
+type UnsafeExportServiceServer interface {
+	mustEmbedUnimplementedExportServiceServer()
+}
+
+func RegisterExportServiceServer(s grpc.ServiceRegistrar, srv ExportServiceServer) {
+	// If the following call pancis, it indicates UnimplementedExportServiceServer was
+	// embedded by pointer and is nil.  This will cause panics if an
+	// unimplemented method is ever invoked, so we test this at initialization
+	// time to prevent it from happening at runtime later due to I/O.
+	if t, ok := srv.(interface{ testEmbeddedByValue() }); ok {
+		t.testEmbeddedByValue()
+	}
+	s.RegisterService(&ExportService_ServiceDesc, srv)
+}
+
+func _ExportService_ExportCandlesticks_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(ExportCandlesticksRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(ExportServiceServer).ExportCandlesticks(m, &grpc.GenericServerStream[ExportCandlesticksRequest, ExportCandlesticksResponse]{ServerStream: stream})
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type ExportService_ExportCandlesticksServer = grpc.ServerStreamingServer[ExportCandlesticksResponse]
+
+// ExportService_ServiceDesc is the grpc.ServiceDesc for ExportService service.
+// It's only intended for direct use with grpc.RegisterService,
+// and not to be introspected or modified (even as a copy)
+var ExportService_ServiceDesc = grpc.ServiceDesc{
+	ServiceName: "export.ExportService",
+	HandlerType: (*ExportServiceServer)(nil),
+	Methods:     []grpc.MethodDesc{},
+	Streams: []grpc.StreamDesc{
+		{
+			StreamName:    "ExportCandlesticks",
+			Handler:       _ExportService_ExportCandlesticks_Handler,
+			ServerStreams: true,
+		},
+	},
+	Metadata: "proto/export/contracts/service.proto",
+}
@@ -0,0 +1,150 @@
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// versions:
+// 	protoc-gen-go v1.34.2
+// 	protoc        v4.24.0--rc2
+// source: proto/replication/contracts/models.proto
+
+package contracts
+
+import (
+	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
+	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	reflect "reflect"
+	sync "sync"
+)
+
+const (
+	// Verify that this generated code is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(20 - protoimpl.MinVersion)
+	// Verify that runtime/protoimpl is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
+)
+
+type StreamBarUpdatesRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// address the following replica advertises, for the leader's logs
+	Follower string `protobuf:"bytes,1,opt,name=follower,proto3" json:"follower,omitempty"`
+}
+
+func (x *StreamBarUpdatesRequest) Reset() {
+	*x = StreamBarUpdatesRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_proto_replication_contracts_models_proto_msgTypes[0]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *StreamBarUpdatesRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*StreamBarUpdatesRequest) ProtoMessage() {}
+
+func (x *StreamBarUpdatesRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_replication_contracts_models_proto_msgTypes[0]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use StreamBarUpdatesRequest.ProtoReflect.Descriptor instead.
+func (*StreamBarUpdatesRequest) Descriptor() ([]byte, []int) {
+	return file_proto_replication_contracts_models_proto_rawDescGZIP(), []int{0}
+}
+
+func (x *StreamBarUpdatesRequest) GetFollower() string {
+	if x != nil {
+		return x.Follower
+	}
+	return ""
+}
+
+var File_proto_replication_contracts_models_proto protoreflect.FileDescriptor
+
+var file_proto_replication_contracts_models_proto_rawDesc = []byte{
+	0x0a, 0x28, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x2f, 0x72, 0x65, 0x70, 0x6c, 0x69, 0x63, 0x61, 0x74,
+	0x69, 0x6f, 0x6e, 0x2f, 0x63, 0x6f, 0x6e, 0x74, 0x72, 0x61, 0x63, 0x74, 0x73, 0x2f, 0x6d, 0x6f,
+	0x64, 0x65, 0x6c, 0x73, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x12, 0x0b, 0x72, 0x65, 0x70, 0x6c,
+	0x69, 0x63, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x22, 0x35, 0x0a, 0x17, 0x53, 0x74, 0x72, 0x65, 0x61,
+	0x6d, 0x42, 0x61, 0x72, 0x55, 0x70, 0x64, 0x61, 0x74, 0x65, 0x73, 0x52, 0x65, 0x71, 0x75, 0x65,
+	0x73, 0x74, 0x12, 0x1a, 0x0a, 0x08, 0x66, 0x6f, 0x6c, 0x6c, 0x6f, 0x77, 0x65, 0x72, 0x18, 0x01,
+	0x20, 0x01, 0x28, 0x09, 0x52, 0x08, 0x66, 0x6f, 0x6c, 0x6c, 0x6f, 0x77, 0x65, 0x72, 0x42, 0x4b,
+	0x5a, 0x49, 0x67, 0x69, 0x74, 0x68, 0x75, 0x62, 0x2e, 0x63, 0x6f, 0x6d, 0x2f, 0x72, 0x61, 0x6d,
+	0x61, 0x73, 0x62, 0x65, 0x69, 0x6e, 0x61, 0x74, 0x79, 0x2f, 0x74, 0x72, 0x61, 0x64, 0x69, 0x6e,
+	0x67, 0x2d, 0x63, 0x68, 0x61, 0x72, 0x74, 0x2d, 0x73, 0x65, 0x72, 0x76, 0x69, 0x63, 0x65, 0x2f,
+	0x70, 0x72, 0x6f, 0x74, 0x6f, 0x2f, 0x72, 0x65, 0x70, 0x6c, 0x69, 0x63, 0x61, 0x74, 0x69, 0x6f,
+	0x6e, 0x2f, 0x63, 0x6f, 0x6e, 0x74, 0x72, 0x61, 0x63, 0x74, 0x73, 0x62, 0x06, 0x70, 0x72, 0x6f,
+	0x74, 0x6f, 0x33,
+}
+
+var (
+	file_proto_replication_contracts_models_proto_rawDescOnce sync.Once
+	file_proto_replication_contracts_models_proto_rawDescData = file_proto_replication_contracts_models_proto_rawDesc
+)
+
+func file_proto_replication_contracts_models_proto_rawDescGZIP() []byte {
+	file_proto_replication_contracts_models_proto_rawDescOnce.Do(func() {
+		file_proto_replication_contracts_models_proto_rawDescData = protoimpl.X.CompressGZIP(file_proto_replication_contracts_models_proto_rawDescData)
+	})
+	return file_proto_replication_contracts_models_proto_rawDescData
+}
+
+var file_proto_replication_contracts_models_proto_msgTypes = make([]protoimpl.MessageInfo, 1)
+var file_proto_replication_contracts_models_proto_goTypes = []any{
+	(*StreamBarUpdatesRequest)(nil), // 0: replication.StreamBarUpdatesRequest
+}
+var file_proto_replication_contracts_models_proto_depIdxs = []int32{
+	0, // [0:0] is the sub-list for method output_type
+	0, // [0:0] is the sub-list for method input_type
+	0, // [0:0] is the sub-list for extension type_name
+	0, // [0:0] is the sub-list for extension extendee
+	0, // [0:0] is the sub-list for field type_name
+}
+
+func init() { file_proto_replication_contracts_models_proto_init() }
+func file_proto_replication_contracts_models_proto_init() {
+	if File_proto_replication_contracts_models_proto != nil {
+		return
+	}
+	if !protoimpl.UnsafeEnabled {
+		file_proto_replication_contracts_models_proto_msgTypes[0].Exporter = func(v any, i int) any {
+			switch v := v.(*StreamBarUpdatesRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+	}
+	type x struct{}
+	out := protoimpl.TypeBuilder{
+		File: protoimpl.DescBuilder{
+			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
+			RawDescriptor: file_proto_replication_contracts_models_proto_rawDesc,
+			NumEnums:      0,
+			NumMessages:   1,
+			NumExtensions: 0,
+			NumServices:   0,
+		},
+		GoTypes:           file_proto_replication_contracts_models_proto_goTypes,
+		DependencyIndexes: file_proto_replication_contracts_models_proto_depIdxs,
+		MessageInfos:      file_proto_replication_contracts_models_proto_msgTypes,
+	}.Build()
+	File_proto_replication_contracts_models_proto = out.File
+	file_proto_replication_contracts_models_proto_rawDesc = nil
+	file_proto_replication_contracts_models_proto_goTypes = nil
+	file_proto_replication_contracts_models_proto_depIdxs = nil
+}
@@ -0,0 +1,133 @@
+// Code generated by protoc-gen-go-grpc. DO NOT EDIT.
+// versions:
+// - protoc-gen-go-grpc v1.5.1
+// - protoc             v4.24.0--rc2
+// source: proto/replication/contracts/service.proto
+
+package contracts
+
+import (
+	context "context"
+	contracts "github.com/ramasbeinaty/trading-chart-service/proto/candlestick/contracts"
+	grpc "google.golang.org/grpc"
+	codes "google.golang.org/grpc/codes"
+	status "google.golang.org/grpc/status"
+)
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the grpc package it is being compiled against.
+// Requires gRPC-Go v1.64.0 or later.
+const _ = grpc.SupportPackageIsVersion9
+
+const (
+	ReplicationService_StreamBarUpdates_FullMethodName = "/replication.ReplicationService/StreamBarUpdates"
+)
+
+// ReplicationServiceClient is the client API for ReplicationService service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
+//
+// served by the leader to the other replicas, which don't ingest trades
+type ReplicationServiceClient interface {
+	// streams a snapshot of the recent and in progress bars of every symbol,
+	// then every update of the bars in progress
+	StreamBarUpdates(ctx context.Context, in *StreamBarUpdatesRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[contracts.Candlestick], error)
+}
+
+type replicationServiceClient struct {
+	cc grpc.ClientConnInterface
+}
+
+func NewReplicationServiceClient(cc grpc.ClientConnInterface) ReplicationServiceClient {
+	return &replicationServiceClient{cc}
+}
+
+func (c *replicationServiceClient) StreamBarUpdates(ctx context.Context, in *StreamBarUpdatesRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[contracts.Candlestick], error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	stream, err := c.cc.NewStream(ctx, &ReplicationService_ServiceDesc.Streams[0], ReplicationService_StreamBarUpdates_FullMethodName, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &grpc.GenericClientStream[StreamBarUpdatesRequest, contracts.Candlestick]{ClientStream: stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type ReplicationService_StreamBarUpdatesClient = grpc.ServerStreamingClient[contracts.Candlestick]
+
+// ReplicationServiceServer is the server API for ReplicationService service.
+// All implementations must embed UnimplementedReplicationServiceServer
+// for forward compatibility.
+//
+// served by the leader to the other replicas, which don't ingest trades
+type ReplicationServiceServer interface {
+	// streams a snapshot of the recent and in progress bars of every symbol,
+	// then every update of the bars in progress
+	StreamBarUpdates(*StreamBarUpdatesRequest, grpc.ServerStreamingServer[contracts.Candlestick]) error
+	mustEmbedUnimplementedReplicationServiceServer()
+}
+
+// UnimplementedReplicationServiceServer must be embedded to have
+// forward compatible implementations.
+//
+// NOTE: this should be embedded by value instead of pointer to avoid a nil
+// pointer dereference when methods are called.
+type UnimplementedReplicationServiceServer struct{}
+
+func (UnimplementedReplicationServiceServer) StreamBarUpdates(*StreamBarUpdatesRequest, grpc.ServerStreamingServer[contracts.Candlestick]) error {
+	return status.Errorf(codes.Unimplemented, "method StreamBarUpdates not implemented")
+}
+func (UnimplementedReplicationServiceServer) mustEmbedUnimplementedReplicationServiceServer() {}
+func (UnimplementedReplicationServiceServer) testEmbeddedByValue()                            {}
+
+// UnsafeReplicationServiceServer may be embedded to opt out of forward compatibility for this service.
+// Use of this interface is not recommended, as added methods to ReplicationServiceServer will
+// result in compilation errors.
+type UnsafeReplicationServiceServer interface {
+	mustEmbedUnimplementedReplicationServiceServer()
+}
+
+func RegisterReplicationServiceServer(s grpc.ServiceRegistrar, srv ReplicationServiceServer) {
+	// If the following call pancis, it indicates UnimplementedReplicationServiceServer was
+	// embedded by pointer and is nil.  This will cause panics if an
+	// unimplemented method is ever invoked, so we test this at initialization
+	// time to prevent it from happening at runtime later due to I/O.
+	if t, ok := srv.(interface{ testEmbeddedByValue() }); ok {
+		t.testEmbeddedByValue()
+	}
+	s.RegisterService(&ReplicationService_ServiceDesc, srv)
+}
+
+func _ReplicationService_StreamBarUpdates_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(StreamBarUpdatesRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(ReplicationServiceServer).StreamBarUpdates(m, &grpc.GenericServerStream[StreamBarUpdatesRequest, contracts.Candlestick]{ServerStream: stream})
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type ReplicationService_StreamBarUpdatesServer = grpc.ServerStreamingServer[contracts.Candlestick]
+
+// ReplicationService_ServiceDesc is the grpc.ServiceDesc for ReplicationService service.
+// It's only intended for direct use with grpc.RegisterService,
+// and not to be introspected or modified (even as a copy)
+var ReplicationService_ServiceDesc = grpc.ServiceDesc{
+	ServiceName: "replication.ReplicationService",
+	HandlerType: (*ReplicationServiceServer)(nil),
+	Methods:     []grpc.MethodDesc{},
+	Streams: []grpc.StreamDesc{
+		{
+			StreamName:    "StreamBarUpdates",
+			Handler:       _ReplicationService_StreamBarUpdates_Handler,
+			ServerStreams: true,
+		},
+	},
+	Metadata: "proto/replication/contracts/service.proto",
+}